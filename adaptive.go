@@ -0,0 +1,212 @@
+package rxgo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AdaptiveMode is the mode of a subject subscription with the Adaptive back pressure strategy.
+type AdaptiveMode uint32
+
+const (
+	// Buffering queues all the items.
+	Buffering AdaptiveMode = iota
+	// Sampling queues one item out of the sample rate, the other items being dropped.
+	Sampling
+	// LatestOnly queues the latest item only, replacing the queued items.
+	LatestOnly
+)
+
+func (m AdaptiveMode) String() string {
+	switch m {
+	case Buffering:
+		return "buffering"
+	case Sampling:
+		return "sampling"
+	case LatestOnly:
+		return "latest-only"
+	default:
+		return "unknown"
+	}
+}
+
+// AdaptivePolicy defines when a subject subscription with the Adaptive back pressure strategy switches modes.
+// A subscription switches to Sampling, then to LatestOnly, as soon as its queue reaches their depth, and
+// switches back to the previous mode once its queue is drained, if it spent the cooldown in its mode.
+type AdaptivePolicy struct {
+	// SampleAbove is the queue depth from which a subscription samples its items, 256 if zero.
+	SampleAbove int
+	// SampleRate is the number of items out of which one is queued while sampling, 10 if zero.
+	SampleRate int
+	// LatestAbove is the queue depth from which a subscription only queues the latest item, 1024 if zero.
+	LatestAbove int
+	// Cooldown is the minimum time spent in a mode before switching back to the previous one, a second if zero.
+	Cooldown time.Duration
+}
+
+// withDefaults returns the policy, with the default value of each zero field
+func (p AdaptivePolicy) withDefaults() AdaptivePolicy {
+	if p.SampleAbove <= 0 {
+		p.SampleAbove = 256
+	}
+	if p.SampleRate <= 0 {
+		p.SampleRate = 10
+	}
+	if p.LatestAbove <= 0 {
+		p.LatestAbove = 1024
+	}
+	if p.Cooldown <= 0 {
+		p.Cooldown = time.Second
+	}
+	return p
+}
+
+// adaptiveQueue is the queue of a subscription with the Adaptive strategy, switching modes as it grows.
+type adaptiveQueue struct {
+	mu     sync.Mutex
+	items  []Item
+	closed bool
+	// ready is signaled once an item was queued or the queue closed
+	ready  chan struct{}
+	policy AdaptivePolicy
+	clock  Clock
+	mode   AdaptiveMode
+	// since is the time the current mode was entered
+	since time.Time
+	// sampled is the number of items received while sampling
+	sampled int
+	// transitioned is called with each mode transition, without lock
+	transitioned func(from, to AdaptiveMode)
+}
+
+func newAdaptiveQueue(policy AdaptivePolicy, clock Clock, transitioned func(from, to AdaptiveMode)) *adaptiveQueue {
+	return &adaptiveQueue{
+		ready:        make(chan struct{}, 1),
+		policy:       policy.withDefaults(),
+		clock:        clock,
+		since:        clock.Now(),
+		transitioned: transitioned,
+	}
+}
+
+// push queues an item per the current mode, and returns the items dropped. The inline errors are always
+// queued.
+func (q *adaptiveQueue) push(item Item) []Item {
+	q.mu.Lock()
+	from, to := q.adapt()
+	var dropped []Item
+	switch {
+	case q.closed:
+	case unwrapped(item).Error():
+		q.items = append(q.items, item)
+	case q.mode == Sampling:
+		if q.sampled%q.policy.SampleRate == 0 {
+			q.items = append(q.items, item)
+		} else {
+			dropped = append(dropped, item)
+		}
+		q.sampled++
+	case q.mode == LatestOnly:
+		kept := q.items[:0]
+		for _, queued := range q.items {
+			if unwrapped(queued).Error() {
+				kept = append(kept, queued)
+			} else {
+				dropped = append(dropped, queued)
+			}
+		}
+		q.items = append(kept, item)
+	default:
+		q.items = append(q.items, item)
+	}
+	q.mu.Unlock()
+
+	q.signal()
+	q.notify(from, to)
+	return dropped
+}
+
+// pop returns the next item, waiting until an item is queued. It returns false once the queue is closed and
+// drained, or the context is done.
+func (q *adaptiveQueue) pop(ctx context.Context) (Item, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.items) > 0 {
+			item := q.items[0]
+			q.items[0] = Item{}
+			q.items = q.items[1:]
+			from, to := q.adapt()
+			q.mu.Unlock()
+			q.notify(from, to)
+			return item, true
+		}
+		closed := q.closed
+		q.mu.Unlock()
+		if closed {
+			return Item{}, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return Item{}, false
+		case <-q.ready:
+		}
+	}
+}
+
+// adapt switches the mode per the queue depth, and returns the previous and the current mode. The caller
+// holds mu.
+func (q *adaptiveQueue) adapt() (AdaptiveMode, AdaptiveMode) {
+	from := q.mode
+	depth := len(q.items)
+	switch {
+	case depth >= q.policy.LatestAbove && q.mode < LatestOnly:
+		q.mode = LatestOnly
+	case depth >= q.policy.SampleAbove && q.mode < Sampling:
+		q.mode = Sampling
+	case depth == 0 && q.mode > Buffering && q.clock.Now().Sub(q.since) >= q.policy.Cooldown:
+		q.mode--
+	}
+	if q.mode != from {
+		q.since = q.clock.Now()
+		q.sampled = 0
+	}
+	return from, q.mode
+}
+
+func (q *adaptiveQueue) notify(from, to AdaptiveMode) {
+	if from != to && q.transitioned != nil {
+		q.transitioned(from, to)
+	}
+}
+
+func (q *adaptiveQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// len returns the number of queued items
+func (q *adaptiveQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.items)
+}
+
+// close closes the queue, the queued items being still sent
+func (q *adaptiveQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.signal()
+}
+
+// adaptiveTransition is called with the mode transitions of a subscriber with the Adaptive strategy
+func (sub *subscriberState) adaptiveTransition(from, to AdaptiveMode) {
+	s := sub.subject
+	s.logger().Info("rxgo: back pressure mode changed", "subscriber", sub.id, "from", from.String(), "to", to.String())
+	globalHooks.modeChanged(s, sub.id, from, to)
+}
@@ -0,0 +1,99 @@
+package rxgo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestAdaptiveQueue(t *testing.T) {
+	clock := NewTestScheduler(time.Now())
+	var transitions []AdaptiveMode
+	queue := newAdaptiveQueue(AdaptivePolicy{SampleAbove: 2, SampleRate: 2, LatestAbove: 5, Cooldown: time.Second}, clock,
+		func(from, to AdaptiveMode) {
+			transitions = append(transitions, to)
+		})
+	push := func(vs ...interface{}) []interface{} {
+		var dropped []Item
+		for _, v := range vs {
+			dropped = append(dropped, queue.push(Of(v))...)
+		}
+		return values(dropped)
+	}
+	pop := func() interface{} {
+		item, _ := queue.pop(context.Background())
+		if item.Error() {
+			return item.E
+		}
+		return item.V
+	}
+
+	assert.Empty(t, push(1, 2))
+	// sampling one item out of two
+	assert.Equal(t, []interface{}{4, 6}, push(3, 4, 5, 6))
+	// the latest item replaces the queued ones, the inline errors being kept
+	queue.push(Error(errFoo))
+	assert.Equal(t, []interface{}{1, 2, 3, 5}, push(7))
+	assert.Equal(t, []interface{}{7}, push(8))
+	assert.Equal(t, errFoo, pop())
+	assert.Equal(t, 8, pop())
+	assert.Equal(t, []AdaptiveMode{Sampling, LatestOnly}, transitions)
+
+	// drained, the queue switches back a mode per cooldown
+	assert.Empty(t, push(9))
+	assert.Equal(t, 9, pop())
+	assert.Equal(t, LatestOnly, queue.mode)
+	clock.Advance(time.Second)
+	assert.Empty(t, push(10))
+	assert.Equal(t, 10, pop())
+	clock.Advance(time.Second)
+	assert.Empty(t, push(11, 12))
+	assert.Equal(t, []AdaptiveMode{Sampling, LatestOnly, Sampling, Buffering}, transitions)
+
+	queue.close()
+	assert.Equal(t, 11, pop())
+	assert.Equal(t, 12, pop())
+	_, ok := queue.pop(context.Background())
+	assert.False(t, ok)
+}
+
+func TestSubject_Adaptive(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	var mu sync.Mutex
+	var transitions []AdaptiveMode
+	dispose := OnModeChangeHook(func(subject ISubject, id int, from, to AdaptiveMode) {
+		mu.Lock()
+		defer mu.Unlock()
+		transitions = append(transitions, to)
+	})
+	defer dispose()
+
+	subject := NewSubject(WithBackPressureStrategy(Adaptive),
+		WithAdaptivePolicy(AdaptivePolicy{SampleAbove: 10, SampleRate: 2, LatestAbove: 20}))
+	_, obs := subject.Subscribe()
+	// the observer does not read until all the items are published, which never blocks
+	ch := obs.Observe()
+	for i := 1; i <= 100; i++ {
+		subject.Next(i)
+	}
+	subject.Complete()
+
+	mu.Lock()
+	assert.Equal(t, []AdaptiveMode{Sampling, LatestOnly}, transitions)
+	mu.Unlock()
+	var received []interface{}
+	for item := range ch {
+		received = append(received, item.V)
+	}
+	// the latest item is kept
+	assert.Equal(t, 100, received[len(received)-1])
+	for i := 1; i < len(received); i++ {
+		assert.Less(t, received[i-1].(int), received[i].(int))
+	}
+	stats := subject.Stats()
+	assert.Equal(t, uint64(100-len(received)), stats.Dropped)
+}
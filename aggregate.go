@@ -0,0 +1,144 @@
+package rxgo
+
+import (
+	"container/list"
+	"context"
+	"time"
+)
+
+// FlushPolicy defines when AggregateByKey emits the snapshots of its aggregates. Whatever the policy, the
+// aggregates updated since their latest snapshot are emitted once the Observable completes.
+type FlushPolicy struct {
+	// Count emits the snapshot of an aggregate once it accumulated Count items since its latest snapshot,
+	// if positive.
+	Count int
+	// Interval emits the snapshots of the aggregates updated since the latest flush periodically, if positive.
+	Interval time.Duration
+	// Reset restarts an aggregate from the seed once its snapshot is emitted, making tumbling rollups instead
+	// of running aggregates. The state of the keys is then released at each flush, until they are seen again.
+	Reset bool
+}
+
+// KeyedAggregate is a snapshot of the aggregate of a key emitted by AggregateByKey.
+type KeyedAggregate struct {
+	Key   interface{}
+	Value interface{}
+	// Count is the number of items accumulated, since the latest reset with FlushPolicy.Reset.
+	Count int
+}
+
+// keyedState is the running aggregate of a key
+type keyedState struct {
+	value interface{}
+	count int
+	// pending is the number of items accumulated since the latest snapshot
+	pending int
+	// elem is the key in the order of the keys
+	elem *list.Element
+}
+
+// keyedAggregator maintains the aggregates of the keys, in the order the keys were first seen
+type keyedAggregator struct {
+	seed   interface{}
+	acc    Func2
+	policy FlushPolicy
+	states StateStore
+	keys   *list.List
+	// evicted holds the snapshots of the aggregates evicted by the store while updated since their latest
+	// snapshot, until emitted
+	evicted []KeyedAggregate
+}
+
+func newKeyedAggregator(seed interface{}, acc Func2, policy FlushPolicy, states StateStore) *keyedAggregator {
+	a := &keyedAggregator{
+		seed:   seed,
+		acc:    acc,
+		policy: policy,
+		states: states,
+		keys:   list.New(),
+	}
+	states.OnEvict(func(key, v interface{}) {
+		state := v.(*keyedState)
+		a.keys.Remove(state.elem)
+		if state.pending > 0 {
+			a.evicted = append(a.evicted, a.snapshot(key, state))
+		}
+	})
+	return a
+}
+
+// add accumulates an item into the aggregate of its key, returning the aggregate if its snapshot is due
+func (a *keyedAggregator) add(ctx context.Context, key, v interface{}) (*keyedState, error) {
+	existing, exists := a.states.Get(key)
+	state := &keyedState{value: a.seed}
+	if exists {
+		state = existing.(*keyedState)
+	}
+	value, err := a.acc(ctx, state.value, v)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		state.elem = a.keys.PushBack(key)
+	}
+	state.value = value
+	state.count++
+	state.pending++
+	// putting the key may evict others
+	a.states.Put(key, state)
+	if a.policy.Count > 0 && state.pending >= a.policy.Count {
+		return state, nil
+	}
+	return nil, nil
+}
+
+// snapshot returns the snapshot of the aggregate of a key, resetting it if required
+func (a *keyedAggregator) snapshot(key interface{}, state *keyedState) KeyedAggregate {
+	snapshot := KeyedAggregate{Key: key, Value: state.value, Count: state.count}
+	state.pending = 0
+	if a.policy.Reset {
+		state.value = a.seed
+		state.count = 0
+	}
+	return snapshot
+}
+
+// emitEvicted emits the snapshots of the evicted aggregates, returning whether the context is not done
+func (a *keyedAggregator) emitEvicted(ctx context.Context, next chan<- Item) bool {
+	for len(a.evicted) > 0 {
+		snapshot := a.evicted[0]
+		a.evicted = a.evicted[1:]
+		if !Of(snapshot).SendContext(ctx, next) {
+			return false
+		}
+	}
+	a.evicted = nil
+	return true
+}
+
+// flush emits the snapshots of the aggregates updated since their latest snapshot, returning whether the
+// context is not done
+func (a *keyedAggregator) flush(ctx context.Context, next chan<- Item) bool {
+	for elem := a.keys.Front(); elem != nil; {
+		// the key may be removed or evicted
+		following := elem.Next()
+		key := elem.Value
+		elem = following
+		v, exists := a.states.Get(key)
+		if !exists {
+			continue
+		}
+		state := v.(*keyedState)
+		if state.pending > 0 {
+			if !Of(a.snapshot(key, state)).SendContext(ctx, next) {
+				return false
+			}
+		}
+		if a.policy.Reset && state.count == 0 {
+			// back to the seed
+			a.states.Delete(key)
+			a.keys.Remove(state.elem)
+		}
+	}
+	return a.emitEvicted(ctx, next)
+}
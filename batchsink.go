@@ -0,0 +1,146 @@
+package rxgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// BatchSink observes an Observable and writes its items in batches of flushSize with write, a batch being
+// also written once its oldest item was buffered for flushInterval, if set. A failed write is retried per the
+// policy set with WithBatchBackOff. As the next item is only received once the batch is written, a slow
+// writer slows down the Observable.
+//
+// The pending items are written once the Observable completes or emits an error, and once the context is
+// done, the latter write not being canceled. It returns nil once the Observable completed, the error emitted
+// by the Observable or the error of a batch whose write failed, the observation being stopped, or the context
+// error. With the ContinueOnError strategy, the errors of the Observable are skipped and a batch whose write
+// failed is dropped.
+func BatchSink(ctx context.Context, observable Observable, flushSize int, flushInterval Duration,
+	write func(ctx context.Context, batch []interface{}) error, opts ...Option) error {
+	if flushSize <= 0 {
+		return IllegalInputError{error: "flush size must be positive"}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sink := &batchSink{
+		flushSize:     flushSize,
+		flushInterval: flushInterval,
+		write:         write,
+		option:        parseOptions(opts...),
+	}
+	return sink.run(ctx, observable.Observe(append(opts, WithContext(ctx))...))
+}
+
+// batchSink buffers the items of an Observable and writes them in batches
+type batchSink struct {
+	flushSize     int
+	flushInterval Duration
+	write         func(ctx context.Context, batch []interface{}) error
+	option        Option
+	buffer        []interface{}
+}
+
+// run consumes the items until the source completes or the context is done, and returns the error stopping
+// the sink, if any
+func (s *batchSink) run(ctx context.Context, observe <-chan Item) error {
+	clock := s.option.getClock()
+	var timer ClockTimer
+	var flushTime <-chan time.Time
+	stopTimer := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, flushTime = nil, nil
+		}
+	}
+	defer stopTimer()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// the pending items are still written, without being canceled
+			if err := s.flush(detachedContext{ctx}); err != nil {
+				return err
+			}
+			return ctx.Err()
+		case <-flushTime:
+			timer, flushTime = nil, nil
+			if err := s.flush(ctx); err != nil {
+				return err
+			}
+		case item, ok := <-observe:
+			if !ok {
+				return s.flush(ctx)
+			}
+			if item.Error() {
+				if s.option.getErrorStrategy() == ContinueOnError {
+					continue
+				}
+				if err := s.flush(ctx); err != nil {
+					return err
+				}
+				return item.E
+			}
+
+			s.buffer = append(s.buffer, item.V)
+			if len(s.buffer) >= s.flushSize {
+				stopTimer()
+				if err := s.flush(ctx); err != nil {
+					return err
+				}
+			} else if timer == nil && s.flushInterval != nil {
+				// the interval is measured from the oldest buffered item
+				if d := s.flushInterval.duration(); d > 0 {
+					timer = clock.NewTimer(d)
+					flushTime = timer.C()
+				}
+			}
+		}
+	}
+}
+
+// flush writes the buffered items, retried per the back-off policy. A failed batch is dropped and its error
+// returned, unless the ContinueOnError strategy is set.
+func (s *batchSink) flush(ctx context.Context) error {
+	if len(s.buffer) == 0 {
+		return nil
+	}
+	batch := s.buffer
+	s.buffer = nil
+
+	policy := backoff.WithContext(s.option.getBatchBackOff()(), ctx)
+	err := backoff.RetryNotifyWithTimer(func() error {
+		return s.write(ctx, batch)
+	}, policy, nil, &backOffTimer{clock: s.option.getClock()})
+	if err == nil {
+		return nil
+	}
+	s.option.getLogger().Error("rxgo: batch write failed", "error", err, "items", len(batch))
+	if s.option.getErrorStrategy() == ContinueOnError {
+		return nil
+	}
+	return err
+}
+
+// detachedContext carries the values of its parent without being canceled with it
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,136 @@
+package rxgo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// batchRecorder records the batches written by BatchSink, failing the first failures writes
+type batchRecorder struct {
+	mu       sync.Mutex
+	batches  [][]interface{}
+	failures int
+	attempts int
+	ctxErr   error
+}
+
+func (r *batchRecorder) write(ctx context.Context, batch []interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts++
+	if r.failures > 0 {
+		r.failures--
+		return errFoo
+	}
+	r.ctxErr = ctx.Err()
+	r.batches = append(r.batches, batch)
+	return nil
+}
+
+func (r *batchRecorder) written() [][]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]interface{}(nil), r.batches...)
+}
+
+func TestBatchSink(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	recorder := &batchRecorder{}
+	assert.NoError(t, BatchSink(ctx, testObservable(ctx, 1, 2, 3, 4, 5), 2, nil, recorder.write))
+	assert.Equal(t, [][]interface{}{{1, 2}, {3, 4}, {5}}, recorder.written())
+
+	assert.IsType(t, IllegalInputError{}, BatchSink(ctx, testObservable(ctx, 1), 0, nil, recorder.write))
+}
+
+func TestBatchSink_Interval(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Now())
+	recorder := &batchRecorder{}
+	src := make(chan Item)
+	done := make(chan error, 1)
+	go func() {
+		done <- BatchSink(context.Background(), FromChannel(src), 10, WithDuration(time.Second), recorder.write,
+			WithClock(scheduler))
+	}()
+
+	src <- Of(1)
+	scheduler.BlockUntil(1)
+	scheduler.Advance(500 * time.Millisecond)
+	src <- Of(2)
+	// the interval is measured from the oldest item
+	scheduler.Advance(500 * time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return len(recorder.written()) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, [][]interface{}{{1, 2}}, recorder.written())
+
+	src <- Of(3)
+	close(src)
+	assert.NoError(t, <-done)
+	assert.Equal(t, [][]interface{}{{1, 2}, {3}}, recorder.written())
+}
+
+func TestBatchSink_Retry(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	recorder := &batchRecorder{failures: 2}
+	retry := WithBatchBackOff(func() backoff.BackOff {
+		return backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 2)
+	})
+	assert.NoError(t, BatchSink(ctx, testObservable(ctx, 1, 2), 2, nil, recorder.write, retry))
+	assert.Equal(t, [][]interface{}{{1, 2}}, recorder.written())
+	assert.Equal(t, 3, recorder.attempts)
+
+	// the retries are exhausted
+	recorder = &batchRecorder{failures: 3}
+	assert.Equal(t, errFoo, BatchSink(ctx, testObservable(ctx, 1, 2, 3, 4), 2, nil, recorder.write, retry))
+	assert.Empty(t, recorder.written())
+	assert.Equal(t, 3, recorder.attempts)
+
+	// the failed batch is dropped
+	recorder = &batchRecorder{failures: 3}
+	assert.NoError(t, BatchSink(ctx, testObservable(ctx, 1, 2, 3, 4), 2, nil, recorder.write, retry,
+		WithErrorStrategy(ContinueOnError)))
+	assert.Equal(t, [][]interface{}{{3, 4}}, recorder.written())
+}
+
+func TestBatchSink_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	recorder := &batchRecorder{}
+	assert.Equal(t, errFoo, BatchSink(ctx, testObservable(ctx, 1, 2, 3, errFoo, 4), 2, nil, recorder.write))
+	assert.Equal(t, [][]interface{}{{1, 2}, {3}}, recorder.written())
+
+	recorder = &batchRecorder{}
+	assert.NoError(t, BatchSink(ctx, testObservable(ctx, 1, 2, 3, errFoo, 4), 2, nil, recorder.write,
+		WithErrorStrategy(ContinueOnError)))
+	assert.Equal(t, [][]interface{}{{1, 2}, {3, 4}}, recorder.written())
+}
+
+func TestBatchSink_Cancel(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	recorder := &batchRecorder{}
+	src := make(chan Item)
+	done := make(chan error, 1)
+	go func() {
+		done <- BatchSink(ctx, FromChannel(src), 10, nil, recorder.write)
+	}()
+	src <- Of(1)
+	src <- Of(2)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	// the pending items are written with a context not canceled
+	assert.Equal(t, [][]interface{}{{1, 2}}, recorder.written())
+	assert.NoError(t, recorder.ctxErr)
+}
@@ -14,37 +14,97 @@ type BehaviorSubject struct {
 // NewBehaviorSubject Creates a new behavior subject
 func NewBehaviorSubject(opts ...Option) *BehaviorSubject {
 	res := BehaviorSubject{
-		Subject:       *NewSubject(opts...), // subscriber must be able to receive last item and new items
+		Subject:       newSubject(opts...), // subscriber must be able to receive last item and new items
 		lastValueLock: sync.Mutex{},
 	}
+	globalSubjects.add(&res.Subject, &res)
 
 	return &res
 }
 
 // Next shadows base next function to capture the last item.
 func (s *BehaviorSubject) Next(value interface{}) {
+	s.NextItem(Of(value))
+}
+
+// NextItem shadows base next item function to capture the last value. Inline errors are not captured.
+// Capturing and publishing under the same lock as Subscribe keeps a new subscriber from missing the value
+// or receiving it twice.
+func (s *BehaviorSubject) NextItem(item Item) {
 	s.lastValueLock.Lock()
 	defer s.lastValueLock.Unlock()
 
-	s.lastValue = value
+	items := s.intercept([]Item{item})
+	for _, item := range items {
+		if !item.Error() {
+			s.lastValue = item.V
+		}
+	}
+	s.nextItems(items...)
+}
+
+// NextBatch shadows base next batch function to capture the last value of the batch.
+func (s *BehaviorSubject) NextBatch(values []interface{}) {
+	s.lastValueLock.Lock()
+	defer s.lastValueLock.Unlock()
 
-	s.Subject.Next(value)
+	batch := s.newBatch(values)
+	defer s.releaseBatch(batch)
+
+	for _, item := range batch.items {
+		if !item.Error() {
+			s.lastValue = item.V
+		}
+	}
+
+	s.nextItems(batch.items...)
 }
 
 // Subscribe shadows base subscribe function to replay the last captured item.
 func (s *BehaviorSubject) Subscribe() (Subscription, Observable) {
+	return s.SubscribeWhere(nil)
+}
+
+// SubscribeWhere shadows base subscribe where function to replay the last captured item, if it satisfies
+// the predicate.
+func (s *BehaviorSubject) SubscribeWhere(predicate func(interface{}) bool) (Subscription, Observable) {
 	s.Lock()
 	defer s.Unlock()
 
+	s.lastValueLock.Lock()
+	defer s.lastValueLock.Unlock()
+
 	// create buffered channel to hold last item
-	sub, obs := s.createSubscription(1)
-	subChan := s.subscribers[sub.GetId()]
+	sub, obs := s.createFilteredSubscription(1, predicate)
+	subscriber := s.subscribers[sub.GetId()]
 
 	if s.lastValue != nil {
-		s.lastValueLock.Lock()
-		subChan <- Of(s.lastValue)
-		s.lastValueLock.Unlock()
+		s.send(subscriber, Of(s.lastValue))
 	}
 
 	return sub, obs
 }
+
+// Producer registers a producer of the subject, like Subject.Producer, its items being published to the
+// BehaviorSubject.
+func (s *BehaviorSubject) Producer() *ProducerHandle {
+	return s.Subject.producer(s)
+}
+
+// SubscribeTo forwards the notifications of the sources to the subject, like Subject.SubscribeTo, the values
+// being published to the BehaviorSubject.
+func (s *BehaviorSubject) SubscribeTo(sources ...Observable) Disposable {
+	return s.Subject.subscribeTo(s, sources)
+}
+
+func (s *BehaviorSubject) info() SubjectInfo {
+	info := s.Subject.info()
+	info.Type = "BehaviorSubject"
+
+	s.lastValueLock.Lock()
+	defer s.lastValueLock.Unlock()
+	if s.lastValue != nil {
+		info.Buffered = 1
+	}
+	return info
+}
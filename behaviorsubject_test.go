@@ -10,6 +10,7 @@ import (
 // TestBehaviorSubject verifies that two subscribers started at different times receive the same result
 func TestBehaviorSubject(t *testing.T) {
 	subject := NewBehaviorSubject()
+	defer subject.Complete()
 
 	// obs1 no last value
 	_, obs1 := subject.Subscribe()
@@ -34,3 +35,17 @@ func TestBehaviorSubject(t *testing.T) {
 	assert.Equal(t, []int{1, 2}, values1)
 	assert.Equal(t, []int{1, 2}, values2)
 }
+
+// TestBehaviorNextBatch verifies the last value of a batch is replayed to new subscribers
+func TestBehaviorNextBatch(t *testing.T) {
+	subject := NewBehaviorSubject()
+
+	subject.NextBatch([]interface{}{1, 2, 3})
+
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(3)
+}
@@ -0,0 +1,95 @@
+package rxgo
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// SubjectBenchmark is a subject configuration measured by BenchmarkSubject.
+type SubjectBenchmark struct {
+	// Name is the sub-benchmark name used by RunSubjectBenchmarks.
+	Name string
+	// Subscribers is the number of subscribers consuming the items.
+	Subscribers int
+	// BatchSize publishes the items with NextBatch by batches of this size, or with Next if zero.
+	BatchSize int
+	// Options are the subject options.
+	Options []Option
+}
+
+// BenchmarkSubject measures the throughput of a subject configuration: b.N items are published to a subject
+// whose subscribers consume them as fast as possible. Besides the time per item, it reports the published
+// items per second and the items dropped per published item.
+func BenchmarkSubject(b *testing.B, config SubjectBenchmark) {
+	subject := NewSubject(config.Options...)
+
+	wg := sync.WaitGroup{}
+	wg.Add(config.Subscribers)
+	for i := 0; i < config.Subscribers; i++ {
+		_, obs := subject.Subscribe()
+		observe := obs.Observe()
+		go func() {
+			defer wg.Done()
+			for range observe {
+			}
+		}()
+	}
+
+	var batch []interface{}
+	if config.BatchSize > 0 {
+		batch = make([]interface{}, config.BatchSize)
+		for i := range batch {
+			batch[i] = i
+		}
+	}
+	item := Of(0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; {
+		if batch == nil {
+			subject.NextItem(item)
+			i++
+			continue
+		}
+		if n := b.N - i; n < len(batch) {
+			batch = batch[:n]
+		}
+		subject.NextBatch(batch)
+		i += len(batch)
+	}
+	elapsed := time.Since(start)
+	b.StopTimer()
+
+	stats := subject.Stats()
+	subject.Complete()
+	wg.Wait()
+
+	b.ReportMetric(float64(b.N)/elapsed.Seconds(), "items/s")
+	b.ReportMetric(float64(stats.Dropped)/float64(b.N), "dropped/op")
+}
+
+// RunSubjectBenchmarks runs BenchmarkSubject for each configuration as a sub-benchmark, so that configurations
+// can be compared on the same hardware:
+//
+//	func BenchmarkBus(b *testing.B) {
+//		rxgo.RunSubjectBenchmarks(b,
+//			rxgo.SubjectBenchmark{Name: "block", Subscribers: 100},
+//			rxgo.SubjectBenchmark{Name: "drop", Subscribers: 100,
+//				Options: []rxgo.Option{rxgo.WithBackPressureStrategy(rxgo.Drop)}},
+//		)
+//	}
+func RunSubjectBenchmarks(b *testing.B, configs ...SubjectBenchmark) {
+	for i, config := range configs {
+		name := config.Name
+		if name == "" {
+			name = fmt.Sprintf("config-%d", i)
+		}
+		b.Run(name, func(b *testing.B) {
+			BenchmarkSubject(b, config)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package rxgo
+
+// Child creates a subject receiving the values of the subject satisfying filter, transformed by transform, so
+// that event-bus trees are built without forwarding the items manually. A nil filter accepts every value and
+// a nil transform forwards the values unchanged. An error returned by transform and the inline errors of the
+// subject are delivered inline. The child terminates once the subject terminates, with the same error if any,
+// while terminating the child stops the forwarding. The options configure the child.
+//
+// The child is a subscriber of the subject: with the Block back pressure strategy, a slow subscriber of the
+// child slows the subject down.
+func (s *Subject) Child(filter Predicate, transform Func, opts ...Option) *Subject {
+	return s.child(s.createFilteredSubscription, filter, transform, opts)
+}
+
+// child creates a child subject fed by the subscription created with subscribe, the lock being held
+func (s *Subject) child(subscribe func(bufferSize int, predicate func(interface{}) bool) (Subscription, Observable), filter Predicate, transform Func, opts []Option) *Subject {
+	child := NewSubject(opts...)
+
+	s.Lock()
+	if s.terminated {
+		err := s.err
+		s.Unlock()
+		child.terminateWith(err)
+		return child
+	}
+	sub, obs := subscribe(0, filter)
+	s.Unlock()
+
+	go s.forward(child, sub, obs.Observe(), transform)
+	return child
+}
+
+// forward forwards the items of a subscription to a child subject until either terminates
+func (s *Subject) forward(child *Subject, sub Subscription, observe <-chan Item, transform Func) {
+	ctx := child.option.buildContext(emptyContext)
+	// with StopOnError, an error item is held until the next notification, as it may be the error terminating
+	// the subject
+	hold := s.option.getErrorStrategy() == StopOnError
+	var pending *Item
+	flush := func() {
+		if pending != nil {
+			child.NextItem(*pending)
+			pending = nil
+		}
+	}
+	for {
+		select {
+		case <-child.Done():
+			sub.Unsubscribe()
+			return
+		case item, ok := <-observe:
+			if !ok {
+				err := s.Err()
+				if err == nil || pending == nil || pending.E != err {
+					flush()
+					err = nil
+				}
+				child.terminateWith(err)
+				return
+			}
+			flush()
+			if item.Error() {
+				if hold {
+					pending = &item
+				} else {
+					child.NextItem(item)
+				}
+				continue
+			}
+			if transform == nil {
+				child.NextItem(item)
+				continue
+			}
+			v, err := transform(ctx, item.V)
+			if err != nil {
+				child.NextItem(Error(err))
+				continue
+			}
+			child.Next(v)
+		}
+	}
+}
+
+// terminateWith terminates a subject with an error, or completes it
+func (s *Subject) terminateWith(err error) {
+	if err != nil {
+		s.Error(err)
+	}
+	select {
+	case <-s.Done():
+	default:
+		// not terminated by the error with ContinueOnError
+		s.Complete()
+	}
+}
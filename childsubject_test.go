@@ -0,0 +1,112 @@
+package rxgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestSubject_Child(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	parent := NewSubject()
+	child := parent.Child(func(i interface{}) bool {
+		return i.(int)%2 == 0
+	}, func(_ context.Context, i interface{}) (interface{}, error) {
+		return i.(int) * 10, nil
+	})
+	_, obs := child.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	for i := 1; i <= 4; i++ {
+		parent.Next(i)
+	}
+	parent.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(20, 40)
+	observer.AssertNoErrors()
+	<-child.Done()
+	assert.NoError(t, child.Err())
+}
+
+func TestSubject_Child_Tree(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	root := NewSubject()
+	orders := root.Child(func(i interface{}) bool {
+		return i.(string)[0] == 'o'
+	}, nil)
+	paid := orders.Child(func(i interface{}) bool {
+		return i == "order.paid"
+	}, nil)
+	_, obs := paid.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	root.Next("user.created")
+	root.Next("order.created")
+	root.Next("order.paid")
+	root.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("order.paid")
+}
+
+func TestSubject_Child_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	parent := NewSubject()
+	child := parent.Child(nil, func(_ context.Context, i interface{}) (interface{}, error) {
+		if i == 2 {
+			return nil, errBar
+		}
+		return i, nil
+	}, WithErrorStrategy(ContinueOnError))
+	_, obs := child.Subscribe()
+	observer := NewTestObserver(t, obs, WithErrorStrategy(ContinueOnError))
+
+	parent.Next(1)
+	parent.NextItem(Error(errBar))
+	parent.Next(2)
+	parent.Next(3)
+	parent.Error(errFoo)
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(1, 3)
+	assert.Equal(t, []error{errBar, errBar, errFoo}, observer.Errors())
+}
+
+func TestSubject_Child_StopOnError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	parent := NewSubject()
+	child := parent.Child(nil, nil)
+	parent.Next(1)
+	parent.Error(errFoo)
+
+	<-child.Done()
+	assert.Equal(t, errFoo, child.Err())
+}
+
+func TestSubject_Child_Terminated(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	parent := NewSubject()
+	parent.Error(errFoo)
+	child := parent.Child(nil, nil)
+	<-child.Done()
+	assert.Equal(t, errFoo, child.Err())
+}
+
+// TestSubject_Child_Complete verifies completing the child unsubscribes it from its parent
+func TestSubject_Child_Complete(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	parent := NewSubject()
+	child := parent.Child(nil, nil)
+	child.Complete()
+
+	assert.Eventually(t, func() bool {
+		parent.RLock()
+		defer parent.RUnlock()
+		return len(parent.subscribers) == 0
+	}, time.Second, time.Millisecond)
+	parent.Complete()
+}
@@ -0,0 +1,183 @@
+package rxgo
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is the error emitted by CircuitBreaker while the circuit is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets the observations through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects the observations.
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe through, closing the circuit if it succeeds.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerPolicy configures a CircuitBreaker.
+type CircuitBreakerPolicy struct {
+	// Window is the number of latest outcomes the failure rate is computed on, 10 by default.
+	Window int
+	// MinCalls is the number of outcomes in the window below which the circuit does not open, Window by default.
+	MinCalls int
+	// FailureRate is the rate of failed outcomes in the window opening the circuit, 0.5 by default.
+	FailureRate float64
+	// OpenTimeout is the time the circuit stays open before letting a probe through, 10 seconds by default.
+	OpenTimeout time.Duration
+	// IsFailure reports whether an error is a failure of the guarded service. By default, every error is.
+	IsFailure func(error) bool
+	// Drop makes an open circuit complete the observations instead of emitting ErrCircuitOpen.
+	Drop bool
+}
+
+// CircuitBreaker monitors the outcome of the Observables guarded by the CircuitBreaker operator, typically the
+// calls to a remote service made within FlatMap, and opens the circuit once too many of them failed, so that the
+// following ones fail fast without being observed. It is safe for concurrent use and meant to be shared by the
+// Observables calling the same service.
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	policy   CircuitBreakerPolicy
+	clock    Clock
+	state    CircuitState
+	outcomes []bool
+	next     int
+	count    int
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a closed CircuitBreaker. The WithClock option sets the clock measuring the open
+// timeout.
+func NewCircuitBreaker(policy CircuitBreakerPolicy, opts ...Option) *CircuitBreaker {
+	if policy.Window <= 0 {
+		policy.Window = 10
+	}
+	if policy.MinCalls <= 0 || policy.MinCalls > policy.Window {
+		policy.MinCalls = policy.Window
+	}
+	if policy.FailureRate <= 0 {
+		policy.FailureRate = 0.5
+	}
+	if policy.OpenTimeout <= 0 {
+		policy.OpenTimeout = 10 * time.Second
+	}
+	if policy.IsFailure == nil {
+		policy.IsFailure = func(error) bool {
+			return true
+		}
+	}
+
+	return &CircuitBreaker{
+		policy:   policy,
+		clock:    parseOptions(opts...).getClock(),
+		outcomes: make([]bool, policy.Window),
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.clock.Now().Sub(b.openedAt) >= b.policy.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow returns whether an observation may proceed, and whether it is the probe let through once the open
+// timeout elapsed
+func (b *CircuitBreaker) allow() (allowed, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitClosed:
+		return true, false
+	case CircuitOpen:
+		if b.clock.Now().Sub(b.openedAt) < b.policy.OpenTimeout {
+			return false, false
+		}
+		b.state = CircuitHalfOpen
+	}
+	if b.probing {
+		return false, false
+	}
+	b.probing = true
+	return true, true
+}
+
+// record records the outcome of an allowed observation
+func (b *CircuitBreaker) record(failed, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+		if failed {
+			b.open()
+		} else {
+			b.state = CircuitClosed
+		}
+		return
+	}
+	if b.state != CircuitClosed {
+		// the circuit opened while the observation was in progress
+		return
+	}
+
+	if b.count == len(b.outcomes) {
+		if b.outcomes[b.next] {
+			b.failures--
+		}
+	} else {
+		b.count++
+	}
+	b.outcomes[b.next] = failed
+	b.next = (b.next + 1) % len(b.outcomes)
+	if failed {
+		b.failures++
+	}
+	if b.count >= b.policy.MinCalls && float64(b.failures)/float64(b.count) >= b.policy.FailureRate {
+		b.open()
+	}
+}
+
+// release releases an allowed observation stopped without outcome, letting another probe through
+func (b *CircuitBreaker) release(probe bool) {
+	if !probe {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// open opens the circuit and clears the outcomes, the lock being held
+func (b *CircuitBreaker) open() {
+	b.state = CircuitOpen
+	b.openedAt = b.clock.Now()
+	b.next, b.count, b.failures = 0, 0, 0
+}
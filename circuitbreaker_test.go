@@ -0,0 +1,97 @@
+package rxgo
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// callService returns an Observable emitting 1, or err if not nil, and counting its observations
+func callService(calls *int32, err error) Observable {
+	return Defer([]Producer{func(ctx context.Context, next chan<- Item) {
+		atomic.AddInt32(calls, 1)
+		if err != nil {
+			Error(err).SendContext(ctx, next)
+			return
+		}
+		Of(1).SendContext(ctx, next)
+	}})
+}
+
+func Test_CircuitBreaker(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx := context.Background()
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	breaker := NewCircuitBreaker(CircuitBreakerPolicy{Window: 4, MinCalls: 2, OpenTimeout: time.Second},
+		WithClock(scheduler))
+	var calls int32
+
+	Assert(ctx, t, callService(&calls, nil).CircuitBreaker(breaker), HasItems(1))
+	Assert(ctx, t, callService(&calls, errFoo).CircuitBreaker(breaker), HasError(errFoo))
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	// the service is not called while the circuit is open
+	Assert(ctx, t, callService(&calls, nil).CircuitBreaker(breaker), IsEmpty(), HasError(ErrCircuitOpen))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	scheduler.Advance(time.Second)
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+	Assert(ctx, t, callService(&calls, nil).CircuitBreaker(breaker), HasItems(1))
+	assert.Equal(t, CircuitClosed, breaker.State())
+}
+
+func Test_CircuitBreaker_ProbeFailure(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx := context.Background()
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	breaker := NewCircuitBreaker(CircuitBreakerPolicy{Window: 1, OpenTimeout: time.Second}, WithClock(scheduler))
+	var calls int32
+
+	Assert(ctx, t, callService(&calls, errFoo).CircuitBreaker(breaker), HasError(errFoo))
+	scheduler.Advance(time.Second)
+	Assert(ctx, t, callService(&calls, errBar).CircuitBreaker(breaker), HasError(errBar))
+	assert.Equal(t, CircuitOpen, breaker.State())
+	Assert(ctx, t, callService(&calls, nil).CircuitBreaker(breaker), HasError(ErrCircuitOpen))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func Test_CircuitBreaker_Drop(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx := context.Background()
+	breaker := NewCircuitBreaker(CircuitBreakerPolicy{Window: 1, Drop: true})
+	var calls int32
+
+	Assert(ctx, t, callService(&calls, errFoo).CircuitBreaker(breaker), HasError(errFoo))
+	Assert(ctx, t, callService(&calls, nil).CircuitBreaker(breaker), IsEmpty(), HasNoError())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func Test_CircuitBreaker_IsFailure(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx := context.Background()
+	breaker := NewCircuitBreaker(CircuitBreakerPolicy{Window: 1, IsFailure: func(err error) bool {
+		return err != errBar
+	}})
+	var calls int32
+
+	Assert(ctx, t, callService(&calls, errBar).CircuitBreaker(breaker), HasError(errBar))
+	assert.Equal(t, CircuitClosed, breaker.State())
+	Assert(ctx, t, callService(&calls, errFoo).CircuitBreaker(breaker), HasError(errFoo))
+	assert.Equal(t, CircuitOpen, breaker.State())
+}
+
+func Test_CircuitBreaker_FlatMap(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	breaker := NewCircuitBreaker(CircuitBreakerPolicy{Window: 2})
+	var calls int32
+
+	obs := Just(1, 2, 3, 4)().FlatMap(func(Item) Observable {
+		return callService(&calls, errFoo).CircuitBreaker(breaker)
+	}, WithErrorStrategy(ContinueOnError))
+	Assert(context.Background(), t, obs, HasErrors(errFoo, errFoo, ErrCircuitOpen, ErrCircuitOpen))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,91 @@
+package rxgo
+
+import "time"
+
+// Clock provides the time to the time-based operators. The default clock relies on the time package,
+// a TestScheduler allows tests to advance the time deterministically.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) ClockTimer
+	NewTicker(d time.Duration) ClockTicker
+}
+
+// ClockTimer is a single event created by a Clock, the counterpart of time.Timer.
+type ClockTimer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// ClockTicker is a periodic event created by a Clock, the counterpart of time.Ticker.
+type ClockTicker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// SystemClock returns the clock relying on the time package, the default clock.
+func SystemClock() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) NewTimer(d time.Duration) ClockTimer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) ClockTicker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
+
+// backOffTimer is the timer of the back-off retries, created by a Clock
+type backOffTimer struct {
+	clock Clock
+	timer ClockTimer
+}
+
+func (t *backOffTimer) Start(d time.Duration) {
+	t.timer = t.clock.NewTimer(d)
+}
+
+func (t *backOffTimer) Stop() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+func (t *backOffTimer) C() <-chan time.Time {
+	return t.timer.C()
+}
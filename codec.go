@@ -0,0 +1,141 @@
+package rxgo
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// NotificationKind is the kind of a notification encoded by a Codec.
+type NotificationKind byte
+
+const (
+	// KindNext is an item holding a value.
+	KindNext NotificationKind = iota
+	// KindErrorItem is an item holding an error, delivered inline without terminating.
+	KindErrorItem
+	// KindError is the termination with an error.
+	KindError
+	// KindComplete is the completion.
+	KindComplete
+)
+
+// Codec encodes the notifications leaving the process, such as the items persisted by a DiskReplaySubject or
+// sent by a RemoteSubject.
+type Codec interface {
+	// Marshal encodes a notification, the item being empty for KindComplete.
+	Marshal(kind NotificationKind, item Item) ([]byte, error)
+	// Unmarshal decodes a notification.
+	Unmarshal(data []byte) (NotificationKind, Item, error)
+}
+
+// NewCodec creates a codec encoding the values with marshal and unmarshal, such as the functions of a protobuf
+// library. A notification is encoded as its kind followed by the encoded value, or by the message of the error.
+func NewCodec(marshal func(interface{}) ([]byte, error), unmarshal func([]byte) (interface{}, error)) Codec {
+	return valueCodec{marshal: marshal, unmarshal: unmarshal}
+}
+
+type valueCodec struct {
+	marshal   func(interface{}) ([]byte, error)
+	unmarshal func([]byte) (interface{}, error)
+}
+
+func (c valueCodec) Marshal(kind NotificationKind, item Item) ([]byte, error) {
+	var payload []byte
+	switch kind {
+	case KindNext:
+		data, err := c.marshal(item.V)
+		if err != nil {
+			return nil, err
+		}
+		payload = data
+	case KindErrorItem, KindError:
+		payload = []byte(item.E.Error())
+	}
+	return append([]byte{byte(kind)}, payload...), nil
+}
+
+func (c valueCodec) Unmarshal(data []byte) (NotificationKind, Item, error) {
+	if len(data) == 0 {
+		return 0, Item{}, errors.New("empty notification")
+	}
+	kind, payload := NotificationKind(data[0]), data[1:]
+	switch kind {
+	case KindNext:
+		v, err := c.unmarshal(payload)
+		if err != nil {
+			return kind, Item{}, err
+		}
+		return kind, Of(v), nil
+	case KindErrorItem, KindError:
+		return kind, Error(errors.New(string(payload))), nil
+	case KindComplete:
+		return kind, Item{}, nil
+	default:
+		return kind, Item{}, errors.New("unknown notification kind")
+	}
+}
+
+// NewJSONCodec creates a codec encoding the values in JSON. The values are decoded into interface{} values, or
+// into the values created with target, such as pointers to a struct type.
+func NewJSONCodec(target func() interface{}) Codec {
+	return NewCodec(json.Marshal, func(data []byte) (interface{}, error) {
+		if target != nil {
+			v := target()
+			err := json.Unmarshal(data, v)
+			return v, err
+		}
+		var v interface{}
+		err := json.Unmarshal(data, &v)
+		return v, err
+	})
+}
+
+// NewGobCodec creates a codec encoding the values with encoding/gob, which keeps their type. The types of the
+// values must be registered with gob.Register.
+func NewGobCodec() Codec {
+	return NewCodec(func(v interface{}) ([]byte, error) {
+		var buf bytes.Buffer
+		// encoded as an interface value, so that the type is sent along
+		if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}, func(data []byte) (interface{}, error) {
+		var v interface{}
+		err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v)
+		return v, err
+	})
+}
+
+// codecs are the registered codecs
+var codecs = struct {
+	sync.RWMutex
+	byName map[string]Codec
+}{
+	byName: map[string]Codec{
+		"json": NewJSONCodec(nil),
+		"gob":  NewGobCodec(),
+	},
+}
+
+// RegisterCodec registers a codec under a name, replacing the codec registered under the same name, so that
+// it can be looked up by configurations. The "json" and "gob" codecs are registered by default; a protobuf
+// codec can be registered with NewCodec.
+func RegisterCodec(name string, codec Codec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+
+	codecs.byName[name] = codec
+}
+
+// LookupCodec returns the codec registered under a name.
+func LookupCodec(name string) (Codec, bool) {
+	codecs.RLock()
+	defer codecs.RUnlock()
+
+	codec, exists := codecs.byName[name]
+	return codec, exists
+}
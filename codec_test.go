@@ -0,0 +1,90 @@
+package rxgo
+
+import (
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	gob.Register(remoteEvent{})
+}
+
+func Test_JSONCodec(t *testing.T) {
+	codec := NewJSONCodec(nil)
+	data, err := codec.Marshal(KindNext, Of(map[string]int{"a": 1}))
+	assert.NoError(t, err)
+	kind, item, err := codec.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, KindNext, kind)
+	assert.Equal(t, map[string]interface{}{"a": 1.0}, item.V)
+
+	_, _, err = codec.Unmarshal(append([]byte{byte(KindNext)}, '{'))
+	assert.Error(t, err)
+	_, _, err = codec.Unmarshal(nil)
+	assert.Error(t, err)
+}
+
+func Test_JSONCodec_Target(t *testing.T) {
+	codec := NewJSONCodec(func() interface{} {
+		return &remoteEvent{}
+	})
+	data, err := codec.Marshal(KindNext, Of(remoteEvent{Name: "a"}))
+	assert.NoError(t, err)
+	_, item, err := codec.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, &remoteEvent{Name: "a"}, item.V)
+}
+
+func Test_GobCodec(t *testing.T) {
+	codec := NewGobCodec()
+	data, err := codec.Marshal(KindNext, Of(remoteEvent{Name: "a"}))
+	assert.NoError(t, err)
+	_, item, err := codec.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, remoteEvent{Name: "a"}, item.V)
+
+	_, err = codec.Marshal(KindNext, Of(struct{ unregistered int }{}))
+	assert.Error(t, err)
+}
+
+func Test_Codec_Notifications(t *testing.T) {
+	codec := NewGobCodec()
+	for _, kind := range []NotificationKind{KindErrorItem, KindError} {
+		data, err := codec.Marshal(kind, Error(errFoo))
+		assert.NoError(t, err)
+		decoded, item, err := codec.Unmarshal(data)
+		assert.NoError(t, err)
+		assert.Equal(t, kind, decoded)
+		assert.EqualError(t, item.E, errFoo.Error())
+	}
+
+	data, err := codec.Marshal(KindComplete, Item{})
+	assert.NoError(t, err)
+	kind, item, err := codec.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, KindComplete, kind)
+	assert.Equal(t, Item{}, item)
+}
+
+func Test_RegisterCodec(t *testing.T) {
+	_, exists := LookupCodec("json")
+	assert.True(t, exists)
+	_, exists = LookupCodec("gob")
+	assert.True(t, exists)
+	_, exists = LookupCodec("unknown")
+	assert.False(t, exists)
+
+	codec := NewCodec(func(v interface{}) ([]byte, error) {
+		return []byte(v.(string)), nil
+	}, func(data []byte) (interface{}, error) {
+		return string(data), nil
+	})
+	RegisterCodec("test", codec)
+	registered, exists := LookupCodec("test")
+	assert.True(t, exists)
+	data, err := registered.Marshal(KindNext, Of("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{byte(KindNext), 'a'}, data)
+}
@@ -0,0 +1,140 @@
+package rxgo
+
+import (
+	"context"
+	"sync"
+)
+
+// conflatingQueue is the queue of a subscription with the Conflate strategy. An item whose key is already
+// queued replaces the queued item, or is merged into it, keeping its position, so that a slow subscriber
+// receives the latest value of each key once it catches up.
+type conflatingQueue struct {
+	mu      sync.Mutex
+	entries []*conflatedEntry
+	// keys holds the queued entry of each key
+	keys   map[interface{}]*conflatedEntry
+	closed bool
+	// ready is signaled once an item was queued or the queue closed
+	ready chan struct{}
+	keyOf func(interface{}) interface{}
+	merge func(queued, next interface{}) interface{}
+}
+
+type conflatedEntry struct {
+	key  interface{}
+	item Item
+}
+
+func newConflatingQueue(keyOf func(interface{}) interface{}, merge func(queued, next interface{}) interface{}) *conflatingQueue {
+	return &conflatingQueue{
+		keys:  make(map[interface{}]*conflatedEntry),
+		ready: make(chan struct{}, 1),
+		keyOf: keyOf,
+		merge: merge,
+	}
+}
+
+// push queues an item, conflated with the queued item with the same key if any. The inline errors are never
+// conflated. No item is dropped: the replaced items are acknowledged.
+func (q *conflatingQueue) push(item Item) []Item {
+	q.mu.Lock()
+	var replaced Item
+	if !q.closed {
+		replaced = q.conflate(item)
+	}
+	q.mu.Unlock()
+
+	replaced.Ack()
+	q.signal()
+	return nil
+}
+
+// conflate queues an item, returning the queued item it replaced if any. The caller holds mu.
+func (q *conflatingQueue) conflate(item Item) Item {
+	value := unwrapped(item)
+	if value.Error() {
+		q.entries = append(q.entries, &conflatedEntry{item: item})
+		return Item{}
+	}
+	var key interface{}
+	if q.keyOf != nil {
+		key = q.keyOf(value.V)
+	}
+	entry, exists := q.keys[key]
+	if !exists {
+		entry = &conflatedEntry{key: key, item: item}
+		q.keys[key] = entry
+		q.entries = append(q.entries, entry)
+		return Item{}
+	}
+	replaced := entry.item
+	if q.merge != nil {
+		item = withValue(item, q.merge(unwrapped(replaced).V, value.V))
+	}
+	entry.item = item
+	return replaced
+}
+
+// withValue returns an item holding the given value, still held until its time to live elapsed if any
+func withValue(item Item, v interface{}) Item {
+	if e, ok := item.V.(*expiringItem); ok {
+		expiring := *e
+		expiring.item.V = v
+		item.V = &expiring
+		return item
+	}
+	item.V = v
+	return item
+}
+
+// pop returns the next item, waiting until an item is queued. It returns false once the queue is closed and
+// drained, or the context is done.
+func (q *conflatingQueue) pop(ctx context.Context) (Item, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.entries) > 0 {
+			entry := q.entries[0]
+			q.entries[0] = nil
+			q.entries = q.entries[1:]
+			if q.keys[entry.key] == entry {
+				delete(q.keys, entry.key)
+			}
+			q.mu.Unlock()
+			return entry.item, true
+		}
+		closed := q.closed
+		q.mu.Unlock()
+		if closed {
+			return Item{}, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return Item{}, false
+		case <-q.ready:
+		}
+	}
+}
+
+func (q *conflatingQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// len returns the number of queued items
+func (q *conflatingQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.entries)
+}
+
+// close closes the queue, the queued items being still sent
+func (q *conflatingQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.signal()
+}
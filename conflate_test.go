@@ -0,0 +1,84 @@
+package rxgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+type quote struct {
+	symbol string
+	price  int
+}
+
+func TestConflatingQueue(t *testing.T) {
+	symbol := func(i interface{}) interface{} {
+		return i.(quote).symbol
+	}
+	queue := newConflatingQueue(symbol, nil)
+	acked := 0
+	ack := func() {
+		acked++
+	}
+
+	queue.push(Of(quote{"a", 1}).WithAck(ack))
+	queue.push(Of(quote{"b", 1}))
+	queue.push(Error(errFoo))
+	queue.push(Of(quote{"a", 2}))
+	queue.push(Error(errFoo))
+	assert.Equal(t, 4, queue.len())
+	// the replaced item is acknowledged
+	assert.Equal(t, 1, acked)
+	queue.close()
+
+	var items []Item
+	for item, ok := queue.pop(context.Background()); ok; item, ok = queue.pop(context.Background()) {
+		items = append(items, item)
+	}
+	// the latest value of a key keeps the position of the first one, the errors not being conflated
+	assert.Equal(t, []Item{Of(quote{"a", 2}), Of(quote{"b", 1}), Error(errFoo), Error(errFoo)}, items)
+	assert.Empty(t, queue.keys)
+}
+
+func TestConflatingQueue_Merge(t *testing.T) {
+	queue := newConflatingQueue(nil, func(queued, next interface{}) interface{} {
+		return queued.(int) + next.(int)
+	})
+
+	queue.push(Of(1))
+	queue.push(Of(2))
+	item, _ := queue.pop(context.Background())
+	assert.Equal(t, 3, item.V)
+	queue.push(Of(4))
+	item, _ = queue.pop(context.Background())
+	assert.Equal(t, 4, item.V)
+}
+
+func TestSubject_Conflate(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject(WithBackPressureStrategy(Conflate), WithConflation(func(i interface{}) interface{} {
+		return i.(quote).symbol
+	}, nil))
+	_, obs := subject.Subscribe()
+	// the observer does not read until all the items are published, which never blocks
+	ch := obs.Observe()
+	for price := 1; price <= 100; price++ {
+		subject.Next(quote{"a", price})
+		subject.Next(quote{"b", price})
+	}
+	subject.Complete()
+
+	latest := make(map[string]int)
+	for item := range ch {
+		q := item.V.(quote)
+		if previous, exists := latest[q.symbol]; exists {
+			// the quotes of a symbol are received in order
+			assert.Less(t, previous, q.price)
+		}
+		latest[q.symbol] = q.price
+	}
+	assert.Equal(t, map[string]int{"a": 100, "b": 100}, latest)
+	assert.Zero(t, subject.Stats().Dropped)
+}
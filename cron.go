@@ -0,0 +1,226 @@
+package rxgo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule gives the activation times of Cron. It is implemented by the schedules of github.com/robfig/cron.
+type Schedule interface {
+	// Next returns the first activation time strictly after t, or the zero time if there is none.
+	Next(t time.Time) time.Time
+}
+
+// CronParser parses a cron expression into a Schedule.
+type CronParser func(spec string) (Schedule, error)
+
+// Cron creates an Observable emitting the activation times of a cron expression, parsed by the parser set
+// with WithCronParser, ParseCron by default. Each observer starts its own schedule until the context is done.
+// The activations missed by a slow observer are skipped.
+func Cron(ctx context.Context, spec string, opts ...Option) Observable {
+	schedule, err := parseOptions(opts...).getCronParser()(spec)
+	if err != nil {
+		return Thrown(IllegalInputError{error: err.Error()})
+	}
+
+	return &ObservableImpl{
+		iterable: newFactoryIterable(func(propagatedOptions ...Option) <-chan Item {
+			option := parseOptions(append(opts, propagatedOptions...)...)
+			next := option.buildChannel()
+			ctx := option.buildContext(ctx)
+			clock := option.getClock()
+
+			go func() {
+				defer close(next)
+				last := clock.Now()
+				for {
+					at := schedule.Next(last)
+					if at.IsZero() {
+						return
+					}
+					timer := clock.NewTimer(at.Sub(clock.Now()))
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return
+					case <-timer.C():
+					}
+					if !Of(at).SendContext(ctx, next) {
+						return
+					}
+					if last = clock.Now(); last.Before(at) {
+						last = at
+					}
+				}
+			}()
+			return next
+		}),
+	}
+}
+
+// cronSchedule is a parsed cron expression, each field holding the bits of its allowed values
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domOrDow makes a time match if either its day of month or its day of week does, both being restricted
+	domOrDow bool
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{min: 0, max: 59}
+	cronHour   = cronField{min: 0, max: 23}
+	cronDom    = cronField{min: 1, max: 31}
+	cronMonth  = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is also accepted for Sunday
+	cronDow = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a standard cron expression of five fields: minute, hour, day of month, month and day of
+// week. A field is a comma-separated list of values, ranges (1-5) and steps (*/15, 0-30/10); months and days of
+// week may be named (jan, mon). The descriptors @yearly, @monthly, @weekly, @daily and @hourly are accepted.
+// The activation times are computed in the location of the time passed to Next.
+func ParseCron(spec string) (Schedule, error) {
+	if expr, ok := cronDescriptors[strings.ToLower(strings.TrimSpace(spec))]; ok {
+		spec = expr
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	var s cronSchedule
+	var err error
+	for i, target := range []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&s.minute, cronMinute},
+		{&s.hour, cronHour},
+		{&s.dom, cronDom},
+		{&s.month, cronMonth},
+		{&s.dow, cronDow},
+	} {
+		if *target.bits, err = target.field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("cron expression %q: %v", spec, err)
+		}
+	}
+	// Sunday is either 0 or 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domOrDow = fields[2] != "*" && fields[4] != "*"
+	return &s, nil
+}
+
+// parse returns the bits of the values of a field
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		expr, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			expr = part[:i]
+		}
+
+		var from, to int
+		switch i := strings.IndexByte(expr, '-'); {
+		case expr == "*":
+			from, to = f.min, f.max
+		case i >= 0:
+			var err error
+			if from, err = f.value(expr[:i]); err != nil {
+				return 0, err
+			}
+			if to, err = f.value(expr[i+1:]); err != nil {
+				return 0, err
+			}
+			if from > to {
+				return 0, fmt.Errorf("invalid range %q", expr)
+			}
+		default:
+			v, err := f.value(expr)
+			if err != nil {
+				return 0, err
+			}
+			from, to = v, v
+			if step > 1 {
+				// 5/15 is a shorthand for 5-max/15
+				to = f.max
+			}
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value returns the value of a number or a name within the bounds of the field
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q, expected %d-%d", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first activation time strictly after t, looking up to five years ahead
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	limit := t.Year() + 5
+
+	for t.Year() <= limit {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domOrDow {
+		return dom || dow
+	}
+	return dom && dow
+}
@@ -0,0 +1,115 @@
+package rxgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestParseCron(t *testing.T) {
+	at := func(s string) time.Time {
+		v, err := time.Parse("2006-01-02 15:04", s)
+		assert.NoError(t, err)
+		return v
+	}
+	for _, tc := range []struct {
+		spec     string
+		from     string
+		expected []string
+	}{
+		{"* * * * *", "2024-01-01 10:00", []string{"2024-01-01 10:01", "2024-01-01 10:02"}},
+		{"*/15 * * * *", "2024-01-01 10:07", []string{"2024-01-01 10:15", "2024-01-01 10:30"}},
+		{"5/20 9-10 * * *", "2024-01-01 10:50", []string{"2024-01-02 09:05", "2024-01-02 09:25"}},
+		{"0 0 * * *", "2024-02-28 12:00", []string{"2024-02-29 00:00", "2024-03-01 00:00"}},
+		{"@hourly", "2024-12-31 23:30", []string{"2025-01-01 00:00", "2025-01-01 01:00"}},
+		{"30 2 * * mon-fri", "2024-01-05 03:00", []string{"2024-01-08 02:30", "2024-01-09 02:30"}},
+		{"0 0 * * 7", "2024-01-01 00:00", []string{"2024-01-07 00:00", "2024-01-14 00:00"}},
+		// restricted day of month and day of week: either one matches
+		{"0 0 13 * fri", "2024-09-01 00:00", []string{"2024-09-06 00:00", "2024-09-13 00:00", "2024-09-20 00:00"}},
+		{"0 0 29 feb *", "2024-03-01 00:00", []string{"2028-02-29 00:00"}},
+		{"0 0 1,15 jan,jul *", "2024-01-10 00:00", []string{"2024-01-15 00:00", "2024-07-01 00:00"}},
+	} {
+		schedule, err := ParseCron(tc.spec)
+		assert.NoError(t, err, tc.spec)
+		next := at(tc.from)
+		for _, expected := range tc.expected {
+			next = schedule.Next(next)
+			assert.Equal(t, at(expected), next, tc.spec)
+		}
+	}
+
+	schedule, err := ParseCron("0 0 30 feb *")
+	assert.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		_, err := ParseCron(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestCron(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	start := time.Date(2024, 1, 1, 0, 0, 30, 0, time.UTC)
+	scheduler := NewTestScheduler(start)
+	observe := Cron(ctx, "*/15 * * * *", WithClock(scheduler)).Observe()
+
+	for i := 1; i <= 3; i++ {
+		scheduler.BlockUntil(1)
+		scheduler.Advance(15 * time.Minute)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 15*i, 0, 0, time.UTC), (<-observe).V)
+	}
+
+	// the missed activations are skipped
+	scheduler.BlockUntil(1)
+	scheduler.Advance(time.Hour)
+	assert.Equal(t, time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC), (<-observe).V)
+	scheduler.BlockUntil(1)
+	scheduler.Advance(15 * time.Minute)
+	assert.Equal(t, time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC), (<-observe).V)
+
+	cancel()
+	for range observe {
+	}
+}
+
+type fixedSchedule []time.Time
+
+func (s fixedSchedule) Next(t time.Time) time.Time {
+	for _, at := range s {
+		if at.After(t) {
+			return at
+		}
+	}
+	return time.Time{}
+}
+
+func TestCron_Parser(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	scheduler := NewTestScheduler(start)
+	parser := WithCronParser(func(spec string) (Schedule, error) {
+		return fixedSchedule{start.Add(time.Second), start.Add(2 * time.Second)}, nil
+	})
+	observe := Cron(context.Background(), "every second, twice", parser, WithClock(scheduler)).Observe()
+
+	scheduler.BlockUntil(1)
+	scheduler.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Second), (<-observe).V)
+	scheduler.BlockUntil(1)
+	scheduler.Advance(time.Second)
+	assert.Equal(t, start.Add(2*time.Second), (<-observe).V)
+	// the schedule has no more activations
+	_, ok := <-observe
+	assert.False(t, ok)
+}
+
+func TestCron_InvalidSpec(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	Assert(context.Background(), t, Cron(context.Background(), "every day"), HasAnError())
+}
@@ -0,0 +1,128 @@
+package rxgo
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrDropped is the reason of a dead letter dropped because of the Drop back pressure strategy.
+	ErrDropped = errors.New("item dropped")
+	// ErrExpired is the reason of a dead letter queued longer than its time to live.
+	ErrExpired = errors.New("item expired")
+	// ErrMaxDeliveries is the reason of a dead letter delivered the maximum number of times to a durable
+	// subscription without being acknowledged.
+	ErrMaxDeliveries = errors.New("item delivered too many times")
+	// ErrMaxPending is the reason of a dead letter removed from a durable subscription holding the maximum
+	// number of pending items.
+	ErrMaxPending = errors.New("too many pending items")
+)
+
+// PanicError is the reason of a dead letter whose callback panicked.
+type PanicError struct {
+	Value interface{}
+	// Stage is the stage of the pipeline the callback observed, as named with Named, if known.
+	Stage string
+}
+
+func (e PanicError) Error() string {
+	if e.Stage != "" {
+		return fmt.Sprintf("callback panicked in stage %s: %v", e.Stage, e.Value)
+	}
+	return fmt.Sprintf("callback panicked: %v", e.Value)
+}
+
+// DeadLetter is an item which was not processed by a subject subscriber.
+type DeadLetter struct {
+	// Item is the item not processed.
+	Item Item
+	// Subscriber is the subscriber ID, NoSubscriber for an item rejected before its delivery.
+	Subscriber int
+	// Reason is the reason why the item was not processed: ErrDropped, ErrExpired, ErrMaxDeliveries,
+	// ErrMaxPending, PanicError, ValidationError.
+	Reason error
+}
+
+// deadLetterSink receives the items not processed by a subscriber. It returns false if the items are not
+// collected.
+type deadLetterSink interface {
+	deadLetter(item Item, reason error) bool
+}
+
+// DeadLetters returns an Observable emitting a DeadLetter for each item dropped for a subscriber because of
+// the back pressure strategy or its time to live, or whose DoOnNext or ForEach callback panicked. Such a callback panic is
+// recovered once DeadLetters was called, and the next items are processed.
+//
+// The dead letters are delivered like the items of a Subject with the Block strategy: their observers must
+// keep up with the failures. The Observable completes with the subject.
+func (s *Subject) DeadLetters() Observable {
+	s.Lock()
+	defer s.Unlock()
+
+	letters := s.loadDeadLetters()
+	if letters == nil {
+		subject := newSubject()
+		letters = &subject
+		s.deadLetters.Store(letters)
+	}
+
+	letters.Lock()
+	defer letters.Unlock()
+	_, obs := letters.createSubscription(0)
+	return obs
+}
+
+// loadDeadLetters returns the subject emitting the dead letters, nil if they are not collected
+func (s *Subject) loadDeadLetters() *Subject {
+	letters, _ := s.deadLetters.Load().(*Subject)
+	return letters
+}
+
+// deadLetter publishes a dead letter, if they are collected
+func (s *Subject) deadLetter(subscriber int, item Item, reason error) bool {
+	letters := s.loadDeadLetters()
+	if letters == nil {
+		return false
+	}
+	letters.Next(DeadLetter{Item: item, Subscriber: subscriber, Reason: reason})
+	return true
+}
+
+func (sub *subscriberState) deadLetter(item Item, reason error) bool {
+	return sub.subject.deadLetter(sub.id, item, reason)
+}
+
+// deadLetterSinkOf returns the dead letter sink of a subject subscription iterable, nil for other iterables
+func deadLetterSinkOf(iterable Iterable) deadLetterSink {
+	if named, ok := iterable.(*stageIterable); ok {
+		iterable = named.Iterable
+	}
+	if source, ok := iterable.(*eventSourceIterable); ok {
+		if sink, ok := source.listener.(deadLetterSink); ok {
+			return sink
+		}
+	}
+	return nil
+}
+
+// guarded returns a function sending the value to the dead letter sink if f panics, f itself without sink
+func (f NextFunc) guarded(sink deadLetterSink, logger Logger, stage string) NextFunc {
+	if sink == nil {
+		return f
+	}
+	return func(i interface{}) {
+		defer func() {
+			if r := recover(); r != nil {
+				if !sink.deadLetter(Of(i), PanicError{Value: r, Stage: stage}) {
+					panic(r)
+				}
+				if stage != "" {
+					logger.Error("rxgo: panic in callback", "panic", r, "stage", stage)
+				} else {
+					logger.Error("rxgo: panic in callback", "panic", r)
+				}
+			}
+		}()
+		f(i)
+	}
+}
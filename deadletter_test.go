@@ -0,0 +1,80 @@
+package rxgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// TestDeadLetters_Dropped verifies the items dropped for a subscriber are dead letters
+func TestDeadLetters_Dropped(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject(WithBackPressureStrategy(Drop))
+	letters := NewTestObserver(t, subject.DeadLetters())
+
+	sub, obs := subject.Subscribe()
+	// observed but never consumed
+	obs.Observe()
+	subject.Next(1)
+	subject.Next(2)
+
+	letters.AwaitCount(2, time.Second)
+	assert.Equal(t, []interface{}{
+		DeadLetter{Item: Of(1), Subscriber: sub.GetId(), Reason: ErrDropped},
+		DeadLetter{Item: Of(2), Subscriber: sub.GetId(), Reason: ErrDropped},
+	}, letters.Values())
+
+	subject.Complete()
+	letters.AwaitDone(time.Second)
+}
+
+// TestDeadLetters_Panic verifies a callback panic is recovered and reported as a dead letter
+func TestDeadLetters_Panic(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	letters := NewTestObserver(t, subject.DeadLetters())
+
+	sub, obs := subject.Subscribe()
+	processed := make([]interface{}, 0)
+	done := obs.DoOnNext(func(i interface{}) {
+		if i == 2 {
+			panic("boom")
+		}
+		processed = append(processed, i)
+	})
+
+	subject.Next(1)
+	subject.Next(2)
+	subject.Next(3)
+	letters.AwaitCount(1, time.Second)
+	subject.Complete()
+
+	<-done
+	assert.Equal(t, []interface{}{1, 3}, processed)
+	letters.AwaitDone(time.Second)
+	letters.AssertValues(DeadLetter{Item: Of(2), Subscriber: sub.GetId(), Reason: PanicError{Value: "boom"}})
+}
+
+// TestDeadLetters_PanicStage verifies the dead letter of a callback panic is attributed to the named stage
+func TestDeadLetters_PanicStage(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	letters := NewTestObserver(t, subject.DeadLetters())
+
+	sub, obs := subject.Subscribe()
+	done := obs.Named("billing").DoOnNext(func(i interface{}) {
+		panic("boom")
+	})
+
+	subject.Next(1)
+	letters.AwaitCount(1, time.Second)
+	subject.Complete()
+
+	<-done
+	letters.AwaitDone(time.Second)
+	reason := PanicError{Value: "boom", Stage: "billing"}
+	letters.AssertValues(DeadLetter{Item: Of(1), Subscriber: sub.GetId(), Reason: reason})
+	assert.Equal(t, "callback panicked in stage billing: boom", reason.Error())
+}
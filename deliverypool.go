@@ -0,0 +1,61 @@
+package rxgo
+
+// deliveryQueueSize is the capacity of each delivery pool queue
+const deliveryQueueSize = 64
+
+// deliveryPool delivers the items of a subject with a fixed number of goroutines. Subscribers are sharded
+// over the goroutines so that each subscriber receives its items in order.
+type deliveryPool struct {
+	queues []chan delivery
+}
+
+type delivery struct {
+	subscriber *subscriberState
+	item       Item
+	close      bool
+}
+
+func newDeliveryPool(workers int) *deliveryPool {
+	p := &deliveryPool{
+		queues: make([]chan delivery, workers),
+	}
+	for i := range p.queues {
+		queue := make(chan delivery, deliveryQueueSize)
+		p.queues[i] = queue
+		go func() {
+			for d := range queue {
+				source := d.subscriber.source
+				if d.close || source.deliver(d.item) {
+					source.close()
+				}
+			}
+		}()
+	}
+	return p
+}
+
+// dispatch queues an item for a subscriber
+func (p *deliveryPool) dispatch(subscriber *subscriberState, item Item) {
+	p.queues[subscriber.id%len(p.queues)] <- delivery{subscriber: subscriber, item: item}
+}
+
+// closeSubscriber queues the closing of a subscriber, after its pending items
+func (p *deliveryPool) closeSubscriber(subscriber *subscriberState) {
+	p.queues[subscriber.id%len(p.queues)] <- delivery{subscriber: subscriber, close: true}
+}
+
+// queued returns the number of pending deliveries
+func (p *deliveryPool) queued() int {
+	n := 0
+	for _, queue := range p.queues {
+		n += len(queue)
+	}
+	return n
+}
+
+// stop stops the goroutines once the pending deliveries are done
+func (p *deliveryPool) stop() {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+}
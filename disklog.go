@@ -0,0 +1,392 @@
+package rxgo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultSegmentSize is the size above which a log segment is rotated
+	defaultSegmentSize = 64 << 20
+	// defaultCatchUpBuffer is the number of live items buffered for an observer replaying the log
+	defaultCatchUpBuffer = 1024
+	segmentSuffix        = ".log"
+	// recordHeaderSize is the size of a record header: payload length, timestamp and kind
+	recordHeaderSize = 4 + 8 + 1
+	// checksumSize is the size of the checksum starting the payload of a checked record
+	checksumSize = 4
+	// maxRecordSize bounds the payload of a record, guarding against a corrupted header
+	maxRecordSize = maxFrameSize
+)
+
+// recordChecked is the kind of the records, holding an item encoded with the codec, preceded by the CRC-32 of
+// the timestamp, the kind and the encoded item
+const recordChecked byte = 1
+
+// DiskReplayConfig configures the log of a DiskReplaySubject.
+type DiskReplayConfig struct {
+	// Dir is the directory holding the log segments. It is created if needed.
+	Dir string
+	// SegmentSize is the size above which a segment is rotated, 64 MiB by default.
+	SegmentSize int64
+	// MaxSize is the total size above which the oldest segments are removed, unlimited if zero.
+	MaxSize int64
+	// MaxAge is the age above which a segment is removed, unlimited if zero. A segment is as old as its
+	// newest item.
+	MaxAge time.Duration
+	// Codec encodes the items, NewCodec(Marshal, Unmarshal) by default.
+	Codec Codec
+	// Marshal encodes a value, json.Marshal by default. It is ignored if Codec is set.
+	Marshal func(interface{}) ([]byte, error)
+	// Unmarshal decodes a value, with encoding/json by default. It is ignored if Codec is set.
+	Unmarshal func([]byte) (interface{}, error)
+	// CatchUpBuffer is the number of items published while an observer replays the log which are buffered for
+	// it, 1024 by default. Once full, the items are held back like for a slow observer: the publishers are
+	// blocked, or the items dropped with the Drop strategy.
+	CatchUpBuffer int
+}
+
+// segment is a file of the log holding the records from its base sequence number
+type segment struct {
+	base     uint64
+	path     string
+	size     int64
+	modified time.Time
+}
+
+// segmentLog is an append-only log of items split in segment files. The active segment, the last one,
+// is rotated once it exceeds the segment size, and the oldest segments are removed according to the
+// retention limits.
+type segmentLog struct {
+	mu       sync.Mutex
+	config   DiskReplayConfig
+	clock    Clock
+	segments []*segment
+	active   *os.File
+	next     uint64
+}
+
+func openSegmentLog(config DiskReplayConfig, clock Clock) (*segmentLog, error) {
+	if config.Dir == "" {
+		return nil, IllegalInputError{error: "empty log directory"}
+	}
+	if config.SegmentSize <= 0 {
+		config.SegmentSize = defaultSegmentSize
+	}
+	if config.CatchUpBuffer <= 0 {
+		config.CatchUpBuffer = defaultCatchUpBuffer
+	}
+	if config.Marshal == nil {
+		config.Marshal = json.Marshal
+	}
+	if config.Unmarshal == nil {
+		config.Unmarshal = func(data []byte) (interface{}, error) {
+			var v interface{}
+			err := json.Unmarshal(data, &v)
+			return v, err
+		}
+	}
+	if config.Codec == nil {
+		config.Codec = NewCodec(config.Marshal, config.Unmarshal)
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	l := &segmentLog{
+		config: config,
+		clock:  clock,
+	}
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+	if len(l.segments) == 0 {
+		return l, l.rotate()
+	}
+
+	// recover the active segment, truncated at the first record torn or corrupted by a crash. The older
+	// segments were complete once rotated, so they are not scanned: a record corrupted since then ends the
+	// replay of its segment, which resumes at the next one.
+	last := l.segments[len(l.segments)-1]
+	count, size, err := scanSegment(last.path)
+	if err != nil {
+		return nil, err
+	}
+	if size != last.size {
+		if err := os.Truncate(last.path, size); err != nil {
+			return nil, err
+		}
+		last.size = size
+	}
+	l.next = last.base + count
+	l.active, err = os.OpenFile(last.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return l, l.retain(clock.Now())
+}
+
+// load lists the segments of the log directory
+func (l *segmentLog) load() error {
+	entries, err := os.ReadDir(l.config.Dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		base, err := strconv.ParseUint(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		l.segments = append(l.segments, &segment{
+			base:     base,
+			path:     filepath.Join(l.config.Dir, name),
+			size:     info.Size(),
+			modified: info.ModTime(),
+		})
+	}
+	sort.Slice(l.segments, func(i, j int) bool {
+		return l.segments[i].base < l.segments[j].base
+	})
+	return nil
+}
+
+// scanSegment returns the number of complete records of a segment and their size
+func scanSegment(path string) (uint64, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	var count uint64
+	var size int64
+	err = readRecords(bufio.NewReader(f), func(_ time.Time, item []byte) bool {
+		count++
+		size += recordHeaderSize + checksumSize + int64(len(item))
+		return true
+	})
+	return count, size, err
+}
+
+// readRecords reads the records of a segment until f returns false, the end, or a torn or corrupted record.
+// f receives the encoded item of each record, without its checksum.
+func readRecords(r io.Reader, f func(timestamp time.Time, item []byte) bool) error {
+	header := make([]byte, recordHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return err
+		}
+		length := binary.BigEndian.Uint32(header)
+		if !validHeader(length, header[12]) {
+			return nil
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return err
+		}
+		if binary.BigEndian.Uint32(payload) != checksum(header, payload[checksumSize:]) {
+			return nil
+		}
+		timestamp := time.Unix(0, int64(binary.BigEndian.Uint64(header[4:])))
+		if !f(timestamp, payload[checksumSize:]) {
+			return nil
+		}
+	}
+}
+
+// validHeader returns whether a record header is valid: a checked record, such as not a zero-filled header,
+// whose payload holds at least its checksum
+func validHeader(length uint32, kind byte) bool {
+	return kind == recordChecked && length >= checksumSize && length <= maxRecordSize
+}
+
+// checksum returns the checksum of a checked record, covering its timestamp, its kind and its encoded item
+func checksum(header, item []byte) uint32 {
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(header[4:recordHeaderSize])
+	_, _ = crc.Write(item)
+	return crc.Sum32()
+}
+
+// append writes an item at the end of the log
+func (l *segmentLog) append(item Item) error {
+	kind := KindNext
+	if item.Error() {
+		kind = KindErrorItem
+	}
+	payload, err := l.config.Codec.Marshal(kind, item)
+	if err != nil {
+		return err
+	}
+	if checksumSize+len(payload) > maxRecordSize {
+		return fmt.Errorf("record of %d bytes exceeds %d bytes", len(payload), maxRecordSize-checksumSize)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active == nil {
+		return errors.New("log closed")
+	}
+	now := l.clock.Now()
+	record := make([]byte, recordHeaderSize+checksumSize+len(payload))
+	binary.BigEndian.PutUint32(record, uint32(checksumSize+len(payload)))
+	binary.BigEndian.PutUint64(record[4:], uint64(now.UnixNano()))
+	record[12] = recordChecked
+	binary.BigEndian.PutUint32(record[recordHeaderSize:], checksum(record, payload))
+	copy(record[recordHeaderSize+checksumSize:], payload)
+	if _, err := l.active.Write(record); err != nil {
+		return err
+	}
+	l.next++
+
+	last := l.segments[len(l.segments)-1]
+	last.size += int64(len(record))
+	last.modified = now
+	if last.size >= l.config.SegmentSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	return l.retain(now)
+}
+
+// rotate closes the active segment and starts a new one
+func (l *segmentLog) rotate() error {
+	if l.active != nil {
+		if err := l.active.Close(); err != nil {
+			return err
+		}
+		l.active = nil
+	}
+	path := filepath.Join(l.config.Dir, fmt.Sprintf("%020d%s", l.next, segmentSuffix))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	l.active = f
+	l.segments = append(l.segments, &segment{base: l.next, path: path, modified: l.clock.Now()})
+	return nil
+}
+
+// retain removes the oldest segments exceeding the retention limits, the active segment excepted
+func (l *segmentLog) retain(now time.Time) error {
+	var total int64
+	for _, s := range l.segments {
+		total += s.size
+	}
+	for len(l.segments) > 1 {
+		oldest := l.segments[0]
+		tooLarge := l.config.MaxSize > 0 && total > l.config.MaxSize
+		tooOld := l.config.MaxAge > 0 && now.Sub(oldest.modified) > l.config.MaxAge
+		if !tooLarge && !tooOld {
+			break
+		}
+		if err := os.Remove(oldest.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= oldest.size
+		l.segments = l.segments[1:]
+	}
+	return nil
+}
+
+// bounds returns the sequence number of the first retained item and of the next item
+func (l *segmentLog) bounds() (uint64, uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.segments[0].base, l.next
+}
+
+// read calls f on the items retained before the end sequence number, until f returns false.
+// A segment removed by the retention in the meantime is skipped.
+func (l *segmentLog) read(end uint64, f func(Item) bool) error {
+	l.mu.Lock()
+	segments := make([]segment, len(l.segments))
+	for i, s := range l.segments {
+		segments[i] = *s
+	}
+	l.mu.Unlock()
+
+	for _, s := range segments {
+		if s.base >= end {
+			return nil
+		}
+		file, err := os.Open(s.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+
+		seq := s.base
+		more := true
+		var decodeErr error
+		err = readRecords(bufio.NewReader(file), func(_ time.Time, payload []byte) bool {
+			if seq >= end {
+				more = false
+				return false
+			}
+			seq++
+			_, item, err := l.config.Codec.Unmarshal(payload)
+			if err != nil {
+				decodeErr = err
+				return false
+			}
+			more = f(item)
+			return more
+		})
+		file.Close()
+		if err != nil {
+			return err
+		}
+		if decodeErr != nil {
+			return decodeErr
+		}
+		if !more {
+			return nil
+		}
+	}
+	return nil
+}
+
+// close closes the active segment
+func (l *segmentLog) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.active == nil {
+		return nil
+	}
+	err := l.active.Close()
+	l.active = nil
+	return err
+}
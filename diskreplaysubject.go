@@ -0,0 +1,162 @@
+package rxgo
+
+import "sync"
+
+// DiskReplaySubject subject which replays the items retained in an append-only log to new subscribers.
+// The log is split in segment files, so that the replay history can exceed the memory and survive restarts.
+type DiskReplaySubject struct {
+	Subject
+	// appendMu orders the items appended and published with the observations reading the log, without
+	// excluding the subscriptions
+	appendMu sync.Mutex
+	log      *segmentLog
+}
+
+// NewDiskReplaySubject creates a disk replay subject, recovering the items retained in the log directory.
+func NewDiskReplaySubject(config DiskReplayConfig, opts ...Option) (*DiskReplaySubject, error) {
+	res := DiskReplaySubject{
+		Subject: newSubject(opts...),
+	}
+	log, err := openSegmentLog(config, res.option.getClock())
+	if err != nil {
+		return nil, err
+	}
+	res.log = log
+	globalSubjects.add(&res.Subject, &res)
+
+	return &res, nil
+}
+
+// Next shadows base next function to append the item to the log
+func (s *DiskReplaySubject) Next(value interface{}) {
+	s.NextItem(Of(value))
+}
+
+// NextItem shadows base next item function to append the item to the log, inline errors included.
+// A failure to append is logged and the item is still published.
+func (s *DiskReplaySubject) NextItem(item Item) {
+	s.publishAppended(s.intercept([]Item{item}))
+}
+
+// NextBatch shadows base next batch function to append the items to the log.
+func (s *DiskReplaySubject) NextBatch(values []interface{}) {
+	batch := s.newBatch(values)
+	defer s.releaseBatch(batch)
+
+	s.publishAppended(batch.items)
+}
+
+// publishAppended appends items to the log, then publishes them, so that an observer receives them either from
+// the log or from the publication. The subject lock is not held while publishing, so that a subscriber
+// blocking the publication can be unsubscribed.
+func (s *DiskReplaySubject) publishAppended(items []Item) {
+	s.appendMu.Lock()
+	defer s.appendMu.Unlock()
+
+	for _, item := range items {
+		s.append(item)
+	}
+	s.nextItems(items...)
+}
+
+func (s *DiskReplaySubject) append(item Item) {
+	if err := s.log.append(item); err != nil {
+		s.logger().Error("rxgo: log append failed", "error", err)
+	}
+}
+
+// Subscribe shadows base subscribe function to replay the log. The log is read when the returned
+// Observable is observed: an observer receives the items appended until then, followed by the new items.
+func (s *DiskReplaySubject) Subscribe() (Subscription, Observable) {
+	return s.SubscribeWhere(nil)
+}
+
+// SubscribeWhere shadows base subscribe where function to replay the items of the log satisfying the
+// predicate.
+func (s *DiskReplaySubject) SubscribeWhere(predicate func(interface{}) bool) (Subscription, Observable) {
+	s.Lock()
+	defer s.Unlock()
+
+	sub, live := s.createFilteredSubscription(0, predicate)
+	return sub, &ObservableImpl{
+		iterable: &diskReplayIterable{subject: s, live: live, predicate: predicate},
+	}
+}
+
+// Close closes the log. Items published afterwards are not retained.
+func (s *DiskReplaySubject) Close() error {
+	return s.log.close()
+}
+
+// Producer registers a producer of the subject, like Subject.Producer, its items being published to the
+// DiskReplaySubject.
+func (s *DiskReplaySubject) Producer() *ProducerHandle {
+	return s.Subject.producer(s)
+}
+
+// SubscribeTo forwards the notifications of the sources to the subject, like Subject.SubscribeTo, the values
+// being published to the DiskReplaySubject.
+func (s *DiskReplaySubject) SubscribeTo(sources ...Observable) Disposable {
+	return s.Subject.subscribeTo(s, sources)
+}
+
+func (s *DiskReplaySubject) info() SubjectInfo {
+	info := s.Subject.info()
+	info.Type = "DiskReplaySubject"
+	first, next := s.log.bounds()
+	info.Buffered = int(next - first)
+	return info
+}
+
+// diskReplayIterable streams the log to an observer, then the live items of its subscription
+type diskReplayIterable struct {
+	subject   *DiskReplaySubject
+	live      Observable
+	predicate func(interface{}) bool
+}
+
+func (i *diskReplayIterable) Observe(opts ...Option) <-chan Item {
+	option := parseOptions(opts...)
+	next := option.buildChannel()
+	ctx := option.buildContext(emptyContext)
+
+	// no item can be appended while the append lock is held. The live items are buffered while the log is
+	// replayed, so that a long replay does not block the publishers until the buffer is full.
+	s := i.subject
+	s.appendMu.Lock()
+	live := i.live.Observe(append(opts[:len(opts):len(opts)], WithBufferedChannel(s.log.config.CatchUpBuffer))...)
+	_, end := s.log.bounds()
+	s.appendMu.Unlock()
+
+	go func() {
+		defer close(next)
+		// keep draining the live items not to block the subject
+		defer func() {
+			for range live {
+			}
+		}()
+
+		stopped := false
+		err := s.log.read(end, func(item Item) bool {
+			if i.predicate != nil && !item.Error() && !i.predicate(item.V) {
+				return true
+			}
+			stopped = !item.SendContext(ctx, next)
+			return !stopped
+		})
+		if stopped {
+			return
+		}
+		if err != nil {
+			s.logger().Error("rxgo: log read failed", "error", err)
+			Error(err).SendContext(ctx, next)
+			return
+		}
+		for item := range live {
+			if !item.SendContext(ctx, next) {
+				return
+			}
+		}
+	}()
+	return next
+}
@@ -0,0 +1,279 @@
+package rxgo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiskReplaySubject verifies the log is replayed after a restart, followed by the live items
+func TestDiskReplaySubject(t *testing.T) {
+	config := DiskReplayConfig{Dir: t.TempDir()}
+
+	subject, err := NewDiskReplaySubject(config)
+	if !assert.NoError(t, err) {
+		return
+	}
+	subject.Next("a")
+	subject.NextItem(Error(errFoo))
+	subject.NextBatch([]interface{}{"b", "c"})
+	if !assert.NoError(t, subject.Close()) {
+		return
+	}
+	subject.Complete()
+
+	restarted, err := NewDiskReplaySubject(config)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer restarted.Close()
+
+	_, obs := restarted.Subscribe()
+	observer := NewTestObserver(t, obs)
+	observer.AwaitCount(3, time.Second)
+	restarted.Next("d")
+	restarted.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("a", "b", "c", "d")
+	observer.AssertError(errFoo)
+}
+
+// TestDiskReplaySubject_UnsubscribeBlocked verifies a subscriber blocking a publisher can be unsubscribed
+func TestDiskReplaySubject_UnsubscribeBlocked(t *testing.T) {
+	subject, err := NewDiskReplaySubject(DiskReplayConfig{Dir: t.TempDir(), CatchUpBuffer: 1})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer subject.Close()
+
+	unsubscribeBlocked(t, subject)
+}
+
+// TestDiskReplaySubject_CatchUp verifies the items published while an observer replays the log are buffered
+// without blocking the publishers, then received after the replayed items
+func TestDiskReplaySubject_CatchUp(t *testing.T) {
+	subject, err := NewDiskReplaySubject(DiskReplayConfig{Dir: t.TempDir(), CatchUpBuffer: 10})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer subject.Close()
+	subject.Next(0)
+
+	// the observer does not consume, so that the replay does not progress
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, obs := subject.Subscribe()
+	observe := obs.Observe(WithContext(ctx))
+
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 1; i <= 10; i++ {
+			subject.Next(i)
+		}
+	}()
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "publisher blocked by the replay")
+	}
+
+	// the replayed value is decoded from JSON
+	assert.Equal(t, []interface{}{0.0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, values(receive(t, observe, 11)))
+	cancel()
+	subject.Complete()
+}
+
+// TestDiskReplaySubject_Retention verifies the oldest segments are removed beyond the maximum size
+func TestDiskReplaySubject_Retention(t *testing.T) {
+	dir := t.TempDir()
+	// a record of a single character value takes 17 bytes
+	subject, err := NewDiskReplaySubject(DiskReplayConfig{Dir: dir, SegmentSize: 32, MaxSize: 64})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer subject.Close()
+
+	for _, v := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		subject.Next(v)
+	}
+	segments, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, segments, 2)
+
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+	observer.AwaitCount(3, time.Second)
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("e", "f", "g")
+}
+
+// TestDiskReplaySubject_MaxAge verifies the segments older than the maximum age are removed
+func TestDiskReplaySubject_MaxAge(t *testing.T) {
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	subject, err := NewDiskReplaySubject(DiskReplayConfig{Dir: t.TempDir(), SegmentSize: 16, MaxAge: time.Minute},
+		WithClock(scheduler))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer subject.Close()
+
+	subject.Next("a")
+	scheduler.Advance(2 * time.Minute)
+	subject.Next("b")
+
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+	observer.AwaitCount(1, time.Second)
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("b")
+}
+
+// TestDiskReplaySubject_TornRecord verifies a record torn by a crash is dropped
+func TestDiskReplaySubject_TornRecord(t *testing.T) {
+	config := DiskReplayConfig{Dir: t.TempDir()}
+
+	subject, err := NewDiskReplaySubject(config)
+	if !assert.NoError(t, err) {
+		return
+	}
+	subject.Next("a")
+	if !assert.NoError(t, subject.Close()) {
+		return
+	}
+
+	segments, err := filepath.Glob(filepath.Join(config.Dir, "*.log"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, segments, 1) {
+		return
+	}
+	f, err := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0o644)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = f.Write([]byte{0, 0, 0, 9, 1})
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, f.Close()) {
+		return
+	}
+
+	restarted, err := NewDiskReplaySubject(config)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer restarted.Close()
+	restarted.Next("b")
+
+	_, obs := restarted.Subscribe()
+	observer := NewTestObserver(t, obs)
+	observer.AwaitCount(2, time.Second)
+	restarted.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("a", "b")
+}
+
+// TestDiskReplaySubject_CorruptedRecord verifies the active segment is truncated at the first corrupted record
+func TestDiskReplaySubject_CorruptedRecord(t *testing.T) {
+	for name, corrupt := range map[string]func(segment []byte) []byte{
+		"zero-filled": func(segment []byte) []byte {
+			return append(segment, make([]byte, 64)...)
+		},
+		"oversized": func(segment []byte) []byte {
+			return append(segment, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, 0, 0, 0, 0, recordChecked)
+		},
+		"kind": func(segment []byte) []byte {
+			return append(segment, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+		},
+		"checksum": func(segment []byte) []byte {
+			// the last byte of the second record
+			segment[len(segment)-1] ^= 0xff
+			return segment
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := DiskReplayConfig{Dir: t.TempDir()}
+			subject, err := NewDiskReplaySubject(config)
+			if !assert.NoError(t, err) {
+				return
+			}
+			subject.Next("a")
+			subject.Next("b")
+			if !assert.NoError(t, subject.Close()) {
+				return
+			}
+			subject.Complete()
+
+			path := filepath.Join(config.Dir, "00000000000000000000.log")
+			segment, err := os.ReadFile(path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if !assert.NoError(t, os.WriteFile(path, corrupt(segment), 0o644)) {
+				return
+			}
+
+			restarted, err := NewDiskReplaySubject(config)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer restarted.Close()
+			_, obs := restarted.Subscribe()
+			observer := NewTestObserver(t, obs)
+			restarted.Next("c")
+			restarted.Complete()
+
+			observer.AwaitDone(time.Second)
+			if name == "checksum" {
+				observer.AssertValues("a", "c")
+			} else {
+				observer.AssertValues("a", "b", "c")
+			}
+			observer.AssertNoErrors()
+		})
+	}
+}
+
+// TestDiskReplaySubject_Codec verifies the items are persisted with the codec
+func TestDiskReplaySubject_Codec(t *testing.T) {
+	config := DiskReplayConfig{Dir: t.TempDir(), Codec: NewGobCodec()}
+
+	subject, err := NewDiskReplaySubject(config)
+	if !assert.NoError(t, err) {
+		return
+	}
+	subject.Next(remoteEvent{Name: "a"})
+	if !assert.NoError(t, subject.Close()) {
+		return
+	}
+	subject.Complete()
+
+	restarted, err := NewDiskReplaySubject(config)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer restarted.Close()
+
+	_, obs := restarted.Subscribe()
+	observer := NewTestObserver(t, obs)
+	observer.AwaitCount(1, time.Second)
+	restarted.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(remoteEvent{Name: "a"})
+}
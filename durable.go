@@ -0,0 +1,523 @@
+package rxgo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// durableSubscription tracks the items of a durable subscription until they are acknowledged. It outlives
+// its subscribers: the items published while no subscriber is attached are kept for the next one.
+type durableSubscription struct {
+	// mu orders the deliveries and the attachment of a subscriber
+	mu         sync.Mutex
+	name       string
+	subscriber *subscriberState
+	// option holds the redelivery policy, set by the latest subscriber
+	option Option
+	// stop stops the redelivery of the items not acknowledged in time to the attached subscriber
+	stop chan struct{}
+
+	pendingMu sync.Mutex
+	next      uint64
+	// oldest is the offset below which no item is pending
+	oldest  uint64
+	pending map[uint64]*pendingItem
+	// keys holds the idempotency keys of the acknowledged items, selected by keyOf
+	keys  StateStore
+	keyOf func(interface{}) interface{}
+}
+
+// pendingItem is an item of a durable subscription awaiting its acknowledgement
+type pendingItem struct {
+	item        Item
+	deliveries  int
+	publishedAt time.Time
+	deliveredAt time.Time
+	// upstream acknowledges the item to its source once acknowledged
+	upstream acknowledger
+}
+
+// acknowledger acknowledges an item
+type acknowledger interface {
+	ack()
+}
+
+// nacker is an acknowledger which may also reject an item, so that it is redelivered
+type nacker interface {
+	nack()
+}
+
+// ackToken identifies an item delivered to a durable subscription
+type ackToken struct {
+	subject *Subject
+	durable *durableSubscription
+	offset  uint64
+}
+
+func (t *ackToken) ack() {
+	t.durable.ack(t.offset)
+}
+
+func (t *ackToken) nack() {
+	go t.durable.redeliver(t.subject, t.offset)
+}
+
+// sharedAck acknowledges an item upstream once all the durable subscriptions it was published to
+// acknowledged it
+type sharedAck struct {
+	remaining int32
+	upstream  acknowledger
+}
+
+func (a *sharedAck) ack() {
+	if atomic.AddInt32(&a.remaining, -1) == 0 {
+		a.upstream.ack()
+	}
+}
+
+func newDurableSubscription(name string) *durableSubscription {
+	return &durableSubscription{
+		name:    name,
+		option:  parseOptions(),
+		pending: make(map[uint64]*pendingItem),
+	}
+}
+
+// publish records an item and delivers it to the attached subscriber, if any. The items are queued for the
+// subscriber, so that a subscriber not observed does not block the publishers.
+func (d *durableSubscription) publish(s *Subject, item Item) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	item, recorded, evicted := d.record(s, item)
+	d.evict(s, evicted)
+	if recorded && d.subscriber != nil {
+		s.send(d.subscriber, item)
+	}
+}
+
+// record assigns the next offset to an item and keeps it until it is acknowledged, returning the oldest
+// pending items removed beyond the WithMaxPending limit. An item whose idempotency key was already
+// acknowledged is acknowledged right away instead.
+func (d *durableSubscription) record(s *Subject, item Item) (Item, bool, []*pendingItem) {
+	d.pendingMu.Lock()
+	if d.processed(item) {
+		d.pendingMu.Unlock()
+		s.logger().Debug("rxgo: duplicate item suppressed", "subscription", d.name, "item", item.V)
+		item.Ack()
+		return item, false, nil
+	}
+	defer d.pendingMu.Unlock()
+
+	now := s.option.getClock().Now()
+	pending := &pendingItem{upstream: item.ack, publishedAt: now}
+	item.ack = &ackToken{subject: s, durable: d, offset: d.next}
+	pending.item = item
+	if d.subscriber != nil {
+		pending.deliveries = 1
+		pending.deliveredAt = now
+	}
+	d.pending[d.next] = pending
+	d.next++
+
+	var evicted []*pendingItem
+	if max := d.option.getMaxPending(); max > 0 {
+		for ; len(d.pending) > max; d.oldest++ {
+			if oldest, exists := d.pending[d.oldest]; exists {
+				delete(d.pending, d.oldest)
+				evicted = append(evicted, oldest)
+			}
+		}
+	}
+	return item, true, evicted
+}
+
+// evict sends the pending items removed beyond the WithMaxPending limit to the dead letters
+func (d *durableSubscription) evict(s *Subject, evicted []*pendingItem) {
+	subscriber := NoSubscriber
+	if d.subscriber != nil {
+		subscriber = d.subscriber.id
+	}
+	for _, pending := range evicted {
+		item := pending.item
+		item.ack = nil
+		s.logger().Warn("rxgo: pending item removed", "subscription", d.name, "item", item.V)
+		s.deadLetter(subscriber, item, ErrMaxPending)
+		// a dead letter is not redelivered by the source either
+		if pending.upstream != nil {
+			pending.upstream.ack()
+		}
+	}
+}
+
+// isPending returns whether the item at an offset is awaiting its acknowledgement
+func (d *durableSubscription) isPending(offset uint64) bool {
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+
+	_, exists := d.pending[offset]
+	return exists
+}
+
+// processed returns whether the idempotency key of an item was already acknowledged. The caller holds
+// pendingMu.
+func (d *durableSubscription) processed(item Item) bool {
+	if item = unwrapped(item); d.keys == nil || item.Error() {
+		return false
+	}
+	_, exists := d.keys.Get(d.keyOf(item.V))
+	return exists
+}
+
+func (d *durableSubscription) ack(offset uint64) {
+	d.pendingMu.Lock()
+	pending, exists := d.pending[offset]
+	delete(d.pending, offset)
+	if exists && d.keys != nil {
+		if item := unwrapped(pending.item); !item.Error() {
+			d.keys.Put(d.keyOf(item.V), true)
+		}
+	}
+	d.pendingMu.Unlock()
+
+	if exists && pending.upstream != nil {
+		pending.upstream.ack()
+	}
+}
+
+// unacked returns the unacknowledged items, in offset order
+func (d *durableSubscription) unacked() []Item {
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+
+	items := make([]Item, 0, len(d.pending))
+	for _, offset := range d.offsets() {
+		items = append(items, d.pending[offset].item)
+	}
+	return items
+}
+
+// offsets returns the offsets of the pending items, in order. The caller holds pendingMu.
+func (d *durableSubscription) offsets() []uint64 {
+	offsets := make([]uint64, 0, len(d.pending))
+	for offset := range d.pending {
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool {
+		return offsets[i] < offsets[j]
+	})
+	return offsets
+}
+
+// stats returns the lag of the durable subscription, whose attached subscriber is given
+func (d *durableSubscription) stats(subscriber int, now time.Time) ConsumerStats {
+	stats := ConsumerStats{Name: d.name, Subscriber: subscriber}
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+	var oldest time.Time
+	for _, pending := range d.pending {
+		if pending.deliveries == 0 {
+			stats.Undelivered++
+		} else {
+			stats.Unacked++
+		}
+		if oldest.IsZero() || pending.publishedAt.Before(oldest) {
+			oldest = pending.publishedAt
+		}
+	}
+	if !oldest.IsZero() {
+		stats.OldestPending = now.Sub(oldest)
+	}
+	return stats
+}
+
+// attach attaches a subscriber and redelivers the unacknowledged items to it
+func (d *durableSubscription) attach(s *Subject, subscriber *subscriberState, option Option) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.subscriber = subscriber
+	d.option = option
+	d.pendingMu.Lock()
+	if d.keyOf = option.getIdempotencyKey(); d.keyOf == nil {
+		d.keys = nil
+	} else if d.keys == nil {
+		d.keys = newStateStore(option)
+	}
+	offsets := d.offsets()
+	d.pendingMu.Unlock()
+	s.send(subscriber, d.deliveries(s, offsets)...)
+
+	if timeout := option.getAckTimeout(); timeout > 0 {
+		d.stop = make(chan struct{})
+		go d.watch(s, subscriber, timeout, d.stop)
+	}
+}
+
+// detach detaches a subscriber, the next items being kept for the next one
+func (d *durableSubscription) detach(subscriber *subscriberState) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.subscriber == subscriber {
+		d.subscriber = nil
+		if d.stop != nil {
+			close(d.stop)
+			d.stop = nil
+		}
+	}
+}
+
+// deliveries returns the pending items at the given offsets to deliver to the attached subscriber. The
+// items delivered the maximum number of times are sent to the dead letters instead, and the items whose
+// idempotency key was acknowledged meanwhile are acknowledged.
+func (d *durableSubscription) deliveries(s *Subject, offsets []uint64) []Item {
+	now := s.option.getClock().Now()
+	maxDeliveries := d.option.getMaxDeliveries()
+
+	d.pendingMu.Lock()
+	items := make([]Item, 0, len(offsets))
+	var exhausted, duplicates []*pendingItem
+	for _, offset := range offsets {
+		pending, exists := d.pending[offset]
+		if !exists {
+			continue
+		}
+		if d.processed(pending.item) {
+			delete(d.pending, offset)
+			duplicates = append(duplicates, pending)
+			continue
+		}
+		if maxDeliveries > 0 && pending.deliveries >= maxDeliveries {
+			delete(d.pending, offset)
+			exhausted = append(exhausted, pending)
+			continue
+		}
+		pending.deliveries++
+		pending.deliveredAt = now
+		items = append(items, pending.item)
+	}
+	d.pendingMu.Unlock()
+
+	for _, pending := range duplicates {
+		s.logger().Debug("rxgo: duplicate item suppressed", "subscription", d.name, "item", pending.item.V)
+		if pending.upstream != nil {
+			pending.upstream.ack()
+		}
+	}
+	for _, pending := range exhausted {
+		item := pending.item
+		item.ack = nil
+		s.logger().Warn("rxgo: item delivered too many times", "subscription", d.name, "item", item.V)
+		s.deadLetter(d.subscriber.id, item, ErrMaxDeliveries)
+		// a dead letter is not redelivered by the source either
+		if pending.upstream != nil {
+			pending.upstream.ack()
+		}
+	}
+	return items
+}
+
+// redeliver redelivers a pending item to the attached subscriber, if any
+func (d *durableSubscription) redeliver(s *Subject, offset uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.subscriber != nil {
+		s.send(d.subscriber, d.deliveries(s, []uint64{offset})...)
+	}
+}
+
+// watch redelivers the items not acknowledged within timeout of their delivery, until stopped
+func (d *durableSubscription) watch(s *Subject, subscriber *subscriberState, timeout time.Duration, stop <-chan struct{}) {
+	clock := s.option.getClock()
+	// the items are not received until observed, their timeouts start then
+	select {
+	case <-stop:
+		return
+	case <-subscriber.source.observed:
+	}
+	now := clock.Now()
+	d.pendingMu.Lock()
+	for _, pending := range d.pending {
+		if pending.deliveries > 0 {
+			pending.deliveredAt = now
+		}
+	}
+	d.pendingMu.Unlock()
+
+	for {
+		wait := d.redeliverExpired(s, subscriber, clock.Now(), timeout)
+		timer := clock.NewTimer(wait)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+	}
+}
+
+// redeliverExpired redelivers the items whose acknowledgement timed out, and returns the time until the
+// next timeout
+func (d *durableSubscription) redeliverExpired(s *Subject, subscriber *subscriberState, now time.Time, timeout time.Duration) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.subscriber != subscriber {
+		return timeout
+	}
+	wait := timeout
+	var expired []uint64
+	d.pendingMu.Lock()
+	for _, offset := range d.offsets() {
+		pending := d.pending[offset]
+		if pending.deliveries == 0 {
+			continue
+		}
+		if deadline := pending.deliveredAt.Add(timeout); !deadline.After(now) {
+			expired = append(expired, offset)
+		} else if until := deadline.Sub(now); until < wait {
+			wait = until
+		}
+	}
+	d.pendingMu.Unlock()
+
+	if len(expired) > 0 {
+		s.send(subscriber, d.deliveries(s, expired)...)
+	}
+	return wait
+}
+
+// SubscribeDurable adds a durable subscriber identified by name. Each item it receives must be acknowledged
+// with Item.Ack. The unacknowledged items, including the ones published while no subscriber was attached
+// under that name, are redelivered to the next subscriber with the same name. A subscriber replaces the
+// current subscriber with the same name, if any.
+//
+// With WithIdempotencyKey, an item whose key was already acknowledged is not delivered again, such as an
+// item republished by a source recovering from a crash.
+//
+// An item rejected with Item.Nack, or not acknowledged within the timeout set with WithAckTimeout, is
+// redelivered to the subscriber. Once delivered the number of times set with WithMaxDeliveries, an item is
+// sent to the dead letters instead. The acknowledgement of an item published with an Ack, such as the item of
+// another durable subscription forwarded with Forward, is passed on once the item is acknowledged by all the
+// durable subscriptions, so that a pipeline of subjects acknowledges its input once processed downstream.
+//
+// The items are received with Observe, as the operators do not forward the acknowledgements.
+func (s *Subject) SubscribeDurable(name string, opts ...Option) (Subscription, Observable) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.durables == nil {
+		s.durables = make(map[string]*durableSubscription)
+	}
+	durable, exists := s.durables[name]
+	if !exists {
+		durable = newDurableSubscription(name)
+		s.durables[name] = durable
+	} else if current := durable.subscriber; current != nil {
+		s.closeSubscriber(current)
+	}
+
+	sub, obs := s.subscribe(len(durable.unacked()), durable, nil, nil)
+	durable.attach(s, s.subscribers[sub.GetId()], parseOptions(opts...))
+	return sub, obs
+}
+
+// Unacked returns the number of unacknowledged items of the durable subscription with the given name.
+func (s *Subject) Unacked(name string) int {
+	s.RLock()
+	durable, exists := s.durables[name]
+	s.RUnlock()
+
+	if !exists {
+		return 0
+	}
+	durable.pendingMu.Lock()
+	defer durable.pendingMu.Unlock()
+	return len(durable.pending)
+}
+
+// durableQueue is the queue of a durable subscriber, holding its deliveries until they are sent to its channel
+// so that the publishers are not blocked. The items no longer pending once popped, such as the items removed
+// beyond the WithMaxPending limit, are skipped; the item already popped is still sent.
+type durableQueue struct {
+	mu     sync.Mutex
+	items  []Item
+	closed bool
+	// ready is signaled once an item was queued or the queue closed
+	ready chan struct{}
+}
+
+func newDurableQueue() *durableQueue {
+	return &durableQueue{
+		ready: make(chan struct{}, 1),
+	}
+}
+
+// push queues an item, none being dropped
+func (q *durableQueue) push(item Item) []Item {
+	q.mu.Lock()
+	if !q.closed {
+		q.items = append(q.items, item)
+	}
+	q.mu.Unlock()
+
+	q.signal()
+	return nil
+}
+
+// pop returns the next item, waiting until an item is queued. It returns false once the queue is closed and
+// drained, or the context is done.
+func (q *durableQueue) pop(ctx context.Context) (Item, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.items) > 0 {
+			item := q.items[0]
+			q.items[0] = Item{}
+			q.items = q.items[1:]
+			q.mu.Unlock()
+			if token, ok := item.ack.(*ackToken); ok && !token.durable.isPending(token.offset) {
+				continue
+			}
+			return item, true
+		}
+		closed := q.closed
+		q.mu.Unlock()
+		if closed {
+			return Item{}, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return Item{}, false
+		case <-q.ready:
+		}
+	}
+}
+
+func (q *durableQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// len returns the number of queued items
+func (q *durableQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.items)
+}
+
+// close closes the queue, the queued items being still sent
+func (q *durableQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.signal()
+}
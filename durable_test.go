@@ -0,0 +1,266 @@
+package rxgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// receive receives n items from a channel
+func receive(t *testing.T, observe <-chan Item, n int) []Item {
+	t.Helper()
+	items := make([]Item, 0, n)
+	for len(items) < n {
+		select {
+		case item := <-observe:
+			items = append(items, item)
+		case <-time.After(time.Second):
+			assert.Fail(t, "timeout waiting for items", "expected %d items, got %d", n, len(items))
+			return items
+		}
+	}
+	return items
+}
+
+func values(items []Item) []interface{} {
+	res := make([]interface{}, len(items))
+	for i, item := range items {
+		res[i] = item.V
+	}
+	return res
+}
+
+// TestSubscribeDurable verifies the unacknowledged items are redelivered to the next subscriber
+func TestSubscribeDurable(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+
+	sub, obs := subject.SubscribeDurable("orders")
+	observe := obs.Observe(WithBufferedChannel(10))
+	subject.Next(1)
+	subject.Next(2)
+	subject.Next(3)
+
+	items := receive(t, observe, 3)
+	assert.Equal(t, []interface{}{1, 2, 3}, values(items))
+	items[0].Ack()
+	assert.Equal(t, 2, subject.Unacked("orders"))
+
+	sub.Unsubscribe()
+	subject.Next(4)
+	assert.Equal(t, 3, subject.Unacked("orders"))
+
+	_, obs = subject.SubscribeDurable("orders")
+	observe = obs.Observe(WithBufferedChannel(10))
+	items = receive(t, observe, 3)
+	assert.Equal(t, []interface{}{2, 3, 4}, values(items))
+	for _, item := range items {
+		item.Ack()
+	}
+	assert.Equal(t, 0, subject.Unacked("orders"))
+	assert.Equal(t, 0, subject.Unacked("unknown"))
+
+	subject.Complete()
+	_, open := <-observe
+	assert.False(t, open)
+}
+
+// TestSubscribeDurable_Replace verifies a durable subscriber replaces the one with the same name
+func TestSubscribeDurable_Replace(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+
+	_, obs := subject.SubscribeDurable("orders")
+	replaced := obs.Observe(WithBufferedChannel(10))
+	subject.Next(1)
+	receive(t, replaced, 1)
+
+	// the item received but not acknowledged is redelivered
+	_, obs = subject.SubscribeDurable("orders")
+	_, open := <-replaced
+	assert.False(t, open)
+
+	observe := obs.Observe(WithBufferedChannel(10))
+	subject.Next(2)
+	assert.Equal(t, []interface{}{1, 2}, values(receive(t, observe, 2)))
+
+	subject.Complete()
+}
+
+// TestSubscribeDurable_Nack verifies a rejected item is redelivered
+func TestSubscribeDurable_Nack(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+
+	_, obs := subject.SubscribeDurable("jobs")
+	observe := obs.Observe(WithBufferedChannel(10))
+	subject.Next(1)
+	subject.Next(2)
+
+	items := receive(t, observe, 2)
+	items[0].Nack()
+	items[1].Ack()
+	redelivered := receive(t, observe, 1)
+	assert.Equal(t, []interface{}{1}, values(redelivered))
+	redelivered[0].Ack()
+	assert.Equal(t, 0, subject.Unacked("jobs"))
+
+	subject.Complete()
+}
+
+// TestSubscribeDurable_AckTimeout verifies the items not acknowledged in time are redelivered, until sent to
+// the dead letters
+func TestSubscribeDurable_AckTimeout(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Now())
+	subject := NewSubject(WithClock(scheduler))
+	letters := subject.DeadLetters().Observe()
+
+	sub, obs := subject.SubscribeDurable("jobs", WithAckTimeout(time.Second), WithMaxDeliveries(2))
+	observe := obs.Observe(WithBufferedChannel(10))
+	scheduler.BlockUntil(1)
+	subject.Next(1)
+	subject.Next(2)
+	items := receive(t, observe, 2)
+	items[1].Ack()
+
+	scheduler.Advance(time.Second)
+	assert.Equal(t, []interface{}{1}, values(receive(t, observe, 1)))
+
+	scheduler.BlockUntil(1)
+	scheduler.Advance(time.Second)
+	letter := (<-letters).V.(DeadLetter)
+	assert.Equal(t, 1, letter.Item.V)
+	assert.Equal(t, ErrMaxDeliveries, letter.Reason)
+	assert.Equal(t, 0, subject.Unacked("jobs"))
+
+	sub.Unsubscribe()
+	subject.Complete()
+}
+
+// TestSubscribeDurable_Pipeline verifies an item forwarded between durable subscriptions is acknowledged
+// upstream once acknowledged by all the downstream durable subscriptions
+func TestSubscribeDurable_Pipeline(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	in := NewSubject()
+	out := NewSubject()
+
+	_, stage := in.SubscribeDurable("stage")
+	_, billing := out.SubscribeDurable("billing")
+	_, shipping := out.SubscribeDurable("shipping")
+	billed := billing.Observe(WithBufferedChannel(10))
+	shipped := shipping.Observe(WithBufferedChannel(10))
+	dispose := Forward(stage, out, WithForwardTransform(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i.(int) * 10, nil
+	}))
+	defer dispose()
+
+	in.Next(1)
+	bill := receive(t, billed, 1)
+	ship := receive(t, shipped, 1)
+	assert.Equal(t, []interface{}{10}, values(bill))
+
+	bill[0].Ack()
+	assert.Equal(t, 1, in.Unacked("stage"))
+	ship[0].Ack()
+	assert.Equal(t, 0, in.Unacked("stage"))
+
+	in.Complete()
+	out.Complete()
+}
+
+// TestSubscribeDurable_IdempotencyKey verifies the items whose key was acknowledged are suppressed
+func TestSubscribeDurable_IdempotencyKey(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	upstream := 0
+	ack := func() {
+		upstream++
+	}
+	id := WithIdempotencyKey(func(i interface{}) interface{} {
+		return i.(string)[:1]
+	})
+
+	_, obs := subject.SubscribeDurable("jobs", id)
+	observe := obs.Observe(WithBufferedChannel(10))
+	subject.NextItem(Of("a1").WithAck(ack))
+	subject.Next("b1")
+	items := receive(t, observe, 2)
+	items[0].Ack()
+	assert.Equal(t, 1, upstream)
+
+	// the republished item is acknowledged without being delivered
+	subject.NextItem(Of("a2").WithAck(ack))
+	assert.Equal(t, 2, upstream)
+	assert.Equal(t, 1, subject.Unacked("jobs"))
+
+	// the redelivered item whose key was acknowledged meanwhile is suppressed
+	subject.Next("b2")
+	items = receive(t, observe, 1)
+	assert.Equal(t, []interface{}{"b2"}, values(items))
+	items[0].Ack()
+	_, obs = subject.SubscribeDurable("jobs", id)
+	observe = obs.Observe(WithBufferedChannel(10))
+	subject.Next("c1")
+	assert.Equal(t, []interface{}{"c1"}, values(receive(t, observe, 1)))
+	assert.Equal(t, 1, subject.Unacked("jobs"))
+
+	subject.Complete()
+}
+
+// TestSubscribeDurable_NotObserved verifies a durable subscriber not observed does not block the publishers
+func TestSubscribeDurable_NotObserved(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+
+	_, obs := subject.SubscribeDurable("jobs")
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 0; i < 100; i++ {
+			subject.Next(i)
+		}
+	}()
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		assert.Fail(t, "publisher blocked by the durable subscriber")
+	}
+	assert.Equal(t, 100, subject.Unacked("jobs"))
+
+	items := receive(t, obs.Observe(), 100)
+	assert.Equal(t, 0, items[0].V)
+	assert.Equal(t, 99, items[99].V)
+
+	subject.Complete()
+}
+
+// TestSubscribeDurable_MaxPending verifies the oldest pending items are sent to the dead letters beyond the
+// limit, and not delivered
+func TestSubscribeDurable_MaxPending(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	letters := subject.DeadLetters().Observe(WithBufferedChannel(10))
+
+	sub, _ := subject.SubscribeDurable("jobs", WithMaxPending(2))
+	sub.Unsubscribe()
+	for i := 1; i <= 4; i++ {
+		subject.Next(i)
+	}
+	assert.Equal(t, 2, subject.Unacked("jobs"))
+	for _, v := range []int{1, 2} {
+		letter := (<-letters).V.(DeadLetter)
+		assert.Equal(t, v, letter.Item.V)
+		assert.Equal(t, ErrMaxPending, letter.Reason)
+	}
+
+	sub, obs := subject.SubscribeDurable("jobs", WithMaxPending(2))
+	observe := obs.Observe(WithBufferedChannel(10))
+	assert.Equal(t, []interface{}{3, 4}, values(receive(t, observe, 2)))
+
+	sub.Unsubscribe()
+	subject.Complete()
+}
@@ -30,6 +30,17 @@ func WithDuration(d time.Duration) Duration {
 	}
 }
 
+type durationFunc func() time.Duration
+
+func (f durationFunc) duration() time.Duration {
+	return f()
+}
+
+// WithDurationFunc is a duration option evaluated each time it is used, so that it can change over time
+func WithDurationFunc(f func() time.Duration) Duration {
+	return durationFunc(f)
+}
+
 var tick = struct{}{}
 
 type causalityDuration struct {
@@ -44,7 +55,6 @@ type execution struct {
 func timeCausality(elems ...interface{}) (context.Context, Observable, Duration) {
 	ch := make(chan Item, 1)
 	fs := make([]execution, len(elems)+1)
-	ctx, cancel := context.WithCancel(context.Background())
 	for i, elem := range elems {
 		i := i
 		elem := elem
@@ -74,11 +84,11 @@ func timeCausality(elems ...interface{}) (context.Context, Observable, Duration)
 	}
 	fs[len(elems)] = execution{
 		f: func() {
-			cancel()
+			close(ch)
 		},
 		isTick: false,
 	}
-	return ctx, FromChannel(ch), &causalityDuration{fs: fs}
+	return context.Background(), FromChannel(ch), &causalityDuration{fs: fs}
 }
 
 func (d *causalityDuration) duration() time.Duration {
@@ -1,5 +1,15 @@
 package rxgo
 
+import "errors"
+
+var (
+	// ErrNotConnectable is returned when connecting an Observable not created with WithPublishStrategy.
+	ErrNotConnectable = errors.New("observable not connectable")
+	// ErrAlreadyConnected is returned when connecting an Observable already connected, or whose source
+	// completed.
+	ErrAlreadyConnected = errors.New("observable already connected")
+)
+
 // IllegalInputError is triggered when the observable receives an illegal input.
 type IllegalInputError struct {
 	error string
@@ -17,3 +27,12 @@ type IndexOutOfBoundError struct {
 func (e IndexOutOfBoundError) Error() string {
 	return "index out of bound: " + e.error
 }
+
+// NoSuchElementError is triggered when an element is expected but the observable is empty.
+type NoSuchElementError struct {
+	error string
+}
+
+func (e NoSuchElementError) Error() string {
+	return "no such element: " + e.error
+}
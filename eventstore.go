@@ -0,0 +1,103 @@
+package rxgo
+
+import (
+	"context"
+	"sync"
+)
+
+type (
+	// Decider returns the events resulting from a command given the current state, or an error rejecting the
+	// command.
+	Decider func(state interface{}, command interface{}) ([]interface{}, error)
+	// Reducer returns the state resulting from an event applied to the current state.
+	Reducer func(state interface{}, event interface{}) interface{}
+)
+
+// EventStore is an event-sourced store: the commands are turned into events by a Decider, the events are
+// appended to a log held by a ReplaySubject, and reduced into the current state held by a BehaviorSubject.
+type EventStore struct {
+	mu     sync.Mutex
+	state  interface{}
+	decide Decider
+	reduce Reducer
+	events *ReplaySubject
+	states *BehaviorSubject
+}
+
+// NewEventStore creates an event store holding an initial state, the last maxEvents events being replayed by
+// Events. The options configure the subjects.
+func NewEventStore(initial interface{}, decide Decider, reduce Reducer, maxEvents int, opts ...Option) *EventStore {
+	s := &EventStore{
+		state:  initial,
+		decide: decide,
+		reduce: reduce,
+		events: NewReplaySubject(maxEvents, opts...),
+		states: NewBehaviorSubject(opts...),
+	}
+	s.states.Next(initial)
+	return s
+}
+
+// Dispatch handles a command: the events decided are published to Events, then the state they are reduced
+// into is published to State. The commands are handled one at a time. The error of the Decider is returned,
+// in which case nothing is published.
+func (s *EventStore) Dispatch(command interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, err := s.decide(s.state, command)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	for _, event := range events {
+		s.state = s.reduce(s.state, event)
+		s.events.Next(event)
+	}
+	s.states.Next(s.state)
+	return nil
+}
+
+// State creates an Observable emitting the current state to each observer, then the state after each
+// dispatched command, until the context passed with WithContext is done or the store is closed.
+func (s *EventStore) State(opts ...Option) Observable {
+	return observeSubject(s.states.Subscribe, opts...)
+}
+
+// Events creates an Observable emitting the events of the log to each observer, then the events of each
+// dispatched command, until the context passed with WithContext is done or the store is closed.
+func (s *EventStore) Events(opts ...Option) Observable {
+	return observeSubject(s.events.Subscribe, opts...)
+}
+
+// Close completes the observers of State and Events.
+func (s *EventStore) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events.Complete()
+	s.states.Complete()
+}
+
+// observeSubject creates an Observable subscribing to a subject for each observer, the subscription being
+// unsubscribed once the context passed with WithContext is done
+func observeSubject(subscribe func() (Subscription, Observable), opts ...Option) Observable {
+	return Defer([]Producer{func(ctx context.Context, next chan<- Item) {
+		sub, obs := subscribe()
+		defer sub.Unsubscribe()
+
+		observe := obs.Observe(WithContext(ctx))
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-observe:
+				if !ok || !item.SendContext(ctx, next) {
+					return
+				}
+			}
+		}
+	}}, opts...)
+}
@@ -0,0 +1,67 @@
+package rxgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+type deposit struct {
+	amount int
+}
+
+type deposited struct {
+	amount int
+}
+
+var errNegativeDeposit = errors.New("negative deposit")
+
+func newAccountStore() *EventStore {
+	return NewEventStore(0, func(_ interface{}, command interface{}) ([]interface{}, error) {
+		d := command.(deposit)
+		if d.amount < 0 {
+			return nil, errNegativeDeposit
+		}
+		return []interface{}{deposited{amount: d.amount}}, nil
+	}, func(state interface{}, event interface{}) interface{} {
+		return state.(int) + event.(deposited).amount
+	}, 10)
+}
+
+func TestEventStore(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	store := newAccountStore()
+	states := NewTestObserver(t, store.State())
+	states.AwaitCount(1, time.Second)
+
+	assert.NoError(t, store.Dispatch(deposit{amount: 10}))
+	assert.Equal(t, errNegativeDeposit, store.Dispatch(deposit{amount: -1}))
+	assert.NoError(t, store.Dispatch(deposit{amount: 5}))
+	states.AwaitCount(3, time.Second)
+
+	// a late observer receives the event log
+	events := NewTestObserver(t, store.Events())
+	events.AwaitCount(2, time.Second)
+	store.Close()
+
+	states.AwaitDone(time.Second)
+	states.AssertValues(0, 10, 15)
+	events.AwaitDone(time.Second)
+	events.AssertValues(deposited{amount: 10}, deposited{amount: 5})
+}
+
+func TestEventStore_Context(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	store := newAccountStore()
+	defer store.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	observer := NewTestObserver(t, store.State(WithContext(ctx)))
+	observer.AwaitCount(1, time.Second)
+	cancel()
+	observer.AwaitDone(time.Second)
+	assert.Equal(t, 0, store.states.Stats().Subscribers)
+}
@@ -5,7 +5,6 @@ import (
 	"math"
 	"sync"
 	"sync/atomic"
-	"time"
 )
 
 // Amb takes several Observables, emit all of the items from only the first of these Observables
@@ -56,9 +55,9 @@ func Amb(observables []Observable, opts ...Option) Observable {
 		go f(o)
 	}
 
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newChannelIterable(next),
-	}
+	}, iterables(observables)...)
 }
 
 // CombineLatest combines the latest item emitted by each Observable via a specified function
@@ -89,7 +88,7 @@ func CombineLatest(f FuncN, observables []Observable, opts ...Option) Observable
 						return
 					}
 					if item.Error() {
-						next <- item
+						item.SendContext(ctx, next)
 						errCh <- struct{}{}
 						return
 					}
@@ -99,7 +98,7 @@ func CombineLatest(f FuncN, observables []Observable, opts ...Option) Observable
 					mutex.Lock()
 					s[i] = item.V
 					if atomic.LoadUint32(&counter) == size {
-						next <- Of(f(s...))
+						Of(f(s...)).SendContext(ctx, next)
 					}
 					mutex.Unlock()
 				}
@@ -122,9 +121,9 @@ func CombineLatest(f FuncN, observables []Observable, opts ...Option) Observable
 		close(errCh)
 	}()
 
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newChannelIterable(next),
-	}
+	}, iterables(observables)...)
 }
 
 // Concat emits the emissions from two or more Observables without interleaving them.
@@ -146,18 +145,16 @@ func Concat(observables []Observable, opts ...Option) Observable {
 					if !ok {
 						break loop
 					}
-					if item.Error() {
-						next <- item
+					if !item.SendContext(ctx, next) || item.Error() {
 						return
 					}
-					next <- item
 				}
 			}
 		}
 	}()
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newChannelIterable(next),
-	}
+	}, iterables(observables)...)
 }
 
 // Create creates an Observable from scratch by calling observer methods programmatically.
@@ -167,6 +164,15 @@ func Create(f []Producer, opts ...Option) Observable {
 	}
 }
 
+// CreateWithEmitter creates an Observable calling f for each observer with an Emitter
+// to push items, an error or the completion. The context passed to f is canceled
+// once the Observable terminates, so that blocking or callback-based APIs can be released.
+func CreateWithEmitter(f func(ctx context.Context, emitter Emitter), opts ...Option) Observable {
+	return &ObservableImpl{
+		iterable: newEmitterIterable(f, opts...),
+	}
+}
+
 // Defer does not create the Observable until the observer subscribes,
 // and creates a fresh Observable for each observer.
 func Defer(f []Producer, opts ...Option) Observable {
@@ -175,6 +181,17 @@ func Defer(f []Producer, opts ...Option) Observable {
 	}
 }
 
+// DeferObservable does not create the Observable until the observer subscribes,
+// and calls the factory to get a fresh Observable for each observer.
+func DeferObservable(factory func() Observable, opts ...Option) Observable {
+	return &ObservableImpl{
+		iterable: newFactoryIterable(func(propagatedOptions ...Option) <-chan Item {
+			mergedOptions := append(opts, propagatedOptions...)
+			return factory().Observe(mergedOptions...)
+		}),
+	}
+}
+
 // Empty creates an Observable with no item and terminate immediately.
 func Empty() Observable {
 	next := make(chan Item)
@@ -199,34 +216,81 @@ func FromEventSource(next <-chan Item, opts ...Option) Observable {
 	option := parseOptions(opts...)
 
 	return &ObservableImpl{
-		iterable: newEventSourceIterable(option.buildContext(emptyContext), next, option.getBackPressureStrategy()),
+		iterable: newEventSourceIterable(option.buildContext(emptyContext), next, option.getBackPressureStrategy(), nil),
+	}
+}
+
+// FromFunc runs f once in a new goroutine and creates an Observable emitting its result,
+// or its error, to every observer.
+func FromFunc(f func() (interface{}, error), opts ...Option) Observable {
+	done := make(chan struct{})
+	var result Item
+
+	go func() {
+		defer close(done)
+		v, err := f()
+		if err != nil {
+			result = Error(err)
+			return
+		}
+		result = Of(v)
+	}()
+
+	return &ObservableImpl{
+		iterable: newFactoryIterable(func(propagatedOptions ...Option) <-chan Item {
+			option := parseOptions(append(opts, propagatedOptions...)...)
+			next := option.buildChannel()
+			ctx := option.buildContext(emptyContext)
+
+			go func() {
+				defer close(next)
+				select {
+				case <-ctx.Done():
+				case <-done:
+					result.SendContext(ctx, next)
+				}
+			}()
+			return next
+		}),
 	}
 }
 
 // Interval creates an Observable emitting incremental integers infinitely between
-// each given time interval.
+// each given time interval. The interval is evaluated again for each item.
+// The underlying timer is stopped and the Observable completes once the context is canceled.
 func Interval(interval Duration, opts ...Option) Observable {
+	// a fixed interval is checked upfront, without evaluating the other durations
+	if d, ok := interval.(*duration); ok && d.d <= 0 {
+		return Thrown(IllegalInputError{error: "interval must be positive"})
+	}
 	option := parseOptions(opts...)
 	next := option.buildChannel()
 	ctx := option.buildContext(emptyContext)
 
 	go func() {
+		defer close(next)
+		clock := option.getClock()
+		timer := clock.NewTimer(interval.duration())
+		defer func() {
+			timer.Stop()
+		}()
+
 		i := 0
 		for {
 			select {
-			case <-time.After(interval.duration()):
+			case <-timer.C():
 				if !Of(i).SendContext(ctx, next) {
 					return
 				}
 				i++
+				timer = clock.NewTimer(interval.duration())
 			case <-ctx.Done():
-				close(next)
 				return
 			}
 		}
 	}()
 	return &ObservableImpl{
-		iterable: newEventSourceIterable(ctx, next, option.getBackPressureStrategy()),
+		iterable: newEventSourceIterable(ctx, next, option.getBackPressureStrategy(), nil),
 	}
 }
 
@@ -282,9 +346,9 @@ func Merge(observables []Observable, opts ...Option) Observable {
 		wg.Wait()
 		close(next)
 	}()
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newChannelIterable(next),
-	}
+	}, iterables(observables)...)
 }
 
 // Never creates an Observable that emits no items and does not terminate.
@@ -343,6 +407,7 @@ func Thrown(err error) Observable {
 }
 
 // Timer returns an Observable that completes after a specified delay.
+// The underlying timer is released as soon as the context is canceled.
 func Timer(d Duration, opts ...Option) Observable {
 	option := parseOptions(opts...)
 	next := make(chan Item, 1)
@@ -350,10 +415,13 @@ func Timer(d Duration, opts ...Option) Observable {
 
 	go func() {
 		defer close(next)
+		timer := option.getClock().NewTimer(d.duration())
+		defer timer.Stop()
+
 		select {
 		case <-ctx.Done():
 			return
-		case <-time.After(d.duration()):
+		case <-timer.C():
 			return
 		}
 	}()
@@ -63,6 +63,68 @@ func Test_Connectable_IterableChannel_Disposed(t *testing.T) {
 	Assert(ctx, t, obs, IsEmpty())
 }
 
+func Test_Connectable_IterableChannel_ConnectWithCancel(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ch := make(chan Item)
+	obs := FromChannel(ch, WithPublishStrategy())
+	observe := obs.Observe()
+	ctx := context.Background()
+
+	disposable, err := obs.ConnectWithCancel(ctx)
+	assert.NoError(t, err)
+	ch <- Of(1)
+	assert.Equal(t, 1, (<-observe).V)
+	_, err = obs.ConnectWithCancel(ctx)
+	assert.Equal(t, ErrAlreadyConnected, err)
+
+	disposable()
+	time.Sleep(50 * time.Millisecond)
+	go func() {
+		ch <- Of(2)
+		close(ch)
+	}()
+	select {
+	case item := <-observe:
+		assert.FailNow(t, "item emitted while disconnected", "%v", item)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	disposable, err = obs.ConnectWithCancel(ctx)
+	assert.NoError(t, err)
+	defer disposable()
+	assert.Equal(t, 2, (<-observe).V)
+	_, ok := <-observe
+	assert.False(t, ok)
+	time.Sleep(50 * time.Millisecond)
+	_, err = obs.ConnectWithCancel(ctx)
+	assert.Equal(t, ErrAlreadyConnected, err)
+}
+
+func Test_Connectable_IterableCreate_ConnectWithCancel_ContextCancelled(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	obs := Create([]Producer{func(ctx context.Context, next chan<- Item) {
+		next <- Of(1)
+		<-ctx.Done()
+	}}, WithPublishStrategy(), WithContext(ctx))
+	observe := obs.Observe()
+
+	_, err := obs.ConnectWithCancel(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, (<-observe).V)
+	cancel()
+	_, ok := <-observe
+	assert.False(t, ok)
+}
+
+func Test_Connectable_ConnectWithCancel_NotConnectable(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	_, err := Just(1, 2, 3)().ConnectWithCancel(context.Background())
+	assert.Equal(t, ErrNotConnectable, err)
+	_, err = FromChannel(make(chan Item)).ConnectWithCancel(context.Background())
+	assert.Equal(t, ErrNotConnectable, err)
+}
+
 func Test_Connectable_IterableChannel_WithoutConnect(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ch := make(chan Item, 10)
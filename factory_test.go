@@ -3,6 +3,7 @@ package rxgo
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -172,6 +173,74 @@ func Test_Create_ContextCancelled(t *testing.T) {
 	}
 }
 
+func Test_CreateWithEmitter(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := CreateWithEmitter(func(ctx context.Context, emitter Emitter) {
+		emitter.Next(1)
+		emitter.Next(2)
+		emitter.Complete()
+		emitter.Next(3)
+	})
+	Assert(context.Background(), t, obs, HasItems(1, 2), HasNoError())
+	Assert(context.Background(), t, obs, HasItems(1, 2), HasNoError())
+}
+
+func Test_CreateWithEmitter_NextItem(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	acked := make(chan struct{})
+	obs := CreateWithEmitter(func(ctx context.Context, emitter Emitter) {
+		emitter.NextItem(Of(1).WithAck(func() {
+			close(acked)
+		}))
+		emitter.NextItem(Error(errFoo))
+	})
+	for item := range obs.Observe() {
+		item.Ack()
+	}
+	<-acked
+}
+
+func Test_CreateWithEmitter_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := CreateWithEmitter(func(ctx context.Context, emitter Emitter) {
+		emitter.Next(1)
+		emitter.Error(errFoo)
+		emitter.Next(2)
+	})
+	Assert(context.Background(), t, obs, HasItems(1), HasError(errFoo))
+}
+
+func Test_CreateWithEmitter_Callback(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := CreateWithEmitter(func(ctx context.Context, emitter Emitter) {
+		go func() {
+			for i := 0; i < 3; i++ {
+				emitter.Next(i)
+			}
+			emitter.Complete()
+		}()
+	})
+	Assert(context.Background(), t, obs, HasItems(0, 1, 2), HasNoError())
+}
+
+func Test_CreateWithEmitter_ContextCancelled(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	disposed := make(chan bool)
+	ctx, cancel := context.WithCancel(context.Background())
+	CreateWithEmitter(func(ctx context.Context, emitter Emitter) {
+		<-ctx.Done()
+		disposed <- emitter.IsDisposed()
+	}, WithContext(ctx)).Run()
+	cancel()
+
+	select {
+	case <-time.Tick(time.Second):
+		assert.FailNow(t, "emitter not disposed")
+	case d := <-disposed:
+		assert.True(t, d)
+	}
+}
+
 func Test_Defer(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	obs := Defer([]Producer{func(ctx context.Context, next chan<- Item) {
@@ -267,6 +336,33 @@ func Test_Defer_Error(t *testing.T) {
 	Assert(context.Background(), t, obs, HasItems(1, 2), HasError(errFoo))
 }
 
+func Test_DeferObservable(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	calls := 0
+	obs := DeferObservable(func() Observable {
+		calls++
+		return Just(calls)()
+	})
+	assert.Equal(t, 0, calls)
+	Assert(context.Background(), t, obs, HasItems(1), HasNoError())
+	Assert(context.Background(), t, obs, HasItems(2), HasNoError())
+}
+
+func Test_DeferObservable_Retry(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	calls := 0
+	obs := DeferObservable(func() Observable {
+		calls++
+		if calls < 3 {
+			return Thrown(errFoo)
+		}
+		return Just(calls)()
+	}).Retry(3, func(err error) bool {
+		return true
+	})
+	Assert(context.Background(), t, obs, HasItems(3), HasNoError())
+}
+
 func Test_Empty(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	obs := Empty()
@@ -355,17 +451,82 @@ func Test_FromEventSource_Drop(t *testing.T) {
 	}))
 }
 
-// FIXME
-//func Test_Interval(t *testing.T) {
-//	defer goleak.VerifyNone(t)
-//	ctx, cancel := context.WithCancel(context.Background())
-//	obs := Interval(WithDuration(time.Nanosecond), WithContext(ctx))
-//	go func() {
-//		time.Sleep(50 * time.Millisecond)
-//		cancel()
-//	}()
-//	Assert(context.Background(), t, obs, IsNotEmpty())
-//}
+func Test_FromFunc(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	var calls int32
+	obs := FromFunc(func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return 1, nil
+	})
+	Assert(context.Background(), t, obs, HasItem(1), HasNoError())
+	Assert(context.Background(), t, obs, HasItem(1), HasNoError())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func Test_FromFunc_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := FromFunc(func() (interface{}, error) {
+		return nil, errFoo
+	})
+	Assert(context.Background(), t, obs, IsEmpty(), HasError(errFoo))
+}
+
+func Test_Interval(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	obs := Interval(WithDuration(time.Nanosecond), WithContext(ctx))
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	Assert(context.Background(), t, obs, IsNotEmpty())
+}
+
+func Test_Interval_TestScheduler(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	ch := Interval(WithDuration(time.Second), WithContext(ctx), WithClock(scheduler)).Observe()
+
+	for i := 0; i < 3; i++ {
+		scheduler.BlockUntil(1)
+		scheduler.Advance(time.Second)
+		assert.Equal(t, i, (<-ch).V)
+	}
+}
+
+func Test_Interval_DurationFunc(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	evaluations := 0
+	ch := Interval(WithDurationFunc(func() time.Duration {
+		evaluations++
+		return time.Duration(evaluations) * time.Second
+	}), WithContext(ctx), WithClock(scheduler)).Observe()
+
+	scheduler.BlockUntil(1)
+	scheduler.Advance(time.Second)
+	assert.Equal(t, 0, (<-ch).V)
+
+	// the interval is evaluated again for each item
+	scheduler.BlockUntil(1)
+	scheduler.Advance(time.Second)
+	select {
+	case <-ch:
+		assert.Fail(t, "emitted before the interval elapsed")
+	case <-time.After(20 * time.Millisecond):
+	}
+	scheduler.Advance(time.Second)
+	assert.Equal(t, 1, (<-ch).V)
+}
+
+func Test_Interval_NotPositive(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	Assert(context.Background(), t, Interval(WithDuration(0)), IsEmpty(), HasAnError())
+}
 
 func Test_JustItem(t *testing.T) {
 	defer goleak.VerifyNone(t)
@@ -510,6 +671,23 @@ func Test_Timer(t *testing.T) {
 	}
 }
 
+func Test_Timer_TestScheduler(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	ch := Timer(WithDuration(time.Hour), WithClock(scheduler)).Observe()
+
+	scheduler.BlockUntil(1)
+	scheduler.Advance(59 * time.Minute)
+	select {
+	case <-ch:
+		assert.FailNow(t, "observable closed too early")
+	default:
+	}
+	scheduler.Advance(time.Minute)
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
 func Test_Timer_Empty(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -0,0 +1,117 @@
+package rxgo
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// SubscribeTo forwards the notifications of the sources to the subject, so that the subject acts as a merge
+// point. The values are published with Next and the errors with Error, the error strategy of the subject
+// deciding whether an error terminates it. Per the WithTerminalPolicy option of the subject, the subject
+// completes once all the sources completed, or once the first one did.
+//
+// The returned Disposable stops the forwarding without terminating the subject. The forwarding also stops once
+// the subject terminated.
+func (s *Subject) SubscribeTo(sources ...Observable) Disposable {
+	return s.subscribeTo(s, sources)
+}
+
+// subscribeTo forwards the notifications of the sources to dst, the subject itself or the subject embedding it
+func (s *Subject) subscribeTo(dst ISubject, sources []Observable) Disposable {
+	ctx, cancel := context.WithCancel(s.option.buildContext(emptyContext))
+	if len(sources) == 0 {
+		return Disposable(cancel)
+	}
+	done := s.Done()
+	policy := s.option.getTerminalPolicy()
+	remaining := int32(len(sources))
+
+	for _, source := range sources {
+		go func(observe <-chan Item) {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-done:
+					cancel()
+					return
+				case item, ok := <-observe:
+					if !ok {
+						if ctx.Err() != nil {
+							// disposed
+							return
+						}
+						if policy == CompleteOnFirst || atomic.AddInt32(&remaining, -1) == 0 {
+							cancel()
+							s.terminateWith(nil)
+						}
+						return
+					}
+					if item.Error() {
+						dst.Error(item.E)
+						continue
+					}
+					dst.Next(item.V)
+				}
+			}
+		}(source.Observe(WithContext(ctx)))
+	}
+	return Disposable(cancel)
+}
+
+// Forward forwards the notifications of src, such as a subscription of a module subject, to dst, such as an
+// application bus, until src terminates. The values are published with Next, filtered by WithForwardFilter and
+// transformed by WithForwardTransform. The errors of src and of the transform are published with Error, or
+// inline with WithErrorIsolation so that they never terminate dst. The completion of src does not complete
+// dst, which SubscribeTo does.
+//
+// The values keep the acknowledgement of their item, so that the items of a durable subscription forwarded
+// to a Subject with durable subscriptions are acknowledged once processed downstream. A filtered out item is
+// acknowledged, an item whose transform failed is not.
+//
+// The returned Disposable stops the forwarding, without unsubscribing a subject subscription, which
+// Subscription.Unsubscribe does. The WithContext option sets the context of the forwarding.
+func Forward(src Observable, dst ISubject, opts ...Option) Disposable {
+	option := parseOptions(opts...)
+	ctx, cancel := context.WithCancel(option.buildContext(emptyContext))
+	filter := option.getForwardFilter()
+	transform := option.getForwardTransform()
+	fail := dst.Error
+	if option.isErrorIsolation() {
+		fail = func(err error) {
+			dst.NextItem(Error(err))
+		}
+	}
+
+	observe := src.Observe(WithContext(ctx))
+	go func() {
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-observe:
+				if !ok {
+					return
+				}
+				if item.Error() {
+					fail(item.E)
+					continue
+				}
+				if filter != nil && !filter(item.V) {
+					item.Ack()
+					continue
+				}
+				if transform != nil {
+					var err error
+					if item.V, err = transform(ctx, item.V); err != nil {
+						fail(err)
+						continue
+					}
+				}
+				dst.NextItem(item)
+			}
+		}
+	}()
+	return Disposable(cancel)
+}
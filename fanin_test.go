@@ -0,0 +1,156 @@
+package rxgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestSubject_SubscribeTo(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	subject.SubscribeTo(Just(1, 2)(), Just(3)())
+	observer.AwaitDone(time.Second)
+	assert.ElementsMatch(t, []interface{}{1, 2, 3}, observer.Values())
+	observer.AssertNoErrors()
+}
+
+func TestReplaySubject_SubscribeTo(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewReplaySubject(10)
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs, WithBufferedChannel(10))
+
+	subject.SubscribeTo(Just(1, 2)())
+	observer.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{1, 2}, observer.Values())
+	// the forwarded values are held for replay
+	assert.Equal(t, []interface{}{1, 2}, subject.PeekAll())
+}
+
+func TestSubject_SubscribeTo_CompleteOnFirst(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject(WithTerminalPolicy(CompleteOnFirst))
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	// the source never completing is disposed of
+	subject.SubscribeTo(Just(1)(), never(Of(0)))
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(1)
+	observer.AssertCompleted()
+}
+
+func TestSubject_SubscribeTo_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	subject.SubscribeTo(Thrown(errFoo), never(Of(0)))
+	observer.AwaitDone(time.Second)
+	observer.AssertError(errFoo)
+	assert.Equal(t, errFoo, subject.Err())
+}
+
+func TestSubject_SubscribeTo_Dispose(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+	src := make(chan Item)
+
+	dispose := subject.SubscribeTo(FromChannel(src))
+	src <- Of(1)
+	observer.AwaitCount(1, time.Second)
+	dispose()
+
+	// the subject is not terminated
+	subject.Next(2)
+	observer.AwaitCount(2, time.Second)
+	subject.Complete()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(1, 2)
+	close(src)
+}
+
+func TestForward(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	module := NewSubject()
+	bus := NewSubject()
+	_, src := module.Subscribe()
+	_, obs := bus.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	Forward(src, bus, WithForwardFilter(func(i interface{}) bool {
+		return i.(int) > 1
+	}), WithForwardTransform(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i.(int) * 10, nil
+	}))
+	module.Next(1)
+	module.Next(2)
+	module.Next(3)
+	module.Complete()
+
+	observer.AwaitCount(2, time.Second)
+	// the bus is not completed by the module
+	observer.AssertNotCompleted()
+	bus.Complete()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(20, 30)
+}
+
+func TestForward_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	bus := NewSubject()
+	_, obs := bus.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	Forward(Just(1, errFoo, 2)(), bus)
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(1)
+	assert.Equal(t, errFoo, bus.Err())
+}
+
+func TestForward_ErrorIsolation(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	bus := NewSubject()
+	_, obs := bus.Subscribe()
+	observer := NewTestObserver(t, obs, WithErrorStrategy(ContinueOnError))
+
+	Forward(Just(1, 2)(), bus, WithForwardTransform(func(_ context.Context, i interface{}) (interface{}, error) {
+		if i == 1 {
+			return nil, errFoo
+		}
+		return i, nil
+	}), WithErrorIsolation())
+	observer.AwaitCount(1, time.Second)
+	bus.Complete()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(2)
+	assert.Equal(t, []error{errFoo}, observer.Errors())
+	assert.NoError(t, bus.Err())
+}
+
+func TestForward_Dispose(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	bus := NewSubject()
+	_, obs := bus.Subscribe()
+	observer := NewTestObserver(t, obs)
+	src := make(chan Item)
+
+	dispose := Forward(FromChannel(src), bus)
+	src <- Of(1)
+	observer.AwaitCount(1, time.Second)
+	dispose()
+	bus.Complete()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(1)
+	close(src)
+}
@@ -0,0 +1,137 @@
+package rxgo
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// fanoutQueueSize is the capacity of each fan-out shard queue
+const fanoutQueueSize = 64
+
+// shardedFanout delivers the items of a subject with a fixed number of shards. Subscribers are spread over
+// the shards, each of them having its own goroutine and lock, so that publishing an item costs one send per
+// shard instead of one per subscriber.
+type shardedFanout struct {
+	mu      sync.RWMutex
+	stopped bool
+	subject *Subject
+	shards  []*fanoutShard
+	wg      sync.WaitGroup
+}
+
+type fanoutShard struct {
+	mu          sync.Mutex
+	subscribers atomic.Value // []*subscriberState
+	queue       chan shardDelivery
+}
+
+// shardDelivery is an item to deliver to the subscribers of a shard at the time it was published,
+// or the closing of a subscriber
+type shardDelivery struct {
+	subscribers []*subscriberState
+	item        Item
+	close       bool
+}
+
+func newShardedFanout(subject *Subject, shards int) *shardedFanout {
+	f := &shardedFanout{
+		subject: subject,
+		shards:  make([]*fanoutShard, shards),
+	}
+	for i := range f.shards {
+		shard := &fanoutShard{
+			queue: make(chan shardDelivery, fanoutQueueSize),
+		}
+		f.shards[i] = shard
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			for d := range shard.queue {
+				for _, subscriber := range d.subscribers {
+					if d.close {
+						subscriber.close()
+					} else {
+						subject.send(subscriber, d.item)
+					}
+				}
+			}
+		}()
+	}
+	return f
+}
+
+// dispatch queues an item for all subscribers
+func (f *shardedFanout) dispatch(item Item) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.stopped {
+		return
+	}
+	for _, shard := range f.shards {
+		if subscribers := shard.load(); len(subscribers) > 0 {
+			shard.queue <- shardDelivery{subscribers: subscribers, item: item}
+		}
+	}
+}
+
+// add adds a subscriber to its shard
+func (f *shardedFanout) add(subscriber *subscriberState) {
+	shard := f.shards[subscriber.id%len(f.shards)]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	current := shard.load()
+	subscribers := make([]*subscriberState, 0, len(current)+1)
+	subscribers = append(subscribers, current...)
+	shard.subscribers.Store(append(subscribers, subscriber))
+}
+
+// closeSubscriber removes a subscriber from its shard and queues its closing, after its pending items
+func (f *shardedFanout) closeSubscriber(subscriber *subscriberState) {
+	shard := f.shards[subscriber.id%len(f.shards)]
+	shard.mu.Lock()
+	current := shard.load()
+	subscribers := make([]*subscriberState, 0, len(current))
+	for _, s := range current {
+		if s != subscriber {
+			subscribers = append(subscribers, s)
+		}
+	}
+	shard.subscribers.Store(subscribers)
+	shard.mu.Unlock()
+
+	shard.queue <- shardDelivery{subscribers: []*subscriberState{subscriber}, close: true}
+}
+
+// queued returns the number of pending deliveries
+func (f *shardedFanout) queued() int {
+	n := 0
+	for _, shard := range f.shards {
+		n += len(shard.queue)
+	}
+	return n
+}
+
+// stop stops the goroutines once the pending deliveries are done. The delivery pool the shards hand over to,
+// if any, is stopped afterwards.
+func (f *shardedFanout) stop(pool *deliveryPool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.stopped = true
+	for _, shard := range f.shards {
+		close(shard.queue)
+	}
+	if pool != nil {
+		go func() {
+			f.wg.Wait()
+			pool.stop()
+		}()
+	}
+}
+
+func (s *fanoutShard) load() []*subscriberState {
+	subscribers, _ := s.subscribers.Load().([]*subscriberState)
+	return subscribers
+}
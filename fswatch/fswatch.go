@@ -0,0 +1,247 @@
+// Package fswatch provides an Observable of file changes, and an Observable of the lines appended to a file.
+//
+// The package has no dependency on a file notification library: the watched path is scanned
+// periodically, which works on every platform and file system, network ones included, at the cost of
+// a detection latency of one scan interval.
+package fswatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+)
+
+const (
+	defaultInterval = 100 * time.Millisecond
+	defaultDebounce = 100 * time.Millisecond
+)
+
+// Op is the kind of a file change.
+type Op int
+
+const (
+	// Create is the creation of a file.
+	Create Op = iota
+	// Write is the modification of a file.
+	Write
+	// Remove is the removal of a file.
+	Remove
+)
+
+func (op Op) String() string {
+	switch op {
+	case Create:
+		return "CREATE"
+	case Write:
+		return "WRITE"
+	case Remove:
+		return "REMOVE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Event is a file change.
+type Event struct {
+	// Path is the path of the changed file.
+	Path string
+	// Op is the kind of change. A file created then written within the debounce time is a creation.
+	Op Op
+}
+
+// Option configures WatchPath and TailFile.
+type Option func(*config)
+
+type config struct {
+	interval  time.Duration
+	debounce  time.Duration
+	clock     rxgo.Clock
+	fromStart bool
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		interval: defaultInterval,
+		debounce: defaultDebounce,
+		clock:    rxgo.SystemClock(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithInterval sets the interval between two scans of the watched path or file, 100ms by default.
+func WithInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.interval = interval
+	}
+}
+
+// WithDebounce sets the time without change after which the changes of a file are emitted as a single
+// event, 100ms by default. Zero emits the changes of each scan.
+func WithDebounce(debounce time.Duration) Option {
+	return func(c *config) {
+		c.debounce = debounce
+	}
+}
+
+// WithClock sets the clock scheduling the scans and measuring the debounce time, the system clock by
+// default. It is meant to pass an rxgo.TestScheduler in tests.
+func WithClock(clock rxgo.Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// WithFromStart makes TailFile emit the lines already in the file, instead of only the lines appended after.
+func WithFromStart() Option {
+	return func(c *config) {
+		c.fromStart = true
+	}
+}
+
+// fileState is the state of a file at a scan
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// pendingEvent is an event waiting for the end of its debounce time
+type pendingEvent struct {
+	event   Event
+	changed time.Time
+}
+
+// WatchPath creates an Observable emitting an Event for each change of a file, or of a file of a directory,
+// excluding its sub-directories. The changes made within the debounce time of each other are emitted as a
+// single event. The Observable completes once the context is done, and emits an error if the path cannot be
+// scanned.
+func WatchPath(ctx context.Context, path string, opts ...Option) rxgo.Observable {
+	c := newConfig(opts)
+
+	return rxgo.CreateWithEmitter(func(ctx context.Context, emitter rxgo.Emitter) {
+		files, err := scan(path)
+		if err != nil {
+			emitter.Error(err)
+			return
+		}
+
+		ticker := c.clock.NewTicker(c.interval)
+		defer ticker.Stop()
+		pending := make(map[string]*pendingEvent)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C():
+				current, err := scan(path)
+				if err != nil {
+					emitter.Error(err)
+					return
+				}
+				for _, event := range diff(files, current) {
+					if p, exists := pending[event.Path]; exists {
+						p.changed = now
+						p.event.Op = merge(p.event.Op, event.Op)
+					} else {
+						pending[event.Path] = &pendingEvent{event: event, changed: now}
+					}
+				}
+				files = current
+
+				for _, event := range due(pending, now, c.debounce) {
+					emitter.Next(event)
+				}
+			}
+		}
+	}, rxgo.WithContext(ctx))
+}
+
+// scan returns the state of the file at path, or of the files of the directory at path
+func scan(path string) (map[string]fileState, error) {
+	files := make(map[string]fileState)
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return files, nil
+		}
+		return nil, err
+	}
+	if !info.IsDir() {
+		files[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+		return files, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// removed since listed
+			continue
+		}
+		files[filepath.Join(path, entry.Name())] = fileState{size: info.Size(), modTime: info.ModTime()}
+	}
+	return files, nil
+}
+
+// diff returns the changes between two scans
+func diff(previous, current map[string]fileState) []Event {
+	events := make([]Event, 0)
+	for path, state := range current {
+		before, exists := previous[path]
+		switch {
+		case !exists:
+			events = append(events, Event{Path: path, Op: Create})
+		case before != state:
+			events = append(events, Event{Path: path, Op: Write})
+		}
+	}
+	for path := range previous {
+		if _, exists := current[path]; !exists {
+			events = append(events, Event{Path: path, Op: Remove})
+		}
+	}
+	return events
+}
+
+// merge returns the operation of two successive debounced changes of a file
+func merge(first, second Op) Op {
+	switch {
+	case first == Create && second == Remove:
+		// created and removed: seen as removed, the file may have existed before the watch
+		return Remove
+	case first == Create:
+		return Create
+	case first == Remove && second != Remove:
+		// removed and created again
+		return Write
+	default:
+		return second
+	}
+}
+
+// due removes and returns the pending events without change for the debounce time, in path order
+func due(pending map[string]*pendingEvent, now time.Time, debounce time.Duration) []Event {
+	events := make([]Event, 0)
+	for path, p := range pending {
+		if now.Sub(p.changed) >= debounce {
+			events = append(events, p.event)
+			delete(pending, path)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Path < events[j].Path
+	})
+	return events
+}
@@ -0,0 +1,90 @@
+package fswatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestWatchPath(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	observer := rxgo.NewTestObserver(t, WatchPath(ctx, dir, WithInterval(5*time.Millisecond), WithDebounce(30*time.Millisecond)))
+	time.Sleep(10 * time.Millisecond)
+
+	// created and written within the debounce time
+	assert.NoError(t, os.WriteFile(path, []byte("{}"), 0o644))
+	assert.NoError(t, os.WriteFile(path, []byte(`{"a": 1}`), 0o644))
+	observer.AwaitCount(1, time.Second)
+
+	assert.NoError(t, os.Remove(path))
+	observer.AwaitCount(2, time.Second)
+
+	cancel()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(Event{Path: path, Op: Create}, Event{Path: path, Op: Remove})
+	observer.AssertNoErrors()
+}
+
+func TestWatchPath_File(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{}"), 0o644))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	observer := rxgo.NewTestObserver(t, WatchPath(ctx, path, WithInterval(5*time.Millisecond), WithDebounce(0)))
+	time.Sleep(10 * time.Millisecond)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"a": 1}`), 0o644))
+	observer.AwaitCount(1, time.Second)
+
+	cancel()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(Event{Path: path, Op: Write})
+}
+
+func TestWatchPath_Clock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	start := time.Now()
+	scheduler := rxgo.NewTestScheduler(start)
+
+	observer := rxgo.NewTestObserver(t, WatchPath(ctx, dir, WithInterval(time.Second), WithDebounce(2*time.Second),
+		WithClock(scheduler)))
+	// the path was scanned once the ticker is created
+	scheduler.BlockUntil(1)
+
+	assert.NoError(t, os.WriteFile(path, []byte("{}"), 0o644))
+	for len(observer.Values()) == 0 {
+		scheduler.Advance(time.Second)
+		time.Sleep(time.Millisecond)
+	}
+	// detected by the first scan, then emitted once the debounce time elapsed
+	assert.True(t, scheduler.Now().Sub(start) >= 3*time.Second)
+
+	cancel()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(Event{Path: path, Op: Create})
+}
+
+func TestMerge(t *testing.T) {
+	assert.Equal(t, Create, merge(Create, Write))
+	assert.Equal(t, Remove, merge(Create, Remove))
+	assert.Equal(t, Write, merge(Remove, Create))
+	assert.Equal(t, Write, merge(Write, Write))
+	assert.Equal(t, Remove, merge(Write, Remove))
+}
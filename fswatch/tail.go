@@ -0,0 +1,169 @@
+package fswatch
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+
+	"github.com/reactivex/rxgo/v2"
+)
+
+// tailChunkSize is the size of the chunks the appended data is read by
+const tailChunkSize = 32 * 1024
+
+// TailFile creates an Observable emitting the lines appended to a file, without their line terminator, like
+// tail -f. A line is emitted once terminated. The file is scanned every interval:
+//   - a rotated file, renamed or removed then created again at path, is read until its end, then the new
+//     file is followed from its beginning
+//   - a truncated file is followed from its beginning
+//
+// The Observable completes once the context is done, and emits an error if the file cannot be opened or read.
+func TailFile(ctx context.Context, path string, opts ...Option) rxgo.Observable {
+	c := newConfig(opts)
+
+	return rxgo.CreateWithEmitter(func(ctx context.Context, emitter rxgo.Emitter) {
+		t, err := openTail(path, c.fromStart)
+		if err != nil {
+			emitter.Error(err)
+			return
+		}
+		defer t.close()
+
+		emit := func(line string) {
+			emitter.Next(line)
+		}
+		ticker := c.clock.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			if err := t.poll(emit); err != nil {
+				emitter.Error(err)
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
+		}
+	}, rxgo.WithContext(ctx))
+}
+
+// tail follows the lines appended to a file
+type tail struct {
+	path   string
+	file   *os.File
+	info   os.FileInfo
+	offset int64
+	// partial is the last line read, not terminated yet
+	partial []byte
+	chunk   []byte
+}
+
+func openTail(path string, fromStart bool) (*tail, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	t := &tail{
+		path:  path,
+		file:  file,
+		chunk: make([]byte, tailChunkSize),
+	}
+	if t.info, err = file.Stat(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if !fromStart {
+		if t.offset, err = file.Seek(0, io.SeekEnd); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t *tail) close() {
+	_ = t.file.Close()
+}
+
+// poll emits the lines appended since the last poll, following a rotated or truncated file
+func (t *tail) poll(emit func(string)) error {
+	if err := t.read(emit); err != nil {
+		return err
+	}
+	info, err := os.Stat(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// rotated, the new file not being created yet
+			return nil
+		}
+		return err
+	}
+
+	if !os.SameFile(info, t.info) {
+		file, err := os.Open(t.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info, err = file.Stat(); err != nil {
+			_ = file.Close()
+			return err
+		}
+		// the rotated file was read until its end, its last line is over
+		if len(t.partial) > 0 {
+			emit(string(t.partial))
+			t.partial = t.partial[:0]
+		}
+		t.close()
+		t.file = file
+		t.info = info
+		t.offset = 0
+		return t.read(emit)
+	}
+
+	if info.Size() < t.offset {
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		t.offset = 0
+		t.partial = t.partial[:0]
+		return t.read(emit)
+	}
+	return nil
+}
+
+// read emits the lines read until the end of the file, by chunks
+func (t *tail) read(emit func(string)) error {
+	for {
+		n, err := t.file.Read(t.chunk)
+		if n > 0 {
+			t.offset += int64(n)
+			t.split(t.chunk[:n], emit)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// split emits the lines terminated by a chunk, keeping the rest as partial
+func (t *tail) split(chunk []byte, emit func(string)) {
+	for {
+		i := bytes.IndexByte(chunk, '\n')
+		if i < 0 {
+			t.partial = append(t.partial, chunk...)
+			return
+		}
+		line := append(t.partial, chunk[:i]...)
+		emit(string(bytes.TrimSuffix(line, []byte{'\r'})))
+		t.partial = line[:0]
+		chunk = chunk[i+1:]
+	}
+}
@@ -0,0 +1,112 @@
+package fswatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func appendFile(t *testing.T, path, data string) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
+	assert.NoError(t, err)
+	_, err = f.WriteString(data)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+}
+
+func TestTailFile(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, path, "before\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	observer := rxgo.NewTestObserver(t, TailFile(ctx, path, WithInterval(5*time.Millisecond)))
+	time.Sleep(20 * time.Millisecond)
+
+	appendFile(t, path, "a\nb")
+	observer.AwaitCount(1, time.Second)
+	// emitted once terminated
+	appendFile(t, path, "c\r\nd\n")
+	observer.AwaitCount(3, time.Second)
+
+	cancel()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("a", "bc", "d")
+	observer.AssertNoErrors()
+}
+
+func TestTailFile_FromStart(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, path, "a\nb\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	observer := rxgo.NewTestObserver(t, TailFile(ctx, path, WithInterval(5*time.Millisecond), WithFromStart()))
+	observer.AwaitCount(2, time.Second)
+
+	cancel()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("a", "b")
+}
+
+func TestTailFile_Rotation(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	observer := rxgo.NewTestObserver(t, TailFile(ctx, path, WithInterval(5*time.Millisecond)))
+	time.Sleep(20 * time.Millisecond)
+
+	appendFile(t, path, "a\n")
+	observer.AwaitCount(1, time.Second)
+	// written before the rotation is detected
+	appendFile(t, path, "b")
+	assert.NoError(t, os.Rename(path, filepath.Join(dir, "app.log.1")))
+	appendFile(t, path, "c\n")
+	observer.AwaitCount(3, time.Second)
+
+	cancel()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("a", "b", "c")
+}
+
+func TestTailFile_Truncation(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, path, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	observer := rxgo.NewTestObserver(t, TailFile(ctx, path, WithInterval(5*time.Millisecond)))
+	time.Sleep(20 * time.Millisecond)
+
+	appendFile(t, path, "first line\n")
+	observer.AwaitCount(1, time.Second)
+	assert.NoError(t, os.Truncate(path, 0))
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, "b\n")
+	observer.AwaitCount(2, time.Second)
+
+	cancel()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("first line", "b")
+}
+
+func TestTailFile_Missing(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	observer := rxgo.NewTestObserver(t, TailFile(context.Background(), filepath.Join(t.TempDir(), "missing.log")))
+	observer.AwaitDone(time.Second)
+	assert.Len(t, observer.Errors(), 1)
+	assert.True(t, os.IsNotExist(observer.Errors()[0]))
+}
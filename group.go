@@ -0,0 +1,202 @@
+package rxgo
+
+import (
+	"sync"
+	"time"
+)
+
+// subscriptionGroup distributes the items of a subject over its members, each item being delivered to a
+// single member
+type subscriptionGroup struct {
+	mu        sync.Mutex
+	name      string
+	members   []*subscriberState
+	balancing GroupBalancing
+	// next is the index of the next member in turn
+	next int
+	// keys holds the member assigned to each partition key, selected by keyOf
+	keys  StateStore
+	keyOf func(interface{}) interface{}
+}
+
+// publish delivers each item to the member picked by the balancing. A terminal item is delivered to all the
+// members.
+func (g *subscriptionGroup) publish(s *Subject, terminal bool, items []Item) {
+	for _, item := range items {
+		if terminal {
+			for _, member := range g.load() {
+				s.send(member, item)
+			}
+			continue
+		}
+		if member := g.pick(item); member != nil {
+			member.sent.add(s.option.getClock().Now(), member.queued())
+			s.send(member, item)
+		}
+	}
+}
+
+// load returns a copy of the members
+func (g *subscriptionGroup) load() []*subscriberState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return append([]*subscriberState(nil), g.members...)
+}
+
+// pick returns the member receiving an item, nil if the group has no member. An item with a partition key
+// goes to the member assigned to its key, the key being assigned to the member picked by the balancing if
+// needed.
+func (g *subscriptionGroup) pick(item Item) *subscriberState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.members) == 0 {
+		return nil
+	}
+	if item = unwrapped(item); g.keys == nil || item.Error() {
+		return g.balanced()
+	}
+	key := g.keyOf(item.V)
+	if member, exists := g.keys.Get(key); exists {
+		return member.(*subscriberState)
+	}
+	member := g.balanced()
+	g.keys.Put(key, member)
+	return member
+}
+
+// balanced returns the member picked by the balancing. The caller holds mu.
+func (g *subscriptionGroup) balanced() *subscriberState {
+	picked := g.next % len(g.members)
+	if g.balancing == LeastLoaded {
+		// the ties are broken in turn
+		least := -1
+		for i := range g.members {
+			index := (g.next + i) % len(g.members)
+			if queued := g.members[index].queued(); least < 0 || queued < least {
+				picked, least = index, queued
+			}
+		}
+	}
+	g.next = picked + 1
+	return g.members[picked]
+}
+
+// add adds a member, the balancing and the partition key being set by the latest member
+func (g *subscriptionGroup) add(subscriber *subscriberState, option Option) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.members = append(g.members, subscriber)
+	g.balancing = option.getGroupBalancing()
+	if g.keyOf = option.getPartitionKey(); g.keyOf == nil {
+		g.keys = nil
+	} else if g.keys == nil {
+		g.keys = newStateStore(option)
+	}
+}
+
+// remove removes a member, returning whether the group is empty. The keys assigned to the member are
+// reassigned by their next item.
+func (g *subscriptionGroup) remove(subscriber *subscriberState) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i, member := range g.members {
+		if member == subscriber {
+			g.members = append(g.members[:i:i], g.members[i+1:]...)
+			break
+		}
+	}
+	if g.keys != nil {
+		var keys []interface{}
+		g.keys.Range(func(key, member interface{}) bool {
+			if member == subscriber {
+				keys = append(keys, key)
+			}
+			return true
+		})
+		for _, key := range keys {
+			g.keys.Delete(key)
+		}
+	}
+	return len(g.members) == 0
+}
+
+// stats returns the lag of the members
+func (g *subscriptionGroup) stats(now time.Time) []ConsumerStats {
+	members := g.load()
+	stats := make([]ConsumerStats, 0, len(members))
+	for _, member := range members {
+		queued := member.queued()
+		consumer := ConsumerStats{Name: g.name, Subscriber: member.id, Undelivered: queued}
+		if oldest, exists := member.sent.oldest(queued); exists {
+			consumer.OldestPending = now.Sub(oldest)
+		}
+		stats = append(stats, consumer)
+	}
+	return stats
+}
+
+// sendTimes records the times the items were sent to a group member. As a member receives its items in
+// order, the items queued for it are the latest ones sent.
+type sendTimes struct {
+	mu    sync.Mutex
+	times []time.Time
+}
+
+// add records the time of an item sent to a member for which queued items are queued, only the times of
+// the queued items and of the item being delivered being kept
+func (t *sendTimes) add(now time.Time, queued int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if excess := len(t.times) - queued - 1; excess > 0 {
+		t.times = t.times[excess:]
+	}
+	t.times = append(t.times, now)
+}
+
+// oldest returns the time the oldest of the queued items was sent, if any
+func (t *sendTimes) oldest(queued int) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if queued == 0 || len(t.times) == 0 {
+		return time.Time{}, false
+	}
+	if queued > len(t.times) {
+		queued = len(t.times)
+	}
+	return t.times[len(t.times)-queued], true
+}
+
+// SubscribeGroup adds a member to the subscription group identified by name, creating the group if needed.
+// Unlike the other subscribers, the members of a group share its items: each item is delivered to a single
+// member, picked in turn or, with WithGroupBalancing(LeastLoaded), the one with the fewest items waiting to be
+// delivered, so that the subject distributes work over parallel workers. A terminal error and the completion
+// are delivered to all the members.
+//
+// With WithPartitionKey, the items with the same key are delivered to the same member, so that the items of
+// an entity are processed in order. A key is assigned to a member by the balancing, and reassigned once the
+// member unsubscribes.
+//
+// The items queued for a member which unsubscribes are discarded, and the items published while the group
+// has no member are not delivered to it.
+func (s *Subject) SubscribeGroup(name string, opts ...Option) (Subscription, Observable) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.groups == nil {
+		s.groups = make(map[string]*subscriptionGroup)
+	}
+	group, exists := s.groups[name]
+	if !exists {
+		group = &subscriptionGroup{name: name}
+		s.groups[name] = group
+	}
+	sub, obs := s.subscribe(0, nil, group, nil)
+	group.add(s.subscribers[sub.GetId()], parseOptions(opts...))
+	return sub, obs
+}
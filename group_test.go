@@ -0,0 +1,134 @@
+package rxgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestSubject_SubscribeGroup(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	_, first := subject.SubscribeGroup("workers")
+	_, second := subject.SubscribeGroup("workers")
+	_, all := subject.Subscribe()
+	firsts := NewTestObserver(t, first, WithBufferedChannel(10))
+	seconds := NewTestObserver(t, second, WithBufferedChannel(10))
+	alls := NewTestObserver(t, all, WithBufferedChannel(10))
+
+	for i := 1; i <= 4; i++ {
+		subject.Next(i)
+	}
+	subject.Error(errFoo)
+
+	for _, observer := range []*TestObserver{firsts, seconds, alls} {
+		observer.AwaitDone(time.Second)
+		// the error is delivered to all the members
+		assert.Equal(t, []error{errFoo}, observer.Errors())
+	}
+	assert.Equal(t, []interface{}{1, 3}, firsts.Values())
+	assert.Equal(t, []interface{}{2, 4}, seconds.Values())
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, alls.Values())
+}
+
+func TestSubject_SubscribeGroup_LeastLoaded(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	_, slow := subject.SubscribeGroup("workers", WithGroupBalancing(LeastLoaded))
+	_, fast := subject.SubscribeGroup("workers", WithGroupBalancing(LeastLoaded))
+	slows := slow.Observe(WithBufferedChannel(10))
+	fasts := NewTestObserver(t, fast, WithBufferedChannel(10))
+
+	subject.Next(1)
+	assert.Eventually(t, func() bool {
+		return len(slows) == 1
+	}, time.Second, time.Millisecond)
+	for i := 2; i <= 5; i++ {
+		subject.Next(i)
+		fasts.AwaitCount(i-1, time.Second)
+	}
+	subject.Complete()
+
+	fasts.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{2, 3, 4, 5}, fasts.Values())
+	assert.Equal(t, 1, (<-slows).V)
+}
+
+func TestSubject_SubscribeGroup_Unsubscribe(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	left, _ := subject.SubscribeGroup("workers")
+	_, remaining := subject.SubscribeGroup("workers")
+	observer := NewTestObserver(t, remaining, WithBufferedChannel(10))
+
+	left.Unsubscribe()
+	subject.Next(1)
+	subject.Next(2)
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{1, 2}, observer.Values())
+	assert.Empty(t, subject.groups)
+}
+
+func TestSubject_SubscribeGroup_PartitionKey(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	key := WithPartitionKey(func(i interface{}) interface{} {
+		return i.([2]int)[0]
+	})
+	observers := make([]*TestObserver, 3)
+	for i := range observers {
+		_, obs := subject.SubscribeGroup("workers", key)
+		observers[i] = NewTestObserver(t, obs, WithBufferedChannel(100))
+	}
+
+	const keys, count = 5, 10
+	for i := 0; i < count; i++ {
+		for k := 0; k < keys; k++ {
+			subject.Next([2]int{k, i})
+		}
+	}
+	subject.Complete()
+
+	members := make(map[int]int)
+	for member, observer := range observers {
+		observer.AwaitDone(time.Second)
+		next := make(map[int]int)
+		for _, v := range observer.Values() {
+			item := v.([2]int)
+			if assigned, exists := members[item[0]]; exists {
+				assert.Equal(t, assigned, member)
+			}
+			members[item[0]] = member
+			// the items of a key are received in order
+			assert.Equal(t, next[item[0]], item[1])
+			next[item[0]]++
+		}
+	}
+	assert.Len(t, members, keys)
+}
+
+func TestSubject_SubscribeGroup_PartitionKey_Reassigned(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	identity := WithPartitionKey(func(i interface{}) interface{} {
+		return i
+	})
+	left, _ := subject.SubscribeGroup("workers", identity)
+	_, remaining := subject.SubscribeGroup("workers", identity)
+	observer := NewTestObserver(t, remaining, WithBufferedChannel(10))
+
+	subject.Next(1)
+	subject.Next(2)
+	subject.Next(2)
+	left.Unsubscribe()
+	// the key of the member which left is reassigned
+	subject.Next(1)
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{2, 2, 1}, observer.Values())
+}
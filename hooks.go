@@ -0,0 +1,136 @@
+package rxgo
+
+import "sync"
+
+type (
+	// SubscribeHook is called each time an observer subscribes to a subject.
+	SubscribeHook func(subject ISubject, id int)
+	// NextHook is called each time an item is published by a subject.
+	NextHook func(subject ISubject, item Item)
+	// ErrorHook is called each time a subject is given an error.
+	ErrorHook func(subject ISubject, err error)
+	// DropHook is called each time an item is dropped for a subscriber because of the Drop back pressure strategy.
+	DropHook func(subject ISubject, id int, item Item)
+	// ModeChangeHook is called each time a subscriber with the Adaptive back pressure strategy changes mode.
+	ModeChangeHook func(subject ISubject, id int, from, to AdaptiveMode)
+)
+
+type hookKind uint32
+
+const (
+	subscribeHookKind hookKind = iota
+	nextHookKind
+	errorHookKind
+	dropHookKind
+	modeChangeHookKind
+)
+
+// hookRegistry holds the package-level hooks
+type hookRegistry struct {
+	sync.RWMutex
+	nextID int
+	hooks  map[hookKind]map[int]interface{}
+}
+
+var globalHooks = &hookRegistry{
+	hooks: make(map[hookKind]map[int]interface{}),
+}
+
+// OnSubscribeHook registers a hook called on every subject subscription.
+// Hooks are called synchronously and must not call back into the subject.
+// The returned Disposable removes the hook.
+func OnSubscribeHook(hook SubscribeHook) Disposable {
+	return globalHooks.register(subscribeHookKind, hook)
+}
+
+// OnNextHook registers a hook called on every item published by a subject.
+// Hooks are called synchronously and must not call back into the subject.
+// The returned Disposable removes the hook.
+func OnNextHook(hook NextHook) Disposable {
+	return globalHooks.register(nextHookKind, hook)
+}
+
+// OnErrorHook registers a hook called on every subject error.
+// Hooks are called synchronously and must not call back into the subject.
+// The returned Disposable removes the hook.
+func OnErrorHook(hook ErrorHook) Disposable {
+	return globalHooks.register(errorHookKind, hook)
+}
+
+// OnDropHook registers a hook called on every item dropped for a subject subscriber.
+// Hooks are called synchronously and must not call back into the subject.
+// The returned Disposable removes the hook.
+func OnDropHook(hook DropHook) Disposable {
+	return globalHooks.register(dropHookKind, hook)
+}
+
+// OnModeChangeHook registers a hook called on every mode change of a subject subscriber with the Adaptive
+// back pressure strategy.
+// Hooks are called synchronously and must not call back into the subject.
+// The returned Disposable removes the hook.
+func OnModeChangeHook(hook ModeChangeHook) Disposable {
+	return globalHooks.register(modeChangeHookKind, hook)
+}
+
+func (r *hookRegistry) register(kind hookKind, hook interface{}) Disposable {
+	r.Lock()
+	defer r.Unlock()
+
+	id := r.nextID
+	r.nextID++
+	if r.hooks[kind] == nil {
+		r.hooks[kind] = make(map[int]interface{})
+	}
+	r.hooks[kind][id] = hook
+
+	return func() {
+		r.Lock()
+		defer r.Unlock()
+		delete(r.hooks[kind], id)
+	}
+}
+
+// get returns a copy of the hooks of a kind, so that they can be called without holding the lock
+func (r *hookRegistry) get(kind hookKind) []interface{} {
+	r.RLock()
+	defer r.RUnlock()
+
+	if len(r.hooks[kind]) == 0 {
+		return nil
+	}
+	res := make([]interface{}, 0, len(r.hooks[kind]))
+	for _, hook := range r.hooks[kind] {
+		res = append(res, hook)
+	}
+	return res
+}
+
+func (r *hookRegistry) subscribed(subject ISubject, id int) {
+	for _, hook := range r.get(subscribeHookKind) {
+		hook.(SubscribeHook)(subject, id)
+	}
+}
+
+func (r *hookRegistry) published(subject ISubject, item Item) {
+	for _, hook := range r.get(nextHookKind) {
+		hook.(NextHook)(subject, item)
+	}
+}
+
+func (r *hookRegistry) failed(subject ISubject, err error) {
+	for _, hook := range r.get(errorHookKind) {
+		hook.(ErrorHook)(subject, err)
+	}
+}
+
+func (r *hookRegistry) dropped(subject ISubject, id int, item Item) {
+	for _, hook := range r.get(dropHookKind) {
+		hook.(DropHook)(subject, id, item)
+	}
+}
+
+func (r *hookRegistry) modeChanged(subject ISubject, id int, from, to AdaptiveMode) {
+	for _, hook := range r.get(modeChangeHookKind) {
+		hook.(ModeChangeHook)(subject, id, from, to)
+	}
+}
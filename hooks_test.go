@@ -0,0 +1,64 @@
+package rxgo
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHooks verifies hooks are called for subject events until they are disposed
+func TestHooks(t *testing.T) {
+	var mutex sync.Mutex
+	events := make([]string, 0)
+	record := func(event string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		events = append(events, event)
+	}
+
+	disposeSubscribe := OnSubscribeHook(func(subject ISubject, id int) {
+		record("subscribe")
+	})
+	disposeNext := OnNextHook(func(subject ISubject, item Item) {
+		record("next")
+	})
+	disposeError := OnErrorHook(func(subject ISubject, err error) {
+		record("error")
+	})
+
+	subject := NewSubject()
+	_, obs := subject.Subscribe()
+	done := obs.DoOnError(func(err error) {})
+	subject.Next(1)
+	subject.Error(errFoo)
+	<-done
+
+	disposeSubscribe()
+	disposeNext()
+	disposeError()
+
+	subject.Subscribe()
+	subject.Next(2)
+	subject.Complete()
+
+	assert.Equal(t, []string{"subscribe", "next", "error"}, events)
+}
+
+// TestDropHook verifies the drop hook is called when an item is dropped for a slow subscriber
+func TestDropHook(t *testing.T) {
+	dropped := make(chan Item, 1)
+	dispose := OnDropHook(func(subject ISubject, id int, item Item) {
+		dropped <- item
+	})
+	defer dispose()
+
+	subject := NewSubject(WithBackPressureStrategy(Drop))
+	defer subject.Complete()
+	_, obs := subject.Subscribe()
+	// observer never reads
+	obs.Observe()
+
+	subject.Next(1)
+	assert.Equal(t, Of(1), <-dropped)
+}
@@ -9,8 +9,9 @@ import (
 type (
 	// Item is a wrapper having either a value or an error.
 	Item struct {
-		V interface{}
-		E error
+		V   interface{}
+		E   error
+		ack acknowledger
 	}
 
 	// TimestampItem attach a timestamp to an item.
@@ -40,6 +41,36 @@ func Error(err error) Item {
 	return Item{E: err}
 }
 
+// Ack acknowledges an item received from a durable subscription, so that it is not redelivered.
+// It does nothing for other items.
+func (i Item) Ack() {
+	if i.ack != nil {
+		i.ack.ack()
+	}
+}
+
+// Nack rejects an item received from a durable subscription, so that it is redelivered right away.
+// It does nothing for other items.
+func (i Item) Nack() {
+	if n, ok := i.ack.(nacker); ok {
+		n.nack()
+	}
+}
+
+// WithAck returns a copy of the item whose Ack calls ack, so that sources can track the processing of their
+// items, like a message broker committing an offset.
+func (i Item) WithAck(ack func()) Item {
+	i.ack = ackFunc(ack)
+	return i
+}
+
+// ackFunc is an acknowledger calling a function
+type ackFunc func()
+
+func (f ackFunc) ack() {
+	f()
+}
+
 // SendItems is an utility function that send a list of interface{} and indicate a strategy on whether to close
 // the channel once the function completes.
 func SendItems(ctx context.Context, ch chan<- Item, strategy CloseChannelStrategy, items ...interface{}) {
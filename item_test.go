@@ -69,3 +69,14 @@ func Test_Item_SendNonBlocking(t *testing.T) {
 	assert.True(t, Of(5).SendNonBlocking(ch))
 	assert.False(t, Of(5).SendNonBlocking(ch))
 }
+
+func Test_Item_WithAck(t *testing.T) {
+	acked := 0
+	item := Of(5).WithAck(func() {
+		acked++
+	})
+	assert.Equal(t, 5, item.V)
+	item.Ack()
+	assert.Equal(t, 1, acked)
+	Of(5).Ack()
+}
@@ -11,6 +11,9 @@ type createIterable struct {
 	subscribers            []chan Item
 	mutex                  sync.RWMutex
 	producerAlreadyCreated bool
+	// closed once the producer of the last connection stopped
+	producing <-chan struct{}
+	completed bool
 }
 
 func newCreateIterable(fs []Producer, opts ...Option) Iterable {
@@ -60,22 +63,56 @@ func (i *createIterable) connect(ctx context.Context) {
 	i.mutex.Unlock()
 }
 
-func (i *createIterable) produce(ctx context.Context) {
-	defer func() {
-		i.mutex.RLock()
-		for _, subscriber := range i.subscribers {
-			close(subscriber)
+func (i *createIterable) connectWithCancel(ctx context.Context) (Disposable, error) {
+	if !parseOptions(i.opts...).isConnectable() {
+		return nil, ErrNotConnectable
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if i.producerAlreadyCreated || i.completed {
+		return nil, ErrAlreadyConnected
+	}
+	i.producerAlreadyCreated = true
+	connection, cancel := context.WithCancel(ctx)
+	previous := i.producing
+	done := make(chan struct{})
+	i.producing = done
+
+	go func() {
+		defer close(done)
+		if previous != nil {
+			// the items are forwarded in order
+			<-previous
+		}
+		if i.forward(connection) || ctx.Err() != nil {
+			i.closeSubscribers()
 		}
-		i.mutex.RUnlock()
 	}()
 
+	return func() {
+		cancel()
+		i.mutex.Lock()
+		if i.producing == done && ctx.Err() == nil {
+			i.producerAlreadyCreated = false
+		}
+		i.mutex.Unlock()
+	}, nil
+}
+
+func (i *createIterable) produce(ctx context.Context) {
+	defer i.closeSubscribers()
+	i.forward(ctx)
+}
+
+// forward forwards the items to the subscribers until ctx is done, returning true if the source completed
+func (i *createIterable) forward(ctx context.Context) bool {
 	for {
 		select {
 		case <-ctx.Done():
-			return
+			return false
 		case item, ok := <-i.next:
 			if !ok {
-				return
+				return true
 			}
 			i.mutex.RLock()
 			for _, subscriber := range i.subscribers {
@@ -85,3 +122,16 @@ func (i *createIterable) produce(ctx context.Context) {
 		}
 	}
 }
+
+// closeSubscribers closes the subscribers once
+func (i *createIterable) closeSubscribers() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if i.completed {
+		return
+	}
+	i.completed = true
+	for _, subscriber := range i.subscribers {
+		close(subscriber)
+	}
+}
@@ -0,0 +1,99 @@
+package rxgo
+
+import (
+	"context"
+	"sync"
+)
+
+type emitterIterable struct {
+	f    func(ctx context.Context, emitter Emitter)
+	opts []Option
+}
+
+func newEmitterIterable(f func(ctx context.Context, emitter Emitter), opts ...Option) Iterable {
+	return &emitterIterable{
+		f:    f,
+		opts: opts,
+	}
+}
+
+func (i *emitterIterable) Observe(opts ...Option) <-chan Item {
+	option := parseOptions(append(i.opts, opts...)...)
+	next := option.buildChannel()
+	ctx, cancel := context.WithCancel(option.buildContext(emptyContext))
+
+	e := &emitter{
+		ctx:    ctx,
+		cancel: cancel,
+		next:   next,
+	}
+
+	go func() {
+		<-ctx.Done()
+		e.dispose()
+	}()
+	go i.f(ctx, e)
+
+	return next
+}
+
+// Emitter is used by CreateWithEmitter to push notifications to an Observer.
+// Calls after Error or Complete are ignored.
+type Emitter interface {
+	// Next emits a value, blocking until it is consumed or the emitter is disposed.
+	Next(value interface{})
+	// NextItem emits an item, blocking until it is consumed or the emitter is disposed. An error item
+	// terminates the Observable like Error.
+	NextItem(item Item)
+	// Error emits an error and terminates the Observable.
+	Error(err error)
+	// Complete terminates the Observable.
+	Complete()
+	// IsDisposed reports whether the Observable has terminated or its context was canceled.
+	IsDisposed() bool
+}
+
+type emitter struct {
+	mutex    sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	next     chan Item
+	disposed bool
+}
+
+func (e *emitter) Next(value interface{}) {
+	e.NextItem(Of(value))
+}
+
+func (e *emitter) NextItem(item Item) {
+	e.mutex.Lock()
+	if !e.disposed {
+		item.SendContext(e.ctx, e.next)
+	}
+	e.mutex.Unlock()
+	if item.Error() {
+		e.cancel()
+	}
+}
+
+func (e *emitter) Error(err error) {
+	e.NextItem(Error(err))
+}
+
+func (e *emitter) Complete() {
+	e.cancel()
+}
+
+func (e *emitter) IsDisposed() bool {
+	return e.ctx.Err() != nil
+}
+
+func (e *emitter) dispose() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if !e.disposed {
+		e.disposed = true
+		close(e.next)
+	}
+}
@@ -3,6 +3,8 @@ package rxgo
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type eventSourceIterable struct {
@@ -10,71 +12,236 @@ type eventSourceIterable struct {
 	observers []chan Item
 	disposed  bool
 	opts      []Option
+	done      chan struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+	strategy  BackpressureStrategy
+	listener  eventSourceListener
+	observed  chan struct{}
+	wakeOnce  sync.Once
+	// replacements are the channels successively replacing the one read by the delivery goroutine, which is
+	// notified by replaced
+	replaceMu    sync.Mutex
+	replacements []<-chan Item
+	replaced     chan struct{}
 }
 
-func newEventSourceIterable(ctx context.Context, next <-chan Item, strategy BackpressureStrategy, opts ...Option) Iterable {
-	it := &eventSourceIterable{
-		observers: make([]chan Item, 0),
-		opts:      opts,
-	}
+// eventSourceListener is notified of the observers and of the outcome of each item sent to them
+type eventSourceListener interface {
+	observed(stage string)
+	delivered(item Item)
+	dropped(item Item)
+	expired(item Item)
+}
 
-	go func() {
-		defer func() {
-			it.closeAllObservers()
-		}()
+// expiringItem is the value of an item holding another item with a time to live
+type expiringItem struct {
+	item     Item
+	deadline time.Time
+	clock    Clock
+}
 
-		deliver := func(item Item) (done bool) {
-			it.RLock()
-			defer it.RUnlock()
+// held returns the item held by an expiring item, with the acknowledgement of the expiring item
+func (e *expiringItem) held(item Item) Item {
+	held := e.item
+	held.ack = item.ack
+	return held
+}
 
-			switch strategy {
-			default:
-				fallthrough
-			case Block:
-				for _, observer := range it.observers {
-					if !item.SendContext(ctx, observer) {
-						return true
-					}
-				}
-			case Drop:
-				for _, observer := range it.observers {
+// withTTL returns an item holding the given item until the time to live elapsed
+func withTTL(item Item, ttl time.Duration, clock Clock) Item {
+	return Of(&expiringItem{item: item, deadline: clock.Now().Add(ttl), clock: clock})
+}
+
+// newEventSourceIterable creates a hot iterable. The optional listener is notified of each item delivered
+// to or dropped for an observer.
+func newEventSourceIterable(ctx context.Context, next <-chan Item, strategy BackpressureStrategy, listener eventSourceListener, opts ...Option) *eventSourceIterable {
+	it := newPassiveEventSourceIterable(ctx, strategy, listener, opts...)
+	go it.run(next)
+	return it
+}
+
+// newAwaitingEventSourceIterable creates a hot iterable which only starts reading the items once observed,
+// so that the first observer does not miss any item. It waits until observed or woken up with wake.
+func newAwaitingEventSourceIterable(ctx context.Context, next <-chan Item, strategy BackpressureStrategy, listener eventSourceListener, opts ...Option) *eventSourceIterable {
+	it := newPassiveEventSourceIterable(ctx, strategy, listener, opts...)
+	it.observed = make(chan struct{})
+	go it.run(next)
+	return it
+}
+
+func (i *eventSourceIterable) run(next <-chan Item) {
+	defer i.close()
+
+	if i.observed != nil {
+		select {
+		case <-i.ctx.Done():
+			return
+		case <-i.observed:
+		}
+	}
+	for {
+		select {
+		case <-i.ctx.Done():
+			return
+		case item, ok := <-next:
+			if !ok {
+				return
+			}
+
+			if done := i.deliver(item); done {
+				return
+			}
+		case <-i.replaced:
+			i.replaceMu.Lock()
+			replacements := i.replacements
+			i.replacements = nil
+			i.replaceMu.Unlock()
+			for _, replacement := range replacements {
+				// the items sent before a replacement are delivered first
+				for drained := false; !drained; {
 					select {
+					case item, ok := <-next:
+						if !ok {
+							drained = true
+						} else if done := i.deliver(item); done {
+							return
+						}
 					default:
-					case <-ctx.Done():
-						return true
-					case observer <- item:
+						drained = true
 					}
 				}
+				next = replacement
 			}
-			return
 		}
+	}
+}
 
-		for {
+// replace makes the delivery goroutine read the items from next, once the items sent to the current channel
+// are delivered. The items are then delivered with the given strategy. No item must be sent to the current
+// channel afterwards.
+func (i *eventSourceIterable) replace(next <-chan Item, strategy BackpressureStrategy) {
+	i.replaceMu.Lock()
+	i.replacements = append(i.replacements, next)
+	i.replaceMu.Unlock()
+	// set without the lock, held by the deliveries
+	atomic.StoreUint32((*uint32)(&i.strategy), uint32(strategy))
+
+	select {
+	case i.replaced <- struct{}{}:
+	default:
+	}
+}
+
+// wake makes an awaiting iterable start reading the items
+func (i *eventSourceIterable) wake() {
+	if i.observed != nil {
+		i.wakeOnce.Do(func() {
+			close(i.observed)
+		})
+	}
+}
+
+// newPassiveEventSourceIterable creates a hot iterable without goroutine: items are pushed with deliver
+// and the iterable is terminated with close.
+func newPassiveEventSourceIterable(ctx context.Context, strategy BackpressureStrategy, listener eventSourceListener, opts ...Option) *eventSourceIterable {
+	ctx, cancel := context.WithCancel(ctx)
+	return &eventSourceIterable{
+		observers: make([]chan Item, 0),
+		opts:      opts,
+		done:      make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+		strategy:  strategy,
+		listener:  listener,
+		replaced:  make(chan struct{}, 1),
+	}
+}
+
+// deliver sends an item to all observers, unless it expired. It returns true once the context is done.
+func (i *eventSourceIterable) deliver(item Item) (done bool) {
+	if i.ctx.Err() != nil {
+		return true
+	}
+	if e, ok := item.V.(*expiringItem); ok {
+		held := e.held(item)
+		if !e.clock.Now().Before(e.deadline) {
+			if i.listener != nil {
+				i.listener.expired(held)
+			}
+			return false
+		}
+		item = held
+	}
+
+	i.RLock()
+	defer i.RUnlock()
+
+	switch BackpressureStrategy(atomic.LoadUint32((*uint32)(&i.strategy))) {
+	default:
+		fallthrough
+	case Block:
+		for _, observer := range i.observers {
+			if !item.SendContext(i.ctx, observer) {
+				return true
+			}
+			if i.listener != nil {
+				i.listener.delivered(item)
+			}
+		}
+	case Drop:
+		for _, observer := range i.observers {
 			select {
-			case <-ctx.Done():
-				return
-			case item, ok := <-next:
-				if !ok {
-					return
+			default:
+				if i.listener != nil {
+					i.listener.dropped(item)
 				}
-
-				if done := deliver(item); done {
-					return
+			case <-i.ctx.Done():
+				return true
+			case observer <- item:
+				if i.listener != nil {
+					i.listener.delivered(item)
 				}
 			}
 		}
-	}()
+	}
+	return
+}
 
-	return it
+// dispose stops the delivery right away, the pending items being discarded. The observers are closed once
+// the delivery goroutine, if any, returned.
+func (i *eventSourceIterable) dispose() {
+	i.cancel()
 }
 
-func (i *eventSourceIterable) closeAllObservers() {
+// close closes all observers, once
+func (i *eventSourceIterable) close() {
 	i.Lock()
+	defer i.Unlock()
+
+	// releases the context
+	i.cancel()
+	if i.disposed {
+		return
+	}
 	for _, observer := range i.observers {
 		close(observer)
 	}
+	i.observers = nil
 	i.disposed = true
-	i.Unlock()
+	close(i.done)
+}
+
+// queued returns the number of items waiting in the observer channels
+func (i *eventSourceIterable) queued() int {
+	i.RLock()
+	defer i.RUnlock()
+
+	n := 0
+	for _, observer := range i.observers {
+		n += len(observer)
+	}
+	return n
 }
 
 func (i *eventSourceIterable) Observe(opts ...Option) <-chan Item {
@@ -82,11 +249,24 @@ func (i *eventSourceIterable) Observe(opts ...Option) <-chan Item {
 	next := option.buildChannel()
 
 	i.Lock()
-	if i.disposed {
+	disposed := i.disposed
+	if disposed {
 		close(next)
 	} else {
 		i.observers = append(i.observers, next)
 	}
 	i.Unlock()
+	i.wake()
+	if !disposed && i.listener != nil {
+		i.listener.observed(option.getStage())
+	}
 	return next
 }
+
+// hasObservers returns whether the iterable is observed
+func (i *eventSourceIterable) hasObservers() bool {
+	i.RLock()
+	defer i.RUnlock()
+
+	return len(i.observers) > 0
+}
@@ -0,0 +1,178 @@
+package rxgo
+
+import (
+	"context"
+	"sync"
+)
+
+// joinWindow is an item whose window is open
+type joinWindow struct {
+	id    int
+	value interface{}
+	// group receives the right items overlapping the window of a left item, for GroupJoin
+	group chan Item
+}
+
+// joinClose notifies the closing of a window, or the error of its window Observable
+type joinClose struct {
+	left bool
+	id   int
+	err  error
+}
+
+// windowJoin correlates the items of two Observables whose windows overlap, shared by JoinWindows and GroupJoin
+type windowJoin struct {
+	ctx         context.Context
+	next        chan<- Item
+	option      Option
+	leftWindow  ItemToObservable
+	rightWindow ItemToObservable
+	// onLeft and onRight emit the correlations of an item with the windows open on the other side, returning
+	// whether to carry on
+	onLeft  func(left *joinWindow) bool
+	onRight func(right *joinWindow) bool
+	closes  chan joinClose
+	wg      sync.WaitGroup
+	ids     int
+	lefts   []*joinWindow
+	rights  []*joinWindow
+}
+
+func newWindowJoin(ctx context.Context, next chan<- Item, option Option, leftWindow, rightWindow ItemToObservable) *windowJoin {
+	return &windowJoin{
+		ctx:         ctx,
+		next:        next,
+		option:      option,
+		leftWindow:  leftWindow,
+		rightWindow: rightWindow,
+		closes:      make(chan joinClose),
+	}
+}
+
+// run correlates the items until both Observables completed, an error stops it or the context is done
+func (j *windowJoin) run(left, right <-chan Item) {
+	ctx, cancel := context.WithCancel(j.ctx)
+	j.ctx = ctx
+	defer func() {
+		cancel()
+		j.wg.Wait()
+		for _, w := range j.lefts {
+			if w.group != nil {
+				close(w.group)
+			}
+		}
+	}()
+
+	for left != nil || right != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case item, ok := <-left:
+			if !ok {
+				left = nil
+				continue
+			}
+			if item.Error() {
+				if !j.fail(item) {
+					return
+				}
+				continue
+			}
+			w := j.open(item, true)
+			j.lefts = append(j.lefts, w)
+			if !j.onLeft(w) {
+				return
+			}
+		case item, ok := <-right:
+			if !ok {
+				right = nil
+				continue
+			}
+			if item.Error() {
+				if !j.fail(item) {
+					return
+				}
+				continue
+			}
+			w := j.open(item, false)
+			j.rights = append(j.rights, w)
+			if !j.onRight(w) {
+				return
+			}
+		case c := <-j.closes:
+			if c.err != nil {
+				if !j.fail(Error(c.err)) {
+					return
+				}
+				continue
+			}
+			if c.left {
+				j.lefts = removeJoinWindow(j.lefts, c.id)
+			} else {
+				j.rights = removeJoinWindow(j.rights, c.id)
+			}
+		}
+	}
+}
+
+// open opens the window of an item, notifying its closing once the window Observable emits or completes
+func (j *windowJoin) open(item Item, left bool) *joinWindow {
+	w := &joinWindow{id: j.ids, value: item.V}
+	j.ids++
+	window := j.rightWindow
+	if left {
+		window = j.leftWindow
+	}
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		ctx, cancel := context.WithCancel(j.ctx)
+		defer cancel()
+		c := joinClose{left: left, id: w.id}
+		select {
+		case <-ctx.Done():
+			return
+		case i, ok := <-window(item).Observe(WithContext(ctx)):
+			if ok && i.Error() {
+				c.err = i.E
+			}
+		}
+		select {
+		case <-ctx.Done():
+		case j.closes <- c:
+		}
+	}()
+	return w
+}
+
+// fail emits an error, returning whether to carry on
+func (j *windowJoin) fail(item Item) bool {
+	if !item.SendContext(j.ctx, j.next) {
+		return false
+	}
+	return j.option.getErrorStrategy() != StopOnError
+}
+
+// result emits the result of a selector, returning whether to carry on
+func (j *windowJoin) result(v interface{}, err error) bool {
+	if err != nil {
+		return j.fail(Error(err))
+	}
+	return Of(v).SendContext(j.ctx, j.next)
+}
+
+// removeJoinWindow removes a closed window, closing its group
+func removeJoinWindow(windows []*joinWindow, id int) []*joinWindow {
+	for i, w := range windows {
+		if w.id == id {
+			if w.group != nil {
+				close(w.group)
+			}
+			copy(windows[i:], windows[i+1:])
+			windows[len(windows)-1] = nil
+			return windows[:len(windows)-1]
+		}
+	}
+	return windows
+}
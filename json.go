@@ -0,0 +1,62 @@
+package rxgo
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// FromJSONDecoder creates an Observable emitting the values decoded from dec, such as a stream of
+// newline-delimited JSON values, and completing at the end of the stream or emitting the decoding error.
+// The values are decoded into interface{} values, or into the values created with WithJSONTarget.
+// As the decoder is consumed, the Observable is meant to be observed once.
+func FromJSONDecoder(ctx context.Context, dec *json.Decoder, opts ...Option) Observable {
+	target := parseOptions(opts...).getJSONTarget()
+	return CreateWithEmitter(func(ctx context.Context, emitter Emitter) {
+		for !emitter.IsDisposed() {
+			var v interface{}
+			var err error
+			if target != nil {
+				v = target()
+				err = dec.Decode(v)
+			} else {
+				err = dec.Decode(&v)
+			}
+			if err == io.EOF {
+				emitter.Complete()
+				return
+			}
+			if err != nil {
+				emitter.Error(err)
+				return
+			}
+			emitter.Next(v)
+		}
+	}, append(opts, WithContext(ctx))...)
+}
+
+// ToJSONEncoder observes an Observable and encodes each item with enc, each value being followed by a
+// newline. Like WriteTo, it returns once the Observable completed, nil, or at the first error, the observation
+// being stopped, and returns the context error if the context is done first.
+func ToJSONEncoder(ctx context.Context, observable Observable, enc *json.Encoder, opts ...Option) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	observe := observable.Observe(append(opts, WithContext(ctx))...)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-observe:
+			if !ok {
+				return nil
+			}
+			if item.Error() {
+				return item.E
+			}
+			if err := enc.Encode(item.V); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package rxgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+type jsonEvent struct {
+	ID int `json:"id"`
+}
+
+func TestFromJSONDecoder(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	Assert(ctx, t, FromJSONDecoder(ctx, json.NewDecoder(strings.NewReader("{\"id\":1}\n{\"id\":2}\n"))),
+		HasItems(map[string]interface{}{"id": 1.}, map[string]interface{}{"id": 2.}), HasNoError())
+	Assert(ctx, t, FromJSONDecoder(ctx, json.NewDecoder(strings.NewReader("{\"id\":1}\n{\"id\":2}\n")),
+		WithJSONTarget(func() interface{} {
+			return &jsonEvent{}
+		})), HasItems(&jsonEvent{ID: 1}, &jsonEvent{ID: 2}), HasNoError())
+	Assert(ctx, t, FromJSONDecoder(ctx, json.NewDecoder(strings.NewReader("1 {"))), HasItems(1.), HasAnError())
+}
+
+func TestToJSONEncoder(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var buf bytes.Buffer
+	assert.NoError(t, ToJSONEncoder(ctx, Just(jsonEvent{ID: 1}, jsonEvent{ID: 2})(), json.NewEncoder(&buf)))
+	assert.Equal(t, "{\"id\":1}\n{\"id\":2}\n", buf.String())
+
+	assert.Equal(t, errFoo, ToJSONEncoder(ctx, testObservable(ctx, 1, errFoo), json.NewEncoder(&buf)))
+	assert.Error(t, ToJSONEncoder(ctx, Just(func() {})(), json.NewEncoder(&buf)))
+}
@@ -0,0 +1,182 @@
+package rxgo
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// LeakKind is the kind of a detected leak.
+type LeakKind uint32
+
+const (
+	// LeakedSubscription is a subscription which was neither unsubscribed nor closed by its subject.
+	LeakedSubscription LeakKind = iota
+	// LeakedGoroutine is a delivery goroutine still running after its subscription was closed,
+	// typically because an observer stopped consuming with the Block strategy.
+	LeakedGoroutine
+)
+
+func (k LeakKind) String() string {
+	switch k {
+	case LeakedSubscription:
+		return "subscription"
+	case LeakedGoroutine:
+		return "goroutine"
+	default:
+		return "unknown"
+	}
+}
+
+// Leak describes a subscription or a delivery goroutine which was not released.
+type Leak struct {
+	Kind LeakKind
+	// Subject is the subject name, or its address if it has no name.
+	Subject string
+	// Subscriber is the subscriber ID.
+	Subscriber int
+	// Created is the subscription creation time.
+	Created time.Time
+	// Stack is the stack trace of the Subscribe call.
+	Stack string
+}
+
+func (l Leak) String() string {
+	return fmt.Sprintf("leaked %s: subject %s, subscriber %d, created at %s\n%s",
+		l.Kind, l.Subject, l.Subscriber, l.Created.Format(time.RFC3339Nano), l.Stack)
+}
+
+type trackedSubscription struct {
+	leak   Leak
+	source *eventSourceIterable
+	closed bool
+}
+
+func (ts *trackedSubscription) running() bool {
+	select {
+	case <-ts.source.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// leakTracker holds the subscriptions created while the leak detection is enabled
+type leakTracker struct {
+	sync.Mutex
+	enabled       int32
+	subscriptions map[*subscriberState]*trackedSubscription
+}
+
+var globalLeaks = &leakTracker{
+	subscriptions: make(map[*subscriberState]*trackedSubscription),
+}
+
+// EnableLeakDetection starts tracking every subject subscription and its delivery goroutine along with
+// the stack trace of the Subscribe call. Only subscriptions created afterwards are tracked.
+// The tracking has a cost and is meant for debugging and tests.
+func EnableLeakDetection() {
+	atomic.StoreInt32(&globalLeaks.enabled, 1)
+}
+
+// DisableLeakDetection stops tracking subscriptions and forgets the tracked ones.
+func DisableLeakDetection() {
+	atomic.StoreInt32(&globalLeaks.enabled, 0)
+
+	globalLeaks.Lock()
+	defer globalLeaks.Unlock()
+	globalLeaks.subscriptions = make(map[*subscriberState]*trackedSubscription)
+}
+
+func (lt *leakTracker) track(s *Subject, sub *subscriberState) {
+	if atomic.LoadInt32(&lt.enabled) == 0 {
+		return
+	}
+
+	name := s.Name()
+	if name == "" {
+		name = fmt.Sprintf("%p", s)
+	}
+
+	lt.Lock()
+	defer lt.Unlock()
+	lt.subscriptions[sub] = &trackedSubscription{
+		leak: Leak{
+			Subject:    name,
+			Subscriber: sub.id,
+			Created:    time.Now(),
+			Stack:      string(debug.Stack()),
+		},
+		source: sub.source,
+	}
+}
+
+func (lt *leakTracker) closed(sub *subscriberState) {
+	if atomic.LoadInt32(&lt.enabled) == 0 {
+		return
+	}
+
+	lt.Lock()
+	defer lt.Unlock()
+	if ts, found := lt.subscriptions[sub]; found {
+		ts.closed = true
+	}
+}
+
+// leaks returns the current leaks and forgets the released subscriptions
+func (lt *leakTracker) leaks() []Leak {
+	lt.Lock()
+	defer lt.Unlock()
+
+	res := make([]Leak, 0)
+	for sub, ts := range lt.subscriptions {
+		switch {
+		case !ts.closed:
+			leak := ts.leak
+			leak.Kind = LeakedSubscription
+			res = append(res, leak)
+		case ts.running():
+			leak := ts.leak
+			leak.Kind = LeakedGoroutine
+			res = append(res, leak)
+		default:
+			delete(lt.subscriptions, sub)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+	return res
+}
+
+// Leaks reports the tracked subscriptions which were never unsubscribed or closed by their subject, and
+// the delivery goroutines still running after their subscription was closed.
+func Leaks() []Leak {
+	return globalLeaks.leaks()
+}
+
+// VerifyNoLeaks fails the test if Leaks reports anything. As delivery goroutines terminate asynchronously,
+// the check is retried for up to a second.
+func VerifyNoLeaks(t *testing.T) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		leaks := Leaks()
+		if len(leaks) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			for _, leak := range leaks {
+				t.Error(leak.String())
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,83 @@
+package rxgo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func leaksOf(subject string) []Leak {
+	res := make([]Leak, 0)
+	for _, leak := range Leaks() {
+		if leak.Subject == subject {
+			res = append(res, leak)
+		}
+	}
+	return res
+}
+
+// TestLeakDetection_Subscription verifies subscriptions never unsubscribed are reported
+func TestLeakDetection_Subscription(t *testing.T) {
+	EnableLeakDetection()
+	defer DisableLeakDetection()
+
+	subject := NewSubject(WithName("leak.subscription"))
+	sub, _ := subject.Subscribe()
+	subject.Subscribe()
+
+	leaks := leaksOf("leak.subscription")
+	assert.Len(t, leaks, 2)
+	for _, leak := range leaks {
+		assert.Equal(t, LeakedSubscription, leak.Kind)
+		assert.True(t, strings.Contains(leak.Stack, "TestLeakDetection_Subscription"))
+	}
+
+	sub.Unsubscribe()
+	// the delivery goroutine terminates asynchronously
+	assert.Eventually(t, func() bool {
+		return len(leaksOf("leak.subscription")) == 1
+	}, time.Second, time.Millisecond)
+	leaks = leaksOf("leak.subscription")
+	assert.Equal(t, LeakedSubscription, leaks[0].Kind)
+	assert.Equal(t, 1, leaks[0].Subscriber)
+
+	subject.Complete()
+	VerifyNoLeaks(t)
+}
+
+// TestLeakDetection_Goroutine verifies delivery goroutines outliving their subject are reported
+func TestLeakDetection_Goroutine(t *testing.T) {
+	EnableLeakDetection()
+	defer DisableLeakDetection()
+
+	subject := NewSubject(WithName("leak.goroutine"))
+	_, obs := subject.Subscribe()
+	// observer which stops consuming
+	ch := obs.Observe()
+	time.Sleep(10 * time.Millisecond)
+
+	subject.Next(1)
+	subject.Complete()
+
+	time.Sleep(10 * time.Millisecond)
+	leaks := leaksOf("leak.goroutine")
+	assert.Len(t, leaks, 1)
+	assert.Equal(t, LeakedGoroutine, leaks[0].Kind)
+	assert.Contains(t, leaks[0].String(), "leaked goroutine: subject leak.goroutine, subscriber 0")
+
+	// release the delivery goroutine
+	for range ch {
+	}
+	VerifyNoLeaks(t)
+}
+
+// TestLeakDetection_Disabled verifies nothing is tracked by default
+func TestLeakDetection_Disabled(t *testing.T) {
+	subject := NewSubject(WithName("leak.disabled"))
+	defer subject.Complete()
+	subject.Subscribe()
+
+	assert.Empty(t, leaksOf("leak.disabled"))
+}
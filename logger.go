@@ -0,0 +1,88 @@
+package rxgo
+
+import "sync/atomic"
+
+// Logger is a structured logger used to report internal events like subscriptions, drops or terminal states.
+// The key-value pairs follow the log/slog convention, so a *slog.Logger can be used directly.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(_ string, _ ...interface{}) {}
+func (noopLogger) Info(_ string, _ ...interface{})  {}
+func (noopLogger) Warn(_ string, _ ...interface{})  {}
+func (noopLogger) Error(_ string, _ ...interface{}) {}
+
+// namedLogger adds the name of a subject to every entry
+type namedLogger struct {
+	Logger
+	name string
+}
+
+func (l namedLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.Logger.Debug(msg, l.with(keysAndValues)...)
+}
+
+func (l namedLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.Logger.Info(msg, l.with(keysAndValues)...)
+}
+
+func (l namedLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.Logger.Warn(msg, l.with(keysAndValues)...)
+}
+
+func (l namedLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.Logger.Error(msg, l.with(keysAndValues)...)
+}
+
+func (l namedLogger) with(keysAndValues []interface{}) []interface{} {
+	return append([]interface{}{"subject", l.name}, keysAndValues...)
+}
+
+// loggerHolder wraps a Logger so that implementations of different types can be stored in an atomic.Value
+type loggerHolder struct {
+	logger Logger
+}
+
+var defaultLogger atomic.Value
+
+func init() {
+	defaultLogger.Store(loggerHolder{logger: noopLogger{}})
+}
+
+// SetDefaultLogger sets the logger used when no logger is passed with WithLogger.
+// By default, nothing is logged.
+func SetDefaultLogger(logger Logger) {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	defaultLogger.Store(loggerHolder{logger: logger})
+}
+
+// DefaultLogger returns the logger used when no logger is passed with WithLogger.
+func DefaultLogger() Logger {
+	return defaultLogger.Load().(loggerHolder).logger
+}
+
+// logPanic logs a panic raised by a callback before propagating it, attributing it to the stage of the
+// pipeline if any, and records it as a span if a tracer is configured.
+// It must be deferred directly.
+func logPanic(option Option, stage string) {
+	if r := recover(); r != nil {
+		keysAndValues := []interface{}{"panic", r}
+		if stage != "" {
+			keysAndValues = append(keysAndValues, "stage", stage)
+		}
+		option.getLogger().Error("rxgo: panic in callback", keysAndValues...)
+		if span := startSpan(option, "rxgo.stage.panic"); span != nil {
+			span.RecordError(PanicError{Value: r, Stage: stage})
+			span.End()
+		}
+		panic(r)
+	}
+}
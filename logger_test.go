@@ -0,0 +1,98 @@
+package rxgo
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLogger struct {
+	sync.Mutex
+	messages      []string
+	keysAndValues [][]interface{}
+}
+
+func (l *recordingLogger) record(msg string, keysAndValues []interface{}) {
+	l.Lock()
+	defer l.Unlock()
+	l.messages = append(l.messages, msg)
+	l.keysAndValues = append(l.keysAndValues, keysAndValues)
+}
+
+func (l *recordingLogger) Debug(msg string, kv ...interface{}) { l.record(msg, kv) }
+func (l *recordingLogger) Info(msg string, kv ...interface{})  { l.record(msg, kv) }
+func (l *recordingLogger) Warn(msg string, kv ...interface{})  { l.record(msg, kv) }
+func (l *recordingLogger) Error(msg string, kv ...interface{}) { l.record(msg, kv) }
+
+// TestWithLogger verifies subject events are reported to the logger
+func TestWithLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	subject := NewSubject(WithLogger(logger))
+
+	sub, _ := subject.Subscribe()
+	sub.Unsubscribe()
+	subject.Subscribe()
+	subject.Error(errFoo)
+
+	assert.Equal(t, []string{
+		"rxgo: subscribed",
+		"rxgo: unsubscribed",
+		"rxgo: subscribed",
+		"rxgo: subject terminated with error",
+	}, logger.messages)
+}
+
+// TestDefaultLogger verifies the package default logger is used without WithLogger
+func TestDefaultLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	SetDefaultLogger(logger)
+	defer SetDefaultLogger(nil)
+
+	subject := NewSubject()
+	subject.Subscribe()
+	subject.Complete()
+
+	assert.Equal(t, []string{"rxgo: subscribed", "rxgo: subject completed"}, logger.messages)
+}
+
+// TestWithLogger_Name verifies the subject name is reported in the log entries
+func TestWithLogger_Name(t *testing.T) {
+	logger := &recordingLogger{}
+	subject := NewSubject(WithLogger(logger), WithName("orders"))
+	subject.Subscribe()
+	subject.Complete()
+
+	assert.Equal(t, []interface{}{"subject", "orders", "subscriber", 0}, logger.keysAndValues[0])
+}
+
+// TestLogPanic_Stage verifies a panic is logged along with its stage before being propagated
+func TestLogPanic_Stage(t *testing.T) {
+	logger := &recordingLogger{}
+	assert.PanicsWithValue(t, "boom", func() {
+		defer logPanic(parseOptions(WithLogger(logger)), "parse")
+		panic("boom")
+	})
+	assert.Equal(t, []string{"rxgo: panic in callback"}, logger.messages)
+	assert.Equal(t, []interface{}{"panic", "boom", "stage", "parse"}, logger.keysAndValues[0])
+}
+
+// TestWithLogger_DropStage verifies an item dropped for a named subscription is attributed to its stage
+func TestWithLogger_DropStage(t *testing.T) {
+	logger := &recordingLogger{}
+	subject := NewSubject(WithLogger(logger), WithBackPressureStrategy(Drop))
+	_, obs := subject.Subscribe()
+	// observed but never consumed
+	obs.Named("billing").Observe()
+	subject.Next(1)
+	subject.Complete()
+
+	logger.Lock()
+	defer logger.Unlock()
+	assert.Contains(t, logger.messages, "rxgo: item dropped")
+	for i, msg := range logger.messages {
+		if msg == "rxgo: item dropped" {
+			assert.Equal(t, []interface{}{"subscriber", 0, "stage", "billing", "item", 1}, logger.keysAndValues[i])
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package rxgo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// MarbleFrame is the virtual duration of a marble diagram frame.
+const MarbleFrame = time.Millisecond
+
+// MarbleEvent is a notification of a marble diagram.
+type MarbleEvent struct {
+	// Frame is the frame the notification occurs at.
+	Frame int
+	// Item is the value or the error, unset for a completion.
+	Item Item
+	// Complete is set for a completion.
+	Complete bool
+}
+
+func (e MarbleEvent) String() string {
+	switch {
+	case e.Complete:
+		return fmt.Sprintf("%d:|", e.Frame)
+	case e.Item.Error():
+		return fmt.Sprintf("%d:#(%v)", e.Frame, e.Item.E)
+	default:
+		return fmt.Sprintf("%d:%v", e.Frame, e.Item.V)
+	}
+}
+
+// ParseMarbles parses a marble diagram like "--a--b--(c|)":
+//   - '-' is a frame without notification
+//   - '|' is the completion
+//   - '#' is the error err
+//   - '(' and ')' group notifications occurring at the same frame
+//   - spaces are ignored
+//   - any other character is a value, looked up in values or used as is if absent
+//
+// Each frame holds either a dash, a single notification or a group.
+func ParseMarbles(marbles string, values map[string]interface{}, err error) ([]MarbleEvent, error) {
+	events := make([]MarbleEvent, 0)
+	frame := 0
+	inGroup := false
+	terminated := false
+
+	for _, c := range marbles {
+		if terminated && c != ')' && c != ' ' && c != '-' {
+			return nil, IllegalInputError{error: fmt.Sprintf("notification after termination in %q", marbles)}
+		}
+		switch c {
+		case ' ':
+			continue
+		case '-':
+			if inGroup {
+				return nil, IllegalInputError{error: fmt.Sprintf("frame inside a group in %q", marbles)}
+			}
+		case '(':
+			if inGroup {
+				return nil, IllegalInputError{error: fmt.Sprintf("nested group in %q", marbles)}
+			}
+			inGroup = true
+			continue
+		case ')':
+			if !inGroup {
+				return nil, IllegalInputError{error: fmt.Sprintf("unexpected ')' in %q", marbles)}
+			}
+			inGroup = false
+		case '|':
+			events = append(events, MarbleEvent{Frame: frame, Complete: true})
+			terminated = true
+		case '#':
+			if err == nil {
+				return nil, IllegalInputError{error: fmt.Sprintf("error without value in %q", marbles)}
+			}
+			events = append(events, MarbleEvent{Frame: frame, Item: Error(err)})
+			terminated = true
+		default:
+			var v interface{} = string(c)
+			if value, exists := values[string(c)]; exists {
+				v = value
+			}
+			events = append(events, MarbleEvent{Frame: frame, Item: Of(v)})
+		}
+		if !inGroup {
+			frame++
+		}
+	}
+	if inGroup {
+		return nil, IllegalInputError{error: fmt.Sprintf("unclosed group in %q", marbles)}
+	}
+	return events, nil
+}
+
+// marbleFrames returns the number of frames of a marble diagram
+func marbleFrames(marbles string) int {
+	frames := 0
+	inGroup := false
+	for _, c := range marbles {
+		switch c {
+		case ' ':
+		case '(':
+			inGroup = true
+		case ')':
+			inGroup = false
+			frames++
+		default:
+			if !inGroup {
+				frames++
+			}
+		}
+	}
+	return frames
+}
+
+// ColdObservable creates an Observable emitting the notifications of a marble diagram, each frame lasting
+// MarbleFrame on the scheduler. Every observer gets its own timeline starting when it observes.
+// It panics if the diagram is invalid.
+func ColdObservable(scheduler *TestScheduler, marbles string, values map[string]interface{}, err error, opts ...Option) Observable {
+	events, perr := ParseMarbles(marbles, values, err)
+	if perr != nil {
+		panic(perr)
+	}
+
+	return &ObservableImpl{
+		iterable: newFactoryIterable(func(propagatedOptions ...Option) <-chan Item {
+			option := parseOptions(append(opts, propagatedOptions...)...)
+			next := option.buildChannel()
+			ctx := option.buildContext(emptyContext)
+			start := scheduler.Now()
+
+			go func() {
+				for _, event := range events {
+					if wait := start.Add(time.Duration(event.Frame) * MarbleFrame).Sub(scheduler.Now()); wait > 0 {
+						timer := scheduler.NewTimer(wait)
+						select {
+						case <-ctx.Done():
+							timer.Stop()
+							close(next)
+							return
+						case <-timer.C():
+						}
+					}
+					if event.Complete {
+						break
+					}
+					if !event.Item.SendContext(ctx, next) {
+						break
+					}
+				}
+				if len(events) > 0 && (events[len(events)-1].Complete || events[len(events)-1].Item.Error()) {
+					close(next)
+					return
+				}
+				// no termination: wait for the context
+				<-ctx.Done()
+				close(next)
+			}()
+			return next
+		}),
+	}
+}
+
+// ExpectObservable observes the observable and advances the scheduler frame by frame until the observable
+// completes or the end of the expected marble diagram is reached, then asserts the recorded notifications
+// match the diagram.
+//
+// Between frames, it waits for the other goroutines to block so that the effects of a frame are recorded
+// at that frame. The observable must use the scheduler as clock to be driven by the virtual time.
+func ExpectObservable(t *testing.T, scheduler *TestScheduler, iterable Iterable, marbles string, values map[string]interface{}, err error) bool {
+	t.Helper()
+
+	expected, perr := ParseMarbles(marbles, values, err)
+	if perr != nil {
+		return assert.Fail(t, perr.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := scheduler.Now()
+	frameOf := func() int {
+		return int(scheduler.Now().Sub(start) / MarbleFrame)
+	}
+
+	observe := iterable.Observe(WithContext(ctx))
+	recorded := make(chan []MarbleEvent)
+	go func() {
+		events := make([]MarbleEvent, 0)
+		for item := range observe {
+			events = append(events, MarbleEvent{Frame: frameOf(), Item: item})
+		}
+		// an observable closing after an error does not complete
+		if len(events) == 0 || !events[len(events)-1].Item.Error() {
+			events = append(events, MarbleEvent{Frame: frameOf(), Complete: true})
+		}
+		recorded <- events
+	}()
+
+	var actual []MarbleEvent
+	frames := marbleFrames(marbles)
+loop:
+	for frame := 0; ; frame++ {
+		settle()
+		select {
+		case actual = <-recorded:
+			break loop
+		default:
+		}
+		if frame >= frames {
+			// the observable did not terminate within the diagram: stop observing
+			cancel()
+			select {
+			case actual = <-recorded:
+			case <-time.After(time.Second):
+				return assert.Fail(t, "observable not stopped by its context")
+			}
+			if last := len(actual) - 1; last >= 0 && actual[last].Complete {
+				actual = actual[:last]
+			}
+			break loop
+		}
+		scheduler.Advance(MarbleFrame)
+	}
+
+	return assert.Equal(t, expected, actual)
+}
+
+// settle waits until all the other goroutines are blocked, or a short timeout
+func settle() {
+	deadline := time.Now().Add(50 * time.Millisecond)
+	buf := make([]byte, 64*1024)
+	idle := 0
+	for idle < 2 && time.Now().Before(deadline) {
+		runtime.Gosched()
+		n := runtime.Stack(buf, true)
+		for n == len(buf) {
+			buf = make([]byte, 2*len(buf))
+			n = runtime.Stack(buf, true)
+		}
+		if othersBlocked(buf[:n]) {
+			idle++
+		} else {
+			idle = 0
+		}
+	}
+}
+
+// othersBlocked returns whether all goroutines of a stack dump but the first one, the current one,
+// are blocked
+func othersBlocked(dump []byte) bool {
+	first := true
+	for _, line := range bytes.Split(dump, []byte("\n")) {
+		if !bytes.HasPrefix(line, []byte("goroutine ")) {
+			continue
+		}
+		if first {
+			first = false
+			continue
+		}
+		i := bytes.IndexByte(line, '[')
+		if i < 0 {
+			continue
+		}
+		state := line[i+1:]
+		if bytes.HasPrefix(state, []byte("running")) || bytes.HasPrefix(state, []byte("runnable")) {
+			return false
+		}
+	}
+	return true
+}
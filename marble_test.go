@@ -0,0 +1,76 @@
+package rxgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestParseMarbles(t *testing.T) {
+	events, err := ParseMarbles("--a-(bc)-#", map[string]interface{}{"a": 1}, errFoo)
+	assert.NoError(t, err)
+	assert.Equal(t, []MarbleEvent{
+		{Frame: 2, Item: Of(1)},
+		{Frame: 4, Item: Of("b")},
+		{Frame: 4, Item: Of("c")},
+		{Frame: 6, Item: Error(errFoo)},
+	}, events)
+
+	events, err = ParseMarbles("a - (b|)", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []MarbleEvent{
+		{Frame: 0, Item: Of("a")},
+		{Frame: 2, Item: Of("b")},
+		{Frame: 2, Complete: true},
+	}, events)
+	assert.Equal(t, 3, marbleFrames("a - (b|)"))
+}
+
+func TestParseMarbles_Invalid(t *testing.T) {
+	for _, marbles := range []string{"-(a", "a)", "((a))", "(a-b)", "-|-a", "#"} {
+		_, err := ParseMarbles(marbles, nil, nil)
+		assert.IsType(t, IllegalInputError{}, err, marbles)
+	}
+}
+
+func TestColdObservable(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	source := ColdObservable(scheduler, "-a--b-|", nil, nil)
+
+	ExpectObservable(t, scheduler, source, "-a--b-|", nil, nil)
+	// every observer gets its own timeline
+	ExpectObservable(t, scheduler, source, "-a--b-|", nil, nil)
+}
+
+func TestExpectObservable_Map(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	source := ColdObservable(scheduler, "-a-b-#", map[string]interface{}{"a": 1, "b": 2}, errFoo)
+
+	obs := source.Map(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i.(int) * 10, nil
+	})
+	ExpectObservable(t, scheduler, obs, "-x-y-#", map[string]interface{}{"x": 10, "y": 20}, errFoo)
+}
+
+func TestExpectObservable_Debounce(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	source := ColdObservable(scheduler, "ab-----c----", nil, nil)
+
+	obs := source.Debounce(WithDuration(3*MarbleFrame), WithClock(scheduler))
+	ExpectObservable(t, scheduler, obs, "----b-----c-", nil, nil)
+}
+
+func TestExpectObservable_Mismatch(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	source := ColdObservable(scheduler, "-a|", nil, nil)
+
+	mockT := &testing.T{}
+	assert.False(t, ExpectObservable(mockT, scheduler, source, "a-|", nil, nil))
+}
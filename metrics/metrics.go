@@ -0,0 +1,184 @@
+// Package metrics exposes subject statistics in the Prometheus text exposition format.
+//
+// The package has no dependency on the Prometheus client library: a Collector is an http.Handler
+// which can be scraped directly, and the snapshots returned by Collect can be bridged to a
+// prometheus.Collector if needed.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+)
+
+// ContentType is the content type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// StatsSource is implemented by subjects.
+type StatsSource interface {
+	Stats() rxgo.SubjectStats
+}
+
+// Collector collects the statistics of a set of named subjects.
+type Collector struct {
+	mu        sync.RWMutex
+	namespace string
+	sources   map[string]StatsSource
+}
+
+// NewCollector creates a collector. Metric names are prefixed with the namespace, "rxgo" if empty.
+func NewCollector(namespace string) *Collector {
+	if namespace == "" {
+		namespace = "rxgo"
+	}
+	return &Collector{
+		namespace: namespace,
+		sources:   make(map[string]StatsSource),
+	}
+}
+
+// Register adds a subject under the given name, replacing any subject with the same name.
+func (c *Collector) Register(name string, source StatsSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sources[name] = source
+}
+
+// Unregister removes the subject with the given name.
+func (c *Collector) Unregister(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.sources, name)
+}
+
+// Collect returns a snapshot of the statistics of all registered subjects, indexed by name.
+func (c *Collector) Collect() map[string]rxgo.SubjectStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	res := make(map[string]rxgo.SubjectStats, len(c.sources))
+	for name, source := range c.sources {
+		res[name] = source.Stats()
+	}
+	return res
+}
+
+// WriteTo writes the statistics of all registered subjects in the text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	stats := c.Collect()
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	gauge := func(metric, help string, value func(rxgo.SubjectStats) string) {
+		c.header(cw, metric, help, "gauge")
+		for _, name := range names {
+			fmt.Fprintf(cw, "%s_%s{subject=%q} %s\n", c.namespace, metric, name, value(stats[name]))
+		}
+	}
+	counter := func(metric, help string, value func(rxgo.SubjectStats) uint64) {
+		c.header(cw, metric, help, "counter")
+		for _, name := range names {
+			fmt.Fprintf(cw, "%s_%s{subject=%q} %d\n", c.namespace, metric, name, value(stats[name]))
+		}
+	}
+
+	gauge("subject_subscribers", "Number of current subscribers.", func(s rxgo.SubjectStats) string {
+		return strconv.Itoa(s.Subscribers)
+	})
+	counter("subject_emitted_total", "Number of items published.", func(s rxgo.SubjectStats) uint64 {
+		return s.Emitted
+	})
+	counter("subject_delivered_total", "Number of items delivered to observers.", func(s rxgo.SubjectStats) uint64 {
+		return s.Delivered
+	})
+	counter("subject_dropped_total", "Number of items dropped by the back pressure strategy.", func(s rxgo.SubjectStats) uint64 {
+		return s.Dropped
+	})
+	counter("subject_expired_total", "Number of items discarded after their time to live.", func(s rxgo.SubjectStats) uint64 {
+		return s.Expired
+	})
+	gauge("subject_queue_depth", "Number of items waiting to be delivered.", func(s rxgo.SubjectStats) string {
+		return strconv.Itoa(s.QueueDepth)
+	})
+
+	consumer := func(metric, help string, value func(rxgo.ConsumerStats) string) {
+		c.header(cw, metric, help, "gauge")
+		for _, name := range names {
+			for _, consumer := range stats[name].Consumers {
+				fmt.Fprintf(cw, "%s_%s{subject=%q,consumer=%q,subscriber=\"%d\"} %s\n", c.namespace, metric, name,
+					consumer.Name, consumer.Subscriber, value(consumer))
+			}
+		}
+	}
+	consumer("subject_consumer_undelivered", "Number of items waiting to be delivered to a consumer.", func(s rxgo.ConsumerStats) string {
+		return strconv.Itoa(s.Undelivered)
+	})
+	consumer("subject_consumer_unacked", "Number of items delivered to a consumer and not acknowledged.", func(s rxgo.ConsumerStats) string {
+		return strconv.Itoa(s.Unacked)
+	})
+	consumer("subject_consumer_oldest_pending_seconds", "Age of the oldest item pending for a consumer.", func(s rxgo.ConsumerStats) string {
+		return seconds(s.OldestPending)
+	})
+
+	const latency = "subject_publish_latency_seconds"
+	c.header(cw, latency, "Time taken to hand over an item to all subscribers.", "histogram")
+	for _, name := range names {
+		h := stats[name].PublishLatency
+		for i, bound := range h.Bounds {
+			fmt.Fprintf(cw, "%s_%s_bucket{subject=%q,le=%q} %d\n", c.namespace, latency, name, seconds(bound), h.Counts[i])
+		}
+		fmt.Fprintf(cw, "%s_%s_bucket{subject=%q,le=\"+Inf\"} %d\n", c.namespace, latency, name, h.Count)
+		fmt.Fprintf(cw, "%s_%s_sum{subject=%q} %s\n", c.namespace, latency, name, seconds(h.Sum))
+		fmt.Fprintf(cw, "%s_%s_count{subject=%q} %d\n", c.namespace, latency, name, h.Count)
+	}
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP serves the statistics of all registered subjects in the text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = c.WriteTo(w)
+}
+
+func (c *Collector) header(w io.Writer, metric, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s_%s %s\n", c.namespace, metric, help)
+	fmt.Fprintf(w, "# TYPE %s_%s %s\n", c.namespace, metric, kind)
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+}
+
+// countingWriter counts the bytes written and keeps the first error
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector(t *testing.T) {
+	subject := rxgo.NewSubject()
+	defer subject.Complete()
+
+	_, obs := subject.Subscribe()
+	received := make(chan interface{}, 2)
+	obs.DoOnNext(func(i interface{}) {
+		received <- i
+	})
+
+	subject.Next(1)
+	subject.Next(2)
+	<-received
+	<-received
+	assert.Eventually(t, func() bool {
+		return subject.Stats().Delivered == 2
+	}, time.Second, time.Millisecond)
+
+	collector := NewCollector("")
+	collector.Register("orders", subject)
+
+	stats := collector.Collect()
+	assert.Equal(t, 1, stats["orders"].Subscribers)
+	assert.Equal(t, uint64(2), stats["orders"].Emitted)
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "# TYPE rxgo_subject_subscribers gauge\n")
+	assert.Contains(t, body, "rxgo_subject_subscribers{subject=\"orders\"} 1\n")
+	assert.Contains(t, body, "rxgo_subject_emitted_total{subject=\"orders\"} 2\n")
+	assert.Contains(t, body, "rxgo_subject_delivered_total{subject=\"orders\"} 2\n")
+	assert.Contains(t, body, "rxgo_subject_dropped_total{subject=\"orders\"} 0\n")
+	assert.Contains(t, body, "rxgo_subject_expired_total{subject=\"orders\"} 0\n")
+	assert.Contains(t, body, "rxgo_subject_publish_latency_seconds_bucket{subject=\"orders\",le=\"+Inf\"} 2\n")
+	assert.Contains(t, body, "rxgo_subject_publish_latency_seconds_count{subject=\"orders\"} 2\n")
+
+	collector.Unregister("orders")
+	var sb strings.Builder
+	_, err := collector.WriteTo(&sb)
+	assert.NoError(t, err)
+	assert.NotContains(t, sb.String(), "orders")
+}
+
+func TestCollector_Namespace(t *testing.T) {
+	subject := rxgo.NewSubject()
+	defer subject.Complete()
+
+	collector := NewCollector("bus")
+	collector.Register("events", subject)
+
+	var sb strings.Builder
+	n, err := collector.WriteTo(&sb)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(sb.Len()), n)
+	assert.Contains(t, sb.String(), "bus_subject_subscribers{subject=\"events\"} 0\n")
+	assert.Contains(t, sb.String(), "bus_subject_publish_latency_seconds_bucket{subject=\"events\",le=\""+
+		"1e-06\"} 0\n")
+}
+
+func TestCollector_Consumers(t *testing.T) {
+	subject := rxgo.NewSubject()
+	defer subject.Complete()
+
+	audit, _ := subject.SubscribeDurable("audit")
+	audit.Unsubscribe()
+	_, member := subject.SubscribeGroup("workers")
+	_ = member.Observe(rxgo.WithBufferedChannel(1))
+	subject.Next(1)
+	assert.Eventually(t, func() bool {
+		return subject.Stats().QueueDepth == 1
+	}, time.Second, time.Millisecond)
+
+	collector := NewCollector("")
+	collector.Register("orders", subject)
+	var sb strings.Builder
+	_, err := collector.WriteTo(&sb)
+	assert.NoError(t, err)
+
+	body := sb.String()
+	assert.Contains(t, body, "# TYPE rxgo_subject_consumer_undelivered gauge\n")
+	assert.Contains(t, body, "rxgo_subject_consumer_undelivered{subject=\"orders\",consumer=\"audit\",subscriber=\"-1\"} 1\n")
+	assert.Contains(t, body, "rxgo_subject_consumer_undelivered{subject=\"orders\",consumer=\"workers\",subscriber=\"1\"} 1\n")
+	assert.Contains(t, body, "rxgo_subject_consumer_unacked{subject=\"orders\",consumer=\"audit\",subscriber=\"-1\"} 0\n")
+	assert.Contains(t, body, "rxgo_subject_consumer_oldest_pending_seconds{subject=\"orders\",consumer=\"workers\",subscriber=\"1\"}")
+}
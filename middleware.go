@@ -0,0 +1,73 @@
+package rxgo
+
+// Use adds a middleware processing the values published to the subject before they are delivered to its
+// subscribers, such as a validation, an enrichment or an authorization check. A middleware returns the NextFunc
+// processing a value, which passes it, possibly modified, to next, or drops it by not calling next. The
+// middlewares run in the order they were added, on the publishing goroutine, and must be safe for concurrent
+// use if the subject is published to concurrently.
+//
+// The inline errors are not passed to the middlewares. A value keeps the acknowledgement of its item, and an
+// item dropped by a middleware is acknowledged.
+func (s *Subject) Use(middleware func(next NextFunc) NextFunc) {
+	s.Lock()
+	defer s.Unlock()
+
+	// the slice is copied, as publishers may iterate over the current one
+	current := s.loadMiddlewares()
+	middlewares := make([]func(next NextFunc) NextFunc, len(current), len(current)+1)
+	copy(middlewares, current)
+	s.middlewares.Store(append(middlewares, middleware))
+}
+
+// loadMiddlewares returns the middlewares of the subject, nil without middleware
+func (s *Subject) loadMiddlewares() []func(next NextFunc) NextFunc {
+	middlewares, _ := s.middlewares.Load().([]func(next NextFunc) NextFunc)
+	return middlewares
+}
+
+// intercept passes items through the middlewares, returning the items to publish. The items are returned as
+// is without middleware.
+func (s *Subject) intercept(items []Item) []Item {
+	middlewares := s.loadMiddlewares()
+	if len(middlewares) == 0 {
+		return items
+	}
+
+	intercepted := make([]Item, 0, len(items))
+	for _, item := range items {
+		if item.Error() {
+			intercepted = append(intercepted, item)
+			continue
+		}
+		passed := false
+		next := NextFunc(func(v interface{}) {
+			passed = true
+			intercepted = append(intercepted, Item{V: v, ack: item.ack})
+		})
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		next(item.V)
+		if !passed {
+			item.Ack()
+		}
+	}
+	return intercepted
+}
+
+// interceptValues passes values through the middlewares, returning the values to publish
+func (s *Subject) interceptValues(values []interface{}) []interface{} {
+	if len(s.loadMiddlewares()) == 0 {
+		return values
+	}
+	items := make([]Item, len(values))
+	for i, v := range values {
+		items[i] = Of(v)
+	}
+	items = s.intercept(items)
+	intercepted := make([]interface{}, len(items))
+	for i, item := range items {
+		intercepted[i] = item.V
+	}
+	return intercepted
+}
@@ -0,0 +1,98 @@
+package rxgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// tag returns a middleware appending a suffix to the string values
+func tag(suffix string) func(NextFunc) NextFunc {
+	return func(next NextFunc) NextFunc {
+		return func(v interface{}) {
+			next(v.(string) + suffix)
+		}
+	}
+}
+
+// dropEmpty is a middleware dropping the empty strings
+func dropEmpty(next NextFunc) NextFunc {
+	return func(v interface{}) {
+		if v.(string) != "" {
+			next(v)
+		}
+	}
+}
+
+func TestSubject_Use(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	subject.Use(dropEmpty)
+	subject.Use(tag("-a"))
+	subject.Use(tag("-b"))
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs, WithBufferedChannel(10))
+
+	acked := false
+	subject.Next("x")
+	subject.NextItem(Of("").WithAck(func() {
+		acked = true
+	}))
+	subject.NextItem(Error(errFoo))
+	subject.NextBatch([]interface{}{"y", "", "z"})
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{"x-a-b", "y-a-b", "z-a-b"}, observer.Values())
+	assert.Equal(t, []error{errFoo}, observer.Errors())
+	assert.True(t, acked)
+}
+
+func TestReplaySubject_Use(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewReplaySubject(10)
+	subject.Use(dropEmpty)
+	subject.Use(tag("!"))
+	subject.Next("x")
+	subject.Next("")
+	subject.NextBatch([]interface{}{"y"})
+
+	// the replayed values went through the middlewares
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+	subject.Complete()
+	observer.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{"x!", "y!"}, observer.Values())
+}
+
+func TestBehaviorSubject_Use(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewBehaviorSubject()
+	subject.Use(dropEmpty)
+	subject.Next("x")
+	subject.Next("")
+
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+	subject.Complete()
+	observer.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{"x"}, observer.Values())
+}
+
+func TestTopicSubject_Use(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewTopicSubject()
+	subject.Use(dropEmpty)
+	subject.Use(tag("!"))
+	_, obs := subject.SubscribeTopic("orders")
+	observer := NewTestObserver(t, obs)
+
+	subject.Next("orders", "x")
+	subject.Next("orders", "")
+	subject.NextBatch("orders", []interface{}{"y"})
+	subject.Complete()
+	observer.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{"x!", "y!"}, observer.Values())
+}
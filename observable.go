@@ -14,6 +14,8 @@ import (
 // Observable is the standard interface for Observables.
 type Observable interface {
 	Iterable
+	seqObservable
+	AggregateByKey(keySelector Func, seed interface{}, acc Func2, policy FlushPolicy, opts ...Option) Observable
 	All(predicate Predicate, opts ...Option) Single
 	AverageFloat32(opts ...Option) Single
 	AverageFloat64(opts ...Option) Single
@@ -23,13 +25,20 @@ type Observable interface {
 	AverageInt32(opts ...Option) Single
 	AverageInt64(opts ...Option) Single
 	BackOffRetry(backOffCfg backoff.BackOff, opts ...Option) Observable
+	BlockingFirst(ctx context.Context) (interface{}, error)
+	BlockingForEach(ctx context.Context, nextFunc NextFunc) error
+	BlockingLast(ctx context.Context) (interface{}, error)
+	BlockingToSlice(ctx context.Context) ([]interface{}, error)
 	BufferWithCount(count int, opts ...Option) Observable
 	BufferWithTime(timespan Duration, opts ...Option) Observable
 	BufferWithTimeOrCount(timespan Duration, count int, opts ...Option) Observable
+	CircuitBreaker(breaker *CircuitBreaker, opts ...Option) Observable
 	Connect(ctx context.Context) (context.Context, Disposable)
+	ConnectWithCancel(ctx context.Context) (Disposable, error)
 	Contains(equal Predicate, opts ...Option) Single
 	Count(opts ...Option) Single
 	Debounce(timespan Duration, opts ...Option) Observable
+	DeduplicateWithin(window Duration, keySelector Func, opts ...Option) Observable
 	DefaultIfEmpty(defaultValue interface{}, opts ...Option) Observable
 	Distinct(apply Func, opts ...Option) Observable
 	DistinctUntilChanged(apply Func, opts ...Option) Observable
@@ -40,6 +49,7 @@ type Observable interface {
 	Error(opts ...Option) error
 	Errors(opts ...Option) []error
 	Filter(apply Predicate, opts ...Option) Observable
+	FilterE(apply PredicateE, opts ...Option) Observable
 	Find(find Predicate, opts ...Option) OptionalSingle
 	First(opts ...Option) OptionalSingle
 	FirstOrDefault(defaultValue interface{}, opts ...Option) Single
@@ -47,19 +57,32 @@ type Observable interface {
 	ForEach(nextFunc NextFunc, errFunc ErrFunc, completedFunc CompletedFunc, opts ...Option) Disposed
 	GroupBy(length int, distribution func(Item) int, opts ...Option) Observable
 	GroupByDynamic(distribution func(Item) string, opts ...Option) Observable
+	GroupJoin(right Observable, leftWindow, rightWindow ItemToObservable, resultSelector Func2, opts ...Option) Observable
 	IgnoreElements(opts ...Option) Observable
 	Join(joiner Func2, right Observable, timeExtractor func(interface{}) time.Time, window Duration, opts ...Option) Observable
+	JoinWindows(right Observable, leftWindow, rightWindow ItemToObservable, resultSelector Func2, opts ...Option) Observable
 	Last(opts ...Option) OptionalSingle
 	LastOrDefault(defaultValue interface{}, opts ...Option) Single
+	LimitConcurrency(n int, apply ItemToObservable, opts ...Option) Observable
 	Map(apply Func, opts ...Option) Observable
+	MapE(apply Func, opts ...Option) Observable
+	MapParallel(apply Func, n int, opts ...Option) Observable
 	Marshal(marshaller Marshaller, opts ...Option) Observable
 	Max(comparator Comparator, opts ...Option) OptionalSingle
 	Min(comparator Comparator, opts ...Option) OptionalSingle
+	MovingAverage(window int, opts ...Option) Observable
+	MovingMax(window int, opts ...Option) Observable
+	MovingMin(window int, opts ...Option) Observable
+	Named(stage string) Observable
+	ObserveOn(scheduler Scheduler, opts ...Option) Observable
 	OnErrorResumeNext(resumeSequence ErrorToObservable, opts ...Option) Observable
 	OnErrorReturn(resumeFunc ErrorFunc, opts ...Option) Observable
 	OnErrorReturnItem(resume interface{}, opts ...Option) Observable
+	Percentile(window int, p float64, opts ...Option) Observable
+	RateLimit(rate float64, burst int, opts ...Option) Observable
 	Reduce(apply Func2, opts ...Option) OptionalSingle
 	Repeat(count int64, frequency Duration, opts ...Option) Observable
+	Resequence(seqSelector func(interface{}) uint64, maxGap uint64, timeout Duration, opts ...Option) Observable
 	Retry(count int, shouldRetry func(error) bool, opts ...Option) Observable
 	Run(opts ...Option) Disposed
 	Sample(iterable Iterable, opts ...Option) Observable
@@ -67,10 +90,13 @@ type Observable interface {
 	SequenceEqual(iterable Iterable, opts ...Option) Single
 	Send(output chan<- Item, opts ...Option)
 	Serialize(from int, identifier func(interface{}) int, opts ...Option) Observable
+	SideOutput(name string) Observable
 	Skip(nth uint, opts ...Option) Observable
 	SkipLast(nth uint, opts ...Option) Observable
 	SkipWhile(apply Predicate, opts ...Option) Observable
+	SkipWhileE(apply PredicateE, opts ...Option) Observable
 	StartWith(iterable Iterable, opts ...Option) Observable
+	SubscribeOn(scheduler Scheduler, opts ...Option) Observable
 	SumFloat32(opts ...Option) OptionalSingle
 	SumFloat64(opts ...Option) OptionalSingle
 	SumInt64(opts ...Option) OptionalSingle
@@ -78,13 +104,17 @@ type Observable interface {
 	TakeLast(nth uint, opts ...Option) Observable
 	TakeUntil(apply Predicate, opts ...Option) Observable
 	TakeWhile(apply Predicate, opts ...Option) Observable
+	TakeWhileE(apply PredicateE, opts ...Option) Observable
 	TimeInterval(opts ...Option) Observable
 	Timestamp(opts ...Option) Observable
+	ToList(initialCapacity int, opts ...Option) Single
 	ToMap(keySelector Func, opts ...Option) Single
 	ToMapWithValueSelector(keySelector, valueSelector Func, opts ...Option) Single
 	ToSlice(initialCapacity int, opts ...Option) ([]interface{}, error)
 	Unmarshal(unmarshaller Unmarshaller, factory func() interface{}, opts ...Option) Observable
+	Wait(ctx context.Context, opts ...Option) error
 	WindowWithCount(count int, opts ...Option) Observable
+	WindowWithEventTime(timespan Duration, timeExtractor func(interface{}) time.Time, lateness Duration, opts ...Option) Observable
 	WindowWithTime(timespan Duration, opts ...Option) Observable
 	WindowWithTimeOrCount(timespan Duration, count int, opts ...Option) Observable
 	ZipFromIterable(iterable Iterable, zipper Func2, opts ...Option) Observable
@@ -94,6 +124,8 @@ type Observable interface {
 type ObservableImpl struct {
 	parent   context.Context
 	iterable Iterable
+	// sideOutputs are the side outputs of the operator creating the Observable, if any
+	sideOutputs *sideOutputs
 }
 
 func defaultErrorFuncOperator(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
@@ -101,7 +133,11 @@ func defaultErrorFuncOperator(ctx context.Context, item Item, dst chan<- Item, o
 	operatorOptions.stop()
 }
 
-func customObservableOperator(parent context.Context, f func(ctx context.Context, next chan Item, option Option, opts ...Option), opts ...Option) Observable {
+func customObservableOperator(parent context.Context, source Iterable, f func(ctx context.Context, next chan Item, option Option, opts ...Option), opts ...Option) Observable {
+	return withStage(1, newCustomObservableOperator(parent, f, opts...), source)
+}
+
+func newCustomObservableOperator(parent context.Context, f func(ctx context.Context, next chan Item, option Option, opts ...Option), opts ...Option) Observable {
 	option := parseOptions(opts...)
 	next := option.buildChannel()
 	ctx := option.buildContext(parent)
@@ -114,7 +150,11 @@ func customObservableOperator(parent context.Context, f func(ctx context.Context
 	return &ObservableImpl{
 		iterable: newFactoryIterable(func(propagatedOptions ...Option) <-chan Item {
 			mergedOptions := append(opts, propagatedOptions...)
-			go f(ctx, next, option, mergedOptions...)
+			go func() {
+				stageOption := parseOptions(mergedOptions...)
+				defer logPanic(stageOption, stageOption.getStage())
+				f(ctx, next, option, mergedOptions...)
+			}()
 			return next
 		}),
 	}
@@ -128,6 +168,10 @@ type operator interface {
 }
 
 func observable(parent context.Context, iterable Iterable, operatorFactory func() operator, forceSeq, bypassGather bool, opts ...Option) Observable {
+	return withStage(1, newObservable(parent, iterable, operatorFactory, forceSeq, bypassGather, opts...), iterable)
+}
+
+func newObservable(parent context.Context, iterable Iterable, operatorFactory func() operator, forceSeq, bypassGather bool, opts ...Option) Observable {
 	option := parseOptions(opts...)
 	parallel, _ := option.getPool()
 
@@ -266,6 +310,7 @@ func optionalSingle(parent context.Context, iterable Iterable, operatorFactory f
 func runSequential(ctx context.Context, next chan Item, iterable Iterable, operatorFactory func() operator, option Option, opts ...Option) {
 	observe := iterable.Observe(opts...)
 	go func() {
+		defer logPanic(option, option.getStage())
 		op := operatorFactory()
 		stopped := false
 		operator := operatorOptions{
@@ -313,6 +358,7 @@ func runParallel(ctx context.Context, next chan Item, observe <-chan Item, opera
 
 		// Gather
 		go func() {
+			defer logPanic(option, option.getStage())
 			op := operatorFactory()
 			stopped := false
 			operator := operatorOptions{
@@ -343,6 +389,7 @@ func runParallel(ctx context.Context, next chan Item, observe <-chan Item, opera
 	// Scatter
 	for i := 0; i < pool; i++ {
 		go func() {
+			defer logPanic(option, option.getStage())
 			op := operatorFactory()
 			stopped := false
 			operator := operatorOptions{
@@ -385,6 +432,7 @@ func runParallel(ctx context.Context, next chan Item, observe <-chan Item, opera
 
 func runFirstItem(ctx context.Context, f func(interface{}) int, notif chan Item, observe <-chan Item, next chan Item, operatorFactory func() operator, option Option, opts ...Option) {
 	go func() {
+		defer logPanic(option, option.getStage())
 		op := operatorFactory()
 		stopped := false
 		operator := operatorOptions{
@@ -12,6 +12,65 @@ import (
 	"github.com/emirpasic/gods/trees/binaryheap"
 )
 
+// AggregateByKey maintains a running aggregate per key, returned by keySelector, accumulating each item into
+// the aggregate of its key with acc, starting from seed. The aggregates are emitted as KeyedAggregate snapshots
+// according to the flush policy, and once the Observable completes. As seed is shared by the keys, acc has to
+// return a new aggregate instead of mutating it.
+func (o *ObservableImpl) AggregateByKey(keySelector Func, seed interface{}, acc Func2, policy FlushPolicy, opts ...Option) Observable {
+	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
+		defer close(next)
+		observe := o.Observe(opts...)
+		aggregator := newKeyedAggregator(seed, acc, policy, newStateStore(option))
+		var tick <-chan time.Time
+		if policy.Interval > 0 {
+			ticker := option.getClock().NewTicker(policy.Interval)
+			defer ticker.Stop()
+			tick = ticker.C()
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-observe:
+				if !ok {
+					aggregator.flush(ctx, next)
+					return
+				}
+				if item.Error() {
+					if !item.SendContext(ctx, next) || option.getErrorStrategy() == StopOnError {
+						return
+					}
+					continue
+				}
+				key, err := keySelector(ctx, item.V)
+				var due *keyedState
+				if err == nil {
+					due, err = aggregator.add(ctx, key, item.V)
+				}
+				if err != nil {
+					if !Error(err).SendContext(ctx, next) || option.getErrorStrategy() == StopOnError {
+						return
+					}
+					continue
+				}
+				if !aggregator.emitEvicted(ctx, next) {
+					return
+				}
+				if due != nil && !Of(aggregator.snapshot(key, due)).SendContext(ctx, next) {
+					return
+				}
+			case <-tick:
+				if !aggregator.flush(ctx, next) {
+					return
+				}
+			}
+		}
+	}
+
+	return customObservableOperator(o.parent, o, f, opts...)
+}
+
 // All determines whether all items emitted by an Observable meet some criteria.
 func (o *ObservableImpl) All(predicate Predicate, opts ...Option) Single {
 	return single(o.parent, o, func() operator {
@@ -378,7 +437,7 @@ func (o *ObservableImpl) BackOffRetry(backOffCfg backoff.BackOff, opts ...Option
 		}
 	}
 	go func() {
-		if err := backoff.Retry(f, backOffCfg); err != nil {
+		if err := backoff.RetryNotifyWithTimer(f, backOffCfg, nil, &backOffTimer{clock: option.getClock()}); err != nil {
 			Error(err).SendContext(ctx, next)
 			close(next)
 			return
@@ -386,9 +445,82 @@ func (o *ObservableImpl) BackOffRetry(backOffCfg backoff.BackOff, opts ...Option
 		close(next)
 	}()
 
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newChannelIterable(next),
+	}, o)
+}
+
+// BlockingFirst blocks until the Observable emits its first item and returns it.
+// It returns the error if the first notification is an error, a NoSuchElementError
+// if the Observable completes without emitting, or the context error once canceled.
+func (o *ObservableImpl) BlockingFirst(ctx context.Context) (interface{}, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case item, ok := <-o.Observe(WithContext(ctx)):
+		if !ok {
+			return nil, NoSuchElementError{error: "observable completed without emitting"}
+		}
+		return item.V, item.E
+	}
+}
+
+// BlockingForEach blocks until the Observable completes, calling nextFunc for each item.
+// It returns the first error emitted by the Observable or the context error once canceled.
+func (o *ObservableImpl) BlockingForEach(ctx context.Context, nextFunc NextFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	observe := o.Observe(WithContext(ctx))
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-observe:
+			if !ok {
+				return nil
+			}
+			if item.Error() {
+				return item.E
+			}
+			nextFunc(item.V)
+		}
+	}
+}
+
+// BlockingLast blocks until the Observable completes and returns its last item.
+// It returns the first error emitted by the Observable, a NoSuchElementError
+// if the Observable completes without emitting, or the context error once canceled.
+func (o *ObservableImpl) BlockingLast(ctx context.Context) (interface{}, error) {
+	var last interface{}
+	found := false
+	err := o.BlockingForEach(ctx, func(i interface{}) {
+		last = i
+		found = true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, NoSuchElementError{error: "observable completed without emitting"}
+	}
+	return last, nil
+}
+
+// BlockingToSlice blocks until the Observable completes and returns all its items.
+// It returns the first error emitted by the Observable or the context error once canceled.
+func (o *ObservableImpl) BlockingToSlice(ctx context.Context) ([]interface{}, error) {
+	s := make([]interface{}, 0)
+	err := o.BlockingForEach(ctx, func(i interface{}) {
+		s = append(s, i)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return s, nil
 }
 
 // BufferWithCount returns an Observable that emits buffers of items it collects
@@ -469,17 +601,24 @@ func (o *ObservableImpl) BufferWithTime(timespan Duration, opts ...Option) Obser
 
 		go func() {
 			defer close(next)
+			clock := option.getClock()
 			duration := timespan.duration()
+			var timer ClockTimer
+			defer func() {
+				timer.Stop()
+			}()
 			for {
+				timer = clock.NewTimer(duration)
 				select {
 				case <-stop:
 					checkBuffer()
 					return
 				case <-ctx.Done():
 					return
-				case <-time.After(duration):
+				case <-timer.C():
 					checkBuffer()
 				}
+				timer.Stop()
 			}
 		}()
 
@@ -508,7 +647,7 @@ func (o *ObservableImpl) BufferWithTime(timespan Duration, opts ...Option) Obser
 		}
 	}
 
-	return customObservableOperator(o.parent, f, opts...)
+	return customObservableOperator(o.parent, o, f, opts...)
 }
 
 // BufferWithTimeOrCount returns an Observable that emits buffers of items it collects from the source
@@ -542,8 +681,14 @@ func (o *ObservableImpl) BufferWithTimeOrCount(timespan Duration, count int, opt
 
 		go func() {
 			defer close(next)
+			clock := option.getClock()
 			duration := timespan.duration()
+			var timer ClockTimer
+			defer func() {
+				timer.Stop()
+			}()
 			for {
+				timer = clock.NewTimer(duration)
 				select {
 				case <-send:
 					checkBuffer()
@@ -552,9 +697,10 @@ func (o *ObservableImpl) BufferWithTimeOrCount(timespan Duration, count int, opt
 					return
 				case <-ctx.Done():
 					return
-				case <-time.After(duration):
+				case <-timer.C():
 					checkBuffer()
 				}
+				timer.Stop()
 			}
 		}()
 
@@ -589,7 +735,59 @@ func (o *ObservableImpl) BufferWithTimeOrCount(timespan Duration, count int, opt
 		}
 	}
 
-	return customObservableOperator(o.parent, f, opts...)
+	return customObservableOperator(o.parent, o, f, opts...)
+}
+
+// CircuitBreaker guards the observation of an Observable, typically a call to a remote service made within
+// FlatMap, with a CircuitBreaker shared by the calls. While the circuit is closed, the Observable is observed and
+// its outcome recorded: a failure once it emits an error judged as such by the policy, a success once it
+// completes otherwise. While the circuit is open, the Observable is not observed and ErrCircuitOpen is emitted,
+// or nothing with the Drop policy. Once the open timeout elapsed, a single observation probes the service,
+// closing the circuit if it succeeds.
+//
+// ErrCircuitOpen can be told apart by the shouldRetry function of Retry, or used to stop a BackOffRetry.
+func (o *ObservableImpl) CircuitBreaker(breaker *CircuitBreaker, opts ...Option) Observable {
+	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
+		defer close(next)
+		allowed, probe := breaker.allow()
+		if !allowed {
+			if !breaker.policy.Drop {
+				Error(ErrCircuitOpen).SendContext(ctx, next)
+			}
+			return
+		}
+
+		observe := o.Observe(opts...)
+		recorded := false
+		defer func() {
+			if !recorded {
+				breaker.release(probe)
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-observe:
+				if !ok {
+					if !recorded {
+						breaker.record(false, probe)
+						recorded = true
+					}
+					return
+				}
+				if item.Error() && !recorded && breaker.policy.IsFailure(item.E) {
+					breaker.record(true, probe)
+					recorded = true
+				}
+				if !item.SendContext(ctx, next) {
+					return
+				}
+			}
+		}
+	}
+
+	return customObservableOperator(o.parent, o, f, opts...)
 }
 
 // Connect instructs a connectable Observable to begin emitting items to its subscribers.
@@ -599,6 +797,29 @@ func (o *ObservableImpl) Connect(ctx context.Context) (context.Context, Disposab
 	return ctx, Disposable(cancel)
 }
 
+// connectableIterable is an Iterable which can be disconnected.
+type connectableIterable interface {
+	connectWithCancel(ctx context.Context) (Disposable, error)
+}
+
+// ConnectWithCancel instructs a connectable Observable to begin emitting items to its subscribers, like
+// Connect. The returned Disposable disconnects the Observable without terminating its subscribers, so that
+// ConnectWithCancel can be called again to resume the emission. Cancelling ctx terminates the subscribers.
+//
+// ErrNotConnectable is returned if the Observable was not created from a channel or a producer with
+// WithPublishStrategy, and ErrAlreadyConnected if it is connected or its source completed.
+func (o *ObservableImpl) ConnectWithCancel(ctx context.Context) (Disposable, error) {
+	iterable := o.iterable
+	if it, ok := iterable.(*stageIterable); ok {
+		iterable = it.Iterable
+	}
+	connectable, ok := iterable.(connectableIterable)
+	if !ok {
+		return nil, ErrNotConnectable
+	}
+	return connectable.connectWithCancel(ctx)
+}
+
 // Contains determines whether an Observable emits a particular item or not.
 func (o *ObservableImpl) Contains(equal Predicate, opts ...Option) Single {
 	return single(o.parent, o, func() operator {
@@ -671,9 +892,15 @@ func (o *ObservableImpl) Debounce(timespan Duration, opts ...Option) Observable
 	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
 		defer close(next)
 		observe := o.Observe(opts...)
+		clock := option.getClock()
 		var latest interface{}
+		var timer ClockTimer
+		defer func() {
+			timer.Stop()
+		}()
 
 		for {
+			timer = clock.NewTimer(timespan.duration())
 			select {
 			case <-ctx.Done():
 				return
@@ -691,7 +918,7 @@ func (o *ObservableImpl) Debounce(timespan Duration, opts ...Option) Observable
 				} else {
 					latest = item.V
 				}
-			case <-time.After(timespan.duration()):
+			case <-timer.C():
 				if latest != nil {
 					if !Of(latest).SendContext(ctx, next) {
 						return
@@ -699,10 +926,85 @@ func (o *ObservableImpl) Debounce(timespan Duration, opts ...Option) Observable
 					latest = nil
 				}
 			}
+			timer.Stop()
+		}
+	}
+
+	return customObservableOperator(o.parent, o, f, opts...)
+}
+
+// DeduplicateWithin suppresses the items whose key, returned by keySelector, was already seen within the window.
+// An item opens the window of its key, the duplicates suppressed within the window not extending it, and the
+// keys are forgotten once their window elapsed so that the memory is bounded by the keys seen within a window.
+// The suppressed items are emitted to the DuplicateOutput side output.
+func (o *ObservableImpl) DeduplicateWithin(window Duration, keySelector Func, opts ...Option) Observable {
+	option := parseOptions(opts...)
+	outputs := &sideOutputs{}
+	return withSideOutputs(observable(o.parent, o, func() operator {
+		return &deduplicateWithinOperator{
+			window:      window,
+			keySelector: keySelector,
+			clock:       option.getClock(),
+			store:       newStateStore(option),
+			outputs:     outputs,
+		}
+	}, true, false, opts...), outputs)
+}
+
+// seenKey is a key along with the time it was seen at
+type seenKey struct {
+	key interface{}
+	at  time.Time
+}
+
+type deduplicateWithinOperator struct {
+	window      Duration
+	keySelector Func
+	clock       Clock
+	// store holds the time each key was seen at
+	store StateStore
+	// order holds the seen keys in time order, to forget them once their window elapsed
+	order   []seenKey
+	outputs *sideOutputs
+}
+
+func (op *deduplicateWithinOperator) next(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	key, err := op.keySelector(ctx, item.V)
+	if err != nil {
+		Error(err).SendContext(ctx, dst)
+		operatorOptions.stop()
+		return
+	}
+
+	now := op.clock.Now()
+	window := op.window.duration()
+	for len(op.order) > 0 && now.Sub(op.order[0].at) >= window {
+		oldest := op.order[0]
+		if at, exists := op.store.Get(oldest.key); exists && at.(time.Time).Equal(oldest.at) {
+			op.store.Delete(oldest.key)
 		}
+		op.order[0] = seenKey{}
+		op.order = op.order[1:]
+	}
+	// the store may hold keys seen before its window
+	if at, exists := op.store.Get(key); exists && now.Sub(at.(time.Time)) < window {
+		op.outputs.emit(DuplicateOutput, item)
+		return
 	}
+	op.store.Put(key, now)
+	op.order = append(op.order, seenKey{key: key, at: now})
+	item.SendContext(ctx, dst)
+}
+
+func (op *deduplicateWithinOperator) err(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	defaultErrorFuncOperator(ctx, item, dst, operatorOptions)
+}
+
+func (op *deduplicateWithinOperator) end(_ context.Context, _ chan<- Item) {
+	op.outputs.complete()
+}
 
-	return customObservableOperator(o.parent, f, opts...)
+func (op *deduplicateWithinOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
 }
 
 // DefaultIfEmpty returns an Observable that emits the items emitted by the source
@@ -740,19 +1042,21 @@ func (op *defaultIfEmptyOperator) gatherNext(_ context.Context, _ Item, _ chan<-
 }
 
 // Distinct suppresses duplicate items in the original Observable and returns
-// a new Observable.
+// a new Observable. The keys seen are kept in the store set by WithStateStore,
+// an evicted key being emitted again.
 func (o *ObservableImpl) Distinct(apply Func, opts ...Option) Observable {
+	option := parseOptions(opts...)
 	return observable(o.parent, o, func() operator {
 		return &distinctOperator{
 			apply:  apply,
-			keyset: make(map[interface{}]interface{}),
+			keyset: newStateStore(option),
 		}
 	}, false, false, opts...)
 }
 
 type distinctOperator struct {
 	apply  Func
-	keyset map[interface{}]interface{}
+	keyset StateStore
 }
 
 func (op *distinctOperator) next(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
@@ -762,11 +1066,11 @@ func (op *distinctOperator) next(ctx context.Context, item Item, dst chan<- Item
 		operatorOptions.stop()
 		return
 	}
-	_, ok := op.keyset[key]
+	_, ok := op.keyset.Get(key)
 	if !ok {
 		item.SendContext(ctx, dst)
 	}
-	op.keyset[key] = nil
+	op.keyset.Put(key, nil)
 }
 
 func (op *distinctOperator) err(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
@@ -782,9 +1086,9 @@ func (op *distinctOperator) gatherNext(ctx context.Context, item Item, dst chan<
 		return
 	}
 
-	if _, contains := op.keyset[item.V]; !contains {
+	if _, contains := op.keyset.Get(item.V); !contains {
 		Of(item.V).SendContext(ctx, dst)
-		op.keyset[item.V] = nil
+		op.keyset.Put(item.V, nil)
 	}
 }
 
@@ -828,9 +1132,12 @@ func (op *distinctUntilChangedOperator) gatherNext(_ context.Context, _ Item, _
 
 // DoOnCompleted registers a callback action that will be called once the Observable terminates.
 func (o *ObservableImpl) DoOnCompleted(completedFunc CompletedFunc, opts ...Option) Disposed {
+	option := parseOptions(opts...)
+	completedFunc = completedFunc.on(option.getScheduler())
 	dispose := make(chan struct{})
 	handler := func(ctx context.Context, src <-chan Item) {
 		defer close(dispose)
+		defer logPanic(option, stageOf(o.iterable))
 		defer completedFunc()
 		for {
 			select {
@@ -840,24 +1147,27 @@ func (o *ObservableImpl) DoOnCompleted(completedFunc CompletedFunc, opts ...Opti
 				if !ok {
 					return
 				}
-				if i.Error() {
+				if i.Error() && option.getErrorStrategy() == StopOnError {
 					return
 				}
 			}
 		}
 	}
 
-	option := parseOptions(opts...)
 	ctx := option.buildContext(o.parent)
 	go handler(ctx, o.Observe(opts...))
 	return dispose
 }
 
 // DoOnError registers a callback action that will be called if the Observable terminates abnormally.
+// With the ContinueOnError strategy, the callback is called for each error until the Observable completes.
 func (o *ObservableImpl) DoOnError(errFunc ErrFunc, opts ...Option) Disposed {
+	option := parseOptions(opts...)
+	errFunc = errFunc.on(option.getScheduler())
 	dispose := make(chan struct{})
 	handler := func(ctx context.Context, src <-chan Item) {
 		defer close(dispose)
+		defer logPanic(option, stageOf(o.iterable))
 		for {
 			select {
 			case <-ctx.Done():
@@ -868,13 +1178,14 @@ func (o *ObservableImpl) DoOnError(errFunc ErrFunc, opts ...Option) Disposed {
 				}
 				if i.Error() {
 					errFunc(i.E)
-					return
+					if option.getErrorStrategy() == StopOnError {
+						return
+					}
 				}
 			}
 		}
 	}
 
-	option := parseOptions(opts...)
 	ctx := option.buildContext(o.parent)
 	go handler(ctx, o.Observe(opts...))
 	return dispose
@@ -882,9 +1193,12 @@ func (o *ObservableImpl) DoOnError(errFunc ErrFunc, opts ...Option) Disposed {
 
 // DoOnNext registers a callback action that will be called on each item emitted by the Observable.
 func (o *ObservableImpl) DoOnNext(nextFunc NextFunc, opts ...Option) Disposed {
+	option := parseOptions(opts...)
+	nextFunc = nextFunc.guarded(deadLetterSinkOf(o.iterable), option.getLogger(), stageOf(o.iterable)).on(option.getScheduler())
 	dispose := make(chan struct{})
 	handler := func(ctx context.Context, src <-chan Item) {
 		defer close(dispose)
+		defer logPanic(option, stageOf(o.iterable))
 		for {
 			select {
 			case <-ctx.Done():
@@ -894,14 +1208,16 @@ func (o *ObservableImpl) DoOnNext(nextFunc NextFunc, opts ...Option) Disposed {
 					return
 				}
 				if i.Error() {
-					return
+					if option.getErrorStrategy() == StopOnError {
+						return
+					}
+					continue
 				}
 				nextFunc(i.V)
 			}
 		}
 	}
 
-	option := parseOptions(opts...)
 	ctx := option.buildContext(o.parent)
 	go handler(ctx, o.Observe(opts...))
 	return dispose
@@ -1018,6 +1334,41 @@ func (op *filterOperator) end(_ context.Context, _ chan<- Item) {
 func (op *filterOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
 }
 
+// FilterE emits only those items from an Observable that pass a fallible predicate test. An error returned by
+// the predicate is handled per the policy set with WithPredicateErrorPolicy.
+func (o *ObservableImpl) FilterE(apply PredicateE, opts ...Option) Observable {
+	policy := parseOptions(opts...).getPredicateErrorPolicy()
+	outputs := &sideOutputs{}
+	return withSideOutputs(observable(o.parent, o, func() operator {
+		return &filterEOperator{fallibleMatch{apply: apply, policy: policy, outputs: outputs}}
+	}, false, false, opts...), outputs)
+}
+
+type filterEOperator struct {
+	fallibleMatch
+}
+
+func (op *filterEOperator) next(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	if match, ok := op.test(ctx, item, dst, operatorOptions); ok && match {
+		item.SendContext(ctx, dst)
+	}
+}
+
+func (op *filterEOperator) err(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	defaultErrorFuncOperator(ctx, item, dst, operatorOptions)
+}
+
+func (op *filterEOperator) end(_ context.Context, _ chan<- Item) {
+	op.outputs.complete()
+}
+
+func (op *filterEOperator) gatherNext(ctx context.Context, item Item, dst chan<- Item, _ operatorOptions) {
+	if _, ok := item.V.(*filterEOperator); ok {
+		return
+	}
+	item.SendContext(ctx, dst)
+}
+
 // Find emits the first item passing a predicate then complete.
 func (o *ObservableImpl) Find(find Predicate, opts ...Option) OptionalSingle {
 	return optionalSingle(o.parent, o, func() operator {
@@ -1147,14 +1498,19 @@ func (o *ObservableImpl) FlatMap(apply ItemToObservable, opts ...Option) Observa
 		}
 	}
 
-	return customObservableOperator(o.parent, f, opts...)
+	return customObservableOperator(o.parent, o, f, opts...)
 }
 
 // ForEach subscribes to the Observable and receives notifications for each element.
 func (o *ObservableImpl) ForEach(nextFunc NextFunc, errFunc ErrFunc, completedFunc CompletedFunc, opts ...Option) Disposed {
+	option := parseOptions(opts...)
+	scheduler := option.getScheduler()
+	nextFunc = nextFunc.guarded(deadLetterSinkOf(o.iterable), option.getLogger(), stageOf(o.iterable))
+	nextFunc, errFunc, completedFunc = nextFunc.on(scheduler), errFunc.on(scheduler), completedFunc.on(scheduler)
 	dispose := make(chan struct{})
 	handler := func(ctx context.Context, src <-chan Item) {
 		defer close(dispose)
+		defer logPanic(option, stageOf(o.iterable))
 		for {
 			select {
 			case <-ctx.Done():
@@ -1174,11 +1530,7 @@ func (o *ObservableImpl) ForEach(nextFunc NextFunc, errFunc ErrFunc, completedFu
 		}
 	}
 
-	ctx := o.parent
-	if ctx == nil {
-		ctx = context.Background()
-	}
-	go handler(ctx, o.Observe(opts...))
+	go handler(option.buildContext(o.parent), o.Observe(opts...))
 	return dispose
 }
 
@@ -1298,7 +1650,37 @@ func (o *ObservableImpl) Join(joiner Func2, right Observable, timeExtractor func
 		}
 	}
 
-	return customObservableOperator(o.parent, f, opts...)
+	return customObservableOperator(o.parent, o, f, opts...)
+}
+
+// JoinWindows correlates the items of the Observable with the items of right whose windows overlap, emitting
+// the result of resultSelector for each pair of a left and a right item. The window of an item opens when it
+// is emitted and closes once the Observable returned by its window selector emits or completes: Never keeps it
+// open, Timer closes it after a duration.
+func (o *ObservableImpl) JoinWindows(right Observable, leftWindow, rightWindow ItemToObservable, resultSelector Func2, opts ...Option) Observable {
+	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
+		defer close(next)
+		j := newWindowJoin(ctx, next, option, leftWindow, rightWindow)
+		j.onLeft = func(l *joinWindow) bool {
+			for _, r := range j.rights {
+				if !j.result(resultSelector(j.ctx, l.value, r.value)) {
+					return false
+				}
+			}
+			return true
+		}
+		j.onRight = func(r *joinWindow) bool {
+			for _, l := range j.lefts {
+				if !j.result(resultSelector(j.ctx, l.value, r.value)) {
+					return false
+				}
+			}
+			return true
+		}
+		j.run(o.Observe(opts...), right.Observe(opts...))
+	}
+
+	return withStage(0, newCustomObservableOperator(o.parent, f, opts...), o, right)
 }
 
 // GroupBy divides an Observable into a set of Observables that each emit a different group of items from the original Observable, organized by key.
@@ -1345,9 +1727,9 @@ func (o *ObservableImpl) GroupBy(length int, distribution func(Item) int, opts .
 		}
 	}()
 
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newSliceIterable(s, opts...),
-	}
+	}, o)
 }
 
 // GroupedObservable is the observable type emitted by the GroupByDynamic operator.
@@ -1358,11 +1740,17 @@ type GroupedObservable struct {
 }
 
 // GroupByDynamic divides an Observable into a dynamic set of Observables that each emit GroupedObservable from the original Observable, organized by key.
+// The groups are kept in the store set by WithStateStore, an evicted group completing. A group is written to the
+// store on each of its items.
 func (o *ObservableImpl) GroupByDynamic(distribution func(Item) string, opts ...Option) Observable {
 	option := parseOptions(opts...)
 	next := option.buildChannel()
 	ctx := option.buildContext(o.parent)
-	chs := make(map[string]chan Item)
+	chs := newStateStore(option)
+	// an evicted group completes, the next items of its key making a new group
+	chs.OnEvict(func(_, ch interface{}) {
+		close(ch.(chan Item))
+	})
 
 	go func() {
 		observe := o.Observe(opts...)
@@ -1376,10 +1764,14 @@ func (o *ObservableImpl) GroupByDynamic(distribution func(Item) string, opts ...
 					break loop
 				}
 				idx := distribution(i)
-				ch, contains := chs[idx]
-				if !contains {
+				var ch chan Item
+				if v, contains := chs.Get(idx); contains {
+					ch = v.(chan Item)
+					// the group is written again on each item, so that a TTL only evicts the idle groups
+					chs.Put(idx, ch)
+				} else {
 					ch = option.buildChannel()
-					chs[idx] = ch
+					chs.Put(idx, ch)
 					Of(GroupedObservable{
 						Observable: &ObservableImpl{
 							iterable: newChannelIterable(ch),
@@ -1390,15 +1782,52 @@ func (o *ObservableImpl) GroupByDynamic(distribution func(Item) string, opts ...
 				i.SendContext(ctx, ch)
 			}
 		}
-		for _, ch := range chs {
-			close(ch)
-		}
+		chs.Range(func(_, ch interface{}) bool {
+			close(ch.(chan Item))
+			return true
+		})
 		close(next)
 	}()
 
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newChannelIterable(next),
+	}, o)
+}
+
+// GroupJoin correlates the items of the Observable with the items of right whose windows overlap, emitting the
+// result of resultSelector for each left item along with an Observable of the right items overlapping its
+// window, passed as the second argument. The window of an item opens when it is emitted and closes once the
+// Observable returned by its window selector emits or completes, completing the Observable of a left item.
+//
+// The Observable of each left item has to be consumed, as it blocks the correlation of the next items.
+func (o *ObservableImpl) GroupJoin(right Observable, leftWindow, rightWindow ItemToObservable, resultSelector Func2, opts ...Option) Observable {
+	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
+		defer close(next)
+		j := newWindowJoin(ctx, next, option, leftWindow, rightWindow)
+		j.onLeft = func(l *joinWindow) bool {
+			l.group = option.buildChannel()
+			if !j.result(resultSelector(j.ctx, l.value, &ObservableImpl{iterable: newChannelIterable(l.group)})) {
+				return false
+			}
+			for _, r := range j.rights {
+				if !Of(r.value).SendContext(j.ctx, l.group) {
+					return false
+				}
+			}
+			return true
+		}
+		j.onRight = func(r *joinWindow) bool {
+			for _, l := range j.lefts {
+				if !Of(r.value).SendContext(j.ctx, l.group) {
+					return false
+				}
+			}
+			return true
+		}
+		j.run(o.Observe(opts...), right.Observe(opts...))
 	}
+
+	return withStage(0, newCustomObservableOperator(o.parent, f, opts...), o, right)
 }
 
 // Last returns a new Observable which emit only last item.
@@ -1472,47 +1901,311 @@ func (op *lastOrDefaultOperator) end(ctx context.Context, dst chan<- Item) {
 func (op *lastOrDefaultOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
 }
 
-// Map transforms the items emitted by an Observable by applying a function to each item.
-func (o *ObservableImpl) Map(apply Func, opts ...Option) Observable {
-	return observable(o.parent, o, func() operator {
-		return &mapOperator{apply: apply}
-	}, false, true, opts...)
-}
-
-type mapOperator struct {
-	apply Func
-}
-
-func (op *mapOperator) next(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
-	res, err := op.apply(ctx, item.V)
-	if err != nil {
-		Error(err).SendContext(ctx, dst)
-		operatorOptions.stop()
-		return
+// LimitConcurrency transforms the items emitted by an Observable into Observables like FlatMap, observing up to n
+// of them concurrently, so that the database or the service they call is protected from an unbounded load. The
+// items of the Observables are emitted as they come. By default, the next item waits for one of the
+// Observables to terminate. With WithBackPressureStrategy(Drop), the items arriving while n Observables are in
+// progress are dropped instead.
+func (o *ObservableImpl) LimitConcurrency(n int, apply ItemToObservable, opts ...Option) Observable {
+	if n < 1 {
+		return Thrown(IllegalInputError{error: "concurrency must be at least 1"})
 	}
-	Of(res).SendContext(ctx, dst)
-}
-
-func (op *mapOperator) err(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
-	defaultErrorFuncOperator(ctx, item, dst, operatorOptions)
-}
 
-func (op *mapOperator) end(_ context.Context, _ chan<- Item) {
-}
+	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
+		ctx, cancel := context.WithCancel(ctx)
+		wg := sync.WaitGroup{}
+		defer func() {
+			cancel()
+			wg.Wait()
+			close(next)
+		}()
+		drop := option.getBackPressureStrategy() == Drop
+		stopOnError := option.getErrorStrategy() == StopOnError
+		slots := make(chan struct{}, n)
 
-func (op *mapOperator) gatherNext(ctx context.Context, item Item, dst chan<- Item, _ operatorOptions) {
-	switch item.V.(type) {
-	case *mapOperator:
-		return
-	}
-	item.SendContext(ctx, dst)
-}
+		observe := o.Observe(opts...)
+		for {
+			acquired := false
+			if !drop {
+				select {
+				case <-ctx.Done():
+					return
+				case slots <- struct{}{}:
+					acquired = true
+				}
+			}
 
-// Marshal transforms the items emitted by an Observable by applying a marshalling to each item.
-func (o *ObservableImpl) Marshal(marshaller Marshaller, opts ...Option) Observable {
-	return o.Map(func(_ context.Context, i interface{}) (interface{}, error) {
-		return marshaller(i)
-	}, opts...)
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-observe:
+				if !ok {
+					wg.Wait()
+					return
+				}
+				if item.Error() {
+					if acquired {
+						<-slots
+					}
+					item.SendContext(ctx, next)
+					if stopOnError {
+						return
+					}
+					continue
+				}
+				if !acquired {
+					select {
+					case slots <- struct{}{}:
+					default:
+						// shed
+						continue
+					}
+				}
+
+				wg.Add(1)
+				go func(item Item) {
+					defer wg.Done()
+					defer func() {
+						<-slots
+					}()
+					observe := apply(item).Observe(append(opts, WithContext(ctx))...)
+					for {
+						select {
+						case <-ctx.Done():
+							return
+						case item, ok := <-observe:
+							if !ok {
+								return
+							}
+							if !item.SendContext(ctx, next) {
+								return
+							}
+							if item.Error() && stopOnError {
+								cancel()
+								return
+							}
+						}
+					}
+				}(item)
+			}
+		}
+	}
+
+	return customObservableOperator(o.parent, o, f, opts...)
+}
+
+// Map transforms the items emitted by an Observable by applying a function to each item.
+func (o *ObservableImpl) Map(apply Func, opts ...Option) Observable {
+	return observable(o.parent, o, func() operator {
+		return &mapOperator{apply: apply}
+	}, false, true, opts...)
+}
+
+type mapOperator struct {
+	apply Func
+}
+
+func (op *mapOperator) next(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	res, err := op.apply(ctx, item.V)
+	if err != nil {
+		Error(err).SendContext(ctx, dst)
+		operatorOptions.stop()
+		return
+	}
+	Of(res).SendContext(ctx, dst)
+}
+
+func (op *mapOperator) err(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	defaultErrorFuncOperator(ctx, item, dst, operatorOptions)
+}
+
+func (op *mapOperator) end(_ context.Context, _ chan<- Item) {
+}
+
+func (op *mapOperator) gatherNext(ctx context.Context, item Item, dst chan<- Item, _ operatorOptions) {
+	switch item.V.(type) {
+	case *mapOperator:
+		return
+	}
+	item.SendContext(ctx, dst)
+}
+
+// MapE transforms the items emitted by an Observable by applying a function to each item, like Map, the
+// function being given a context cancelled once the observation stops: when the context set with WithContext
+// is done, when an error stops it with StopOnError, or once the Observable terminated. A call in progress, such
+// as an I/O, is then cancelled instead of being awaited, its result being discarded. An error returned by the
+// function is routed per the error strategy: StopOnError emits it and stops, ContinueOnError emits it and
+// carries on. With WithPool or WithCPUPool, the function is applied concurrently, the results being emitted as
+// they are computed.
+func (o *ObservableImpl) MapE(apply Func, opts ...Option) Observable {
+	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
+		defer close(next)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		observe := o.Observe(opts...)
+		workers := 1
+		if parallel, pool := option.getPool(); parallel {
+			workers = pool
+		}
+		stopOnError := option.getErrorStrategy() == StopOnError
+
+		wg := sync.WaitGroup{}
+		wg.Add(workers)
+		for i := 0; i < workers; i++ {
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case item, ok := <-observe:
+						if !ok {
+							return
+						}
+						if !item.Error() {
+							v, err := apply(ctx, item.V)
+							if ctx.Err() != nil {
+								return
+							}
+							if err != nil {
+								item = Error(err)
+							} else {
+								item = Of(v)
+							}
+						}
+						if !item.SendContext(ctx, next) {
+							return
+						}
+						if item.Error() && stopOnError {
+							cancel()
+							return
+						}
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	return customObservableOperator(o.parent, o, f, opts...)
+}
+
+// MapParallel transforms the items emitted by an Observable by applying a function to each item on n
+// workers, re-emitting the results in the order of the items. The reorder buffer, 2*n by default and set with
+// WithReorderBuffer, bounds the items processed or held ahead of the oldest item not emitted yet: a slow item
+// holds back the next ones once the buffer is full.
+func (o *ObservableImpl) MapParallel(apply Func, n int, opts ...Option) Observable {
+	if n <= 0 {
+		return Thrown(IllegalInputError{error: "n must be positive"})
+	}
+
+	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
+		defer close(next)
+		ctx, cancel := context.WithCancel(ctx)
+		size := option.getReorderBuffer()
+		if size <= 0 {
+			size = 2 * n
+		} else if size < n {
+			size = n
+		}
+		// a slot is taken by each item read until its result is emitted
+		slots := make(chan struct{}, size)
+		jobs := make(chan sequencedItem)
+		results := make(chan sequencedItem)
+		defer func() {
+			cancel()
+			for range results {
+			}
+		}()
+
+		wg := sync.WaitGroup{}
+		wg.Add(n)
+		for i := 0; i < n; i++ {
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					if !job.item.Error() {
+						v, err := apply(ctx, job.item.V)
+						if err != nil {
+							job.item = Error(err)
+						} else {
+							job.item = Of(v)
+						}
+					}
+					select {
+					case <-ctx.Done():
+						return
+					case results <- job:
+					}
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		go func() {
+			defer close(jobs)
+			observe := o.Observe(opts...)
+			for seq := 0; ; seq++ {
+				select {
+				case <-ctx.Done():
+					return
+				case slots <- struct{}{}:
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case item, ok := <-observe:
+					if !ok {
+						return
+					}
+					select {
+					case <-ctx.Done():
+						return
+					case jobs <- sequencedItem{seq: seq, item: item}:
+					}
+				}
+			}
+		}()
+
+		pending := make(map[int]Item)
+		expected := 0
+		for result := range results {
+			pending[result.seq] = result.item
+			for {
+				item, exists := pending[expected]
+				if !exists {
+					break
+				}
+				delete(pending, expected)
+				expected++
+				<-slots
+				if !item.SendContext(ctx, next) {
+					return
+				}
+				if item.Error() && option.getErrorStrategy() == StopOnError {
+					return
+				}
+			}
+		}
+	}
+
+	return customObservableOperator(o.parent, o, f, opts...)
+}
+
+// sequencedItem is an item along with its position in the Observable
+type sequencedItem struct {
+	seq  int
+	item Item
+}
+
+// Marshal transforms the items emitted by an Observable by applying a marshalling to each item.
+func (o *ObservableImpl) Marshal(marshaller Marshaller, opts ...Option) Observable {
+	return o.Map(func(_ context.Context, i interface{}) (interface{}, error) {
+		return marshaller(i)
+	}, opts...)
 }
 
 // Max determines and emits the maximum-valued item emitted by an Observable according to a comparator.
@@ -1599,11 +2292,133 @@ func (op *minOperator) gatherNext(ctx context.Context, item Item, dst chan<- Ite
 	op.next(ctx, Of(item.V.(*minOperator).max), dst, operatorOptions)
 }
 
+// MovingAverage emits, for each numeric item, the mean of the last window items, maintained in O(1).
+func (o *ObservableImpl) MovingAverage(window int, opts ...Option) Observable {
+	return o.slidingStat(window, func() slidingStat {
+		return newMovingAverage(window)
+	}, opts...)
+}
+
+// MovingMax emits, for each numeric item, the maximum of the last window items, maintained in amortized O(1).
+func (o *ObservableImpl) MovingMax(window int, opts ...Option) Observable {
+	return o.slidingStat(window, func() slidingStat {
+		return newMovingMax(window)
+	}, opts...)
+}
+
+// MovingMin emits, for each numeric item, the minimum of the last window items, maintained in amortized O(1).
+func (o *ObservableImpl) MovingMin(window int, opts ...Option) Observable {
+	return o.slidingStat(window, func() slidingStat {
+		return newMovingMin(window)
+	}, opts...)
+}
+
+// Named names the stage of the pipeline producing the Observable, so that the panics, the dead letters, the
+// drops of a subject subscription, the spans and the topology nodes are attributed to it. The name is passed
+// along when the Observable is observed, and so does not apply to the operators already observed with the
+// Eager observation strategy.
+func (o *ObservableImpl) Named(name string) Observable {
+	named := &stageIterable{
+		Iterable: o.iterable,
+		stage:    &stage{name: "Named", label: name, parents: []Iterable{o.iterable}},
+	}
+	if it, ok := o.iterable.(*stageIterable); ok {
+		named = &stageIterable{
+			Iterable: it.Iterable,
+			stage:    &stage{name: it.stage.name, label: name, parents: it.stage.parents},
+		}
+	}
+	return &ObservableImpl{
+		parent:      o.parent,
+		iterable:    named,
+		sideOutputs: o.sideOutputs,
+	}
+}
+
 // Observe observes an Observable by returning its channel.
 func (o *ObservableImpl) Observe(opts ...Option) <-chan Item {
 	return o.iterable.Observe(opts...)
 }
 
+// observeOnBufferSize is the number of items ObserveOn holds before blocking the source
+const observeOnBufferSize = 128
+
+// ObserveOn emits the items of the Observable from tasks run on the scheduler. The order of the items is
+// preserved whatever the scheduler. Up to 128 items are held while waiting for the scheduler.
+func (o *ObservableImpl) ObserveOn(scheduler Scheduler, opts ...Option) Observable {
+	if scheduler == nil {
+		return Thrown(IllegalInputError{error: "scheduler must not be nil"})
+	}
+
+	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
+		observe := o.Observe(opts...)
+		slots := make(chan struct{}, observeOnBufferSize)
+		mutex := sync.Mutex{}
+		pending := make([]Item, 0)
+		draining := false
+		completed := false
+
+		// drain sends the pending items, a single drain runs at a time
+		drain := func() {
+			for {
+				mutex.Lock()
+				if len(pending) == 0 {
+					draining = false
+					if completed {
+						close(next)
+					}
+					mutex.Unlock()
+					return
+				}
+				item := pending[0]
+				pending = pending[1:]
+				mutex.Unlock()
+
+				item.SendContext(ctx, next)
+				<-slots
+			}
+		}
+		schedule := func(update func()) {
+			mutex.Lock()
+			update()
+			start := !draining
+			draining = true
+			mutex.Unlock()
+			if start {
+				scheduler.Schedule(drain)
+			}
+		}
+
+	loop:
+		for {
+			select {
+			case <-ctx.Done():
+				break loop
+			case item, ok := <-observe:
+				if !ok {
+					break loop
+				}
+				select {
+				case <-ctx.Done():
+					break loop
+				case slots <- struct{}{}:
+				}
+				schedule(func() {
+					pending = append(pending, item)
+				})
+				if item.Error() && option.getErrorStrategy() == StopOnError {
+					break loop
+				}
+			}
+		}
+		schedule(func() {
+			completed = true
+		})
+	}
+
+	return customObservableOperator(o.parent, o, f, opts...)
+}
+
 // OnErrorResumeNext instructs an Observable to pass control to another Observable rather than invoking
 // onError if it encounters an error.
 func (o *ObservableImpl) OnErrorResumeNext(resumeSequence ErrorToObservable, opts ...Option) Observable {
@@ -1681,6 +2496,67 @@ func (op *onErrorReturnItemOperator) end(_ context.Context, _ chan<- Item) {
 func (op *onErrorReturnItemOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
 }
 
+// Percentile emits, for each numeric item, the p-th percentile (0 < p <= 100) of the last window items, using
+// the nearest-rank method, maintained in amortized O(log n).
+func (o *ObservableImpl) Percentile(window int, p float64, opts ...Option) Observable {
+	if p <= 0 || p > 100 {
+		return Thrown(IllegalInputError{error: "percentile must be in (0, 100]"})
+	}
+	return o.slidingStat(window, func() slidingStat {
+		return newMovingPercentile(window, p)
+	}, opts...)
+}
+
+// RateLimit limits the rate of the items emitted by an Observable with a token bucket refilled with rate tokens
+// per second and holding up to burst tokens, each item taking a token. By default, an item waits for its token,
+// delaying the next ones. With WithBackPressureStrategy(Drop), the items arriving without token are dropped
+// instead. Errors are not limited.
+//
+// WithLimiter replaces the token bucket by another limiter, such as a rate.Limiter of golang.org/x/time/rate,
+// rate and burst being ignored.
+func (o *ObservableImpl) RateLimit(rate float64, burst int, opts ...Option) Observable {
+	option := parseOptions(opts...)
+	limiter := option.getLimiter()
+	if limiter == nil {
+		if rate <= 0 || burst < 1 {
+			return Thrown(IllegalInputError{error: "rate must be positive and burst at least 1"})
+		}
+		limiter = newTokenBucket(rate, burst, option.getClock())
+	}
+	drop := option.getBackPressureStrategy() == Drop
+
+	return observable(o.parent, o, func() operator {
+		return &rateLimitOperator{limiter: limiter, drop: drop}
+	}, true, false, opts...)
+}
+
+type rateLimitOperator struct {
+	limiter Limiter
+	drop    bool
+}
+
+func (op *rateLimitOperator) next(ctx context.Context, item Item, dst chan<- Item, _ operatorOptions) {
+	if op.drop {
+		if op.limiter.Allow() {
+			item.SendContext(ctx, dst)
+		}
+		return
+	}
+	if op.limiter.Wait(ctx) == nil {
+		item.SendContext(ctx, dst)
+	}
+}
+
+func (op *rateLimitOperator) err(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	defaultErrorFuncOperator(ctx, item, dst, operatorOptions)
+}
+
+func (op *rateLimitOperator) end(_ context.Context, _ chan<- Item) {
+}
+
+func (op *rateLimitOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
+}
+
 // Reduce applies a function to each item emitted by an Observable, sequentially, and emit the final value.
 func (o *ObservableImpl) Reduce(apply Func2, opts ...Option) OptionalSingle {
 	return optionalSingle(o.parent, o, func() operator {
@@ -1735,11 +2611,13 @@ func (o *ObservableImpl) Repeat(count int64, frequency Duration, opts ...Option)
 		}
 	}
 
+	clock := parseOptions(opts...).getClock()
 	return observable(o.parent, o, func() operator {
 		return &repeatOperator{
 			count:     count,
 			frequency: frequency,
 			seq:       make([]Item, 0),
+			clock:     clock,
 		}
 	}, true, false, opts...)
 }
@@ -1748,6 +2626,7 @@ type repeatOperator struct {
 	count     int64
 	frequency Duration
 	seq       []Item
+	clock     Clock
 }
 
 func (op *repeatOperator) next(ctx context.Context, item Item, dst chan<- Item, _ operatorOptions) {
@@ -1772,7 +2651,13 @@ func (op *repeatOperator) end(ctx context.Context, dst chan<- Item) {
 			}
 		}
 		if op.frequency != nil {
-			time.Sleep(op.frequency.duration())
+			timer := op.clock.NewTimer(op.frequency.duration())
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C():
+			}
 		}
 		for _, v := range op.seq {
 			v.SendContext(ctx, dst)
@@ -1784,6 +2669,135 @@ func (op *repeatOperator) end(ctx context.Context, dst chan<- Item) {
 func (op *repeatOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
 }
 
+// SequenceGap is emitted by Resequence in place of the items given up, from the sequence number From to To
+// included.
+type SequenceGap struct {
+	From uint64
+	To   uint64
+}
+
+// Resequence emits the items in the order of their sequence number, returned by seqSelector and starting at 0,
+// buffering the items arriving ahead of the next expected one. Once the next expected item is missing for the
+// timeout, or an item arrives more than maxGap ahead of it, the missing items are given up: a SequenceGap is
+// emitted in their place, followed by the buffered items. The items arriving once their sequence number was
+// emitted or given up are dropped. Errors are emitted as they arrive.
+func (o *ObservableImpl) Resequence(seqSelector func(interface{}) uint64, maxGap uint64, timeout Duration, opts ...Option) Observable {
+	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
+		defer close(next)
+		observe := o.Observe(opts...)
+		clock := option.getClock()
+		r := &resequencer{pending: make(map[uint64]interface{})}
+		var timer ClockTimer
+		// blockedOn is the sequence number missing since the timer started
+		var blockedOn uint64
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		for {
+			var timeoutC <-chan time.Time
+			if timer != nil {
+				timeoutC = timer.C()
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-observe:
+				if !ok {
+					for len(r.pending) > 0 {
+						if !r.skip(ctx, next) {
+							return
+						}
+					}
+					return
+				}
+				if item.Error() {
+					if !item.SendContext(ctx, next) {
+						return
+					}
+					if option.getErrorStrategy() == StopOnError {
+						return
+					}
+					continue
+				}
+				seq := seqSelector(item.V)
+				if _, exists := r.pending[seq]; exists || seq < r.expected {
+					continue
+				}
+				r.pending[seq] = item.V
+				if !r.drain(ctx, next) {
+					return
+				}
+				for seq >= r.expected && seq-r.expected > maxGap {
+					if !r.skip(ctx, next) {
+						return
+					}
+				}
+			case <-timeoutC:
+				timer = nil
+				if !r.skip(ctx, next) {
+					return
+				}
+			}
+
+			if len(r.pending) == 0 || timer == nil || blockedOn != r.expected {
+				if timer != nil {
+					timer.Stop()
+					timer = nil
+				}
+				if len(r.pending) > 0 {
+					timer = clock.NewTimer(timeout.duration())
+					blockedOn = r.expected
+				}
+			}
+		}
+	}
+
+	return customObservableOperator(o.parent, o, f, opts...)
+}
+
+// resequencer buffers the items arriving ahead of the expected sequence number
+type resequencer struct {
+	expected uint64
+	pending  map[uint64]interface{}
+}
+
+// drain emits the buffered items following each other from the expected one
+func (r *resequencer) drain(ctx context.Context, next chan<- Item) bool {
+	for {
+		v, exists := r.pending[r.expected]
+		if !exists {
+			return true
+		}
+		if !Of(v).SendContext(ctx, next) {
+			return false
+		}
+		delete(r.pending, r.expected)
+		r.expected++
+	}
+}
+
+// skip gives up the items missing before the first buffered one, and emits the buffered items following them
+func (r *resequencer) skip(ctx context.Context, next chan<- Item) bool {
+	first := true
+	var lowest uint64
+	for seq := range r.pending {
+		if first || seq < lowest {
+			lowest, first = seq, false
+		}
+	}
+	if first {
+		return true
+	}
+	if !Of(SequenceGap{From: r.expected, To: lowest - 1}).SendContext(ctx, next) {
+		return false
+	}
+	r.expected = lowest
+	return r.drain(ctx, next)
+}
+
 // Retry retries if a source Observable sends an error, resubscribe to it in the hopes that it will complete without error.
 // Cannot be run in parallel.
 func (o *ObservableImpl) Retry(count int, shouldRetry func(error) bool, opts ...Option) Observable {
@@ -1817,9 +2831,9 @@ func (o *ObservableImpl) Retry(count int, shouldRetry func(error) bool, opts ...
 		close(next)
 	}()
 
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newChannelIterable(next),
-	}
+	}, o)
 }
 
 // Run creates an Observer without consuming the emitted items.
@@ -1914,9 +2928,9 @@ func (o *ObservableImpl) Sample(iterable Iterable, opts ...Option) Observable {
 		}
 	}()
 
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newChannelIterable(next),
-	}
+	}, o, iterable)
 }
 
 // Scan apply a Func2 to each item emitted by an Observable, sequentially, and emit each successive value.
@@ -2126,9 +3140,23 @@ func (o *ObservableImpl) Serialize(from int, identifier func(interface{}) int, o
 		}
 	}()
 
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newChannelIterable(next),
+	}, o)
+}
+
+// SideOutput returns an Observable emitting the items the operator creating the Observable emitted to its side
+// output name, such as the late items of WindowWithEventTime on LateOutput, instead of losing them. The side
+// output is hot: it is meant to be observed before the Observable, and completes once an observation of the
+// Observable terminates. The side outputs are delivered like the items of a Subject with the Block strategy:
+// their observers must keep up with the operator.
+//
+// An Observable whose operator has no side outputs returns an empty Observable.
+func (o *ObservableImpl) SideOutput(name string) Observable {
+	if o.sideOutputs == nil {
+		return Empty()
 	}
+	return o.sideOutputs.observe(name)
 }
 
 // Skip suppresses the first n items in the original Observable and
@@ -2237,6 +3265,48 @@ func (op *skipWhileOperator) end(_ context.Context, _ chan<- Item) {
 func (op *skipWhileOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
 }
 
+// SkipWhileE discard items emitted by an Observable until a specified fallible condition becomes false. An
+// error returned by the predicate is handled per the policy set with WithPredicateErrorPolicy, a skipped item
+// being discarded.
+// Cannot be run in parallel.
+func (o *ObservableImpl) SkipWhileE(apply PredicateE, opts ...Option) Observable {
+	policy := parseOptions(opts...).getPredicateErrorPolicy()
+	outputs := &sideOutputs{}
+	return withSideOutputs(observable(o.parent, o, func() operator {
+		return &skipWhileEOperator{
+			fallibleMatch: fallibleMatch{apply: apply, policy: policy, outputs: outputs},
+			skip:          true,
+		}
+	}, true, false, opts...), outputs)
+}
+
+type skipWhileEOperator struct {
+	fallibleMatch
+	skip bool
+}
+
+func (op *skipWhileEOperator) next(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	if op.skip {
+		match, ok := op.test(ctx, item, dst, operatorOptions)
+		if !ok || match {
+			return
+		}
+		op.skip = false
+	}
+	item.SendContext(ctx, dst)
+}
+
+func (op *skipWhileEOperator) err(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	defaultErrorFuncOperator(ctx, item, dst, operatorOptions)
+}
+
+func (op *skipWhileEOperator) end(_ context.Context, _ chan<- Item) {
+	op.outputs.complete()
+}
+
+func (op *skipWhileEOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
+}
+
 // StartWith emits a specified Iterable before beginning to emit the items from the source Observable.
 func (o *ObservableImpl) StartWith(iterable Iterable, opts ...Option) Observable {
 	option := parseOptions(opts...)
@@ -2281,9 +3351,39 @@ func (o *ObservableImpl) StartWith(iterable Iterable, opts ...Option) Observable
 		}
 	}()
 
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newChannelIterable(next),
+	}, iterable, o)
+}
+
+// SubscribeOn observes the Observable from a task run on the scheduler, which also forwards its items.
+// With a single goroutine scheduler, the subscriptions are hence run one at a time.
+func (o *ObservableImpl) SubscribeOn(scheduler Scheduler, opts ...Option) Observable {
+	if scheduler == nil {
+		return Thrown(IllegalInputError{error: "scheduler must not be nil"})
 	}
+
+	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
+		scheduler.Schedule(func() {
+			defer close(next)
+			observe := o.Observe(opts...)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item, ok := <-observe:
+					if !ok {
+						return
+					}
+					if !item.SendContext(ctx, next) {
+						return
+					}
+				}
+			}
+		})
+	}
+
+	return customObservableOperator(o.parent, o, f, opts...)
 }
 
 // SumFloat32 calculates the average of float32 emitted by an Observable and emits a float32.
@@ -2512,12 +3612,53 @@ func (op *takeWhileOperator) end(_ context.Context, _ chan<- Item) {
 func (op *takeWhileOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
 }
 
+// TakeWhileE returns an Observable that emits items emitted by the source ObservableSource so long as each
+// item satisfied a specified fallible condition, and then completes as soon as this condition is not
+// satisfied. An error returned by the predicate is handled per the policy set with WithPredicateErrorPolicy, a
+// skipped item being neither emitted nor completing the Observable.
+// Cannot be run in parallel.
+func (o *ObservableImpl) TakeWhileE(apply PredicateE, opts ...Option) Observable {
+	policy := parseOptions(opts...).getPredicateErrorPolicy()
+	outputs := &sideOutputs{}
+	return withSideOutputs(observable(o.parent, o, func() operator {
+		return &takeWhileEOperator{fallibleMatch{apply: apply, policy: policy, outputs: outputs}}
+	}, true, false, opts...), outputs)
+}
+
+type takeWhileEOperator struct {
+	fallibleMatch
+}
+
+func (op *takeWhileEOperator) next(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	match, ok := op.test(ctx, item, dst, operatorOptions)
+	if !ok {
+		return
+	}
+	if !match {
+		operatorOptions.stop()
+		return
+	}
+	item.SendContext(ctx, dst)
+}
+
+func (op *takeWhileEOperator) err(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	defaultErrorFuncOperator(ctx, item, dst, operatorOptions)
+}
+
+func (op *takeWhileEOperator) end(_ context.Context, _ chan<- Item) {
+	op.outputs.complete()
+}
+
+func (op *takeWhileEOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
+}
+
 // TimeInterval converts an Observable that emits items into one that emits indications of the amount of time elapsed between those emissions.
 func (o *ObservableImpl) TimeInterval(opts ...Option) Observable {
 	f := func(ctx context.Context, next chan Item, option Option, opts ...Option) {
 		defer close(next)
 		observe := o.Observe(opts...)
-		latest := time.Now().UTC()
+		clock := option.getClock()
+		latest := clock.Now().UTC()
 
 		for {
 			select {
@@ -2535,7 +3676,7 @@ func (o *ObservableImpl) TimeInterval(opts ...Option) Observable {
 						return
 					}
 				} else {
-					now := time.Now().UTC()
+					now := clock.Now().UTC()
 					if !Of(now.Sub(latest)).SendContext(ctx, next) {
 						return
 					}
@@ -2545,22 +3686,24 @@ func (o *ObservableImpl) TimeInterval(opts ...Option) Observable {
 		}
 	}
 
-	return customObservableOperator(o.parent, f, opts...)
+	return customObservableOperator(o.parent, o, f, opts...)
 }
 
 // Timestamp attaches a timestamp to each item emitted by an Observable indicating when it was emitted.
 func (o *ObservableImpl) Timestamp(opts ...Option) Observable {
+	clock := parseOptions(opts...).getClock()
 	return observable(o.parent, o, func() operator {
-		return &timestampOperator{}
+		return &timestampOperator{clock: clock}
 	}, true, false, opts...)
 }
 
 type timestampOperator struct {
+	clock Clock
 }
 
 func (op *timestampOperator) next(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
 	Of(TimestampItem{
-		Timestamp: time.Now().UTC(),
+		Timestamp: op.clock.Now().UTC(),
 		V:         item.V,
 	}).SendContext(ctx, dst)
 }
@@ -2575,6 +3718,45 @@ func (op *timestampOperator) end(_ context.Context, _ chan<- Item) {
 func (op *timestampOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
 }
 
+// ToList collects all items from an Observable and returns a Single emitting them in a slice
+// once the Observable completes. With the ContinueOnError strategy, the errors are emitted and the slice
+// collects the other items.
+// Cannot be run in parallel.
+func (o *ObservableImpl) ToList(initialCapacity int, opts ...Option) Single {
+	stopOnError := parseOptions(opts...).getErrorStrategy() == StopOnError
+	return single(o.parent, o, func() operator {
+		return &toListOperator{
+			s:           make([]interface{}, 0, initialCapacity),
+			stopOnError: stopOnError,
+		}
+	}, true, false, opts...)
+}
+
+type toListOperator struct {
+	s           []interface{}
+	stopOnError bool
+	// stopped is set once an error stopped the Observable, the slice not being emitted
+	stopped bool
+}
+
+func (op *toListOperator) next(_ context.Context, item Item, _ chan<- Item, _ operatorOptions) {
+	op.s = append(op.s, item.V)
+}
+
+func (op *toListOperator) err(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	op.stopped = op.stopOnError
+	defaultErrorFuncOperator(ctx, item, dst, operatorOptions)
+}
+
+func (op *toListOperator) end(ctx context.Context, dst chan<- Item) {
+	if !op.stopped {
+		Of(op.s).SendContext(ctx, dst)
+	}
+}
+
+func (op *toListOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
+}
+
 // ToMap convert the sequence of items emitted by an Observable
 // into a map keyed by a specified key function.
 // Cannot be run in parallel.
@@ -2705,6 +3887,12 @@ func (o *ObservableImpl) Unmarshal(unmarshaller Unmarshaller, factory func() int
 	}, opts...)
 }
 
+// Wait blocks until the Observable completes, returning nil, or emits an error, returning the error.
+// It returns the context error if the context is done first, the observation being stopped.
+func (o *ObservableImpl) Wait(ctx context.Context, opts ...Option) error {
+	return o.Error(append(opts, WithContext(ctx))...)
+}
+
 // WindowWithCount periodically subdivides items from an Observable into Observable windows of a given size and emit these windows
 // rather than emitting the items one at a time.
 func (o *ObservableImpl) WindowWithCount(count int, opts ...Option) Observable {
@@ -2770,6 +3958,104 @@ func (op *windowWithCountOperator) end(_ context.Context, _ chan<- Item) {
 func (op *windowWithCountOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
 }
 
+// EventTimeWindow is a window of items emitted by WindowWithEventTime.
+type EventTimeWindow struct {
+	// Start is the event time the window starts at, included.
+	Start time.Time
+	// End is the event time the window ends at, excluded.
+	End time.Time
+	// Items are the items of the window, in the order they were received.
+	Items []interface{}
+}
+
+// WindowWithEventTime subdivides the items from an Observable into tumbling windows of timespan based on their
+// event time, extracted with timeExtractor, instead of the time they are received at. The watermark is the
+// latest event time received minus the allowed lateness: a window is emitted once the watermark passed its end,
+// and the items of a window already passed by the watermark are late: they are emitted to the LateOutput side
+// output. The windows still open are emitted once the Observable terminates.
+//
+// As the items of a window are only known once it closes, each window is emitted as an EventTimeWindow.
+func (o *ObservableImpl) WindowWithEventTime(timespan Duration, timeExtractor func(interface{}) time.Time, lateness Duration, opts ...Option) Observable {
+	if timespan == nil || timespan.duration() <= 0 {
+		return Thrown(IllegalInputError{error: "timespan must be positive"})
+	}
+
+	outputs := &sideOutputs{}
+	return withSideOutputs(observable(o.parent, o, func() operator {
+		op := &windowWithEventTimeOperator{
+			timespan:      timespan.duration(),
+			timeExtractor: timeExtractor,
+			outputs:       outputs,
+		}
+		if lateness != nil {
+			op.lateness = lateness.duration()
+		}
+		return op
+	}, true, false, opts...), outputs)
+}
+
+type windowWithEventTimeOperator struct {
+	timespan      time.Duration
+	lateness      time.Duration
+	timeExtractor func(interface{}) time.Time
+	watermark     time.Time
+	started       bool
+	// windows are the open windows, in time order
+	windows []*EventTimeWindow
+	outputs *sideOutputs
+}
+
+func (op *windowWithEventTimeOperator) next(ctx context.Context, item Item, dst chan<- Item, _ operatorOptions) {
+	t := op.timeExtractor(item.V)
+	start := t.Truncate(op.timespan)
+	end := start.Add(op.timespan)
+	if op.started && !end.After(op.watermark) {
+		op.outputs.emit(LateOutput, item)
+		return
+	}
+
+	i := 0
+	for i < len(op.windows) && op.windows[i].Start.Before(start) {
+		i++
+	}
+	if i == len(op.windows) || !op.windows[i].Start.Equal(start) {
+		op.windows = append(op.windows, nil)
+		copy(op.windows[i+1:], op.windows[i:])
+		op.windows[i] = &EventTimeWindow{Start: start, End: end}
+	}
+	op.windows[i].Items = append(op.windows[i].Items, item.V)
+
+	if watermark := t.Add(-op.lateness); !op.started || watermark.After(op.watermark) {
+		op.watermark = watermark
+		op.started = true
+	}
+	closed := 0
+	for closed < len(op.windows) && !op.windows[closed].End.After(op.watermark) {
+		if !Of(*op.windows[closed]).SendContext(ctx, dst) {
+			return
+		}
+		op.windows[closed] = nil
+		closed++
+	}
+	op.windows = op.windows[closed:]
+}
+
+func (op *windowWithEventTimeOperator) err(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	defaultErrorFuncOperator(ctx, item, dst, operatorOptions)
+}
+
+func (op *windowWithEventTimeOperator) end(ctx context.Context, dst chan<- Item) {
+	defer op.outputs.complete()
+	for _, window := range op.windows {
+		if !Of(*window).SendContext(ctx, dst) {
+			return
+		}
+	}
+}
+
+func (op *windowWithEventTimeOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
+}
+
 // WindowWithTime periodically subdivides items from an Observable into Observables based on timed windows
 // and emit them rather than emitting the items one at a time.
 func (o *ObservableImpl) WindowWithTime(timespan Duration, opts ...Option) Observable {
@@ -2794,13 +4080,19 @@ func (o *ObservableImpl) WindowWithTime(timespan Duration, opts ...Option) Obser
 				mutex.Unlock()
 			}()
 			defer close(next)
+			clock := option.getClock()
+			var timer ClockTimer
+			defer func() {
+				timer.Stop()
+			}()
 			for {
+				timer = clock.NewTimer(timespan.duration())
 				select {
 				case <-ctx.Done():
 					return
 				case <-done:
 					return
-				case <-time.After(timespan.duration()):
+				case <-timer.C():
 					mutex.Lock()
 					if empty {
 						mutex.Unlock()
@@ -2853,7 +4145,7 @@ func (o *ObservableImpl) WindowWithTime(timespan Duration, opts ...Option) Obser
 		}
 	}
 
-	return customObservableOperator(o.parent, f, opts...)
+	return customObservableOperator(o.parent, o, f, opts...)
 }
 
 // WindowWithTimeOrCount periodically subdivides items from an Observable into Observables based on timed windows or a specific size
@@ -2883,13 +4175,19 @@ func (o *ObservableImpl) WindowWithTimeOrCount(timespan Duration, count int, opt
 				mutex.Unlock()
 			}()
 			defer close(next)
+			clock := option.getClock()
+			var timer ClockTimer
+			defer func() {
+				timer.Stop()
+			}()
 			for {
+				timer = clock.NewTimer(timespan.duration())
 				select {
 				case <-ctx.Done():
 					return
 				case <-done:
 					return
-				case <-time.After(timespan.duration()):
+				case <-timer.C():
 					mutex.Lock()
 					if iCount == 0 {
 						mutex.Unlock()
@@ -2952,7 +4250,7 @@ func (o *ObservableImpl) WindowWithTimeOrCount(timespan Duration, count int, opt
 		}
 	}
 
-	return customObservableOperator(o.parent, f, opts...)
+	return customObservableOperator(o.parent, o, f, opts...)
 }
 
 // ZipFromIterable merges the emissions of an Iterable via a specified function
@@ -3004,7 +4302,7 @@ func (o *ObservableImpl) ZipFromIterable(iterable Iterable, zipper Func2, opts .
 		}
 	}()
 
-	return &ObservableImpl{
+	return withStage(0, &ObservableImpl{
 		iterable: newChannelIterable(next),
-	}
+	}, o, iterable)
 }
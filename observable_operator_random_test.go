@@ -38,15 +38,15 @@ func TestLeak(t *testing.T) {
 				}
 				return sum
 			}, []Observable{
-				Just(1, 2)(),
-				Just(10, 11)(),
-			})
+				Just(1, 2)(WithContext(ctx)),
+				Just(10, 11)(WithContext(ctx)),
+			}, WithContext(ctx))
 		},
 		"Concat": func(ctx context.Context) Observable {
 			return Concat([]Observable{
-				Just(1, 2, 3)(),
-				Just(4, 5, 6)(),
-			})
+				Just(1, 2, 3)(WithContext(ctx)),
+				Just(4, 5, 6)(WithContext(ctx)),
+			}, WithContext(ctx))
 		},
 		"FromChannel": func(ctx context.Context) Observable {
 			return FromChannel(getChannel(ctx), WithContext(ctx))
@@ -76,18 +76,18 @@ func TestLeak(t *testing.T) {
 				return i == 2
 			}, WithContext(ctx))
 		},
-		"For each": func(_ context.Context, obs Observable) {
-			obs.ForEach(func(_ interface{}) {}, func(_ error) {}, func() {})
+		"For each": func(ctx context.Context, obs Observable) {
+			obs.ForEach(func(_ interface{}) {}, func(_ error) {}, func() {}, WithContext(ctx))
 		},
 	}
 
 	defer goleak.VerifyNone(t)
 	for testObservable, factory := range observables {
 		for testAction, action := range actions {
+			factory := factory
+			action := action
 			for i := 0; i < count; i++ {
 				waitTime := randomTime()
-				factory := factory
-				action := action
 				t.Run(fmt.Sprintf("%s - %s - %v - single", testObservable, testAction, waitTime), func(t *testing.T) {
 					t.Parallel()
 					ctx, cancel := context.WithTimeout(context.Background(), waitTime)
@@ -100,7 +100,7 @@ func TestLeak(t *testing.T) {
 					defer cancel()
 					action(ctx, factory(ctx).Map(func(_ context.Context, i interface{}) (interface{}, error) {
 						return i, nil
-					}))
+					}, WithContext(ctx)))
 				})
 				t.Run(fmt.Sprintf("%s - %s - %v - erritem", testObservable, testAction, waitTime), func(t *testing.T) {
 					t.Parallel()
@@ -108,7 +108,7 @@ func TestLeak(t *testing.T) {
 					defer cancel()
 					action(ctx, factory(ctx).Map(func(_ context.Context, i interface{}) (interface{}, error) {
 						return nil, fooErr
-					}))
+					}, WithContext(ctx)))
 				})
 			}
 			t.Run(fmt.Sprintf("%s - %s - already cancelled", testObservable, testAction), func(t *testing.T) {
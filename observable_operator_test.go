@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -24,6 +25,103 @@ var predicateAllInt = func(i interface{}) bool {
 	}
 }
 
+func parity(_ context.Context, i interface{}) (interface{}, error) {
+	if i.(int)%2 == 0 {
+		return "even", nil
+	}
+	return "odd", nil
+}
+
+func sum(_ context.Context, acc, i interface{}) (interface{}, error) {
+	return acc.(int) + i.(int), nil
+}
+
+func Test_Observable_AggregateByKey_Count(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 2, 3, 4, 5, 6).AggregateByKey(parity, 0, sum, FlushPolicy{Count: 2})
+	Assert(ctx, t, obs, HasItems(
+		KeyedAggregate{Key: "odd", Value: 4, Count: 2},
+		KeyedAggregate{Key: "even", Value: 6, Count: 2},
+		KeyedAggregate{Key: "odd", Value: 9, Count: 3},
+		KeyedAggregate{Key: "even", Value: 12, Count: 3},
+	), HasNoError())
+}
+
+func Test_Observable_AggregateByKey_Interval(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	src := make(chan Item)
+	ch := FromChannel(src).AggregateByKey(parity, 0, sum, FlushPolicy{Interval: time.Second, Reset: true},
+		WithClock(scheduler)).Observe()
+
+	scheduler.BlockUntil(1)
+	src <- Of(1)
+	src <- Of(2)
+	src <- Of(3)
+	scheduler.Advance(time.Second)
+	assert.Equal(t, KeyedAggregate{Key: "odd", Value: 4, Count: 2}, (<-ch).V)
+	assert.Equal(t, KeyedAggregate{Key: "even", Value: 2, Count: 1}, (<-ch).V)
+
+	src <- Of(5)
+	scheduler.Advance(time.Second)
+	assert.Equal(t, KeyedAggregate{Key: "odd", Value: 5, Count: 1}, (<-ch).V)
+	close(src)
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func Test_Observable_AggregateByKey_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 2, 3).AggregateByKey(parity, 0,
+		func(ctx context.Context, acc, i interface{}) (interface{}, error) {
+			if i.(int) == 3 {
+				return nil, errFoo
+			}
+			return sum(ctx, acc, i)
+		}, FlushPolicy{Count: 1})
+	Assert(ctx, t, obs, HasItems(
+		KeyedAggregate{Key: "odd", Value: 1, Count: 1},
+		KeyedAggregate{Key: "even", Value: 2, Count: 1},
+	), HasError(errFoo))
+}
+
+// Test_Observable_AggregateByKey_Release verifies a flush releases the state of the keys reset to the seed
+func Test_Observable_AggregateByKey_Release(t *testing.T) {
+	aggregator := newKeyedAggregator(0, sum, FlushPolicy{Count: 2, Reset: true}, NewMemoryStateStore(EvictionPolicy{}))
+	ctx := context.Background()
+	next := make(chan Item, 10)
+	_, _ = aggregator.add(ctx, "a", 1)
+	due, _ := aggregator.add(ctx, "a", 2)
+	assert.Equal(t, KeyedAggregate{Key: "a", Value: 3, Count: 2}, aggregator.snapshot("a", due))
+	_, _ = aggregator.add(ctx, "b", 3)
+	assert.True(t, aggregator.flush(ctx, next))
+	assert.Len(t, next, 1)
+	assert.Equal(t, 0, aggregator.keys.Len())
+	assert.Equal(t, 0, aggregator.states.Len())
+}
+
+func Test_Observable_AggregateByKey_Eviction(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 2, 3, 1, 2).AggregateByKey(
+		func(_ context.Context, item interface{}) (interface{}, error) {
+			return item, nil
+		}, 0, sum, FlushPolicy{}, WithEviction(EvictionPolicy{MaxSize: 2}))
+	// the pending aggregate of an evicted key is emitted, the key restarting from the seed
+	Assert(ctx, t, obs, HasItems(
+		KeyedAggregate{Key: 1, Value: 1, Count: 1},
+		KeyedAggregate{Key: 2, Value: 2, Count: 1},
+		KeyedAggregate{Key: 3, Value: 3, Count: 1},
+		KeyedAggregate{Key: 1, Value: 1, Count: 1},
+		KeyedAggregate{Key: 2, Value: 2, Count: 1},
+	), HasNoError())
+}
+
 func Test_Observable_All_True(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -239,6 +337,93 @@ func Test_Observable_BackOffRetry_Error(t *testing.T) {
 	Assert(ctx, t, obs, HasItems(1, 2, 1, 2, 1, 2, 1, 2), HasError(errFoo))
 }
 
+func Test_Observable_BackOffRetry_Clock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Now())
+	i := 0
+	obs := Defer([]Producer{func(ctx context.Context, next chan<- Item) {
+		next <- Of(i)
+		if i == 0 {
+			i++
+			next <- Error(errFoo)
+		}
+	}}).BackOffRetry(backoff.NewConstantBackOff(time.Hour), WithClock(scheduler))
+	observer := NewTestObserver(t, obs)
+	observer.AwaitCount(1, time.Second)
+	scheduler.BlockUntil(1)
+	scheduler.Advance(time.Hour)
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(0, 1)
+	observer.AssertNoErrors()
+}
+
+func Test_Observable_BlockingFirst(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	v, err := Range(1, 1000).BlockingFirst(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func Test_Observable_BlockingFirst_Empty(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	_, err := Empty().BlockingFirst(context.Background())
+	assert.IsType(t, NoSuchElementError{}, err)
+}
+
+func Test_Observable_BlockingFirst_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	_, err := Thrown(errFoo).BlockingFirst(context.Background())
+	assert.Equal(t, errFoo, err)
+}
+
+func Test_Observable_BlockingFirst_ContextCanceled(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := Never().BlockingFirst(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func Test_Observable_BlockingForEach(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sum := 0
+	err := testObservable(ctx, 1, 2, 3).BlockingForEach(ctx, func(i interface{}) {
+		sum += i.(int)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, sum)
+}
+
+func Test_Observable_BlockingForEach_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := testObservable(ctx, 1, errFoo, 3).BlockingForEach(ctx, func(i interface{}) {})
+	assert.Equal(t, errFoo, err)
+}
+
+func Test_Observable_BlockingLast(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	v, err := Just(1, 2, 3)().BlockingLast(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v)
+
+	_, err = Empty().BlockingLast(context.Background())
+	assert.IsType(t, NoSuchElementError{}, err)
+}
+
+func Test_Observable_BlockingToSlice(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	s, err := Just(1, 2, 3)().BlockingToSlice(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1, 2, 3}, s)
+
+	_, err = Just(1, errFoo)().BlockingToSlice(context.Background())
+	assert.Equal(t, errFoo, err)
+}
+
 func Test_Observable_BufferWithCount(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -399,6 +584,96 @@ func Test_Observable_Debounce_Error(t *testing.T) {
 		HasItems(1, 2), HasError(errFoo))
 }
 
+func Test_Observable_Debounce_TestScheduler(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	start := time.Unix(0, 0)
+	scheduler := NewTestScheduler(start)
+	src := make(chan Item)
+	ch := FromChannel(src).Debounce(WithDuration(time.Second), WithClock(scheduler)).Observe()
+
+	scheduler.BlockUntil(1)
+	scheduler.Advance(500 * time.Millisecond)
+	src <- Of(1)
+	waitDeadline(scheduler, start.Add(1500*time.Millisecond))
+	scheduler.Advance(500 * time.Millisecond)
+	src <- Of(2)
+	waitDeadline(scheduler, start.Add(2*time.Second))
+	scheduler.Advance(time.Second)
+	assert.Equal(t, 2, (<-ch).V)
+
+	close(src)
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func Test_Observable_DeduplicateWithin(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// each item is seen a second after the previous one
+	obs := testObservable(ctx, "a", "b", "a", "a", "b", "c").DeduplicateWithin(WithDuration(2*time.Second),
+		func(_ context.Context, item interface{}) (interface{}, error) {
+			return item, nil
+		}, WithClock(&stepClock{}))
+	Assert(ctx, t, obs, HasItems("a", "b", "a", "b", "c"), HasNoError())
+}
+
+func Test_Observable_DeduplicateWithin_Key(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 11, 2, 21, 3).DeduplicateWithin(WithDuration(time.Hour),
+		func(_ context.Context, item interface{}) (interface{}, error) {
+			return item.(int) % 10, nil
+		})
+	Assert(ctx, t, obs, HasItems(1, 2, 3), HasNoError())
+}
+
+func Test_Observable_DeduplicateWithin_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 1, 2, 3, 4).DeduplicateWithin(WithDuration(time.Hour),
+		func(_ context.Context, item interface{}) (interface{}, error) {
+			if item.(int) == 3 {
+				return nil, errFoo
+			}
+			return item, nil
+		})
+	Assert(ctx, t, obs, HasItems(1, 2), HasError(errFoo))
+}
+
+// Test_Observable_DeduplicateWithin_Eviction verifies the keys are forgotten once their window elapsed
+func Test_Observable_DeduplicateWithin_Eviction(t *testing.T) {
+	op := &deduplicateWithinOperator{
+		window: WithDuration(2 * time.Second),
+		keySelector: func(_ context.Context, item interface{}) (interface{}, error) {
+			return item, nil
+		},
+		clock: &stepClock{},
+		store: NewMemoryStateStore(EvictionPolicy{}),
+	}
+	dst := make(chan Item, 10)
+	for i := 0; i < 5; i++ {
+		op.next(context.Background(), Of(i), dst, operatorOptions{})
+	}
+	assert.Len(t, dst, 5)
+	assert.Equal(t, 2, op.store.Len())
+	assert.Len(t, op.order, 2)
+}
+
+func Test_Observable_DeduplicateWithin_StateStore(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the store holds a single key, "a" being forgotten once "b" is seen
+	obs := testObservable(ctx, "a", "a", "b", "a").DeduplicateWithin(WithDuration(time.Hour),
+		func(_ context.Context, item interface{}) (interface{}, error) {
+			return item, nil
+		}, WithEviction(EvictionPolicy{MaxSize: 1}))
+	Assert(ctx, t, obs, HasItems("a", "b", "a"), HasNoError())
+}
+
 func Test_Observable_DefaultIfEmpty_Empty(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -441,6 +716,17 @@ func Test_Observable_Distinct(t *testing.T) {
 	Assert(ctx, t, obs, HasItems(1, 2, 3), HasNoError())
 }
 
+func Test_Observable_Distinct_Eviction(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// 1 is the least recently seen key once 3 is seen
+	obs := testObservable(ctx, 1, 2, 2, 3, 1).Distinct(func(_ context.Context, item interface{}) (interface{}, error) {
+		return item, nil
+	}, WithEviction(EvictionPolicy{MaxSize: 2, LRU: true}))
+	Assert(ctx, t, obs, HasItems(1, 2, 3, 1), HasNoError())
+}
+
 func Test_Observable_Distinct_Error(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -583,6 +869,28 @@ func Test_Observable_DoOnNext_Error(t *testing.T) {
 	assert.Equal(t, []interface{}{1}, s)
 }
 
+func Test_Observable_DoOnNext_ContinueOnError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := make([]interface{}, 0)
+	<-testObservable(ctx, 1, errFoo, 2).DoOnNext(func(i interface{}) {
+		s = append(s, i)
+	}, WithErrorStrategy(ContinueOnError))
+	assert.Equal(t, []interface{}{1, 2}, s)
+}
+
+func Test_Observable_DoOnError_ContinueOnError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := make([]error, 0)
+	<-testObservable(ctx, errFoo, 1, errBar).DoOnError(func(err error) {
+		errs = append(errs, err)
+	}, WithErrorStrategy(ContinueOnError))
+	assert.Equal(t, []error{errFoo, errBar}, errs)
+}
+
 func Test_Observable_ElementAt(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -680,6 +988,50 @@ func Test_Observable_Filter_Parallel(t *testing.T) {
 	Assert(ctx, t, obs, HasItemsNoOrder(2, 4), HasNoError())
 }
 
+// evenOrFail is a fallible predicate failing on 3
+func evenOrFail(_ context.Context, i interface{}) (bool, error) {
+	if i == 3 {
+		return false, errFoo
+	}
+	return i.(int)%2 == 0, nil
+}
+
+func Test_Observable_FilterE(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 2, 3, 4).FilterE(evenOrFail)
+	Assert(ctx, t, obs, HasItems(2), HasError(errFoo))
+}
+
+func Test_Observable_FilterE_ContinueOnError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 2, 3, 4).FilterE(evenOrFail, WithErrorStrategy(ContinueOnError))
+	Assert(ctx, t, obs, HasItems(2, 4), HasError(errFoo))
+}
+
+func Test_Observable_FilterE_Skip(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 2, 3, 4).FilterE(evenOrFail, WithPredicateErrorPolicy(SkipOnPredicateError))
+	Assert(ctx, t, obs, HasItems(2, 4), HasNoError())
+}
+
+func Test_Observable_FilterE_DeadLetter(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 2, 3, 4).FilterE(evenOrFail, WithPredicateErrorPolicy(DeadLetterOnPredicateError),
+		WithCPUPool())
+	letters := NewTestObserver(t, obs.SideOutput(DeadLetterOutput))
+	Assert(ctx, t, obs, HasItemsNoOrder(2, 4), HasNoError())
+	assert.True(t, letters.AwaitDone(time.Second))
+	letters.AssertValues(DeadLetter{Item: Of(3), Reason: errFoo})
+}
+
 func Test_Observable_Find_NotEmpty(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -988,6 +1340,60 @@ func Test_Observable_GroupByDynamic(t *testing.T) {
 	assert.Equal(t, "10", s[3].(GroupedObservable).Key)
 }
 
+func Test_Observable_GroupByDynamic_Eviction(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	obs := testObservable(ctx, 0, 1, 2, 3).GroupByDynamic(func(item Item) string {
+		return strconv.Itoa(item.V.(int) % 2)
+	}, WithBufferedChannel(4), WithEviction(EvictionPolicy{MaxSize: 1}))
+	s, err := obs.ToSlice(0)
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+	// each group is evicted once the next one is created
+	if len(s) != 4 {
+		assert.FailNow(t, "length", "got=%d, expected=%d", len(s), 4)
+	}
+	for i, group := range s {
+		Assert(ctx, t, group.(GroupedObservable), HasItems(i), HasNoError())
+	}
+}
+
+func Test_Observable_GroupByDynamic_TTL(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	ch := make(chan Item)
+	groups := FromChannel(ch).GroupByDynamic(func(Item) string {
+		return "a"
+	}, WithEviction(EvictionPolicy{TTL: time.Minute}, WithClock(scheduler))).Observe()
+
+	ch <- Of(0)
+	first := (<-groups).V.(GroupedObservable).Observe()
+	assert.Equal(t, 0, (<-first).V)
+	// a group receiving items stays active past the TTL
+	for i := 1; i < 3; i++ {
+		scheduler.Advance(40 * time.Second)
+		ch <- Of(i)
+		assert.Equal(t, i, (<-first).V)
+	}
+
+	// an idle group is evicted
+	scheduler.Advance(time.Minute)
+	ch <- Of(3)
+	_, open := <-first
+	assert.False(t, open)
+	second := (<-groups).V.(GroupedObservable).Observe()
+	assert.Equal(t, 3, (<-second).V)
+
+	close(ch)
+	_, open = <-second
+	assert.False(t, open)
+	_, open = <-groups
+	assert.False(t, open)
+}
+
 func joinTest(ctx context.Context, t *testing.T, left, right []interface{}, window Duration, expected []int64) {
 	leftObs := testObservable(ctx, left...)
 	rightObs := testObservable(ctx, right...)
@@ -1145,6 +1551,88 @@ func Test_Observable_Join_Error_OnRight(t *testing.T) {
 	joinTest(ctx, t, left, right, window, expected)
 }
 
+func never(Item) Observable {
+	return Never()
+}
+
+func Test_Observable_JoinWindows(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	left := make(chan Item)
+	right := make(chan Item)
+	ch := FromChannel(left).JoinWindows(FromChannel(right), never, never,
+		func(_ context.Context, l, r interface{}) (interface{}, error) {
+			return fmt.Sprintf("%v%v", l, r), nil
+		}).Observe()
+
+	left <- Of(1)
+	right <- Of("a")
+	assert.Equal(t, "1a", (<-ch).V)
+	left <- Of(2)
+	assert.Equal(t, "2a", (<-ch).V)
+	right <- Of("b")
+	assert.Equal(t, "1b", (<-ch).V)
+	assert.Equal(t, "2b", (<-ch).V)
+
+	close(left)
+	close(right)
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func Test_Observable_JoinWindows_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, errFoo).JoinWindows(Empty(), never, never,
+		func(_ context.Context, l, r interface{}) (interface{}, error) {
+			return nil, nil
+		})
+	Assert(ctx, t, obs, IsEmpty(), HasError(errFoo))
+}
+
+func Test_Observable_JoinWindows_WindowError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := Just(1)().JoinWindows(Never(), func(Item) Observable {
+		return Thrown(errFoo)
+	}, never, func(_ context.Context, l, r interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	Assert(context.Background(), t, obs, HasError(errFoo))
+}
+
+func Test_Observable_GroupJoin(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	left := make(chan Item)
+	right := make(chan Item)
+	closeWindow := map[int]chan Item{1: make(chan Item), 2: make(chan Item)}
+	ch := FromChannel(left).GroupJoin(FromChannel(right), func(item Item) Observable {
+		return FromChannel(closeWindow[item.V.(int)])
+	}, never, func(_ context.Context, _, rights interface{}) (interface{}, error) {
+		return rights, nil
+	}).Observe()
+
+	left <- Of(1)
+	group1 := (<-ch).V.(Observable).Observe()
+	right <- Of("a")
+	assert.Equal(t, "a", (<-group1).V)
+	close(closeWindow[1])
+	_, ok := <-group1
+	assert.False(t, ok)
+
+	right <- Of("b")
+	left <- Of(2)
+	group2 := (<-ch).V.(Observable).Observe()
+	assert.Equal(t, "a", (<-group2).V)
+	assert.Equal(t, "b", (<-group2).V)
+
+	close(left)
+	close(right)
+	_, ok = <-ch
+	assert.False(t, ok)
+	_, ok = <-group2
+	assert.False(t, ok)
+}
+
 func Test_Observable_Last_NotEmpty(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -1209,6 +1697,75 @@ func Test_Observable_LastOrDefault_Parallel_Empty(t *testing.T) {
 	Assert(ctx, t, obs, HasItem(10))
 }
 
+// gatedCall returns an Observable emitting v*10 once gate is closed, and tracking the number of observations in
+// progress
+func gatedCall(v interface{}, gate chan struct{}, active *int32) Observable {
+	return Defer([]Producer{func(ctx context.Context, next chan<- Item) {
+		atomic.AddInt32(active, 1)
+		defer atomic.AddInt32(active, -1)
+		<-gate
+		Of(v.(int)*10).SendContext(ctx, next)
+	}})
+}
+
+func Test_Observable_LimitConcurrency(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	gate := make(chan struct{})
+	var active int32
+	obs := Just(1, 2, 3, 4, 5)().LimitConcurrency(2, func(item Item) Observable {
+		return gatedCall(item.V, gate, &active)
+	})
+	observer := NewTestObserver(t, obs)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&active) == 2
+	}, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&active))
+
+	close(gate)
+	assert.True(t, observer.AwaitDone(time.Second))
+	assert.ElementsMatch(t, []interface{}{10, 20, 30, 40, 50}, observer.Values())
+	observer.AssertNoErrors()
+}
+
+func Test_Observable_LimitConcurrency_Drop(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	gate := make(chan struct{})
+	var active int32
+	src := make(chan Item)
+	obs := FromChannel(src).LimitConcurrency(1, func(item Item) Observable {
+		return gatedCall(item.V, gate, &active)
+	}, WithBackPressureStrategy(Drop))
+	observer := NewTestObserver(t, obs)
+
+	src <- Of(1)
+	// shed while the first call is in progress, the third item being only received once the second one is
+	// handled
+	src <- Of(2)
+	src <- Of(3)
+	close(src)
+	close(gate)
+	assert.True(t, observer.AwaitDone(time.Second))
+	assert.Contains(t, observer.Values(), 10)
+	assert.NotContains(t, observer.Values(), 20)
+}
+
+func Test_Observable_LimitConcurrency_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := Just(1, 2, 3)().LimitConcurrency(2, func(item Item) Observable {
+		if item.V == 2 {
+			return Thrown(errFoo)
+		}
+		return Just(item.V)()
+	}, WithContext(ctx))
+	Assert(ctx, t, obs, HasError(errFoo))
+	cancel()
+	Assert(context.Background(), t, Just(1)().LimitConcurrency(0, nil), IsEmpty(), HasAnError())
+}
+
 func Test_Observable_Map_One(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -1297,6 +1854,138 @@ func Test_Observable_Map_Parallel(t *testing.T) {
 	Assert(ctx, t, obs, HasItemsNoOrder(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), HasNoError())
 }
 
+func Test_Observable_MapE(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 2, 3).MapE(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i.(int) * 2, nil
+	})
+	Assert(ctx, t, obs, HasItems(2, 4, 6), HasNoError())
+}
+
+func Test_Observable_MapE_ContinueOnError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 2, 3, errBar).MapE(func(_ context.Context, i interface{}) (interface{}, error) {
+		if i == 2 {
+			return nil, errFoo
+		}
+		return i, nil
+	}, WithErrorStrategy(ContinueOnError))
+	Assert(ctx, t, obs, HasItems(1, 3), HasErrors(errFoo, errBar))
+}
+
+func Test_Observable_MapE_StopOnError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	obs := Just(1, 2)().MapE(func(ctx context.Context, i interface{}) (interface{}, error) {
+		if i == 1 {
+			<-started
+			return nil, errFoo
+		}
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return i, nil
+	}, WithPool(2))
+	Assert(context.Background(), t, obs, IsEmpty(), HasError(errFoo))
+	<-cancelled
+}
+
+func Test_Observable_MapE_Context(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	obs := Just(1)().MapE(func(ctx context.Context, i interface{}) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return i, nil
+	}, WithContext(ctx))
+	ch := obs.Observe()
+
+	<-started
+	cancel()
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func Test_Observable_MapParallel(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := Range(0, 20).MapParallel(func(_ context.Context, i interface{}) (interface{}, error) {
+		// the first items take longer
+		time.Sleep(time.Duration(20-i.(int)) * 100 * time.Microsecond)
+		return i.(int) * 10, nil
+	}, 4)
+	expected := make([]interface{}, 20)
+	for i := range expected {
+		expected[i] = i * 10
+	}
+	Assert(context.Background(), t, obs, HasItems(expected...), HasNoError())
+}
+
+func Test_Observable_MapParallel_Workers(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	var active int32
+	all := make(chan struct{})
+	obs := Range(0, 3).MapParallel(func(_ context.Context, i interface{}) (interface{}, error) {
+		// blocks until the 3 items are processed at once
+		if atomic.AddInt32(&active, 1) == 3 {
+			close(all)
+		}
+		<-all
+		return i, nil
+	}, 3)
+	Assert(context.Background(), t, obs, HasItems(0, 1, 2))
+}
+
+func Test_Observable_MapParallel_ReorderBuffer(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	started := make(chan interface{}, 5)
+	release := make(chan struct{})
+	obs := Range(0, 5).MapParallel(func(_ context.Context, i interface{}) (interface{}, error) {
+		started <- i
+		if i == 0 {
+			<-release
+		}
+		return i, nil
+	}, 2, WithReorderBuffer(2))
+	observer := NewTestObserver(t, obs)
+
+	assert.ElementsMatch(t, []interface{}{0, 1}, []interface{}{<-started, <-started})
+	assert.Never(t, func() bool {
+		return len(started) > 0
+	}, 50*time.Millisecond, time.Millisecond)
+	assert.Empty(t, observer.Values())
+
+	close(release)
+	assert.True(t, observer.AwaitDone(time.Second))
+	observer.AssertValues(0, 1, 2, 3, 4)
+}
+
+func Test_Observable_MapParallel_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := Range(0, 10, WithContext(ctx)).MapParallel(func(_ context.Context, i interface{}) (interface{}, error) {
+		if i == 3 {
+			return nil, errFoo
+		}
+		return i, nil
+	}, 4, WithContext(ctx))
+	Assert(ctx, t, obs, HasItems(0, 1, 2), HasError(errFoo))
+}
+
+func Test_Observable_MapParallel_InvalidInput(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := Just(1)().MapParallel(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i, nil
+	}, 0)
+	Assert(context.Background(), t, obs, HasAnError())
+}
+
 func Test_Observable_Marshal(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -1419,6 +2108,30 @@ func Test_Observable_Observe(t *testing.T) {
 	assert.Equal(t, []int{1, 2, 3}, got)
 }
 
+func Test_Observable_ObserveOn(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool := NewWorkerPoolScheduler(4, 0)
+	defer pool.Stop()
+	scheduler := &countingScheduler{scheduler: pool}
+
+	items := make([]interface{}, 1000)
+	for i := range items {
+		items[i] = i
+	}
+	Assert(ctx, t, Just(items...)().ObserveOn(scheduler), HasItems(items...))
+	assert.True(t, atomic.LoadInt32(&scheduler.tasks) > 0)
+}
+
+func Test_Observable_ObserveOn_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	Assert(ctx, t, testObservable(ctx, 1, errFoo, 2).ObserveOn(Immediate), HasItems(1), HasError(errFoo))
+	Assert(ctx, t, Empty().ObserveOn(nil), IsEmpty(), HasAnError())
+}
+
 func Test_Observable_OnErrorResumeNext(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -1447,6 +2160,98 @@ func Test_Observable_OnErrorReturnItem(t *testing.T) {
 	Assert(ctx, t, obs, HasItems(1, 2, "foo", 4, "foo", 6), HasNoError())
 }
 
+func Test_Observable_MovingAverage(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	Assert(ctx, t, Just(1, 2, 3, 4, 5.5)().MovingAverage(2), HasItems(1.0, 1.5, 2.5, 3.5, 4.75))
+	Assert(ctx, t, Just(1, "a")().MovingAverage(2), HasItems(1.0), HasAnError())
+	Assert(ctx, t, Just(1)().MovingAverage(0), IsEmpty(), HasAnError())
+}
+
+func Test_Observable_MovingMinMax(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	Assert(ctx, t, Just(3, 1, 4, 1, 5, 9, 2)().MovingMax(3), HasItems(3.0, 3.0, 4.0, 4.0, 5.0, 9.0, 9.0))
+	Assert(ctx, t, Just(3, 1, 4, 1, 5, 9, 2)().MovingMin(3), HasItems(3.0, 1.0, 1.0, 1.0, 1.0, 1.0, 2.0))
+}
+
+func Test_Observable_Percentile(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	Assert(ctx, t, Just(5, 1, 4, 2, 3)().Percentile(4, 50), HasItems(5.0, 1.0, 4.0, 2.0, 2.0))
+	Assert(ctx, t, Just(5, 1, 4, 2, 3)().Percentile(3, 100), HasItems(5.0, 5.0, 5.0, 4.0, 4.0))
+	Assert(ctx, t, Just(1)().Percentile(3, 0), IsEmpty(), HasAnError())
+}
+
+func Test_Observable_RateLimit(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	ch := Just(1, 2, 3, 4)().RateLimit(1, 2, WithClock(scheduler)).Observe()
+
+	// the burst is emitted at once
+	assert.Equal(t, 1, (<-ch).V)
+	assert.Equal(t, 2, (<-ch).V)
+	for _, v := range []int{3, 4} {
+		scheduler.BlockUntil(1)
+		scheduler.Advance(999 * time.Millisecond)
+		select {
+		case <-ch:
+			assert.FailNow(t, "item emitted too early")
+		default:
+		}
+		scheduler.Advance(time.Millisecond)
+		assert.Equal(t, v, (<-ch).V)
+	}
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func Test_Observable_RateLimit_Drop(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	src := make(chan Item, 5)
+	for _, item := range []Item{Of(1), Of(2), Of(3), Of(4), Error(errFoo)} {
+		src <- item
+	}
+	close(src)
+
+	// a token every two items, the clock moving forward by a second each time it is read
+	obs := FromChannel(src).RateLimit(0.5, 1, WithClock(&stepClock{}), WithBackPressureStrategy(Drop))
+	Assert(context.Background(), t, obs, HasItems(1, 3), HasError(errFoo))
+}
+
+// countingLimiter is a Limiter allowing every event and counting them
+type countingLimiter struct {
+	waits  int32
+	allows int32
+}
+
+func (l *countingLimiter) Allow() bool {
+	atomic.AddInt32(&l.allows, 1)
+	return true
+}
+
+func (l *countingLimiter) Wait(context.Context) error {
+	atomic.AddInt32(&l.waits, 1)
+	return nil
+}
+
+func Test_Observable_RateLimit_Limiter(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	limiter := &countingLimiter{}
+	Assert(context.Background(), t, Just(1, 2, 3)().RateLimit(0, 0, WithLimiter(limiter)), HasItems(1, 2, 3))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&limiter.waits))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&limiter.allows))
+}
+
+func Test_Observable_RateLimit_IllegalInput(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	Assert(context.Background(), t, Just(1)().RateLimit(0, 1), IsEmpty(), HasAnError())
+	Assert(context.Background(), t, Just(1)().RateLimit(1, 0), IsEmpty(), HasAnError())
+}
+
 func Test_Observable_Reduce(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -1607,6 +2412,84 @@ func Test_Observable_Repeat_Frequency(t *testing.T) {
 	frequency.AssertExpectations(t)
 }
 
+func Test_Observable_Repeat_Frequency_TestScheduler(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+
+	ch := testObservable(ctx, 1).Repeat(2, WithDuration(time.Minute), WithClock(scheduler)).Observe()
+	assert.Equal(t, 1, (<-ch).V)
+	for i := 0; i < 2; i++ {
+		scheduler.BlockUntil(1)
+		scheduler.Advance(time.Minute)
+		assert.Equal(t, 1, (<-ch).V)
+	}
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func sequence(i interface{}) uint64 {
+	return uint64(i.(int))
+}
+
+func Test_Observable_Resequence(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 2, 0, 3, 1, 1, 4).Resequence(sequence, 10, WithDuration(time.Hour))
+	Assert(ctx, t, obs, HasItems(0, 1, 2, 3, 4), HasNoError())
+}
+
+func Test_Observable_Resequence_MaxGap(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 0, 3, 1, 4, 6).Resequence(sequence, 1, WithDuration(time.Hour))
+	Assert(ctx, t, obs, HasItems(0, SequenceGap{From: 1, To: 2}, 3, 4, SequenceGap{From: 5, To: 5}, 6))
+}
+
+func Test_Observable_Resequence_Complete(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 4, 0, 2).Resequence(sequence, 10, WithDuration(time.Hour))
+	Assert(ctx, t, obs, HasItems(0, SequenceGap{From: 1, To: 1}, 2, SequenceGap{From: 3, To: 3}, 4))
+}
+
+func Test_Observable_Resequence_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 0, 2, errFoo, 1).Resequence(sequence, 10, WithDuration(time.Hour))
+	Assert(ctx, t, obs, HasItems(0), HasError(errFoo))
+}
+
+func Test_Observable_Resequence_Timeout(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	src := make(chan Item)
+	ch := FromChannel(src).Resequence(sequence, 10, WithDuration(time.Second), WithClock(scheduler)).Observe()
+
+	src <- Of(1)
+	scheduler.BlockUntil(1)
+	scheduler.Advance(999 * time.Millisecond)
+	src <- Of(2)
+	select {
+	case <-ch:
+		assert.FailNow(t, "item emitted before the timeout")
+	default:
+	}
+	scheduler.Advance(time.Millisecond)
+	assert.Equal(t, SequenceGap{From: 0, To: 0}, (<-ch).V)
+	assert.Equal(t, 1, (<-ch).V)
+	assert.Equal(t, 2, (<-ch).V)
+
+	close(src)
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
 func Test_Observable_Retry(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -1901,6 +2784,22 @@ func Test_Observable_SkipWhile(t *testing.T) {
 	Assert(ctx, t, obs, HasItems(3, 4, 5), HasNoError())
 }
 
+func Test_Observable_SkipWhileE(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 2, 3, 4, 5, 6).SkipWhileE(evenOrFail, WithPredicateErrorPolicy(SkipOnPredicateError))
+	Assert(ctx, t, obs, HasItems(5, 6), HasNoError())
+}
+
+func Test_Observable_SkipWhileE_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 2, 3, 4, 5).SkipWhileE(evenOrFail)
+	Assert(ctx, t, obs, IsEmpty(), HasError(errFoo))
+}
+
 func Test_Observable_SkipWhile_Parallel(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -1941,6 +2840,26 @@ func Test_Observable_StartWithIterable_Error2(t *testing.T) {
 	Assert(ctx, t, obs, HasItems(1, 2, 3, 4), HasError(errFoo))
 }
 
+func Test_Observable_SubscribeOn(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	loop := NewEventLoopScheduler(0)
+	defer loop.Stop()
+	scheduler := &countingScheduler{scheduler: loop}
+
+	obs := Defer([]Producer{func(_ context.Context, next chan<- Item) {
+		if !scheduler.inTask() {
+			next <- Error(errFoo)
+			return
+		}
+		next <- Of(1)
+		next <- Of(2)
+	}}).SubscribeOn(scheduler)
+	Assert(ctx, t, obs, HasItems(1, 2), HasNoError())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&scheduler.tasks))
+}
+
 func Test_Observable_SumFloat32_OnlyFloat32(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -2095,6 +3014,26 @@ func Test_Observable_TakeWhile(t *testing.T) {
 	Assert(ctx, t, obs, HasItems(1, 2))
 }
 
+func Test_Observable_TakeWhileE(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 2, 4, 3, 6, 7, 8).TakeWhileE(evenOrFail,
+		WithPredicateErrorPolicy(DeadLetterOnPredicateError))
+	letters := NewTestObserver(t, obs.SideOutput(DeadLetterOutput))
+	Assert(ctx, t, obs, HasItems(2, 4, 6), HasNoError())
+	assert.True(t, letters.AwaitDone(time.Second))
+	letters.AssertValues(DeadLetter{Item: Of(3), Reason: errFoo})
+}
+
+func Test_Observable_TakeWhileE_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 2, 4, 3, 6).TakeWhileE(evenOrFail)
+	Assert(ctx, t, obs, HasItems(2, 4), HasError(errFoo))
+}
+
 func Test_Observable_TimeInterval(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -2121,6 +3060,17 @@ func Test_Observable_Timestamp(t *testing.T) {
 	assert.Equal(t, 3, v.V)
 }
 
+func Test_Observable_Timestamp_TestScheduler(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	scheduler := NewTestScheduler(now)
+
+	observe := testObservable(ctx, 1).Timestamp(WithClock(scheduler)).Observe()
+	assert.Equal(t, TimestampItem{Timestamp: now, V: 1}, (<-observe).V)
+}
+
 func Test_Observable_Error(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -2131,6 +3081,30 @@ func Test_Observable_Error(t *testing.T) {
 	assert.True(t, (<-observe).Error())
 }
 
+func Test_Observable_ToList(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 2, 3).ToList(3)
+	Assert(ctx, t, obs, HasItem([]interface{}{1, 2, 3}), HasNoError())
+}
+
+func Test_Observable_ToList_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, errFoo, 3).ToList(3)
+	Assert(ctx, t, obs, IsEmpty(), HasError(errFoo))
+}
+
+func Test_Observable_ToList_ContinueOnError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, errFoo, 3).ToList(3, WithErrorStrategy(ContinueOnError))
+	Assert(ctx, t, obs, HasItem([]interface{}{1, 3}), HasError(errFoo))
+}
+
 func Test_Observable_ToMap(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -2265,6 +3239,41 @@ func Test_Observable_Unmarshal_Parallel_Error(t *testing.T) {
 	Assert(ctx, t, obs, HasAnError())
 }
 
+func eventTime(i interface{}) time.Time {
+	return time.Unix(int64(i.(int)), 0)
+}
+
+func Test_Observable_WindowWithEventTime(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 12, 3, 16, 4, 25).WindowWithEventTime(WithDuration(10*time.Second), eventTime,
+		WithDuration(5*time.Second))
+	Assert(ctx, t, obs, HasItems(
+		EventTimeWindow{Start: time.Unix(0, 0), End: time.Unix(10, 0), Items: []interface{}{1, 3}},
+		EventTimeWindow{Start: time.Unix(10, 0), End: time.Unix(20, 0), Items: []interface{}{12, 16}},
+		EventTimeWindow{Start: time.Unix(20, 0), End: time.Unix(30, 0), Items: []interface{}{25}},
+	), HasNoError())
+}
+
+func Test_Observable_WindowWithEventTime_NoLateness(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 10, 2, 21, errFoo).WindowWithEventTime(WithDuration(10*time.Second), eventTime, nil)
+	Assert(ctx, t, obs, HasItems(
+		EventTimeWindow{Start: time.Unix(0, 0), End: time.Unix(10, 0), Items: []interface{}{1}},
+		EventTimeWindow{Start: time.Unix(10, 0), End: time.Unix(20, 0), Items: []interface{}{10}},
+		EventTimeWindow{Start: time.Unix(20, 0), End: time.Unix(30, 0), Items: []interface{}{21}},
+	), HasError(errFoo))
+}
+
+func Test_Observable_WindowWithEventTime_InvalidInput(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := Just(1)().WindowWithEventTime(nil, eventTime, nil)
+	Assert(context.Background(), t, obs, HasAnError())
+}
+
 func Test_Observable_WindowWithCount(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -2399,3 +3408,18 @@ func Test_Observable_ZipFromObservable_DifferentLength2(t *testing.T) {
 	zip := obs1.ZipFromIterable(obs2, zipper)
 	Assert(ctx, t, zip, HasItems(11, 22))
 }
+
+func Test_Observable_Wait(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, testObservable(ctx, 1, 2, 3).Wait(ctx))
+	assert.Equal(t, errFoo, testObservable(ctx, 1, errFoo, 3).Wait(ctx))
+}
+
+func Test_Observable_Wait_Context(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, Never().Wait(ctx))
+}
@@ -3,7 +3,9 @@ package rxgo
 import (
 	"context"
 	"runtime"
+	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/teivah/onecontext"
 )
 
@@ -22,6 +24,44 @@ type Option interface {
 	isConnectable() bool
 	isConnectOperation() bool
 	isSerialized() (bool, func(interface{}) int)
+	getLogger() Logger
+	getTracer() Tracer
+	getName() string
+	getClock() Clock
+	getScheduler() Scheduler
+	getWorkerPool() int
+	isBatchDelivery() bool
+	isPooling() bool
+	getShards() int
+	getItemTTL() time.Duration
+	getPollInterval() Duration
+	getPollJitter() float64
+	isPollSkipUnchanged() bool
+	getCronParser() CronParser
+	getJSONTarget() func() interface{}
+	getReplaySpeed() float64
+	getBatchBackOff() func() backoff.BackOff
+	getLimiter() Limiter
+	getReorderBuffer() int
+	getPredicateErrorPolicy() PredicateErrorPolicy
+	getStateStore() func() StateStore
+	getStage() string
+	getTerminalPolicy() TerminalPolicy
+	getForwardFilter() Predicate
+	getForwardTransform() Func
+	isErrorIsolation() bool
+	isWeakSubscriptions() bool
+	getAckTimeout() time.Duration
+	getMaxDeliveries() int
+	getMaxPending() int
+	getIdempotencyKey() func(interface{}) interface{}
+	getProducerCount() int
+	getGroupBalancing() GroupBalancing
+	getPartitionKey() func(interface{}) interface{}
+	getAdaptivePolicy() AdaptivePolicy
+	getConflationKey() func(interface{}) interface{}
+	getConflationMerge() func(queued, next interface{}) interface{}
+	isRemoteTermination() bool
 }
 
 type funcOption struct {
@@ -37,6 +77,44 @@ type funcOption struct {
 	connectable          bool
 	connectOperation     bool
 	serialized           func(interface{}) int
+	logger               Logger
+	tracer               Tracer
+	name                 string
+	clock                Clock
+	scheduler            Scheduler
+	workerPool           int
+	batchDelivery        bool
+	pooling              bool
+	shards               int
+	itemTTL              time.Duration
+	pollInterval         Duration
+	pollJitter           float64
+	pollSkipUnchanged    bool
+	cronParser           CronParser
+	jsonTarget           func() interface{}
+	replaySpeed          float64
+	batchBackOff         func() backoff.BackOff
+	limiter              Limiter
+	reorderBuffer        int
+	predicateErrorPolicy PredicateErrorPolicy
+	stateStore           func() StateStore
+	stage                string
+	terminalPolicy       TerminalPolicy
+	forwardFilter        Predicate
+	forwardTransform     Func
+	errorIsolation       bool
+	weakSubscriptions    bool
+	ackTimeout           time.Duration
+	maxDeliveries        int
+	maxPending           int
+	idempotencyKey       func(interface{}) interface{}
+	producerCount        int
+	groupBalancing       GroupBalancing
+	partitionKey         func(interface{}) interface{}
+	adaptivePolicy       AdaptivePolicy
+	conflationKey        func(interface{}) interface{}
+	conflationMerge      func(queued, next interface{}) interface{}
+	remoteTermination    bool
 }
 
 func (fdo *funcOption) toPropagate() bool {
@@ -100,6 +178,175 @@ func (fdo *funcOption) isSerialized() (bool, func(interface{}) int) {
 	return true, fdo.serialized
 }
 
+func (fdo *funcOption) getLogger() Logger {
+	if fdo.logger == nil {
+		return DefaultLogger()
+	}
+	return fdo.logger
+}
+
+func (fdo *funcOption) getTracer() Tracer {
+	return fdo.tracer
+}
+
+func (fdo *funcOption) getName() string {
+	return fdo.name
+}
+
+func (fdo *funcOption) getScheduler() Scheduler {
+	return fdo.scheduler
+}
+
+func (fdo *funcOption) getWorkerPool() int {
+	return fdo.workerPool
+}
+
+func (fdo *funcOption) isBatchDelivery() bool {
+	return fdo.batchDelivery
+}
+
+func (fdo *funcOption) isPooling() bool {
+	return fdo.pooling
+}
+
+func (fdo *funcOption) getShards() int {
+	return fdo.shards
+}
+
+func (fdo *funcOption) getItemTTL() time.Duration {
+	return fdo.itemTTL
+}
+
+func (fdo *funcOption) getPollInterval() Duration {
+	return fdo.pollInterval
+}
+
+func (fdo *funcOption) getPollJitter() float64 {
+	return fdo.pollJitter
+}
+
+func (fdo *funcOption) isPollSkipUnchanged() bool {
+	return fdo.pollSkipUnchanged
+}
+
+func (fdo *funcOption) getCronParser() CronParser {
+	if fdo.cronParser == nil {
+		return ParseCron
+	}
+	return fdo.cronParser
+}
+
+func (fdo *funcOption) getJSONTarget() func() interface{} {
+	return fdo.jsonTarget
+}
+
+func (fdo *funcOption) getReplaySpeed() float64 {
+	if fdo.replaySpeed <= 0 {
+		return 1
+	}
+	return fdo.replaySpeed
+}
+
+func (fdo *funcOption) getBatchBackOff() func() backoff.BackOff {
+	if fdo.batchBackOff == nil {
+		return func() backoff.BackOff {
+			return backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 3)
+		}
+	}
+	return fdo.batchBackOff
+}
+
+func (fdo *funcOption) getLimiter() Limiter {
+	return fdo.limiter
+}
+
+func (fdo *funcOption) getReorderBuffer() int {
+	return fdo.reorderBuffer
+}
+
+func (fdo *funcOption) getPredicateErrorPolicy() PredicateErrorPolicy {
+	return fdo.predicateErrorPolicy
+}
+
+func (fdo *funcOption) getStateStore() func() StateStore {
+	return fdo.stateStore
+}
+
+func (fdo *funcOption) getStage() string {
+	return fdo.stage
+}
+
+func (fdo *funcOption) getTerminalPolicy() TerminalPolicy {
+	return fdo.terminalPolicy
+}
+
+func (fdo *funcOption) getForwardFilter() Predicate {
+	return fdo.forwardFilter
+}
+
+func (fdo *funcOption) getForwardTransform() Func {
+	return fdo.forwardTransform
+}
+
+func (fdo *funcOption) isErrorIsolation() bool {
+	return fdo.errorIsolation
+}
+
+func (fdo *funcOption) isWeakSubscriptions() bool {
+	return fdo.weakSubscriptions
+}
+
+func (fdo *funcOption) getAckTimeout() time.Duration {
+	return fdo.ackTimeout
+}
+
+func (fdo *funcOption) getMaxDeliveries() int {
+	return fdo.maxDeliveries
+}
+
+func (fdo *funcOption) getMaxPending() int {
+	return fdo.maxPending
+}
+
+func (fdo *funcOption) getIdempotencyKey() func(interface{}) interface{} {
+	return fdo.idempotencyKey
+}
+
+func (fdo *funcOption) getProducerCount() int {
+	return fdo.producerCount
+}
+
+func (fdo *funcOption) getGroupBalancing() GroupBalancing {
+	return fdo.groupBalancing
+}
+
+func (fdo *funcOption) getPartitionKey() func(interface{}) interface{} {
+	return fdo.partitionKey
+}
+
+func (fdo *funcOption) getAdaptivePolicy() AdaptivePolicy {
+	return fdo.adaptivePolicy
+}
+
+func (fdo *funcOption) getConflationKey() func(interface{}) interface{} {
+	return fdo.conflationKey
+}
+
+func (fdo *funcOption) getConflationMerge() func(queued, next interface{}) interface{} {
+	return fdo.conflationMerge
+}
+
+func (fdo *funcOption) isRemoteTermination() bool {
+	return fdo.remoteTermination
+}
+
+func (fdo *funcOption) getClock() Clock {
+	if fdo.clock == nil {
+		return realClock{}
+	}
+	return fdo.clock
+}
+
 func newFuncOption(f func(*funcOption)) *funcOption {
 	return &funcOption{
 		f: f,
@@ -179,8 +426,308 @@ func Serialize(identifier func(interface{}) int) Option {
 	})
 }
 
+// WithLogger sets the logger reporting internal events. The package default logger is used otherwise.
+func WithLogger(logger Logger) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.logger = logger
+	})
+}
+
+// WithTracer sets the tracer recording spans for subject emissions, errors and drops.
+func WithTracer(tracer Tracer) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.tracer = tracer
+	})
+}
+
+// WithName names a subject. The name is reported by Subjects and in the subject log entries.
+func WithName(name string) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.name = name
+	})
+}
+
+// WithClock sets the clock used by the time-based operators, typically a TestScheduler in tests.
+func WithClock(clock Clock) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.clock = clock
+	})
+}
+
+// WithScheduler sets the scheduler running the callbacks of DoOnNext, DoOnError, DoOnCompleted and ForEach.
+// Each callback runs as a task and the next item is only processed once it returned.
+func WithScheduler(scheduler Scheduler) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.scheduler = scheduler
+	})
+}
+
+// WithWorkerPool makes a subject deliver its items with a fixed pool of n goroutines, shared by all its
+// subscribers, instead of a goroutine per subscriber. The subscribers are sharded over the goroutines: with the
+// Block strategy, an observer which stops consuming blocks the other subscribers of its goroutine until it is
+// unsubscribed.
+func WithWorkerPool(n int) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.workerPool = n
+	})
+}
+
+// WithBatchDelivery makes NextBatch deliver a batch to the subject subscribers as a single item holding the
+// values slice, so that a callback is invoked once per batch.
+func WithBatchDelivery() Option {
+	return newFuncOption(func(options *funcOption) {
+		options.batchDelivery = true
+	})
+}
+
+// WithPooling makes a subject recycle the internal buffers holding the items of a batch, reducing the garbage
+// collection pressure of sustained NextBatch calls.
+func WithPooling() Option {
+	return newFuncOption(func(options *funcOption) {
+		options.pooling = true
+	})
+}
+
+// WithShards makes a subject spread its subscribers over n delivery shards, each with its own goroutine,
+// so that publishing an item costs one hand-over per shard instead of one per subscriber.
+func WithShards(n int) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.shards = n
+	})
+}
+
+// WithItemTTL makes a subject discard the items queued for a subscriber longer than ttl instead of delivering
+// them stale. The discarded items are dead letters.
+func WithItemTTL(ttl time.Duration) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.itemTTL = ttl
+	})
+}
+
+// WithPollInterval sets the interval between two fetches of Poll. Created with WithDurationFunc, the interval
+// can be adjusted while polling.
+func WithPollInterval(interval Duration) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.pollInterval = interval
+	})
+}
+
+// WithPollJitter makes Poll wait a random extra delay of up to the given fraction of the interval, so that
+// several pollers spread their fetches.
+func WithPollJitter(fraction float64) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.pollJitter = fraction
+	})
+}
+
+// WithPollSkipUnchanged makes Poll skip the values equal to the previous one.
+func WithPollSkipUnchanged() Option {
+	return newFuncOption(func(options *funcOption) {
+		options.pollSkipUnchanged = true
+	})
+}
+
+// WithCronParser sets the parser of the cron expressions of Cron, ParseCron by default.
+func WithCronParser(parser CronParser) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.cronParser = parser
+	})
+}
+
+// WithJSONTarget makes FromJSONDecoder decode each value into the value returned by factory, typically a
+// pointer to a new struct, instead of a generic interface{} value.
+func WithJSONTarget(factory func() interface{}) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.jsonTarget = factory
+	})
+}
+
+// WithReplaySpeed makes Replay re-emit the recorded notifications factor times faster than recorded, 2 halving
+// the delays. math.Inf(1) re-emits them without delay.
+func WithReplaySpeed(factor float64) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.replaySpeed = factor
+	})
+}
+
+// WithBatchBackOff sets the factory of the policy retrying the failed writes of BatchSink, three retries with
+// an exponential back-off by default. backoff.StopBackOff disables the retries.
+func WithBatchBackOff(factory func() backoff.BackOff) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.batchBackOff = factory
+	})
+}
+
+// WithLimiter makes RateLimit take its tokens from a limiter, such as a rate.Limiter of golang.org/x/time/rate,
+// instead of its own token bucket.
+func WithLimiter(limiter Limiter) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.limiter = limiter
+	})
+}
+
+// WithReorderBuffer sets the number of items MapParallel processes or holds ahead of the oldest item not
+// emitted yet, to re-emit the results in order.
+func WithReorderBuffer(size int) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.reorderBuffer = size
+	})
+}
+
+// WithPredicateErrorPolicy defines how the operators taking a PredicateE handle an error returned by the
+// predicate.
+func WithPredicateErrorPolicy(policy PredicateErrorPolicy) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.predicateErrorPolicy = policy
+	})
+}
+
+// WithStateStore sets the factory creating the state store of the stateful operators, such as Distinct or
+// GroupByDynamic, each observation using its own store.
+func WithStateStore(factory func() StateStore) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.stateStore = factory
+	})
+}
+
+// WithEviction makes the stateful operators keep their state in memory, evicting it per the policy.
+func WithEviction(policy EvictionPolicy, opts ...Option) Option {
+	return WithStateStore(func() StateStore {
+		return NewMemoryStateStore(policy, opts...)
+	})
+}
+
+// WithTerminalPolicy defines when a subject fed by Subject.SubscribeTo completes.
+func WithTerminalPolicy(policy TerminalPolicy) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.terminalPolicy = policy
+	})
+}
+
+// WithForwardFilter makes Forward forward only the values satisfying the predicate.
+func WithForwardFilter(predicate Predicate) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.forwardFilter = predicate
+	})
+}
+
+// WithForwardTransform makes Forward transform the values it forwards, applied after the filter.
+func WithForwardTransform(transform Func) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.forwardTransform = transform
+	})
+}
+
+// WithErrorIsolation makes Forward and Router deliver the errors inline, so that they never terminate the destination.
+func WithErrorIsolation() Option {
+	return newFuncOption(func(options *funcOption) {
+		options.errorIsolation = true
+	})
+}
+
+// WithWeakSubscriptions makes the subscriptions of a subject weak: a subscription which is not observed is
+// unsubscribed, with a warning logged, once its Subscription and its Observable became unreachable without
+// having been unsubscribed.
+func WithWeakSubscriptions() Option {
+	return newFuncOption(func(options *funcOption) {
+		options.weakSubscriptions = true
+	})
+}
+
+// WithAckTimeout makes a durable subscription redeliver the items its subscriber did not acknowledge within
+// timeout of their delivery.
+func WithAckTimeout(timeout time.Duration) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.ackTimeout = timeout
+	})
+}
+
+// WithMaxDeliveries makes a durable subscription send the items delivered n times without being acknowledged
+// to the dead letters instead of redelivering them.
+func WithMaxDeliveries(n int) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.maxDeliveries = n
+	})
+}
+
+// WithMaxPending makes a durable subscription keep at most n unacknowledged items, including the items
+// published while no subscriber is attached. Beyond, the oldest pending items are sent to the dead letters
+// with the ErrMaxPending reason. The items are kept without limit by default.
+func WithMaxPending(n int) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.maxPending = n
+	})
+}
+
+// WithIdempotencyKey makes a durable subscription suppress the items whose key, returned by selector, was
+// already acknowledged. The keys are kept in the store set with WithStateStore, in memory by default.
+func WithIdempotencyKey(selector func(interface{}) interface{}) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.idempotencyKey = selector
+	})
+}
+
+// WithProducerCount makes a subject expect n producers, each calling Complete once done, so that it only
+// completes once all of them completed.
+func WithProducerCount(n int) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.producerCount = n
+	})
+}
+
+// WithGroupBalancing defines how a subscription group picks the member receiving an item.
+func WithGroupBalancing(balancing GroupBalancing) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.groupBalancing = balancing
+	})
+}
+
+// WithPartitionKey makes a subscription group deliver the items with the same key, returned by selector, to
+// the same member. The assignments of the keys are kept in the store set with WithStateStore, in memory by
+// default.
+func WithPartitionKey(selector func(interface{}) interface{}) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.partitionKey = selector
+	})
+}
+
+// WithAdaptivePolicy defines when the subscriptions of a subject with the Adaptive back pressure strategy
+// switch modes. The zero fields take their default value.
+func WithAdaptivePolicy(policy AdaptivePolicy) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.adaptivePolicy = policy
+	})
+}
+
+// WithConflation defines how the subscriptions of a subject with the Conflate back pressure strategy conflate
+// their queued items: the items with the same key, returned by selector, are conflated, the queued value being
+// replaced by the next one or, if merge is not nil, by the value it returns. Without a selector, all the items
+// share a key.
+func WithConflation(selector func(interface{}) interface{}, merge func(queued, next interface{}) interface{}) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.conflationKey = selector
+		options.conflationMerge = merge
+	})
+}
+
+// WithRemoteTermination makes ServeSubject terminate the served subject with the Error and Complete calls of
+// the remote subjects, which are ignored by default.
+func WithRemoteTermination() Option {
+	return newFuncOption(func(options *funcOption) {
+		options.remoteTermination = true
+	})
+}
+
 func connect() Option {
 	return newFuncOption(func(options *funcOption) {
 		options.connectOperation = true
 	})
 }
+
+// inStage passes the name of the pipeline stage observing an Observable, so that its diagnostics are attributed
+// to the stage
+func inStage(stage string) Option {
+	return newFuncOption(func(options *funcOption) {
+		options.stage = stage
+	})
+}
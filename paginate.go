@@ -0,0 +1,46 @@
+package rxgo
+
+import "context"
+
+// Paginate creates an Observable emitting the items of the pages fetched by fetchPage, which returns the items
+// of the page at a cursor along with the cursor of the next page, nil after the last page. The first page is
+// fetched with a nil cursor.
+//
+// The pages are fetched lazily: a page is only fetched once the items of the previous one were consumed, so
+// that a slow or early-stopping consumer does not fetch pages it does not use. Each observer starts from the
+// first page, until the context is done. A fetch error is emitted and stops the pagination, the retries of a
+// page belonging to fetchPage.
+func Paginate(ctx context.Context, fetchPage func(ctx context.Context, cursor interface{}) ([]interface{}, interface{}, error), opts ...Option) Observable {
+	return &ObservableImpl{
+		iterable: newFactoryIterable(func(propagatedOptions ...Option) <-chan Item {
+			option := parseOptions(append(opts, propagatedOptions...)...)
+			next := option.buildChannel()
+			ctx := option.buildContext(ctx)
+
+			go func() {
+				defer close(next)
+				var cursor interface{}
+				for {
+					items, nextCursor, err := fetchPage(ctx, cursor)
+					if ctx.Err() != nil {
+						return
+					}
+					if err != nil {
+						Error(err).SendContext(ctx, next)
+						return
+					}
+					for _, item := range items {
+						if !Of(item).SendContext(ctx, next) {
+							return
+						}
+					}
+					if nextCursor == nil {
+						return
+					}
+					cursor = nextCursor
+				}
+			}()
+			return next
+		}),
+	}
+}
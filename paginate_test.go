@@ -0,0 +1,67 @@
+package rxgo
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// pages returns a fetchPage function serving pages of two items, counting the fetches
+func pages(count int, fetched *int32) func(context.Context, interface{}) ([]interface{}, interface{}, error) {
+	return func(_ context.Context, cursor interface{}) ([]interface{}, interface{}, error) {
+		atomic.AddInt32(fetched, 1)
+		page := 0
+		if cursor != nil {
+			page = cursor.(int)
+		}
+		items := []interface{}{2 * page, 2*page + 1}
+		if page == count-1 {
+			return items, nil, nil
+		}
+		return items, page + 1, nil
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var fetched int32
+	Assert(ctx, t, Paginate(ctx, pages(3, &fetched)), HasItems(0, 1, 2, 3, 4, 5), HasNoError())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&fetched))
+}
+
+func TestPaginate_Lazy(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var fetched int32
+	observe := Paginate(ctx, pages(100, &fetched)).Observe()
+
+	assert.Equal(t, 0, (<-observe).V)
+	assert.Equal(t, 1, (<-observe).V)
+	time.Sleep(20 * time.Millisecond)
+	// the second page is being sent
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetched))
+	cancel()
+	for range observe {
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetched))
+}
+
+func TestPaginate_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := Paginate(ctx, func(_ context.Context, cursor interface{}) ([]interface{}, interface{}, error) {
+		if cursor == nil {
+			return []interface{}{1}, "next", nil
+		}
+		return nil, nil, errFoo
+	})
+	Assert(ctx, t, obs, HasItems(1), HasError(errFoo))
+}
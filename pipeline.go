@@ -0,0 +1,55 @@
+package rxgo
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// RunWith runs an Observable on a goroutine of the group until it completes, the context is done or it emits
+// an error, returned to the group.
+func RunWith(ctx context.Context, g *errgroup.Group, observable Observable, opts ...Option) {
+	g.Go(func() error {
+		return observable.Wait(ctx, opts...)
+	})
+}
+
+// Pipeline runs Observables with a structured lifetime: the first error cancels the pipeline context,
+// stopping all of them, and Wait returns once they are all stopped.
+type Pipeline struct {
+	group *errgroup.Group
+	ctx   context.Context
+}
+
+// NewPipeline creates a Pipeline whose context is derived from the given one.
+func NewPipeline(ctx context.Context) *Pipeline {
+	group, ctx := errgroup.WithContext(ctx)
+	return &Pipeline{
+		group: group,
+		ctx:   ctx,
+	}
+}
+
+// Context returns the pipeline context, done on the first error, once Wait returned or once the parent
+// context is done. It can be passed to the sources of the Observables with WithContext.
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Run runs an Observable until it completes. An error cancels the pipeline.
+func (p *Pipeline) Run(observable Observable, opts ...Option) {
+	RunWith(p.ctx, p.group, observable, opts...)
+}
+
+// Go runs a function, such as a producer publishing to a Subject, with the pipeline context. An error cancels
+// the pipeline.
+func (p *Pipeline) Go(f func(ctx context.Context) error) {
+	p.group.Go(func() error {
+		return f(p.ctx)
+	})
+}
+
+// Wait blocks until all the Observables and functions are stopped, and returns the first error.
+func (p *Pipeline) Wait() error {
+	return p.group.Wait()
+}
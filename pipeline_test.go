@@ -0,0 +1,40 @@
+package rxgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestRunWith(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	var g errgroup.Group
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	RunWith(ctx, &g, testObservable(ctx, 1, 2, 3))
+	RunWith(ctx, &g, testObservable(ctx, 1, errFoo))
+	assert.Equal(t, errFoo, g.Wait())
+}
+
+// TestPipeline verifies the first error stops all the Observables of a pipeline
+func TestPipeline(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	p := NewPipeline(context.Background())
+
+	p.Run(Interval(WithDuration(time.Millisecond), WithContext(p.Context())).Map(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i, nil
+	}))
+	p.Run(Never())
+	p.Go(func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return errFoo
+	})
+
+	assert.Equal(t, errFoo, p.Wait())
+	assert.Error(t, p.Context().Err())
+}
@@ -0,0 +1,69 @@
+package rxgo
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+// Poll creates an Observable calling fetch on a schedule, set with WithPollInterval, and emitting the fetched
+// values. Each observer starts its own polling, the first fetch being immediate, until the context is done.
+//
+// A fetch error is emitted and, unless the ContinueOnError strategy is set, stops the polling.
+// WithPollJitter spreads the fetches and WithPollSkipUnchanged skips the values equal to the previous one.
+func Poll(ctx context.Context, fetch func(ctx context.Context) (interface{}, error), opts ...Option) Observable {
+	if interval := parseOptions(opts...).getPollInterval(); interval == nil || interval.duration() <= 0 {
+		return Thrown(IllegalInputError{error: "poll interval must be positive"})
+	}
+
+	return &ObservableImpl{
+		iterable: newFactoryIterable(func(propagatedOptions ...Option) <-chan Item {
+			option := parseOptions(append(opts, propagatedOptions...)...)
+			next := option.buildChannel()
+			ctx := option.buildContext(ctx)
+
+			go func() {
+				defer close(next)
+				var last interface{}
+				fetched := false
+				for {
+					v, err := fetch(ctx)
+					switch {
+					case ctx.Err() != nil:
+						return
+					case err != nil:
+						if !Error(err).SendContext(ctx, next) || option.getErrorStrategy() == StopOnError {
+							return
+						}
+					case option.isPollSkipUnchanged() && fetched && reflect.DeepEqual(v, last):
+					default:
+						last = v
+						fetched = true
+						if !Of(v).SendContext(ctx, next) {
+							return
+						}
+					}
+
+					timer := option.getClock().NewTimer(pollDelay(option))
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return
+					case <-timer.C():
+					}
+				}
+			}()
+			return next
+		}),
+	}
+}
+
+// pollDelay returns the interval until the next fetch, with its jitter
+func pollDelay(option Option) time.Duration {
+	d := option.getPollInterval().duration()
+	if jitter := option.getPollJitter(); jitter > 0 {
+		d += time.Duration(rand.Float64() * jitter * float64(d))
+	}
+	return d
+}
@@ -0,0 +1,81 @@
+package rxgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// counter returns a fetch function returning the number of previous calls, divided by div
+func counter(div int) func(context.Context) (interface{}, error) {
+	calls := 0
+	return func(context.Context) (interface{}, error) {
+		v := calls / div
+		calls++
+		return v, nil
+	}
+}
+
+func TestPoll(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Now())
+	obs := Poll(context.Background(), counter(1), WithPollInterval(WithDuration(2*MarbleFrame)), WithClock(scheduler))
+	ExpectObservable(t, scheduler, obs, "a-b-c", map[string]interface{}{"a": 0, "b": 1, "c": 2}, nil)
+}
+
+func TestPoll_SkipUnchanged(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Now())
+	obs := Poll(context.Background(), counter(2), WithPollInterval(WithDuration(MarbleFrame)), WithClock(scheduler),
+		WithPollSkipUnchanged())
+	ExpectObservable(t, scheduler, obs, "a-b-c", map[string]interface{}{"a": 0, "b": 1, "c": 2}, nil)
+}
+
+func TestPoll_DynamicInterval(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Now())
+	interval := MarbleFrame
+	obs := Poll(context.Background(), func(context.Context) (interface{}, error) {
+		// slow down once polled
+		interval = 2 * MarbleFrame
+		return "x", nil
+	}, WithPollInterval(WithDurationFunc(func() time.Duration {
+		return interval
+	})), WithClock(scheduler))
+	ExpectObservable(t, scheduler, obs, "x-x-x", nil, nil)
+}
+
+func TestPoll_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewTestScheduler(time.Now())
+	calls := 0
+	fetch := func(context.Context) (interface{}, error) {
+		calls++
+		if calls == 2 {
+			return nil, errFoo
+		}
+		return calls, nil
+	}
+
+	obs := Poll(context.Background(), fetch, WithPollInterval(WithDuration(MarbleFrame)), WithClock(scheduler))
+	ExpectObservable(t, scheduler, obs, "a#", map[string]interface{}{"a": 1}, errFoo)
+
+	// the polling goes on after an error
+	calls = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	observe := Poll(ctx, fetch, WithPollInterval(WithDuration(time.Millisecond)), WithErrorStrategy(ContinueOnError)).Observe()
+	items := receive(t, observe, 3)
+	assert.Equal(t, []Item{Of(1), Error(errFoo), Of(3)}, items)
+	cancel()
+	for range observe {
+	}
+}
+
+func TestPoll_NoInterval(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	Assert(context.Background(), t, Poll(context.Background(), counter(1)), HasAnError())
+}
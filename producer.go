@@ -0,0 +1,89 @@
+package rxgo
+
+import "sync"
+
+// ProducerHandle publishes to a Subject on behalf of one producer, obtained with Subject.Producer. The items
+// of a producer are published in the order of its calls, even from several goroutines, while the items of
+// different producers interleave. Once the producer completed, its calls are ignored.
+type ProducerHandle struct {
+	mu sync.Mutex
+	// subject is the subject published to, such as a ReplaySubject embedding base
+	subject   ISubject
+	base      *Subject
+	completed bool
+}
+
+// Producer registers a producer of the subject. Once all its registered producers, and the producers expected
+// with WithProducerCount, completed, the subject completes.
+func (s *Subject) Producer() *ProducerHandle {
+	return s.producer(s)
+}
+
+// producer registers a producer publishing to dst, the subject itself or the subject embedding it
+func (s *Subject) producer(dst ISubject) *ProducerHandle {
+	s.Lock()
+	defer s.Unlock()
+
+	s.producers++
+	return &ProducerHandle{subject: dst, base: s}
+}
+
+// Next publishes a value.
+func (p *ProducerHandle) Next(value interface{}) {
+	p.NextItem(Of(value))
+}
+
+// NextItem publishes an item.
+func (p *ProducerHandle) NextItem(item Item) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.completed {
+		p.subject.NextItem(item)
+	}
+}
+
+// NextBatch publishes values, in order.
+func (p *ProducerHandle) NextBatch(values []interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.completed {
+		p.subject.NextBatch(values)
+	}
+}
+
+// Error publishes an error, which terminates the subject with the StopOnError strategy, whatever its other
+// producers.
+func (p *ProducerHandle) Error(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.completed {
+		p.subject.Error(err)
+	}
+}
+
+// Complete completes the producer. The subject completes once it was its last registered producer.
+func (p *ProducerHandle) Complete() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.completed {
+		p.completed = true
+		p.base.producerCompleted()
+	}
+}
+
+// producerCompleted completes the subject once all its producers completed
+func (s *Subject) producerCompleted() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.producers--; s.producers > 0 {
+		return
+	}
+	s.logger().Debug("rxgo: subject completed by its producers", "subscribers", len(s.subscribers))
+	s.terminate(nil)
+	s.closeSubscribers()
+}
@@ -0,0 +1,110 @@
+package rxgo
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestSubject_Producer(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs, WithBufferedChannel(1000))
+
+	const producers, count = 4, 100
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		producer := subject.Producer()
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				producer.Next([2]int{p, i})
+			}
+			producer.Complete()
+			// ignored once completed
+			producer.Next([2]int{p, count})
+		}(p)
+	}
+	wg.Wait()
+	observer.AwaitDone(time.Second)
+
+	// the items of each producer are received in order
+	next := make([]int, producers)
+	for _, v := range observer.Values() {
+		item := v.([2]int)
+		assert.Equal(t, next[item[0]], item[1])
+		next[item[0]]++
+	}
+	assert.Equal(t, []int{count, count, count, count}, next)
+}
+
+func TestSubject_Producer_Complete(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	first := subject.Producer()
+	second := subject.Producer()
+
+	first.Complete()
+	first.Complete()
+	select {
+	case <-subject.Done():
+		assert.Fail(t, "completed with a producer left")
+	default:
+	}
+	second.Next(1)
+	second.Complete()
+	<-subject.Done()
+	assert.NoError(t, subject.Err())
+}
+
+func TestSubject_WithProducerCount(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject(WithProducerCount(2))
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs, WithBufferedChannel(10))
+	producer := subject.Producer()
+
+	subject.Next(1)
+	subject.Complete()
+	subject.Next(2)
+	producer.Next(3)
+	producer.Complete()
+	assert.False(t, observer.IsCompleted())
+
+	// the last expected producer completes the subject
+	subject.Complete()
+	observer.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{1, 2, 3}, observer.Values())
+}
+
+func TestReplaySubject_Producer(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewReplaySubject(10)
+	producer := subject.Producer()
+
+	producer.Next(1)
+	producer.NextBatch([]interface{}{2, 3})
+	// the items published by the producer are replayed
+	assert.Equal(t, []interface{}{1, 2, 3}, subject.PeekAll())
+	producer.Complete()
+	<-subject.Done()
+}
+
+func TestBehaviorSubject_Producer(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewBehaviorSubject()
+	producer := subject.Producer()
+
+	producer.Next(42)
+	// the value published by the producer is the current value
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs, WithBufferedChannel(10))
+	producer.Complete()
+	observer.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{42}, observer.Values())
+}
@@ -0,0 +1,84 @@
+package rxgo
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter is a rate limiter. It is implemented by the rate.Limiter of golang.org/x/time/rate, which can be
+// passed to RateLimit with WithLimiter.
+type Limiter interface {
+	// Allow reports whether an event may happen now, consuming a token if so.
+	Allow() bool
+	// Wait blocks until an event may happen, or returns an error once the context is done.
+	Wait(ctx context.Context) error
+}
+
+// tokenBucket is a Limiter refilled with rate tokens per second and holding up to burst tokens, measuring the
+// time with a Clock
+type tokenBucket struct {
+	mu     sync.Mutex
+	clock  Clock
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a full token bucket
+func newTokenBucket(rate float64, burst int, clock Clock) *tokenBucket {
+	return &tokenBucket{
+		clock:  clock,
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   clock.Now(),
+	}
+}
+
+// refill adds the tokens earned since the last refill, the lock being held
+func (b *tokenBucket) refill() {
+	now := b.clock.Now()
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+	}
+	b.last = now
+}
+
+func (b *tokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	b.refill()
+	// the token is reserved, the bucket going into debt until it is earned
+	b.tokens--
+	wait := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := b.clock.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package rxgo
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+)
+
+// FromReaderLines creates an Observable emitting the lines read from r as strings, without their line ending,
+// and completing on EOF or emitting the read error. As the reader is consumed, the Observable is meant to be
+// observed once. Once the context is done, the Observable completes after the pending read returned.
+func FromReaderLines(ctx context.Context, r io.Reader, opts ...Option) Observable {
+	return CreateWithEmitter(func(ctx context.Context, emitter Emitter) {
+		reader := bufio.NewReader(r)
+		for !emitter.IsDisposed() {
+			line, err := reader.ReadString('\n')
+			if line != "" && (err == nil || err == io.EOF) {
+				emitter.Next(strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"))
+			}
+			if err == io.EOF {
+				emitter.Complete()
+				return
+			}
+			if err != nil {
+				emitter.Error(err)
+				return
+			}
+		}
+	}, append(opts, WithContext(ctx))...)
+}
+
+// FromReaderChunks creates an Observable emitting the bytes read from r in chunks of size bytes, the last one
+// being possibly shorter, and completing on EOF or emitting the read error. As the reader is consumed, the
+// Observable is meant to be observed once. Once the context is done, the Observable completes after the
+// pending read returned.
+func FromReaderChunks(ctx context.Context, r io.Reader, size int, opts ...Option) Observable {
+	if size <= 0 {
+		return Thrown(IllegalInputError{error: "chunk size must be positive"})
+	}
+	return CreateWithEmitter(func(ctx context.Context, emitter Emitter) {
+		for !emitter.IsDisposed() {
+			chunk := make([]byte, size)
+			n, err := io.ReadFull(r, chunk)
+			if n > 0 {
+				emitter.Next(chunk[:n])
+			}
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				emitter.Complete()
+				return
+			}
+			if err != nil {
+				emitter.Error(err)
+				return
+			}
+		}
+	}, append(opts, WithContext(ctx))...)
+}
@@ -0,0 +1,33 @@
+package rxgo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"go.uber.org/goleak"
+)
+
+func TestFromReaderLines(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	Assert(ctx, t, FromReaderLines(ctx, strings.NewReader("a\nb\r\n\nc")), HasItems("a", "b", "", "c"), HasNoError())
+	Assert(ctx, t, FromReaderLines(ctx, strings.NewReader("")), IsEmpty(), HasNoError())
+	Assert(ctx, t, FromReaderLines(ctx, iotest.OneByteReader(strings.NewReader("a\nb"))), HasItems("a", "b"), HasNoError())
+	Assert(ctx, t, FromReaderLines(ctx, iotest.ErrReader(errFoo)), IsEmpty(), HasError(errFoo))
+}
+
+func TestFromReaderChunks(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	Assert(ctx, t, FromReaderChunks(ctx, iotest.OneByteReader(strings.NewReader("abcde")), 2),
+		HasItems([]byte("ab"), []byte("cd"), []byte("e")), HasNoError())
+	Assert(ctx, t, FromReaderChunks(ctx, strings.NewReader("abcd"), 2), HasItems([]byte("ab"), []byte("cd")), HasNoError())
+	Assert(ctx, t, FromReaderChunks(ctx, iotest.ErrReader(errFoo), 2), IsEmpty(), HasError(errFoo))
+	Assert(ctx, t, FromReaderChunks(ctx, strings.NewReader("abcd"), 0), HasAnError())
+}
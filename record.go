@@ -0,0 +1,136 @@
+package rxgo
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+// errTruncatedRecord is the error of a recorded notification shorter than its timestamp
+var errTruncatedRecord = errors.New("truncated record")
+
+// Record writes the notifications of an Observable to w along with their time, encoded with the codec, until the
+// Observable terminates or the context is done, so that a production sequence can be reproduced with Replay.
+// The time of a notification is the time elapsed since the recording started, measured with the clock set with
+// WithClock.
+//
+// An error item followed by other items is recorded as an inline error item, the last one as the termination
+// with an error. It returns nil once the termination was recorded, the context error if the context is done
+// first, or the first encoding or write error, the observation being stopped.
+func Record(ctx context.Context, observable Observable, w io.Writer, codec Codec, opts ...Option) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	clock := parseOptions(opts...).getClock()
+	writer := &frameWriter{w: w}
+	start := clock.Now()
+	record := func(at time.Time, kind NotificationKind, item Item) error {
+		data, err := codec.Marshal(kind, item)
+		if err != nil {
+			return err
+		}
+		frame := make([]byte, 8+len(data))
+		binary.BigEndian.PutUint64(frame, uint64(at.Sub(start)))
+		copy(frame[8:], data)
+		return writer.writeFrame(frame)
+	}
+
+	observe := observable.Observe(append(opts, WithContext(ctx))...)
+	// an error item is only known to terminate the Observable once the channel is closed
+	var pending *Item
+	var pendingAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-observe:
+			at := clock.Now()
+			if !ok {
+				if pending != nil {
+					return record(pendingAt, KindError, *pending)
+				}
+				return record(at, KindComplete, Item{})
+			}
+			if pending != nil {
+				if err := record(pendingAt, KindErrorItem, *pending); err != nil {
+					return err
+				}
+				pending = nil
+			}
+
+			if item.Error() {
+				pending, pendingAt = &item, at
+				continue
+			}
+			if err := record(at, KindNext, item); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Replay creates an Observable re-emitting the notifications recorded by Record, each one once its recorded
+// time elapsed since the observation started, measured with the clock set with WithClock: a TestScheduler
+// reproduces the sequence in virtual time. WithReplaySpeed scales the time.
+//
+// A recording ending without termination, such as one interrupted by the context, completes. An unreadable
+// recording emits the read error. As the recording is consumed, the Observable is meant to be observed once.
+func Replay(r io.Reader, codec Codec, opts ...Option) Observable {
+	option := parseOptions(opts...)
+	clock := option.getClock()
+	speed := option.getReplaySpeed()
+
+	return Defer([]Producer{func(ctx context.Context, next chan<- Item) {
+		start := clock.Now()
+		for {
+			data, err := readFrameData(r)
+			if err != nil {
+				if err != io.EOF {
+					Error(err).SendContext(ctx, next)
+				}
+				return
+			}
+			if len(data) < 8 {
+				Error(errTruncatedRecord).SendContext(ctx, next)
+				return
+			}
+			offset := scaleDuration(time.Duration(binary.BigEndian.Uint64(data)), speed)
+			kind, item, err := codec.Unmarshal(data[8:])
+			if err != nil {
+				kind, item = KindErrorItem, Error(err)
+			}
+
+			if delay := start.Add(offset).Sub(clock.Now()); delay > 0 {
+				timer := clock.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C():
+				}
+			}
+
+			switch kind {
+			case KindNext, KindErrorItem:
+				if !item.SendContext(ctx, next) {
+					return
+				}
+			case KindError:
+				item.SendContext(ctx, next)
+				return
+			case KindComplete:
+				return
+			}
+		}
+	}}, opts...)
+}
+
+// scaleDuration divides a recorded duration by the replay speed
+func scaleDuration(d time.Duration, speed float64) time.Duration {
+	if math.IsInf(speed, 1) {
+		return 0
+	}
+	return time.Duration(float64(d) / speed)
+}
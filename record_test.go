@@ -0,0 +1,113 @@
+package rxgo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// stepClock is a clock moving forward by a second each time it is read
+type stepClock struct {
+	Clock
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time {
+	c.now = c.now.Add(time.Second)
+	return c.now
+}
+
+// recording records the items of a closed channel, one second apart
+func recording(t *testing.T, items ...Item) *bytes.Buffer {
+	t.Helper()
+	ch := make(chan Item, len(items))
+	for _, item := range items {
+		ch <- item
+	}
+	close(ch)
+
+	buf := new(bytes.Buffer)
+	err := Record(context.Background(), FromChannel(ch), buf, NewGobCodec(), WithClock(&stepClock{}))
+	assert.NoError(t, err)
+	return buf
+}
+
+func Test_Record_Replay(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	buf := recording(t, Of(1), Of(2))
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	ch := Replay(buf, NewGobCodec(), WithClock(scheduler)).Observe()
+
+	scheduler.BlockUntil(1)
+	scheduler.Advance(999 * time.Millisecond)
+	select {
+	case <-ch:
+		assert.FailNow(t, "item replayed too early")
+	default:
+	}
+	scheduler.Advance(time.Millisecond)
+	assert.Equal(t, 1, (<-ch).V)
+
+	scheduler.BlockUntil(1)
+	scheduler.Advance(time.Second)
+	assert.Equal(t, 2, (<-ch).V)
+
+	scheduler.BlockUntil(1)
+	scheduler.Advance(time.Second)
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func Test_Replay_Speed(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	buf := recording(t, Of(1))
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	ch := Replay(buf, NewGobCodec(), WithClock(scheduler), WithReplaySpeed(2)).Observe()
+
+	scheduler.BlockUntil(1)
+	scheduler.Advance(500 * time.Millisecond)
+	assert.Equal(t, 1, (<-ch).V)
+	scheduler.BlockUntil(1)
+	scheduler.Advance(500 * time.Millisecond)
+	_, ok := <-ch
+	assert.False(t, ok)
+}
+
+func Test_Record_Errors(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	buf := recording(t, Of(1), Error(errFoo), Of(2), Error(errBar))
+	items := receive(t, Replay(buf, NewGobCodec(), WithReplaySpeed(math.Inf(1))).Observe(), 4)
+
+	assert.Equal(t, 1, items[0].V)
+	assert.EqualError(t, items[1].E, errFoo.Error())
+	assert.Equal(t, 2, items[2].V)
+	assert.EqualError(t, items[3].E, errBar.Error())
+}
+
+func Test_Replay_Truncated(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	buf := recording(t, Of(1), Of(2))
+	truncated := bytes.NewReader(buf.Bytes()[:buf.Len()-1])
+	Assert(context.Background(), t, Replay(truncated, NewGobCodec(), WithReplaySpeed(math.Inf(1))),
+		HasItems(1, 2), HasError(io.ErrUnexpectedEOF))
+}
+
+func Test_Record_Context(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan Item)
+	done := make(chan error)
+	go func() {
+		done <- Record(ctx, FromChannel(ch), new(bytes.Buffer), NewGobCodec())
+	}()
+
+	ch <- Of(1)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
@@ -0,0 +1,79 @@
+package rxgo
+
+import (
+	"sort"
+	"sync"
+)
+
+// SubjectInfo describes a live subject.
+type SubjectInfo struct {
+	// Name is the name set with WithName, empty otherwise.
+	Name string
+	// Type is the subject type: Subject, BehaviorSubject, ReplaySubject, DiskReplaySubject or TopicSubject.
+	Type string
+	// Stats is a snapshot of the subject activity.
+	Stats SubjectStats
+	// Buffered is the number of items held for replay to new subscribers.
+	Buffered int
+}
+
+// introspectable is implemented by all subject types
+type introspectable interface {
+	info() SubjectInfo
+}
+
+type registryEntry struct {
+	seq     int
+	subject introspectable
+}
+
+// subjectRegistry holds the live subjects named with WithName, indexed by their base subject. The unnamed
+// subjects are not registered, so that a subject dropped without being terminated is not pinned.
+type subjectRegistry struct {
+	sync.Mutex
+	nextSeq  int
+	subjects map[*Subject]registryEntry
+}
+
+var globalSubjects = &subjectRegistry{
+	subjects: make(map[*Subject]registryEntry),
+}
+
+func (r *subjectRegistry) add(base *Subject, subject introspectable) {
+	if base.Name() == "" {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+
+	r.subjects[base] = registryEntry{seq: r.nextSeq, subject: subject}
+	r.nextSeq++
+}
+
+func (r *subjectRegistry) remove(base *Subject) {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.subjects, base)
+}
+
+// Subjects lists the live subjects named with WithName in creation order. A subject is live until it is
+// completed or terminated by an error.
+func Subjects() []SubjectInfo {
+	globalSubjects.Lock()
+	entries := make([]registryEntry, 0, len(globalSubjects.subjects))
+	for _, entry := range globalSubjects.subjects {
+		entries = append(entries, entry)
+	}
+	globalSubjects.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].seq < entries[j].seq
+	})
+
+	res := make([]SubjectInfo, len(entries))
+	for i, entry := range entries {
+		res[i] = entry.subject.info()
+	}
+	return res
+}
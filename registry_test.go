@@ -0,0 +1,78 @@
+package rxgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func findSubject(name string) (SubjectInfo, bool) {
+	for _, info := range Subjects() {
+		if info.Name == name {
+			return info, true
+		}
+	}
+	return SubjectInfo{}, false
+}
+
+// TestSubjects verifies live subjects are listed until they terminate
+func TestSubjects(t *testing.T) {
+	subject := NewSubject(WithName("registry.subject"))
+	behavior := NewBehaviorSubject(WithName("registry.behavior"))
+	replay := NewReplaySubject(3, WithName("registry.replay"))
+
+	subject.Subscribe()
+	subject.Subscribe()
+	behavior.Next(1)
+	replay.Next(1)
+	replay.Next(2)
+
+	info, found := findSubject("registry.subject")
+	assert.True(t, found)
+	assert.Equal(t, "Subject", info.Type)
+	assert.Equal(t, 2, info.Stats.Subscribers)
+	assert.Equal(t, "registry.subject", subject.Name())
+
+	info, found = findSubject("registry.behavior")
+	assert.True(t, found)
+	assert.Equal(t, "BehaviorSubject", info.Type)
+	assert.Equal(t, 1, info.Buffered)
+
+	info, found = findSubject("registry.replay")
+	assert.True(t, found)
+	assert.Equal(t, "ReplaySubject", info.Type)
+	assert.Equal(t, 2, info.Buffered)
+	assert.Equal(t, uint64(2), info.Stats.Emitted)
+
+	subject.Complete()
+	behavior.Error(errFoo)
+	replay.Complete()
+
+	for _, name := range []string{"registry.subject", "registry.behavior", "registry.replay"} {
+		_, found = findSubject(name)
+		assert.False(t, found, name)
+	}
+}
+
+// TestSubjects_ContinueOnError verifies a subject stays live when errors do not terminate it
+func TestSubjects_ContinueOnError(t *testing.T) {
+	subject := NewSubject(WithName("registry.continue"), WithErrorStrategy(ContinueOnError))
+	defer subject.Complete()
+
+	subject.Error(errFoo)
+	_, found := findSubject("registry.continue")
+	assert.True(t, found)
+}
+
+// TestSubjects_Unnamed verifies the unnamed subjects are not registered
+func TestSubjects_Unnamed(t *testing.T) {
+	subject := NewSubject()
+	replay := NewReplaySubject(1)
+
+	globalSubjects.Lock()
+	_, subjectFound := globalSubjects.subjects[subject]
+	_, replayFound := globalSubjects.subjects[&replay.Subject]
+	globalSubjects.Unlock()
+	assert.False(t, subjectFound)
+	assert.False(t, replayFound)
+}
@@ -0,0 +1,297 @@
+package rxgo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+)
+
+// maxFrameSize bounds the payload of a frame, guarding against a corrupted stream
+const maxFrameSize = 64 << 20
+
+// ServeSubject serves a subject on a listener, turning it into a networked event bus: each connection, opened
+// with DialSubject, receives the notifications of the subject, and its notifications are sent to the subject.
+// The values are encoded with the codec, and the errors sent with their message. A value failing to encode or
+// decode is delivered as an error item, which does not terminate the subject.
+//
+// A connection does not terminate the subject, shared with the other connections: its Error and Complete calls
+// are ignored, unless WithRemoteTermination is passed.
+//
+// The connections are closed once the subject terminated, after its termination was sent. ServeSubject returns
+// the error of the listener once it is closed, after closing the connections.
+func ServeSubject(listener net.Listener, subject ISubject, codec Codec, opts ...Option) error {
+	option := parseOptions(opts...)
+	var mu sync.Mutex
+	conns := make(map[net.Conn]struct{})
+	var wg sync.WaitGroup
+	defer func() {
+		mu.Lock()
+		for conn := range conns {
+			_ = conn.Close()
+		}
+		mu.Unlock()
+		wg.Wait()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		conns[conn] = struct{}{}
+		mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serveConn(conn, subject, codec, option)
+			mu.Lock()
+			delete(conns, conn)
+			mu.Unlock()
+		}()
+	}
+}
+
+// serveConn relays the notifications of a connection until it is closed
+func serveConn(conn net.Conn, subject ISubject, codec Codec, option Option) {
+	defer conn.Close()
+	sub, obs := subject.Subscribe()
+	defer sub.Unsubscribe()
+	w := &frameWriter{w: conn}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for item := range obs.Observe() {
+			if err := w.writeItem(item, codec); err != nil {
+				_ = conn.Close()
+				return
+			}
+		}
+		// the subject terminated
+		var err error
+		if s, ok := subject.(interface{ Err() error }); ok {
+			err = s.Err()
+		}
+		if err != nil {
+			_ = w.write(codec, KindError, Error(err))
+		} else {
+			_ = w.write(codec, KindComplete, Item{})
+		}
+		_ = conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	for {
+		kind, item, err := readFrame(r, codec)
+		if err != nil {
+			break
+		}
+		switch {
+		case kind == KindNext || kind == KindErrorItem:
+			subject.NextItem(item)
+		case !option.isRemoteTermination():
+			option.getLogger().Warn("rxgo: remote termination ignored", "remote", conn.RemoteAddr().String())
+		case kind == KindError:
+			subject.Error(item.E)
+		case kind == KindComplete:
+			subject.Complete()
+		}
+	}
+	sub.Unsubscribe()
+	<-done
+}
+
+// RemoteSubject is a subject served by another process with ServeSubject. Its subscribers receive the
+// notifications of the served subject, and its notifications are sent to the served subject, reaching the
+// subscribers of all the remote subjects connected to it.
+type RemoteSubject struct {
+	Subject
+	conn   net.Conn
+	codec  Codec
+	writer *frameWriter
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	done      chan struct{}
+}
+
+// DialSubject connects to a subject served with ServeSubject on a network address, such as a "tcp" or "unix"
+// one, the values being encoded with the codec. The options configure the local subject.
+func DialSubject(network, address string, codec Codec, opts ...Option) (*RemoteSubject, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	res := &RemoteSubject{
+		Subject: newSubject(opts...),
+		conn:    conn,
+		codec:   codec,
+		writer:  &frameWriter{w: conn},
+		closed:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	globalSubjects.add(&res.Subject, res)
+	go res.read()
+
+	return res, nil
+}
+
+// read delivers the notifications of the served subject to the local subscribers, until the connection is
+// closed
+func (s *RemoteSubject) read() {
+	defer close(s.done)
+	r := bufio.NewReader(s.conn)
+	for {
+		kind, item, err := readFrame(r, s.codec)
+		if err != nil {
+			select {
+			case <-s.closed:
+				s.Subject.Complete()
+			default:
+				s.Subject.Error(err)
+			}
+			return
+		}
+		switch kind {
+		case KindNext, KindErrorItem:
+			s.Subject.NextItem(item)
+		case KindError:
+			// the error was delivered as an item before the termination
+			s.Lock()
+			s.terminate(item.E)
+			s.closeSubscribers()
+			s.Unlock()
+			return
+		case KindComplete:
+			s.Subject.Complete()
+			return
+		}
+	}
+}
+
+// Next sends a value to the served subject.
+func (s *RemoteSubject) Next(value interface{}) {
+	s.NextItem(Of(value))
+}
+
+// NextItem sends an item to the served subject. An item holding an error is delivered inline.
+func (s *RemoteSubject) NextItem(item Item) {
+	s.send(s.writer.writeItem(item, s.codec))
+}
+
+// NextBatch sends values to the served subject, in order.
+func (s *RemoteSubject) NextBatch(values []interface{}) {
+	for _, v := range values {
+		s.Next(v)
+	}
+}
+
+// Error sends an error to the served subject, terminating it if served with WithRemoteTermination.
+func (s *RemoteSubject) Error(err error) {
+	s.send(s.writer.write(s.codec, KindError, Error(err)))
+}
+
+// Complete completes the served subject, if served with WithRemoteTermination.
+func (s *RemoteSubject) Complete() {
+	s.send(s.writer.write(s.codec, KindComplete, Item{}))
+}
+
+// send handles the result of a write. A failed write closes the connection, terminating the local subject with
+// the error.
+func (s *RemoteSubject) send(err error) {
+	if err != nil {
+		s.logger().Error("rxgo: remote subject write failed", "error", err)
+		_ = s.conn.Close()
+	}
+}
+
+// Close closes the connection, completing the local subscribers.
+func (s *RemoteSubject) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		err = s.conn.Close()
+	})
+	<-s.done
+	return err
+}
+
+func (s *RemoteSubject) info() SubjectInfo {
+	info := s.Subject.info()
+	info.Type = "RemoteSubject"
+	return info
+}
+
+// frameWriter writes frames, a frame being made of the length of a notification encoded with the codec, and
+// of the encoded notification
+type frameWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *frameWriter) write(codec Codec, kind NotificationKind, item Item) error {
+	data, err := codec.Marshal(kind, item)
+	if err != nil {
+		return err
+	}
+	return w.writeFrame(data)
+}
+
+// writeItem writes an item. A value failing to encode is written as an error item.
+func (w *frameWriter) writeItem(item Item, codec Codec) error {
+	if item.Error() {
+		return w.write(codec, KindErrorItem, item)
+	}
+	data, err := codec.Marshal(KindNext, item)
+	if err != nil {
+		return w.write(codec, KindErrorItem, Error(err))
+	}
+	return w.writeFrame(data)
+}
+
+func (w *frameWriter) writeFrame(data []byte) error {
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := w.w.Write(frame)
+	return err
+}
+
+// readFrame reads and decodes the next notification. A notification failing to decode is returned as an error
+// item.
+func readFrame(r io.Reader, codec Codec) (NotificationKind, Item, error) {
+	data, err := readFrameData(r)
+	if err != nil {
+		return 0, Item{}, err
+	}
+	kind, item, err := codec.Unmarshal(data)
+	if err != nil {
+		return KindErrorItem, Error(err), nil
+	}
+	return kind, item, nil
+}
+
+// readFrameData reads the payload of the next frame
+func readFrameData(r io.Reader) ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if size > maxFrameSize {
+		return nil, errors.New("frame too large")
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
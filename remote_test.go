@@ -0,0 +1,177 @@
+package rxgo
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+type remoteEvent struct {
+	Name string
+}
+
+// serve serves a subject on a listener, returning a function stopping the server
+func serve(t *testing.T, listener net.Listener, subject ISubject, codec Codec, opts ...Option) func() {
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeSubject(listener, subject, codec, opts...)
+	}()
+	return func() {
+		assert.NoError(t, listener.Close())
+		assert.Error(t, <-done)
+	}
+}
+
+// awaitDone waits for the termination of a subject
+func awaitDone(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "timeout waiting for the termination")
+	}
+}
+
+// TestRemoteSubject verifies the notifications flow both ways between a served subject and remote subjects
+func TestRemoteSubject(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := NewSubject()
+	stop := serve(t, listener, server, NewJSONCodec(nil))
+	defer stop()
+
+	_, serverObs := server.Subscribe()
+	serverObserve := serverObs.Observe()
+	client, err := DialSubject("tcp", listener.Addr().String(), NewJSONCodec(nil))
+	assert.NoError(t, err)
+	defer client.Close()
+	_, clientObs := client.Subscribe()
+	clientObserve := clientObs.Observe()
+	assert.NoError(t, server.AwaitSubscribers(context.Background(), 2))
+
+	server.Next(1)
+	client.Next("a")
+	client.NextItem(Error(errFoo))
+	assert.Equal(t, []interface{}{1, "a"}, values(receive(t, serverObserve, 2)))
+	assert.Equal(t, errFoo.Error(), (<-serverObserve).E.Error())
+	// the values are decoded into interface{} values
+	assert.Equal(t, []interface{}{1.0, "a"}, values(receive(t, clientObserve, 2)))
+	assert.Equal(t, errFoo.Error(), (<-clientObserve).E.Error())
+
+	server.Complete()
+	awaitDone(t, client.Done())
+	assert.NoError(t, client.Err())
+	_, open := <-clientObserve
+	assert.False(t, open)
+}
+
+// TestRemoteSubject_Error verifies the error terminating a served subject terminates the remote subjects
+func TestRemoteSubject_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	dir := t.TempDir()
+	listener, err := net.Listen("unix", filepath.Join(dir, "bus.sock"))
+	assert.NoError(t, err)
+	server := NewSubject()
+	stop := serve(t, listener, server, NewGobCodec(), WithRemoteTermination())
+	defer stop()
+
+	client, err := DialSubject("unix", listener.Addr().String(), NewGobCodec())
+	assert.NoError(t, err)
+	defer client.Close()
+	_, clientObs := client.Subscribe()
+	clientObserve := clientObs.Observe()
+	assert.NoError(t, server.AwaitSubscribers(context.Background(), 1))
+
+	client.Error(errors.New("remote failure"))
+	items := receive(t, clientObserve, 1)
+	assert.Equal(t, "remote failure", items[0].E.Error())
+	awaitDone(t, client.Done())
+	assert.EqualError(t, client.Err(), "remote failure")
+	assert.EqualError(t, server.Err(), "remote failure")
+}
+
+// TestRemoteSubject_TerminationIgnored verifies a remote subject does not terminate the served subject by
+// default
+func TestRemoteSubject_TerminationIgnored(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := NewSubject()
+	stop := serve(t, listener, server, NewJSONCodec(nil))
+	defer stop()
+
+	_, serverObs := server.Subscribe()
+	serverObserve := serverObs.Observe()
+	client, err := DialSubject("tcp", listener.Addr().String(), NewJSONCodec(nil))
+	assert.NoError(t, err)
+	defer client.Close()
+	assert.NoError(t, server.AwaitSubscribers(context.Background(), 2))
+
+	client.Error(errFoo)
+	client.Complete()
+	client.Next("a")
+	// the notifications of a connection are handled in order
+	assert.Equal(t, []interface{}{"a"}, values(receive(t, serverObserve, 1)))
+	select {
+	case <-server.Done():
+		assert.Fail(t, "terminated by a remote subject")
+	default:
+	}
+	server.Complete()
+}
+
+// TestRemoteSubject_Close verifies closing a remote subject completes its subscribers, while a lost
+// connection terminates them with an error
+func TestRemoteSubject_Close(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := NewSubject()
+	stop := serve(t, listener, server, NewJSONCodec(nil))
+
+	closed, err := DialSubject("tcp", listener.Addr().String(), NewJSONCodec(nil))
+	assert.NoError(t, err)
+	lost, err := DialSubject("tcp", listener.Addr().String(), NewJSONCodec(nil))
+	assert.NoError(t, err)
+	assert.NoError(t, server.AwaitSubscribers(context.Background(), 2))
+
+	assert.NoError(t, closed.Close())
+	assert.NoError(t, closed.Err())
+	stop()
+	awaitDone(t, lost.Done())
+	assert.Error(t, lost.Err())
+	assert.NoError(t, lost.Close())
+}
+
+// TestRemoteSubject_Codec verifies the values decoded with a typed codec
+func TestRemoteSubject_Codec(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := NewSubject()
+	codec := NewJSONCodec(func() interface{} {
+		return &remoteEvent{}
+	})
+	stop := serve(t, listener, server, codec)
+	defer stop()
+
+	client, err := DialSubject("tcp", listener.Addr().String(), codec)
+	assert.NoError(t, err)
+	defer client.Close()
+	_, clientObs := client.Subscribe()
+	clientObserve := clientObs.Observe()
+	assert.NoError(t, server.AwaitSubscribers(context.Background(), 1))
+
+	server.Next(remoteEvent{Name: "a"})
+	server.Next(make(chan int))
+	items := receive(t, clientObserve, 2)
+	assert.Equal(t, &remoteEvent{Name: "a"}, items[0].V)
+	assert.True(t, items[1].Error())
+}
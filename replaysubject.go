@@ -1,64 +1,166 @@
 package rxgo
 
-import (
-	"container/list"
-	"sync"
-)
-
 // ReplaySubject subject which replays the last received items to new subscribers
 type ReplaySubject struct {
 	Subject
-	buffer         *list.List
-	bufferLock     sync.Mutex
+	buffer         *ringBuffer
 	maxReplayItems int
 }
 
 // NewReplaySubject creates a new replay subject
 func NewReplaySubject(maxReplayItems int, opts ...Option) *ReplaySubject {
 	res := ReplaySubject{
-		Subject:        *NewSubject(opts...), // subscriber must be able to received current buffer and new items
+		Subject:        newSubject(opts...), // subscriber must be able to received current buffer and new items
 		maxReplayItems: maxReplayItems,
-		buffer:         list.New(),
-		bufferLock:     sync.Mutex{},
+		buffer:         newRingBuffer(maxReplayItems),
 	}
+	globalSubjects.add(&res.Subject, &res)
 
 	return &res
 }
 
 // Next shadows base next function to capture the item history
 func (s *ReplaySubject) Next(value interface{}) {
-	s.bufferLock.Lock()
-	defer s.bufferLock.Unlock()
-
-	// add to buffer
-	s.buffer.PushBack(value)
-	// check for max length
-	if s.buffer.Len() > s.maxReplayItems {
-		// remove oldest item at the front
-		s.buffer.Remove(s.buffer.Front())
+	s.NextItem(Of(value))
+}
+
+// NextItem shadows base next item function to capture the item history, inline errors included.
+func (s *ReplaySubject) NextItem(item Item) {
+	s.publishReplayed(s.intercept([]Item{item}))
+}
+
+// NextBatch shadows base next batch function to capture the item history.
+func (s *ReplaySubject) NextBatch(values []interface{}) {
+	batch := s.newBatch(values)
+	defer s.releaseBatch(batch)
+
+	s.publishReplayed(batch.items)
+}
+
+// publishReplayed buffers items, then publishes them to the subscribers at the time they were buffered, so
+// that a new subscriber receives them either from the replay or from the publication. The subject lock is not
+// held while publishing, so that a subscriber blocking the publication can be unsubscribed.
+func (s *ReplaySubject) publishReplayed(items []Item) {
+	s.buffer.mu.Lock()
+	for _, item := range items {
+		s.buffer.push(item)
 	}
+	list := s.loadSubscribers()
+	s.buffer.mu.Unlock()
 
-	s.Subject.Next(value)
+	s.nextItemsTo(list, items...)
 }
 
 // Subscribe shadows base subscribe function to replay the item history
 func (s *ReplaySubject) Subscribe() (Subscription, Observable) {
+	return s.SubscribeWhere(nil)
+}
+
+// SubscribeWhere shadows base subscribe where function to replay the items of the history satisfying the
+// predicate.
+func (s *ReplaySubject) SubscribeWhere(predicate func(interface{}) bool) (Subscription, Observable) {
+	return s.subscribeReplaying(s.maxReplayItems, predicate)
+}
+
+// SubscribeWithReplay adds a subscriber replaying only the last n items of the history, so that subscribers of
+// the same subject request different depths: a dashboard only the last value, an auditor the full history.
+// A depth above the capacity of the subject replays the whole history, and zero replays nothing.
+func (s *ReplaySubject) SubscribeWithReplay(n int) (Subscription, Observable) {
+	return s.subscribeReplaying(n, nil)
+}
+
+// subscribeReplaying adds a subscriber replaying the last n items of the history satisfying the predicate, if
+// any
+func (s *ReplaySubject) subscribeReplaying(n int, predicate func(interface{}) bool) (Subscription, Observable) {
 	s.Lock()
 	defer s.Unlock()
+	s.buffer.mu.Lock()
+	defer s.buffer.mu.Unlock()
 
+	if held := s.buffer.len(); n > held {
+		n = held
+	}
+	if n < 0 {
+		n = 0
+	}
 	// create buffered channel to hold all current replay items
-	sub, obs := s.createSubscription(s.buffer.Len())
-	subChan := s.subscribers[sub.GetId()]
+	sub, obs := s.createFilteredSubscription(n, predicate)
+	subscriber := s.subscribers[sub.GetId()]
 
-	s.bufferLock.Lock()
-	defer s.bufferLock.Unlock()
+	// replay buffered items, no item can be pushed while the buffer lock is held
+	s.buffer.last(n, func(item Item) {
+		s.send(subscriber, item)
+	})
 
-	// replay buffered items
-	elem := s.buffer.Front()
-	for elem != nil {
-		subChan <- Of(elem.Value)
-		elem = elem.Next()
+	return sub, obs
+}
+
+// Snapshot returns the values held for replay, from the oldest to the newest. Inline errors are returned as
+// error values. Together with RestoreFrom, it allows to persist the replay history across restarts.
+func (s *ReplaySubject) Snapshot() []interface{} {
+	return s.PeekAll()
+}
+
+// Len returns the number of items held for replay.
+func (s *ReplaySubject) Len() int {
+	return s.buffer.len()
+}
+
+// Cap returns the maximum number of items held for replay.
+func (s *ReplaySubject) Cap() int {
+	return len(s.buffer.items)
+}
+
+// PeekAll returns a copy of the values held for replay, from the oldest to the newest, without subscribing, so
+// that health checks and debug endpoints report the retained history. Inline errors are returned as error
+// values.
+func (s *ReplaySubject) PeekAll() []interface{} {
+	s.buffer.mu.Lock()
+	defer s.buffer.mu.Unlock()
+
+	values := make([]interface{}, 0, s.buffer.len())
+	s.buffer.each(func(item Item) {
+		if item.Error() {
+			values = append(values, item.E)
+		} else {
+			values = append(values, item.V)
+		}
+	})
+	return values
+}
+
+// RestoreFrom replaces the values held for replay, typically with a Snapshot taken before a restart. Error
+// values are restored as inline errors. Only the last maxReplayItems values are kept, and none is published
+// to the current subscribers.
+func (s *ReplaySubject) RestoreFrom(values []interface{}) {
+	s.buffer.mu.Lock()
+	defer s.buffer.mu.Unlock()
+
+	s.buffer.reset()
+	for _, value := range values {
+		if err, ok := value.(error); ok {
+			s.buffer.push(Error(err))
+		} else {
+			s.buffer.push(Of(value))
+		}
 	}
+}
 
-	return sub, obs
+// Producer registers a producer of the subject, like Subject.Producer, its items being published to the
+// ReplaySubject.
+func (s *ReplaySubject) Producer() *ProducerHandle {
+	return s.Subject.producer(s)
+}
+
+// SubscribeTo forwards the notifications of the sources to the subject, like Subject.SubscribeTo, the values
+// being published to the ReplaySubject.
+func (s *ReplaySubject) SubscribeTo(sources ...Observable) Disposable {
+	return s.Subject.subscribeTo(s, sources)
+}
+
+func (s *ReplaySubject) info() SubjectInfo {
+	info := s.Subject.info()
+	info.Type = "ReplaySubject"
+	info.Buffered = s.buffer.len()
+	return info
 }
@@ -0,0 +1,133 @@
+package rxgo
+
+import (
+	"container/list"
+	"sync"
+	"testing"
+	"time"
+)
+
+const benchReplayItems = 1000
+
+// listReplayBuffer is the former ReplaySubject buffer, kept as a baseline
+type listReplayBuffer struct {
+	mu    sync.Mutex
+	items *list.List
+	max   int
+}
+
+func (l *listReplayBuffer) push(item Item) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.items.PushBack(item)
+	if l.items.Len() > l.max {
+		l.items.Remove(l.items.Front())
+	}
+}
+
+func reportItemsPerSecond(b *testing.B, start time.Time) {
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "items/s")
+}
+
+func Benchmark_ReplayBuffer_List(b *testing.B) {
+	buffer := &listReplayBuffer{items: list.New(), max: benchReplayItems}
+	item := Of(1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		buffer.push(item)
+	}
+	reportItemsPerSecond(b, start)
+}
+
+func Benchmark_ReplayBuffer_Ring(b *testing.B) {
+	buffer := newRingBuffer(benchReplayItems)
+	item := Of(1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		buffer.push(item)
+	}
+	reportItemsPerSecond(b, start)
+}
+
+func Benchmark_ReplayBuffer_List_Parallel(b *testing.B) {
+	buffer := &listReplayBuffer{items: list.New(), max: benchReplayItems}
+	item := Of(1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buffer.push(item)
+		}
+	})
+	reportItemsPerSecond(b, start)
+}
+
+func Benchmark_ReplayBuffer_Ring_Parallel(b *testing.B) {
+	buffer := newRingBuffer(benchReplayItems)
+	item := Of(1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buffer.push(item)
+		}
+	})
+	reportItemsPerSecond(b, start)
+}
+
+// Benchmark_ReplaySubject_Next publishes to a subscriber consuming the items
+func Benchmark_ReplaySubject_Next(b *testing.B) {
+	subject := NewReplaySubject(benchReplayItems)
+	sub, obs := subject.Subscribe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range obs.Observe() {
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		subject.Next(i)
+	}
+	reportItemsPerSecond(b, start)
+	b.StopTimer()
+
+	subject.Unsubscribe(sub.GetId())
+	<-done
+}
+
+// Benchmark_ReplaySubject_Next_Parallel publishes from several goroutines to a subscriber consuming the items
+func Benchmark_ReplaySubject_Next_Parallel(b *testing.B) {
+	subject := NewReplaySubject(benchReplayItems)
+	sub, obs := subject.Subscribe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range obs.Observe() {
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			subject.Next(1)
+		}
+	})
+	reportItemsPerSecond(b, start)
+	b.StopTimer()
+
+	subject.Unsubscribe(sub.GetId())
+	<-done
+}
@@ -1,16 +1,20 @@
 package rxgo
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
 )
 
 // TestReplaySubject verifies that a new subscriber receives the entire history
 func TestReplaySubject(t *testing.T) {
 	subject := NewReplaySubject(10)
+	defer subject.Complete()
 
 	// load buffer
 	for i := 0; i < 3; i++ {
@@ -38,6 +42,7 @@ func TestReplaySubject(t *testing.T) {
 // TestMaxItemsReplay verifies only the last n elements are kept in replay buffer
 func TestMaxItemsReplay(t *testing.T) {
 	subject := NewReplaySubject(2)
+	defer subject.Complete()
 
 	// load buffer, expect to keep 2,3 in buffer
 	for i := 0; i < 4; i++ {
@@ -61,3 +66,195 @@ func TestMaxItemsReplay(t *testing.T) {
 	assert.Equal(t, []int{2, 3, 4, 5}, values)
 	fmt.Printf("values: %v", values)
 }
+
+// TestReplayNextItem verifies inline errors are replayed to new subscribers
+func TestReplayNextItem(t *testing.T) {
+	subject := NewReplaySubject(10)
+
+	subject.Next(1)
+	subject.NextItem(Error(errFoo))
+
+	_, obs := subject.Subscribe()
+	subject.Complete()
+
+	items := make([]Item, 0)
+	<-obs.ForEach(func(i interface{}) {
+		items = append(items, Of(i))
+	}, func(e error) {
+		items = append(items, Error(e))
+	}, func() {})
+
+	assert.Equal(t, []Item{Of(1), Error(errFoo)}, items)
+}
+
+// TestReplayNextBatch verifies batches are replayed to new subscribers
+func TestReplayNextBatch(t *testing.T) {
+	subject := NewReplaySubject(3)
+
+	subject.NextBatch([]interface{}{1, 2, 3, 4})
+
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(2, 3, 4)
+}
+
+// TestReplaySnapshot verifies a restored history is replayed to new subscribers
+func TestReplaySnapshot(t *testing.T) {
+	subject := NewReplaySubject(3)
+	subject.Next(1)
+	subject.NextItem(Error(errFoo))
+	subject.Next(2)
+	subject.Next(3)
+
+	snapshot := subject.Snapshot()
+	assert.Equal(t, []interface{}{errFoo, 2, 3}, snapshot)
+	subject.Complete()
+
+	restored := NewReplaySubject(2)
+	restored.Next(0)
+	restored.RestoreFrom(snapshot)
+	assert.Equal(t, []interface{}{2, 3}, restored.Snapshot())
+
+	_, obs := restored.Subscribe()
+	observer := NewTestObserver(t, obs)
+	restored.Next(4)
+	restored.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(2, 3, 4)
+	observer.AssertNoErrors()
+}
+
+// TestReplaySubscribeWhere verifies only the history satisfying the predicate is replayed
+func TestReplaySubscribeWhere(t *testing.T) {
+	subject := NewReplaySubject(10)
+	for i := 0; i < 4; i++ {
+		subject.Next(i)
+	}
+
+	_, obs := subject.SubscribeWhere(func(i interface{}) bool {
+		return i.(int) >= 2
+	})
+	observer := NewTestObserver(t, obs)
+	subject.Next(1)
+	subject.Next(4)
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(2, 3, 4)
+}
+
+func TestReplaySubscribeWithReplay(t *testing.T) {
+	subject := NewReplaySubject(5)
+	for i := 0; i < 7; i++ {
+		subject.Next(i)
+	}
+
+	_, obs := subject.SubscribeWithReplay(1)
+	dashboard := NewTestObserver(t, obs)
+	_, obs = subject.SubscribeWithReplay(10)
+	auditor := NewTestObserver(t, obs)
+	_, obs = subject.SubscribeWithReplay(0)
+	live := NewTestObserver(t, obs)
+	subject.Next(7)
+	subject.Complete()
+
+	dashboard.AwaitDone(time.Second)
+	dashboard.AssertValues(6, 7)
+	auditor.AwaitDone(time.Second)
+	auditor.AssertValues(2, 3, 4, 5, 6, 7)
+	live.AwaitDone(time.Second)
+	live.AssertValues(7)
+}
+
+func TestReplayInspection(t *testing.T) {
+	subject := NewReplaySubject(3)
+	defer subject.Complete()
+	assert.Equal(t, 0, subject.Len())
+	assert.Equal(t, 3, subject.Cap())
+	assert.Empty(t, subject.PeekAll())
+
+	subject.Next(1)
+	subject.Next(2)
+	assert.Equal(t, 2, subject.Len())
+	assert.Equal(t, []interface{}{1, 2}, subject.PeekAll())
+
+	subject.Next(3)
+	subject.Next(4)
+	assert.Equal(t, 3, subject.Len())
+	assert.Equal(t, 3, subject.Cap())
+	values := subject.PeekAll()
+	assert.Equal(t, []interface{}{2, 3, 4}, values)
+
+	// a copy
+	values[0] = 0
+	assert.Equal(t, []interface{}{2, 3, 4}, subject.PeekAll())
+}
+
+// TestReplayConcurrentNext verifies concurrent publishers wrapping onto the same slot of the history
+func TestReplayConcurrentNext(t *testing.T) {
+	subject := NewReplaySubject(1)
+
+	var wg sync.WaitGroup
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				subject.Next(i)
+			}
+		}()
+	}
+	wg.Wait()
+	subject.Complete()
+
+	assert.Equal(t, 1, subject.Len())
+	assert.Equal(t, []interface{}{99}, subject.PeekAll())
+}
+
+// TestReplayUnsubscribeBlocked verifies a subscriber blocking a publisher can be unsubscribed
+func TestReplayUnsubscribeBlocked(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewReplaySubject(10)
+	unsubscribeBlocked(t, subject)
+	assert.Equal(t, []interface{}{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, subject.PeekAll())
+}
+
+// unsubscribeBlocked unsubscribes a subscriber whose observer does not consume while a publisher is blocked on
+// it, then completes the subject
+func unsubscribeBlocked(t *testing.T, subject ISubject) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sub, obs := subject.Subscribe()
+	_ = obs.Observe(WithContext(ctx))
+
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 0; i < 10; i++ {
+			subject.Next(i)
+		}
+	}()
+	select {
+	case <-published:
+		assert.Fail(t, "publisher not blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unsubscribed := make(chan struct{})
+	go func() {
+		defer close(unsubscribed)
+		sub.Unsubscribe()
+	}()
+	select {
+	case <-unsubscribed:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "Unsubscribe blocked")
+	}
+	<-published
+	subject.Complete()
+}
@@ -0,0 +1,49 @@
+package rxgo
+
+// resize replaces the buffer of the subscriber with a buffer of the given size, unless its items are queued
+// by the Adaptive or the Conflate strategy. The items already buffered are delivered first. With the Drop strategy, the items
+// are then dropped once the buffer is full, instead of once the buffers of the observers are full.
+func (sub *subscriberState) resize(buffer int) {
+	if buffer < 0 {
+		buffer = 0
+	}
+	sub.Lock()
+	defer sub.Unlock()
+
+	if sub.closed || sub.ch == nil || sub.queue != nil {
+		return
+	}
+	sub.ch = make(chan Item, buffer)
+	strategy := sub.subject.option.getBackPressureStrategy()
+	if strategy == Drop {
+		// the delivery goroutine waits for the observers, the buffer absorbing the bursts
+		sub.dropWhenFull = true
+		strategy = Block
+	}
+	sub.source.replace(sub.ch, strategy)
+}
+
+// resize resizes the buffer of a subscriber
+func (s *Subject) resize(id, buffer int) {
+	s.RLock()
+	subscriber, exists := s.subscribers[id]
+	s.RUnlock()
+
+	if exists {
+		subscriber.resize(buffer)
+	}
+}
+
+// ResizeSubscriptions resizes the buffers of the current subscriptions, and sets the buffer of the next ones,
+// as Subscription.Resize. The buffer of a subscription replaying items is not shrunk below the number of
+// replayed items.
+func (s *Subject) ResizeSubscriptions(buffer int) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.buffer = buffer
+	s.resized = true
+	for _, subscriber := range s.subscribers {
+		subscriber.resize(buffer)
+	}
+}
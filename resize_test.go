@@ -0,0 +1,86 @@
+package rxgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// awaitTaken waits until the delivery goroutine of a subscriber took the items of its buffer
+func awaitTaken(t *testing.T, subject *Subject, sub Subscription) {
+	t.Helper()
+	subject.RLock()
+	subscriber := subject.subscribers[sub.GetId()]
+	subject.RUnlock()
+	assert.Eventually(t, func() bool {
+		subscriber.RLock()
+		defer subscriber.RUnlock()
+		return len(subscriber.ch) == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestSubscription_Resize_Drop(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject(WithBackPressureStrategy(Drop))
+	sub, obs := subject.Subscribe()
+	observe := obs.Observe(WithBufferedChannel(1))
+	sub.(ResizableSubscription).Resize(3)
+
+	subject.Next(1)
+	subject.Next(2)
+	// 1 is buffered by the observer, 2 is waiting for the observer
+	awaitTaken(t, subject, sub)
+	for i := 3; i <= 6; i++ {
+		subject.Next(i)
+	}
+	subject.Complete()
+
+	assert.Equal(t, []interface{}{1, 2, 3, 4, 5}, values(receive(t, observe, 5)))
+	assert.Equal(t, uint64(1), subject.Stats().Dropped)
+}
+
+func TestSubscription_Resize_Block(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	sub, obs := subject.Subscribe()
+	observe := obs.Observe()
+	sub.(ResizableSubscription).Resize(2)
+
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 1; i <= 3; i++ {
+			subject.Next(i)
+		}
+	}()
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		assert.Fail(t, "publisher blocked")
+	}
+	// the buffered items are delivered before the items of the new buffer
+	sub.(ResizableSubscription).Resize(5)
+	for i := 4; i <= 8; i++ {
+		subject.Next(i)
+	}
+	subject.Complete()
+
+	assert.Equal(t, []interface{}{1, 2, 3, 4, 5, 6, 7, 8}, values(receive(t, observe, 8)))
+}
+
+func TestSubject_ResizeSubscriptions(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	before, _ := subject.Subscribe()
+	subject.ResizeSubscriptions(4)
+	after, _ := subject.Subscribe()
+
+	subject.RLock()
+	for _, sub := range []Subscription{before, after} {
+		assert.Equal(t, 4, subject.subscribers[sub.GetId()].capacity())
+	}
+	subject.RUnlock()
+	subject.Complete()
+}
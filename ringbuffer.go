@@ -0,0 +1,70 @@
+package rxgo
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ringBuffer is a preallocated buffer holding the last items pushed. Its lock is held by the callers to push,
+// reset or read the items, as concurrent writers may wrap onto the same slot, while the tail index is atomic
+// so that its length can be read at any time.
+type ringBuffer struct {
+	mu    sync.Mutex
+	tail  uint64
+	items []Item
+}
+
+func newRingBuffer(capacity int) *ringBuffer {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &ringBuffer{
+		items: make([]Item, capacity),
+	}
+}
+
+// push adds an item, overwriting the oldest one once the buffer is full
+func (r *ringBuffer) push(item Item) {
+	if len(r.items) == 0 {
+		return
+	}
+	i := atomic.LoadUint64(&r.tail)
+	r.items[i%uint64(len(r.items))] = item
+	atomic.StoreUint64(&r.tail, i+1)
+}
+
+// reset removes all items
+func (r *ringBuffer) reset() {
+	for i := range r.items {
+		r.items[i] = Item{}
+	}
+	atomic.StoreUint64(&r.tail, 0)
+}
+
+// len returns the number of items held
+func (r *ringBuffer) len() int {
+	tail := atomic.LoadUint64(&r.tail)
+	if tail > uint64(len(r.items)) {
+		return len(r.items)
+	}
+	return int(tail)
+}
+
+// each calls f on the items held, from the oldest to the newest
+func (r *ringBuffer) each(f func(Item)) {
+	r.last(r.len(), f)
+}
+
+// last calls f on the n newest items held at most, from the oldest to the newest
+func (r *ringBuffer) last(n int, f func(Item)) {
+	if held := r.len(); n > held {
+		n = held
+	}
+	if n <= 0 {
+		return
+	}
+	tail := atomic.LoadUint64(&r.tail)
+	for i := tail - uint64(n); i < tail; i++ {
+		f(r.items[i%uint64(len(r.items))])
+	}
+}
@@ -0,0 +1,102 @@
+package rxgo
+
+import (
+	"context"
+	"sync"
+)
+
+// Router demultiplexes a stream into Subjects: each value is published to the Subject of the first route
+// whose predicate it satisfies, or to the default route if none matches.
+type Router struct {
+	mu       sync.RWMutex
+	routes   []route
+	fallback ISubject
+	option   Option
+}
+
+type route struct {
+	predicate Predicate
+	dst       ISubject
+}
+
+// NewRouter creates a Router without route. The WithErrorIsolation option makes it publish the errors inline.
+func NewRouter(opts ...Option) *Router {
+	return &Router{option: parseOptions(opts...)}
+}
+
+// Route adds a route publishing the values satisfying predicate to dst. The routes are evaluated in the order
+// they were added.
+func (r *Router) Route(predicate Predicate, dst ISubject) *Router {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes = append(r.routes, route{predicate: predicate, dst: dst})
+	return r
+}
+
+// Default sets the Subject receiving the values matching no route, and the errors. Without default route,
+// they are dropped.
+func (r *Router) Default(dst ISubject) *Router {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fallback = dst
+	return r
+}
+
+// Next routes a value.
+func (r *Router) Next(value interface{}) {
+	r.NextItem(Of(value))
+}
+
+// NextItem routes an item, which keeps its acknowledgement. An error is published to the default route, with
+// Error or inline with WithErrorIsolation. An item dropped for lack of default route is acknowledged.
+func (r *Router) NextItem(item Item) {
+	dst := r.destination(item)
+	switch {
+	case dst == nil:
+		r.option.getLogger().Debug("rxgo: item without route", "item", item.V, "error", item.E)
+		item.Ack()
+	case item.Error() && !r.option.isErrorIsolation():
+		dst.Error(item.E)
+	default:
+		dst.NextItem(item)
+	}
+}
+
+// destination returns the Subject an item is routed to, nil if none
+func (r *Router) destination(item Item) ISubject {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !item.Error() {
+		for _, route := range r.routes {
+			if route.predicate(item.V) {
+				return route.dst
+			}
+		}
+	}
+	return r.fallback
+}
+
+// From routes the notifications of src until it terminates. The completion of src does not complete the
+// Subjects. The returned Disposable stops the routing, and the WithContext option sets its context.
+func (r *Router) From(src Observable, opts ...Option) Disposable {
+	ctx, cancel := context.WithCancel(parseOptions(opts...).buildContext(emptyContext))
+	observe := src.Observe(WithContext(ctx))
+	go func() {
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-observe:
+				if !ok {
+					return
+				}
+				r.NextItem(item)
+			}
+		}
+	}()
+	return Disposable(cancel)
+}
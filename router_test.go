@@ -0,0 +1,61 @@
+package rxgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestRouter(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	small, large, other := NewSubject(), NewSubject(), NewSubject()
+	observe := func(s *Subject) *TestObserver {
+		_, obs := s.Subscribe()
+		return NewTestObserver(t, obs, WithBufferedChannel(10))
+	}
+	smalls, larges, others := observe(small), observe(large), observe(other)
+
+	router := NewRouter(WithErrorIsolation()).
+		Route(func(i interface{}) bool { return i.(int) < 10 }, small).
+		Route(func(i interface{}) bool { return i.(int) < 100 }, large).
+		Default(other)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	router.From(testObservable(ctx, 1, 50, 500, errFoo, 5), WithContext(ctx))
+
+	smalls.AwaitCount(2, time.Second)
+	small.Complete()
+	large.Complete()
+	other.Complete()
+	for _, observer := range []*TestObserver{smalls, larges, others} {
+		observer.AwaitDone(time.Second)
+	}
+	assert.Equal(t, []interface{}{1, 5}, smalls.Values())
+	assert.Equal(t, []interface{}{50}, larges.Values())
+	assert.Equal(t, []interface{}{500}, others.Values())
+	assert.Equal(t, []error{errFoo}, others.Errors())
+}
+
+func TestRouter_NoDefault(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	even := NewSubject()
+	_, obs := even.Subscribe()
+	observer := NewTestObserver(t, obs, WithBufferedChannel(10))
+	router := NewRouter().Route(func(i interface{}) bool { return i.(int)%2 == 0 }, even)
+
+	acked := false
+	router.NextItem(Of(1).WithAck(func() {
+		acked = true
+	}))
+	router.Next(2)
+	router.NextItem(Error(errFoo))
+	even.Complete()
+
+	observer.AwaitDone(time.Second)
+	assert.True(t, acked)
+	assert.Equal(t, []interface{}{2}, observer.Values())
+	assert.Empty(t, observer.Errors())
+}
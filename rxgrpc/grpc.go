@@ -0,0 +1,95 @@
+//go:build go1.18
+// +build go1.18
+
+// Package rxgrpc bridges Observables and gRPC streams.
+//
+// The package does not depend on google.golang.org/grpc: the adapters rely on the Recv and Send methods of
+// the generated stream types, so they work with any gRPC version. They require Go 1.18 or later.
+package rxgrpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/reactivex/rxgo/v2"
+)
+
+// Sender is the sending side of a gRPC stream, implemented by the generated server-streaming and
+// bidirectional stream types.
+type Sender[T any] interface {
+	Send(T) error
+	Context() context.Context
+}
+
+// closeSender is implemented by the client side of gRPC streams.
+type closeSender interface {
+	CloseSend() error
+}
+
+// FromGRPCStream creates an Observable emitting the messages received by recv, typically the Recv method of
+// a gRPC stream, with ctx being the context of the stream.
+//
+// The Observable completes once recv returns io.EOF, the end of the stream, and emits any other error
+// returned by recv, such as a gRPC status error. Once ctx is done, the Observable completes without error:
+// cancelling the stream context unsubscribes.
+func FromGRPCStream[T any](ctx context.Context, recv func() (T, error), opts ...rxgo.Option) rxgo.Observable {
+	return rxgo.CreateWithEmitter(func(ctx context.Context, emitter rxgo.Emitter) {
+		for {
+			msg, err := recv()
+			if err != nil {
+				if ctx.Err() != nil {
+					// the stream was cancelled along with its context
+					return
+				}
+				if err == io.EOF {
+					emitter.Complete()
+				} else {
+					emitter.Error(err)
+				}
+				return
+			}
+			emitter.Next(msg)
+			if emitter.IsDisposed() {
+				return
+			}
+		}
+	}, append(opts, rxgo.WithContext(ctx))...)
+}
+
+// SendToGRPC sends the items of an Observable to a gRPC stream, until the Observable completes, the stream
+// context is done or a send fails. The items must be of the message type of the stream.
+//
+// The Observable is observed with the stream context, so that it is unsubscribed once the client goes away or
+// the call is cancelled. An error emitted by the Observable is returned: a server handler returning it ends
+// the call with this error, which should be built with the status package to carry a gRPC code. Once the
+// Observable completed, the sending side of a client stream is closed.
+func SendToGRPC[T any](stream Sender[T], observable rxgo.Observable) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	observe := observable.Observe(rxgo.WithContext(ctx))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-observe:
+			if !ok {
+				if closer, ok := stream.(closeSender); ok {
+					return closer.CloseSend()
+				}
+				return nil
+			}
+			if item.Error() {
+				return item.E
+			}
+			msg, ok := item.V.(T)
+			if !ok {
+				return fmt.Errorf("%T is not a %T message", item.V, msg)
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
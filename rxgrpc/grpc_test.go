@@ -0,0 +1,134 @@
+//go:build go1.18
+// +build go1.18
+
+package rxgrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+var errStream = errors.New("stream")
+
+type message struct {
+	ID int
+}
+
+// fakeStream is an in-memory stream receiving the queued messages, then failing with err
+type fakeStream struct {
+	ctx      context.Context
+	received chan *message
+	err      error
+	sent     []*message
+	closed   bool
+}
+
+func newFakeStream(ctx context.Context, err error, messages ...*message) *fakeStream {
+	s := &fakeStream{
+		ctx:      ctx,
+		received: make(chan *message, len(messages)),
+		err:      err,
+	}
+	for _, msg := range messages {
+		s.received <- msg
+	}
+	if err != nil {
+		close(s.received)
+	}
+	return s
+}
+
+func (s *fakeStream) Recv() (*message, error) {
+	select {
+	case msg, ok := <-s.received:
+		if !ok {
+			return nil, s.err
+		}
+		return msg, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func (s *fakeStream) Send(msg *message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+type fakeClientStream struct {
+	*fakeStream
+}
+
+func (s fakeClientStream) CloseSend() error {
+	s.closed = true
+	return nil
+}
+
+func TestFromGRPCStream(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	stream := newFakeStream(context.Background(), io.EOF, &message{ID: 1}, &message{ID: 2})
+
+	rxgo.Assert(context.Background(), t, FromGRPCStream(stream.Context(), stream.Recv),
+		rxgo.HasItems(&message{ID: 1}, &message{ID: 2}), rxgo.HasNoError())
+}
+
+func TestFromGRPCStream_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	stream := newFakeStream(context.Background(), errStream, &message{ID: 1})
+
+	rxgo.Assert(context.Background(), t, FromGRPCStream(stream.Context(), stream.Recv),
+		rxgo.HasItems(&message{ID: 1}), rxgo.HasError(errStream))
+}
+
+func TestFromGRPCStream_Cancel(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := newFakeStream(ctx, nil, &message{ID: 1})
+
+	observer := rxgo.NewTestObserver(t, FromGRPCStream(stream.Context(), stream.Recv))
+	observer.AwaitCount(1, time.Second)
+	cancel()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(&message{ID: 1})
+	observer.AssertNoErrors()
+}
+
+func TestSendToGRPC(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	stream := newFakeStream(context.Background(), nil)
+
+	assert.NoError(t, SendToGRPC[*message](stream, rxgo.Just(&message{ID: 1}, &message{ID: 2})()))
+	assert.Equal(t, []*message{{ID: 1}, {ID: 2}}, stream.sent)
+	assert.False(t, stream.closed)
+
+	assert.Equal(t, errStream, SendToGRPC[*message](stream, rxgo.Thrown(errStream)))
+	assert.Error(t, SendToGRPC[*message](stream, rxgo.Just("a")()))
+}
+
+func TestSendToGRPC_CloseSend(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	stream := fakeClientStream{newFakeStream(context.Background(), nil)}
+
+	assert.NoError(t, SendToGRPC[*message](stream, rxgo.Just(&message{ID: 1})()))
+	assert.True(t, stream.closed)
+}
+
+func TestSendToGRPC_Cancel(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := newFakeStream(ctx, nil)
+	cancel()
+
+	assert.Equal(t, context.Canceled, SendToGRPC[*message](stream, rxgo.Never()))
+}
@@ -0,0 +1,87 @@
+package rxhttp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/reactivex/rxgo/v2"
+)
+
+// WithChunkSize makes FromHTTP stream the response body as []byte chunks of at most size bytes, instead of
+// emitting the response.
+func WithChunkSize(size int) Option {
+	return func(c *config) {
+		c.chunkSize = size
+	}
+}
+
+// FromHTTP creates an Observable sending the request created by newRequest with client for each
+// observation, as it is built on rxgo.Defer: Retry, BackOffRetry or Repeat send a new request, and it
+// composes with Timeout or CircuitBreaker. The Observable emits the *http.Response, its body being read and
+// closed, then completes. A response with a non-2xx status code is a StatusError. With WithChunkSize, the body
+// is streamed as chunks instead. A nil client is replaced by the client of WithClient.
+//
+// The request is sent with the context of the observation, canceled once the context is done.
+func FromHTTP(ctx context.Context, client *http.Client, newRequest func(ctx context.Context) (*http.Request, error), opts ...Option) rxgo.Observable {
+	c := newConfig(opts)
+	if client == nil {
+		client = c.client
+	}
+
+	return rxgo.Defer([]rxgo.Producer{func(ctx context.Context, next chan<- rxgo.Item) {
+		if err := c.send(ctx, client, newRequest, next); err != nil {
+			rxgo.Error(err).SendContext(ctx, next)
+		}
+	}}, rxgo.WithContext(ctx))
+}
+
+// send sends a request and emits its response, or the chunks of its body
+func (c config) send(ctx context.Context, client *http.Client, newRequest func(ctx context.Context) (*http.Request, error), next chan<- rxgo.Item) error {
+	req, err := newRequest(ctx)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return StatusError{StatusCode: resp.StatusCode}
+	}
+
+	if c.chunkSize > 0 {
+		buf := make([]byte, c.chunkSize)
+		for {
+			n, err := resp.Body.Read(buf)
+			if n > 0 {
+				chunk := make([]byte, n)
+				copy(chunk, buf[:n])
+				if !rxgo.Of(chunk).SendContext(ctx, next) {
+					return nil
+				}
+			}
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	rxgo.Of(resp).SendContext(ctx, next)
+	return nil
+}
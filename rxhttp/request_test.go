@@ -0,0 +1,82 @@
+package rxhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func getRequest(url string) func(ctx context.Context) (*http.Request, error) {
+	return func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	}
+}
+
+func TestFromHTTP_Retry(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, r.Header.Get("X-Token"))
+	}))
+	defer server.Close()
+	defer server.CloseClientConnections()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := FromHTTP(ctx, server.Client(), getRequest(server.URL), WithHeader("X-Token", "secret")).
+		Retry(2, func(err error) bool {
+			return err == StatusError{StatusCode: http.StatusServiceUnavailable}
+		}).
+		Map(func(_ context.Context, i interface{}) (interface{}, error) {
+			body, err := io.ReadAll(i.(*http.Response).Body)
+			return string(body), err
+		})
+	observer := rxgo.NewTestObserver(t, obs)
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("secret")
+	observer.AssertNoErrors()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestFromHTTP_StatusError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	defer server.CloseClientConnections()
+
+	observer := rxgo.NewTestObserver(t, FromHTTP(context.Background(), server.Client(), getRequest(server.URL)))
+	observer.AwaitDone(time.Second)
+	observer.AssertError(StatusError{StatusCode: http.StatusNotFound})
+}
+
+func TestFromHTTP_Chunks(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "0123456789")
+	}))
+	defer server.Close()
+	defer server.CloseClientConnections()
+
+	observer := rxgo.NewTestObserver(t, FromHTTP(context.Background(), server.Client(), getRequest(server.URL),
+		WithChunkSize(4)))
+	observer.AwaitDone(time.Second)
+	observer.AssertNoErrors()
+	body := ""
+	for _, chunk := range observer.Values() {
+		assert.True(t, len(chunk.([]byte)) <= 4)
+		body += string(chunk.([]byte))
+	}
+	assert.Equal(t, "0123456789", body)
+}
@@ -0,0 +1,353 @@
+// Package rxhttp bridges Observables and HTTP streams.
+//
+// FromSSE consumes a Server-Sent Events stream as an Observable, and ServeSSE streams an Observable, such
+// as the subscription of a Subject, to a browser. FromWebSocket and ToWebSocket do the same over a
+// WebSocket connection.
+package rxhttp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/reactivex/rxgo/v2"
+)
+
+// ContentType is the content type of a Server-Sent Events stream.
+const ContentType = "text/event-stream"
+
+// Event is a server-sent event.
+type Event struct {
+	// ID is the event identifier, sent back with the Last-Event-ID header when reconnecting.
+	ID string
+	// Event is the event type, empty for a message.
+	Event string
+	// Data is the event data, its lines being separated by newlines.
+	Data string
+	// Retry is the reconnection time requested by the server, zero if unset.
+	Retry time.Duration
+}
+
+// StatusError is the error of a response with an unexpected status code.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+}
+
+// Option configures the sources and the sinks.
+type Option func(*config)
+
+type config struct {
+	client    *http.Client
+	header    http.Header
+	backOff   func() backoff.BackOff
+	heartbeat time.Duration
+	encode    func(interface{}) ([]byte, error)
+	clock     rxgo.Clock
+	chunkSize int
+
+	dial          func(ctx context.Context) (WebSocketConn, error)
+	normalClosure func(err error) bool
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		client: http.DefaultClient,
+		header: make(http.Header),
+		backOff: func() backoff.BackOff {
+			return backoff.NewExponentialBackOff()
+		},
+		encode: json.Marshal,
+		clock:  rxgo.SystemClock(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithClient sets the HTTP client of FromSSE, and of FromHTTP if its client is nil, http.DefaultClient by
+// default.
+func WithClient(client *http.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WithHeader adds a request header to FromSSE and FromHTTP.
+func WithHeader(key, value string) Option {
+	return func(c *config) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithBackOff sets the policy of FromSSE and FromWebSocket to wait before reconnecting. By default, the delays
+// grow exponentially and the source gives up after 15 minutes without connection.
+func WithBackOff(factory func() backoff.BackOff) Option {
+	return func(c *config) {
+		c.backOff = factory
+	}
+}
+
+// WithHeartbeat makes ServeSSE send a comment every interval without event, so that proxies do not close an
+// idle stream, and ToWebSocket send a ping every interval.
+func WithHeartbeat(interval time.Duration) Option {
+	return func(c *config) {
+		c.heartbeat = interval
+	}
+}
+
+// WithEncoder sets the encoding of the values sent by ServeSSE and ToWebSocket, json.Marshal by default.
+func WithEncoder(encode func(interface{}) ([]byte, error)) Option {
+	return func(c *config) {
+		c.encode = encode
+	}
+}
+
+// WithClock sets the clock timing the reconnections and the heartbeats, the system clock by default. It is
+// meant to pass an rxgo.TestScheduler in tests.
+func WithClock(clock rxgo.Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// FromSSE creates an Observable emitting the events of a Server-Sent Events stream. Once the stream ends or
+// fails, it reconnects after the back-off delay with the identifier of the last event, and emits the error
+// once the back-off gives up. It completes once the context is done, or when the server responds with 204 No
+// Content.
+func FromSSE(ctx context.Context, url string, opts ...Option) rxgo.Observable {
+	c := newConfig(opts)
+
+	return rxgo.CreateWithEmitter(func(ctx context.Context, emitter rxgo.Emitter) {
+		policy := backoff.WithContext(c.backOff(), ctx)
+		lastID := ""
+		for {
+			connected, err := c.stream(ctx, url, &lastID, emitter)
+			if ctx.Err() != nil {
+				return
+			}
+			if err == errNoContent {
+				emitter.Complete()
+				return
+			}
+			if connected {
+				policy.Reset()
+			}
+			delay := policy.NextBackOff()
+			if delay == backoff.Stop {
+				if err == nil {
+					err = io.ErrUnexpectedEOF
+				}
+				emitter.Error(err)
+				return
+			}
+
+			timer := c.clock.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C():
+			}
+		}
+	}, rxgo.WithContext(ctx))
+}
+
+var errNoContent = StatusError{StatusCode: http.StatusNoContent}
+
+// stream emits the events of a single connection. It returns whether the connection was established.
+func (c config) stream(ctx context.Context, url string, lastID *string, emitter rxgo.Emitter) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", ContentType)
+	req.Header.Set("Cache-Control", "no-cache")
+	if *lastID != "" {
+		req.Header.Set("Last-Event-ID", *lastID)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, StatusError{StatusCode: resp.StatusCode}
+	}
+
+	err = readEvents(resp.Body, func(event Event) {
+		if event.ID != "" {
+			*lastID = event.ID
+		}
+		emitter.Next(event)
+	})
+	return true, err
+}
+
+// readEvents calls f for each event of a stream, until its end or a read error
+func readEvents(r io.Reader, f func(Event)) error {
+	reader := bufio.NewReader(r)
+	var event Event
+	var data strings.Builder
+	hasData := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// an event interrupted by the end of the stream is discarded
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		if line == "" {
+			if hasData {
+				event.Data = data.String()
+				f(event)
+			}
+			event = Event{}
+			data.Reset()
+			hasData = false
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// comment
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+		case "event":
+			event.Event = value
+		case "id":
+			event.ID = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				event.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// ServeSSE streams the items of an Observable to the client as Server-Sent Events, until the Observable
+// completes or the request is canceled. Event values are sent as is, strings and []byte values as data and
+// other values encoded with the encoder, JSON by default. An error emitted by the Observable is sent as an
+// event of type "error" and returned.
+func ServeSSE(w http.ResponseWriter, r *http.Request, observable rxgo.Observable, opts ...Option) error {
+	c := newConfig(opts)
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return fmt.Errorf("%T is not an http.Flusher", w)
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	observe := observable.Observe(rxgo.WithContext(ctx))
+
+	var heartbeat <-chan time.Time
+	if c.heartbeat > 0 {
+		ticker := c.clock.NewTicker(c.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-heartbeat:
+			if _, err := io.WriteString(w, ":\n\n"); err != nil {
+				return err
+			}
+			flusher.Flush()
+		case item, ok := <-observe:
+			if !ok {
+				return nil
+			}
+			event, err := c.toEvent(item)
+			if err != nil {
+				return err
+			}
+			if err := writeEvent(w, event); err != nil {
+				return err
+			}
+			flusher.Flush()
+			if item.Error() {
+				return item.E
+			}
+		}
+	}
+}
+
+// toEvent converts an item to an event
+func (c config) toEvent(item rxgo.Item) (Event, error) {
+	if item.Error() {
+		return Event{Event: "error", Data: item.E.Error()}, nil
+	}
+	switch v := item.V.(type) {
+	case Event:
+		return v, nil
+	case string:
+		return Event{Data: v}, nil
+	case []byte:
+		return Event{Data: string(v)}, nil
+	default:
+		data, err := c.encode(v)
+		if err != nil {
+			return Event{}, err
+		}
+		return Event{Data: string(data)}, nil
+	}
+}
+
+// writeEvent writes an event in the stream format
+func writeEvent(w io.Writer, event Event) error {
+	var b strings.Builder
+	if event.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", event.ID)
+	}
+	if event.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", event.Event)
+	}
+	if event.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", event.Retry.Milliseconds())
+	}
+	for _, line := range strings.Split(event.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(w, b.String())
+	return err
+}
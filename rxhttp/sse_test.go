@@ -0,0 +1,138 @@
+package rxhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func constantBackOff() backoff.BackOff {
+	return backoff.NewConstantBackOff(time.Millisecond)
+}
+
+func TestServeSSE(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = ServeSSE(w, r, rxgo.Just(Event{ID: "1", Event: "created", Data: "a\nb"}, "c", map[string]int{"d": 1})())
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, ContentType, resp.Header.Get("Content-Type"))
+
+	events := make([]Event, 0)
+	assert.NoError(t, readEvents(resp.Body, func(event Event) {
+		events = append(events, event)
+	}))
+	assert.Equal(t, []Event{
+		{ID: "1", Event: "created", Data: "a\nb"},
+		{Data: "c"},
+		{Data: `{"d":1}`},
+	}, events)
+}
+
+// TestFromSSE verifies the stream is resumed from the last event after a disconnection
+func TestFromSSE(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	var connections int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&connections, 1) {
+		case 1:
+			_ = ServeSSE(w, r, rxgo.Just(Event{ID: "1", Data: "a"})())
+		case 2:
+			if r.Header.Get("Last-Event-ID") != "1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_ = ServeSSE(w, r, rxgo.Just(Event{ID: "2", Data: "b"})())
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	observer := rxgo.NewTestObserver(t, FromSSE(ctx, server.URL, WithBackOff(constantBackOff)))
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(Event{ID: "1", Data: "a"}, Event{ID: "2", Data: "b"})
+	observer.AssertNoErrors()
+}
+
+func TestFromSSE_GiveUp(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	observer := rxgo.NewTestObserver(t, FromSSE(ctx, server.URL, WithBackOff(func() backoff.BackOff {
+		return backoff.WithMaxRetries(constantBackOff(), 2)
+	})))
+	observer.AwaitDone(time.Second)
+	observer.AssertError(StatusError{StatusCode: http.StatusServiceUnavailable})
+}
+
+func TestFromSSE_Clock(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	var connections int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&connections, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	scheduler := rxgo.NewTestScheduler(time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	observer := rxgo.NewTestObserver(t, FromSSE(ctx, server.URL, WithClock(scheduler), WithBackOff(func() backoff.BackOff {
+		return backoff.NewConstantBackOff(time.Hour)
+	})))
+	// the reconnection waits for the virtual time
+	scheduler.BlockUntil(1)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connections))
+	scheduler.Advance(time.Hour)
+	observer.AwaitDone(time.Second)
+	observer.AssertNoErrors()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&connections))
+}
+
+func TestReadEvents(t *testing.T) {
+	stream := ": comment\n" +
+		"retry: 1000\n" +
+		"data: a\n" +
+		"\n" +
+		"event: update\r\n" +
+		"data:b\r\n" +
+		"data\r\n" +
+		"\r\n" +
+		"id: 3\n" +
+		"\n" +
+		"data: interrupted\n"
+	events := make([]Event, 0)
+	assert.NoError(t, readEvents(strings.NewReader(stream), func(event Event) {
+		events = append(events, event)
+	}))
+	assert.Equal(t, []Event{
+		{Data: "a", Retry: time.Second},
+		{Event: "update", Data: "b\n"},
+	}, events)
+}
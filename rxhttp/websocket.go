@@ -0,0 +1,250 @@
+package rxhttp
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/reactivex/rxgo/v2"
+)
+
+// The WebSocket message types, as defined by RFC 6455.
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+)
+
+// closeNormalClosure is the status code of a normal closure
+const closeNormalClosure = 1000
+
+// writeWait is the time allowed to write a control message
+const writeWait = 10 * time.Second
+
+// WebSocketConn is the subset of a WebSocket connection used by the adapters. It is implemented by the
+// connections of github.com/gorilla/websocket, and other libraries can be bridged with a small wrapper.
+// As with gorilla, ReadMessage is called by a single goroutine, WriteMessage by a single goroutine, and
+// WriteControl and Close concurrently with them.
+type WebSocketConn interface {
+	ReadMessage() (messageType int, data []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+	Close() error
+}
+
+// WebSocketMessage is a WebSocket data message.
+type WebSocketMessage struct {
+	// Type is TextMessage or BinaryMessage.
+	Type int
+	// Data is the message payload.
+	Data []byte
+}
+
+// WithRedial makes FromWebSocket reconnect with dial once the connection fails, after the back-off delay.
+func WithRedial(dial func(ctx context.Context) (WebSocketConn, error)) Option {
+	return func(c *config) {
+		c.dial = dial
+	}
+}
+
+// WithNormalClosure sets how FromWebSocket recognizes the read error of a connection closed normally by the
+// peer, which completes the Observable. With gorilla/websocket:
+//
+//	rxhttp.WithNormalClosure(func(err error) bool {
+//		return websocket.IsCloseError(err, websocket.CloseNormalClosure)
+//	})
+func WithNormalClosure(normal func(err error) bool) Option {
+	return func(c *config) {
+		c.normalClosure = normal
+	}
+}
+
+// FromWebSocket creates an Observable emitting the data messages read from a connection as WebSocketMessage
+// values. The Observable owns the connection: it is closed once the context is done or the Observable
+// terminates.
+//
+// The Observable completes once the peer closed the connection normally. Otherwise, the read error is emitted
+// unless WithRedial is set, in which case the Observable reconnects after the back-off delay, the back-off
+// being reset once a message is read, and emits the error once the back-off gives up.
+func FromWebSocket(ctx context.Context, conn WebSocketConn, opts ...Option) rxgo.Observable {
+	c := newConfig(opts)
+
+	return rxgo.CreateWithEmitter(func(ctx context.Context, emitter rxgo.Emitter) {
+		policy := backoff.WithContext(c.backOff(), ctx)
+		current := &connHolder{conn: conn}
+		defer current.close()
+		go func() {
+			// closing the connection unblocks the pending read
+			<-ctx.Done()
+			current.close()
+		}()
+
+		for {
+			received, err := readMessages(current.get(), emitter)
+			if ctx.Err() != nil {
+				return
+			}
+			if c.normalClosure != nil && c.normalClosure(err) {
+				emitter.Complete()
+				return
+			}
+			if c.dial == nil {
+				emitter.Error(err)
+				return
+			}
+			if received {
+				policy.Reset()
+			}
+
+			for {
+				delay := policy.NextBackOff()
+				if delay == backoff.Stop {
+					emitter.Error(err)
+					return
+				}
+				timer := c.clock.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C():
+				}
+
+				var next WebSocketConn
+				next, err = c.dial(ctx)
+				if err == nil {
+					if !current.replace(next) {
+						// closed in the meantime by the context
+						return
+					}
+					break
+				}
+			}
+		}
+	}, rxgo.WithContext(ctx))
+}
+
+// readMessages emits the data messages of a connection until a read error. It returns whether a message was
+// read.
+func readMessages(conn WebSocketConn, emitter rxgo.Emitter) (bool, error) {
+	received := false
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return received, err
+		}
+		received = true
+		if messageType == TextMessage || messageType == BinaryMessage {
+			emitter.Next(WebSocketMessage{Type: messageType, Data: data})
+		}
+	}
+}
+
+// connHolder holds the current connection of FromWebSocket
+type connHolder struct {
+	mu     sync.Mutex
+	conn   WebSocketConn
+	closed bool
+}
+
+func (h *connHolder) get() WebSocketConn {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.conn
+}
+
+// replace replaces the connection, closing the previous one. It returns false, closing the new connection,
+// if the holder is closed.
+func (h *connHolder) replace(conn WebSocketConn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		_ = conn.Close()
+		return false
+	}
+	_ = h.conn.Close()
+	h.conn = conn
+	return true
+}
+
+func (h *connHolder) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.closed {
+		h.closed = true
+		_ = h.conn.Close()
+	}
+}
+
+// ToWebSocket writes the items of an Observable to a connection, until the Observable completes, the context
+// is done or a write fails. WebSocketMessage values are sent as is, strings as text messages, []byte values
+// as binary messages and other values encoded with the encoder, JSON by default, as text messages.
+//
+// Once the Observable completed, a normal closure message is sent. An error emitted by the Observable is
+// returned. With WithHeartbeat, a ping is sent every interval so that idle connections are kept alive and
+// dead peers detected. The connection is not closed.
+func ToWebSocket(ctx context.Context, conn WebSocketConn, observable rxgo.Observable, opts ...Option) error {
+	c := newConfig(opts)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	observe := observable.Observe(rxgo.WithContext(ctx))
+
+	var heartbeat <-chan time.Time
+	if c.heartbeat > 0 {
+		ticker := c.clock.NewTicker(c.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-heartbeat:
+			if err := conn.WriteControl(PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return err
+			}
+		case item, ok := <-observe:
+			if !ok {
+				closing := make([]byte, 2)
+				binary.BigEndian.PutUint16(closing, closeNormalClosure)
+				return conn.WriteControl(CloseMessage, closing, time.Now().Add(writeWait))
+			}
+			if item.Error() {
+				return item.E
+			}
+			message, err := c.toMessage(item.V)
+			if err != nil {
+				return err
+			}
+			if err := conn.WriteMessage(message.Type, message.Data); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toMessage converts a value to a message
+func (c config) toMessage(v interface{}) (WebSocketMessage, error) {
+	switch v := v.(type) {
+	case WebSocketMessage:
+		return v, nil
+	case string:
+		return WebSocketMessage{Type: TextMessage, Data: []byte(v)}, nil
+	case []byte:
+		return WebSocketMessage{Type: BinaryMessage, Data: v}, nil
+	default:
+		data, err := c.encode(v)
+		if err != nil {
+			return WebSocketMessage{}, err
+		}
+		return WebSocketMessage{Type: TextMessage, Data: data}, nil
+	}
+}
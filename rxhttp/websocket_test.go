@@ -0,0 +1,155 @@
+package rxhttp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+var (
+	errClosed = errors.New("closed")
+	errBroken = errors.New("broken")
+)
+
+type frame struct {
+	messageType int
+	data        []byte
+}
+
+// fakeConn is an in-memory connection reading the queued frames, then failing with err
+type fakeConn struct {
+	mu      sync.Mutex
+	frames  chan frame
+	err     error
+	closed  chan struct{}
+	once    sync.Once
+	written []frame
+}
+
+func newFakeConn(err error, frames ...frame) *fakeConn {
+	c := &fakeConn{
+		frames: make(chan frame, len(frames)),
+		err:    err,
+		closed: make(chan struct{}),
+	}
+	for _, f := range frames {
+		c.frames <- f
+	}
+	if err != nil {
+		close(c.frames)
+	}
+	return c
+}
+
+func (c *fakeConn) ReadMessage() (int, []byte, error) {
+	select {
+	case f, ok := <-c.frames:
+		if !ok {
+			return 0, nil, c.err
+		}
+		return f.messageType, f.data, nil
+	case <-c.closed:
+		return 0, nil, errClosed
+	}
+}
+
+func (c *fakeConn) WriteMessage(messageType int, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.written = append(c.written, frame{messageType: messageType, data: data})
+	return nil
+}
+
+func (c *fakeConn) WriteControl(messageType int, data []byte, _ time.Time) error {
+	return c.WriteMessage(messageType, data)
+}
+
+func (c *fakeConn) Close() error {
+	c.once.Do(func() {
+		close(c.closed)
+	})
+	return nil
+}
+
+func (c *fakeConn) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFromWebSocket(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	conn := newFakeConn(errClosed, frame{TextMessage, []byte("a")}, frame{PingMessage, nil}, frame{BinaryMessage, []byte("b")})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	observer := rxgo.NewTestObserver(t, FromWebSocket(ctx, conn, WithNormalClosure(func(err error) bool {
+		return err == errClosed
+	})))
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(WebSocketMessage{Type: TextMessage, Data: []byte("a")}, WebSocketMessage{Type: BinaryMessage, Data: []byte("b")})
+	observer.AssertNoErrors()
+	assert.Eventually(t, conn.isClosed, time.Second, time.Millisecond)
+}
+
+func TestFromWebSocket_Redial(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	first := newFakeConn(errBroken, frame{TextMessage, []byte("a")})
+	second := newFakeConn(nil, frame{TextMessage, []byte("b")})
+	dials := 0
+	ctx, cancel := context.WithCancel(context.Background())
+
+	observer := rxgo.NewTestObserver(t, FromWebSocket(ctx, first, WithBackOff(constantBackOff),
+		WithRedial(func(context.Context) (WebSocketConn, error) {
+			dials++
+			if dials == 1 {
+				return nil, errBroken
+			}
+			return second, nil
+		})))
+	observer.AwaitCount(2, time.Second)
+	cancel()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(WebSocketMessage{Type: TextMessage, Data: []byte("a")}, WebSocketMessage{Type: TextMessage, Data: []byte("b")})
+	observer.AssertNoErrors()
+	assert.Equal(t, 2, dials)
+	assert.Eventually(t, first.isClosed, time.Second, time.Millisecond)
+	assert.Eventually(t, second.isClosed, time.Second, time.Millisecond)
+}
+
+func TestFromWebSocket_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	observer := rxgo.NewTestObserver(t, FromWebSocket(ctx, newFakeConn(errBroken)))
+	observer.AwaitDone(time.Second)
+	observer.AssertError(errBroken)
+}
+
+func TestToWebSocket(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	conn := newFakeConn(nil)
+
+	assert.NoError(t, ToWebSocket(ctx, conn, rxgo.Just("a", map[string]int{"b": 1}, WebSocketMessage{Type: BinaryMessage, Data: []byte("c")})()))
+	assert.Equal(t, []frame{
+		{TextMessage, []byte("a")},
+		{TextMessage, []byte(`{"b":1}`)},
+		{BinaryMessage, []byte("c")},
+		{CloseMessage, []byte{0x03, 0xe8}},
+	}, conn.written)
+
+	assert.Equal(t, errBroken, ToWebSocket(ctx, conn, rxgo.Thrown(errBroken)))
+}
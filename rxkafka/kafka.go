@@ -0,0 +1,264 @@
+// Package rxkafka bridges Observables and Kafka topics.
+//
+// The package does not depend on a Kafka client: FromConsumer and ToProducer are defined against the small
+// Reader and Writer interfaces, modeled after github.com/segmentio/kafka-go, which other clients can
+// implement with a small wrapper.
+package rxkafka
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+)
+
+// Header is a message header.
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Message is a Kafka message.
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []Header
+	Time      time.Time
+}
+
+// Reader is the consumer read by FromConsumer. With kafka-go, it is a *kafka.Reader of a consumer group
+// converting the messages.
+type Reader interface {
+	// FetchMessage returns the next message, blocking until one is available or the context is done.
+	FetchMessage(ctx context.Context) (Message, error)
+	// CommitMessages commits the offsets of messages.
+	CommitMessages(ctx context.Context, msgs ...Message) error
+}
+
+// Writer is the producer written by ToProducer.
+type Writer interface {
+	// WriteMessages writes messages, returning once they are acknowledged by the brokers.
+	WriteMessages(ctx context.Context, msgs ...Message) error
+}
+
+// Option configures ToProducer.
+type Option func(*config)
+
+type config struct {
+	topic  string
+	key    func(interface{}) []byte
+	encode func(interface{}) ([]byte, error)
+}
+
+// WithTopic sets the topic of the messages written by ToProducer, empty by default to use the topic of the
+// writer.
+func WithTopic(topic string) Option {
+	return func(c *config) {
+		c.topic = topic
+	}
+}
+
+// WithKey sets the function computing the key of the messages written by ToProducer from the item values,
+// so that related values go to the same partition. By default, the messages have no key.
+func WithKey(key func(interface{}) []byte) Option {
+	return func(c *config) {
+		c.key = key
+	}
+}
+
+// WithEncoder sets the encoding of the values written by ToProducer, json.Marshal by default.
+func WithEncoder(encode func(interface{}) ([]byte, error)) Option {
+	return func(c *config) {
+		c.encode = encode
+	}
+}
+
+// FromConsumer creates an Observable emitting the messages fetched by a reader as Message values, until the
+// context is done or a fetch fails, in which case the error is emitted.
+//
+// The offsets are not committed automatically: acknowledging an item with Ack commits its offset once the
+// previous messages of its partition are acknowledged too, so that messages processed out of order are never
+// skipped. The messages left unacknowledged are fetched again by the next consumer of the group, giving
+// at-least-once processing. A failed commit is emitted as an error.
+func FromConsumer(ctx context.Context, reader Reader) rxgo.Observable {
+	return rxgo.CreateWithEmitter(func(ctx context.Context, emitter rxgo.Emitter) {
+		fetchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		offsets := newCommitter(ctx, reader, cancel)
+		for {
+			msg, err := reader.FetchMessage(fetchCtx)
+			if commitErr := offsets.failure(); commitErr != nil {
+				emitter.Error(commitErr)
+				return
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					emitter.Error(err)
+				}
+				return
+			}
+			emitter.NextItem(rxgo.Of(msg).WithAck(offsets.track(msg)))
+		}
+	}, rxgo.WithContext(ctx))
+}
+
+// committer commits the offsets of the acknowledged messages, partition by partition
+type committer struct {
+	ctx    context.Context
+	reader Reader
+	// stopFetch interrupts the fetch loop once a commit failed
+	stopFetch func()
+
+	mu         sync.Mutex
+	partitions map[partition]*pendingMessages
+	err        error
+}
+
+type partition struct {
+	topic string
+	id    int
+}
+
+// pendingMessages are the messages of a partition fetched but not committed, in offset order
+type pendingMessages struct {
+	messages []Message
+	acked    map[int64]bool
+}
+
+func newCommitter(ctx context.Context, reader Reader, stopFetch func()) *committer {
+	return &committer{
+		ctx:        ctx,
+		reader:     reader,
+		stopFetch:  stopFetch,
+		partitions: make(map[partition]*pendingMessages),
+	}
+}
+
+// track records a fetched message and returns its acknowledgement
+func (c *committer) track(msg Message) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := partition{topic: msg.Topic, id: msg.Partition}
+	pending, exists := c.partitions[key]
+	if !exists {
+		pending = &pendingMessages{acked: make(map[int64]bool)}
+		c.partitions[key] = pending
+	}
+	pending.messages = append(pending.messages, msg)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.ack(pending, msg.Offset)
+		})
+	}
+}
+
+// ack acknowledges an offset and commits the longest acknowledged prefix of the partition
+func (c *committer) ack(pending *pendingMessages, offset int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return
+	}
+	pending.acked[offset] = true
+	n := 0
+	for n < len(pending.messages) && pending.acked[pending.messages[n].Offset] {
+		delete(pending.acked, pending.messages[n].Offset)
+		n++
+	}
+	if n == 0 {
+		return
+	}
+	last := pending.messages[n-1]
+	pending.messages = pending.messages[n:]
+
+	// committing the last message commits the previous ones
+	if err := c.reader.CommitMessages(c.ctx, last); err != nil && c.ctx.Err() == nil {
+		// reported by the fetch loop, an acknowledgement being possibly called by the observer goroutine
+		c.err = err
+		c.stopFetch()
+	}
+}
+
+// failure returns the error of a failed commit, if any
+func (c *committer) failure() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// ToProducer writes the items of an Observable to a writer, until the Observable completes, the context is
+// done or a write fails. Message values are written as is, []byte and string values as the message value and
+// other values encoded with the encoder, JSON by default.
+//
+// Each item is acknowledged with Ack once written, so that a pipeline from FromConsumer to ToProducer commits
+// the consumed offsets only once the results are produced. An error emitted by the Observable is returned.
+func ToProducer(ctx context.Context, writer Writer, observable rxgo.Observable, opts ...Option) error {
+	c := config{
+		encode: json.Marshal,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	observe := observable.Observe(rxgo.WithContext(ctx))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-observe:
+			if !ok {
+				return nil
+			}
+			if item.Error() {
+				return item.E
+			}
+			msg, err := c.toMessage(item.V)
+			if err != nil {
+				return err
+			}
+			if err := writer.WriteMessages(ctx, msg); err != nil {
+				return err
+			}
+			item.Ack()
+		}
+	}
+}
+
+// toMessage converts a value to a message
+func (c config) toMessage(v interface{}) (Message, error) {
+	var msg Message
+	switch v := v.(type) {
+	case Message:
+		msg = v
+	case []byte:
+		msg.Value = v
+	case string:
+		msg.Value = []byte(v)
+	default:
+		data, err := c.encode(v)
+		if err != nil {
+			return Message{}, err
+		}
+		msg.Value = data
+	}
+	if c.topic != "" {
+		msg.Topic = c.topic
+	}
+	if c.key != nil && msg.Key == nil {
+		msg.Key = c.key(v)
+	}
+	return msg, nil
+}
@@ -0,0 +1,171 @@
+package rxkafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+var errKafka = errors.New("kafka")
+
+// fakeReader fetches the queued messages, then fails with err or blocks until the context is done
+type fakeReader struct {
+	messages  chan Message
+	err       error
+	commitErr error
+
+	mu        sync.Mutex
+	committed []int64
+}
+
+func newFakeReader(err error, messages ...Message) *fakeReader {
+	r := &fakeReader{
+		messages: make(chan Message, len(messages)),
+		err:      err,
+	}
+	for _, msg := range messages {
+		r.messages <- msg
+	}
+	if err != nil {
+		close(r.messages)
+	}
+	return r
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (Message, error) {
+	select {
+	case msg, ok := <-r.messages:
+		if !ok {
+			return Message{}, r.err
+		}
+		return msg, nil
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	}
+}
+
+func (r *fakeReader) CommitMessages(_ context.Context, msgs ...Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.commitErr != nil {
+		return r.commitErr
+	}
+	for _, msg := range msgs {
+		r.committed = append(r.committed, msg.Offset)
+	}
+	return nil
+}
+
+func (r *fakeReader) commits() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]int64(nil), r.committed...)
+}
+
+type fakeWriter struct {
+	written []Message
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...Message) error {
+	w.written = append(w.written, msgs...)
+	return nil
+}
+
+func message(partition int, offset int64) Message {
+	return Message{Topic: "orders", Partition: partition, Offset: offset}
+}
+
+func receive(t *testing.T, observe <-chan rxgo.Item, n int) []rxgo.Item {
+	t.Helper()
+	items := make([]rxgo.Item, 0, n)
+	for i := 0; i < n; i++ {
+		select {
+		case item := <-observe:
+			items = append(items, item)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "timeout waiting for items")
+		}
+	}
+	return items
+}
+
+func TestFromConsumer(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	reader := newFakeReader(nil, message(0, 10), message(0, 11), message(1, 20), message(0, 12))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	items := receive(t, FromConsumer(ctx, reader).Observe(), 4)
+	assert.Equal(t, message(0, 11), items[1].V)
+
+	items[1].Ack()
+	assert.Empty(t, reader.commits())
+	items[0].Ack()
+	assert.Equal(t, []int64{11}, reader.commits())
+	items[2].Ack()
+	items[2].Ack()
+	assert.Equal(t, []int64{11, 20}, reader.commits())
+	items[3].Ack()
+	assert.Equal(t, []int64{11, 20, 12}, reader.commits())
+}
+
+func TestFromConsumer_FetchError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	reader := newFakeReader(errKafka, message(0, 10))
+
+	rxgo.Assert(context.Background(), t, FromConsumer(context.Background(), reader),
+		rxgo.HasItems(message(0, 10)), rxgo.HasError(errKafka))
+}
+
+func TestFromConsumer_CommitError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	reader := newFakeReader(nil, message(0, 10))
+	reader.commitErr = errKafka
+
+	observe := FromConsumer(context.Background(), reader).Observe()
+	items := receive(t, observe, 1)
+	items[0].Ack()
+	items = receive(t, observe, 1)
+	assert.Equal(t, errKafka, items[0].E)
+}
+
+func TestToProducer(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	writer := &fakeWriter{}
+
+	err := ToProducer(context.Background(), writer, rxgo.Just("a", map[string]int{"b": 1}, Message{Key: []byte("k"), Value: []byte("c")})(),
+		WithTopic("out"), WithKey(func(v interface{}) []byte {
+			return []byte("key")
+		}))
+	assert.NoError(t, err)
+	assert.Equal(t, []Message{
+		{Topic: "out", Key: []byte("key"), Value: []byte("a")},
+		{Topic: "out", Key: []byte("key"), Value: []byte(`{"b":1}`)},
+		{Topic: "out", Key: []byte("k"), Value: []byte("c")},
+	}, writer.written)
+
+	assert.Equal(t, errKafka, ToProducer(context.Background(), writer, rxgo.Thrown(errKafka)))
+}
+
+func TestToProducer_Ack(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	reader := newFakeReader(errKafka, message(0, 10), message(0, 11))
+	writer := &fakeWriter{}
+
+	processed := FromConsumer(context.Background(), reader).
+		Filter(func(v interface{}) bool {
+			return v.(Message).Offset == 11
+		})
+	assert.Equal(t, errKafka, ToProducer(context.Background(), writer, processed))
+	assert.Len(t, writer.written, 1)
+	// the filtered out message is not acknowledged, holding back the commit of the next one
+	assert.Empty(t, reader.commits())
+}
@@ -0,0 +1,225 @@
+// Package rxkv persists the state of the stateful operators, such as the keys seen by Distinct, into a key-value
+// database, so that it survives restarts.
+//
+// The package does not depend on a database: the store is defined against a small interface, which a bucket of
+// go.etcd.io/bbolt or a github.com/dgraph-io/badger database implements with a small wrapper.
+package rxkv
+
+import (
+	"encoding/gob"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+)
+
+func init() {
+	// the time a key was seen at by DeduplicateWithin
+	gob.Register(time.Time{})
+}
+
+// KV is a key-value database, such as a bbolt bucket.
+type KV interface {
+	// Get returns the value of a key, nil if it does not exist.
+	Get(key []byte) ([]byte, error)
+	// Put sets the value of a key.
+	Put(key, value []byte) error
+	// Delete removes a key, doing nothing if it does not exist.
+	Delete(key []byte) error
+	// ForEach calls f for each key and its value, until f returns an error.
+	ForEach(f func(key, value []byte) error) error
+}
+
+// Option configures a store.
+type Option func(*config)
+
+type config struct {
+	codec   rxgo.Codec
+	onError func(error)
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		codec: rxgo.NewGobCodec(),
+		onError: func(err error) {
+			rxgo.DefaultLogger().Error("rxkv: state store operation failed", "error", err)
+		},
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithCodec sets the codec encoding the keys and the states, gob by default. Their types must then be
+// registered with gob.Register, time.Time being registered by the package.
+func WithCodec(codec rxgo.Codec) Option {
+	return func(c *config) {
+		c.codec = codec
+	}
+}
+
+// WithErrorHandler sets the function called with the errors of the database or of the codec, the operation
+// failing as if the key did not exist. By default, an error is logged with the default logger of rxgo.
+func WithErrorHandler(handler func(error)) Option {
+	return func(c *config) {
+		c.onError = handler
+	}
+}
+
+// WithPanicOnError makes the errors of the database or of the codec panic, stopping the process rather than
+// losing the state.
+func WithPanicOnError() Option {
+	return WithErrorHandler(func(err error) {
+		panic(err)
+	})
+}
+
+// store is a StateStore persisting its entries into a KV
+type store struct {
+	kv     KV
+	config config
+	length int
+}
+
+// NewStateStore creates a StateStore persisting its entries into kv, the entries already in kv being the
+// initial state. The states of an operator must be encodable by the codec: Distinct and DeduplicateWithin can
+// be persisted, while GroupByDynamic and AggregateByKey keep live values. The store evicts nothing, the entries
+// being deleted by the operators only.
+func NewStateStore(kv KV, opts ...Option) rxgo.StateStore {
+	s := &store{
+		kv:     kv,
+		config: newConfig(opts),
+	}
+	if err := kv.ForEach(func(_, _ []byte) error {
+		s.length++
+		return nil
+	}); err != nil {
+		s.config.onError(err)
+	}
+	return s
+}
+
+func (s *store) Get(key interface{}) (interface{}, bool) {
+	k, ok := s.encode(key)
+	if !ok {
+		return nil, false
+	}
+	data, err := s.kv.Get(k)
+	if err != nil {
+		s.config.onError(err)
+		return nil, false
+	}
+	if data == nil {
+		return nil, false
+	}
+	return s.decode(data)
+}
+
+func (s *store) Put(key, value interface{}) {
+	k, ok := s.encode(key)
+	if !ok {
+		return
+	}
+	v, ok := s.encode(value)
+	if !ok {
+		return
+	}
+	existing, err := s.kv.Get(k)
+	if err != nil {
+		s.config.onError(err)
+		return
+	}
+	if err := s.kv.Put(k, v); err != nil {
+		s.config.onError(err)
+		return
+	}
+	if existing == nil {
+		s.length++
+	}
+}
+
+func (s *store) Delete(key interface{}) {
+	k, ok := s.encode(key)
+	if !ok {
+		return
+	}
+	existing, err := s.kv.Get(k)
+	if err != nil {
+		s.config.onError(err)
+		return
+	}
+	if existing == nil {
+		return
+	}
+	if err := s.kv.Delete(k); err != nil {
+		s.config.onError(err)
+		return
+	}
+	s.length--
+}
+
+func (s *store) Len() int {
+	return s.length
+}
+
+// errStop stops the iteration of ForEach
+type errStop struct{}
+
+func (errStop) Error() string {
+	return "stop"
+}
+
+func (s *store) Range(f func(key, value interface{}) bool) {
+	// collected first, as f may delete the entries
+	type entry struct {
+		key, value interface{}
+	}
+	var entries []entry
+	err := s.kv.ForEach(func(k, v []byte) error {
+		key, ok := s.decode(k)
+		if !ok {
+			return errStop{}
+		}
+		value, ok := s.decode(v)
+		if !ok {
+			return errStop{}
+		}
+		entries = append(entries, entry{key: key, value: value})
+		return nil
+	})
+	if err != nil {
+		if _, stopped := err.(errStop); !stopped {
+			s.config.onError(err)
+		}
+		return
+	}
+	for _, e := range entries {
+		if !f(e.key, e.value) {
+			return
+		}
+	}
+}
+
+// OnEvict does nothing, as the store evicts nothing.
+func (s *store) OnEvict(func(key, value interface{})) {
+}
+
+// encode encodes a key or a state, returning false if it failed
+func (s *store) encode(v interface{}) ([]byte, bool) {
+	data, err := s.config.codec.Marshal(rxgo.KindNext, rxgo.Of(v))
+	if err != nil {
+		s.config.onError(err)
+		return nil, false
+	}
+	return data, true
+}
+
+// decode decodes a key or a state, returning false if it failed
+func (s *store) decode(data []byte) (interface{}, bool) {
+	_, item, err := s.config.codec.Unmarshal(data)
+	if err != nil {
+		s.config.onError(err)
+		return nil, false
+	}
+	return item.V, true
+}
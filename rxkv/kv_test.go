@@ -0,0 +1,178 @@
+package rxkv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+var errKV = errors.New("kv")
+
+// memoryKV is a KV in memory, failing with err if set
+type memoryKV struct {
+	entries map[string][]byte
+	err     error
+}
+
+func newMemoryKV() *memoryKV {
+	return &memoryKV{entries: make(map[string][]byte)}
+}
+
+func (kv *memoryKV) Get(key []byte) ([]byte, error) {
+	if kv.err != nil {
+		return nil, kv.err
+	}
+	return kv.entries[string(key)], nil
+}
+
+func (kv *memoryKV) Put(key, value []byte) error {
+	if kv.err != nil {
+		return kv.err
+	}
+	kv.entries[string(key)] = value
+	return nil
+}
+
+func (kv *memoryKV) Delete(key []byte) error {
+	if kv.err != nil {
+		return kv.err
+	}
+	delete(kv.entries, string(key))
+	return nil
+}
+
+func (kv *memoryKV) ForEach(f func(key, value []byte) error) error {
+	if kv.err != nil {
+		return kv.err
+	}
+	for k, v := range kv.entries {
+		if err := f([]byte(k), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func identity(_ context.Context, i interface{}) (interface{}, error) {
+	return i, nil
+}
+
+func Test_StateStore(t *testing.T) {
+	kv := newMemoryKV()
+	store := NewStateStore(kv)
+	store.Put("a", 1)
+	store.Put("b", 2)
+	store.Put("a", 3)
+	v, ok := store.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, 2, store.Len())
+
+	store.Delete("a")
+	store.Delete("a")
+	_, ok = store.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, store.Len())
+
+	var keys []interface{}
+	store.Range(func(key, value interface{}) bool {
+		keys = append(keys, key)
+		assert.Equal(t, 2, value)
+		return true
+	})
+	assert.Equal(t, []interface{}{"b"}, keys)
+
+	// reopened
+	assert.Equal(t, 1, NewStateStore(kv).Len())
+}
+
+func Test_StateStore_Distinct(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv := newMemoryKV()
+	store := rxgo.WithStateStore(func() rxgo.StateStore {
+		return NewStateStore(kv)
+	})
+
+	rxgo.Assert(ctx, t, rxgo.Just(1, 2, 2)().Distinct(identity, store), rxgo.HasItems(1, 2))
+	// the keys seen by the previous run are kept
+	rxgo.Assert(ctx, t, rxgo.Just(2, 3, 1)().Distinct(identity, store), rxgo.HasItems(3))
+}
+
+func Test_StateStore_DeduplicateWithin(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv := newMemoryKV()
+	store := rxgo.WithStateStore(func() rxgo.StateStore {
+		return NewStateStore(kv)
+	})
+	scheduler := rxgo.NewTestScheduler(time.Unix(0, 0))
+
+	rxgo.Assert(ctx, t, rxgo.Just("a", "b")().DeduplicateWithin(rxgo.WithDuration(time.Minute), identity, store,
+		rxgo.WithClock(scheduler)), rxgo.HasItems("a", "b"))
+	rxgo.Assert(ctx, t, rxgo.Just("a")().DeduplicateWithin(rxgo.WithDuration(time.Minute), identity, store,
+		rxgo.WithClock(scheduler)), rxgo.IsEmpty())
+	// the keys seen by the previous runs are forgotten once their window elapsed
+	scheduler.Advance(time.Minute)
+	rxgo.Assert(ctx, t, rxgo.Just("a")().DeduplicateWithin(rxgo.WithDuration(time.Minute), identity, store,
+		rxgo.WithClock(scheduler)), rxgo.HasItems("a"))
+}
+
+func Test_StateStore_Error(t *testing.T) {
+	kv := newMemoryKV()
+	var errs []error
+	store := NewStateStore(kv, WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+	store.Put("a", 1)
+	kv.err = errKV
+	_, ok := store.Get("a")
+	assert.False(t, ok)
+	store.Put("b", 2)
+	assert.Equal(t, []error{errKV, errKV}, errs)
+	assert.Equal(t, 1, store.Len())
+}
+
+func Test_StateStore_ErrorLogged(t *testing.T) {
+	logger := &recordingLogger{}
+	rxgo.SetDefaultLogger(logger)
+	defer rxgo.SetDefaultLogger(nil)
+	kv := newMemoryKV()
+	kv.err = errKV
+
+	store := NewStateStore(kv)
+	store.Put("a", 1)
+	assert.Equal(t, []error{errKV, errKV}, logger.errors)
+}
+
+func Test_StateStore_PanicOnError(t *testing.T) {
+	kv := newMemoryKV()
+	kv.err = errKV
+	assert.PanicsWithValue(t, errKV, func() {
+		NewStateStore(kv, WithPanicOnError())
+	})
+}
+
+// recordingLogger records the errors logged
+type recordingLogger struct {
+	errors []error
+}
+
+func (l *recordingLogger) Debug(string, ...interface{}) {}
+func (l *recordingLogger) Info(string, ...interface{})  {}
+func (l *recordingLogger) Warn(string, ...interface{})  {}
+
+func (l *recordingLogger) Error(_ string, keysAndValues ...interface{}) {
+	for i := 1; i < len(keysAndValues); i += 2 {
+		if err, ok := keysAndValues[i].(error); ok {
+			l.errors = append(l.errors, err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Package rxmqtt bridges Observables and MQTT topics, so that sensor streams can be processed with operators
+// such as Debounce, WindowWithTime or Reduce.
+//
+// The package does not depend on an MQTT client: FromTopic and ToTopic are defined against the small Client
+// interface, which a client of github.com/eclipse/paho.mqtt.golang implements with a small wrapper.
+package rxmqtt
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/reactivex/rxgo/v2"
+)
+
+// The quality of service levels.
+const (
+	// AtMostOnce delivers a message at most once, without acknowledgement.
+	AtMostOnce byte = 0
+	// AtLeastOnce delivers a message until it is acknowledged, possibly more than once.
+	AtLeastOnce byte = 1
+	// ExactlyOnce delivers a message exactly once.
+	ExactlyOnce byte = 2
+)
+
+// Message is an MQTT message.
+type Message struct {
+	Topic     string
+	QoS       byte
+	Retained  bool
+	Duplicate bool
+	Payload   []byte
+}
+
+// Client is the subset of an MQTT client used by the adapters.
+type Client interface {
+	// Subscribe calls handler for each message published to topic, which may contain wildcards. The message is
+	// acknowledged to the broker once ack is called.
+	Subscribe(topic string, qos byte, handler func(msg Message, ack func())) error
+	// Unsubscribe ends the subscription to topic.
+	Unsubscribe(topic string) error
+	// Publish publishes a payload to topic, returning once it is delivered according to qos.
+	Publish(topic string, qos byte, retained bool, payload []byte) error
+}
+
+// Option configures the adapters.
+type Option func(*config)
+
+type config struct {
+	autoAck  bool
+	qos      byte
+	retained bool
+	encode   func(interface{}) ([]byte, error)
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		encode: json.Marshal,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithAutoAck makes FromTopic acknowledge the messages once emitted, instead of when the items are
+// acknowledged with Ack.
+func WithAutoAck() Option {
+	return func(c *config) {
+		c.autoAck = true
+	}
+}
+
+// WithQoS sets the quality of service of the messages published by ToTopic, AtMostOnce by default.
+func WithQoS(qos byte) Option {
+	return func(c *config) {
+		c.qos = qos
+	}
+}
+
+// WithRetained makes ToTopic publish retained messages, the last of which is sent to the new subscribers of
+// the topic.
+func WithRetained() Option {
+	return func(c *config) {
+		c.retained = true
+	}
+}
+
+// WithEncoder sets the encoding of the values published by ToTopic, json.Marshal by default.
+func WithEncoder(encode func(interface{}) ([]byte, error)) Option {
+	return func(c *config) {
+		c.encode = encode
+	}
+}
+
+// FromTopic creates an Observable emitting the messages published to a topic as Message values. Each
+// observer subscribes to the topic, and unsubscribes once the context is done or the observation stopped. A
+// failed subscription is emitted as an error.
+//
+// The messages received with the AtLeastOnce or ExactlyOnce quality of service are acknowledged to the
+// broker once their item is acknowledged with Ack, or once emitted with WithAutoAck, so that the broker
+// delivers again the messages left unprocessed by a client with a persistent session. The messages are
+// emitted from the goroutine of the client, which is blocked until they are consumed.
+func FromTopic(ctx context.Context, client Client, topic string, qos byte, opts ...Option) rxgo.Observable {
+	c := newConfig(opts)
+
+	return rxgo.CreateWithEmitter(func(ctx context.Context, emitter rxgo.Emitter) {
+		err := client.Subscribe(topic, qos, func(msg Message, ack func()) {
+			if msg.QoS == AtMostOnce || c.autoAck {
+				emitter.Next(msg)
+				ack()
+				return
+			}
+			emitter.NextItem(rxgo.Of(msg).WithAck(ack))
+		})
+		if err != nil {
+			emitter.Error(err)
+			return
+		}
+		<-ctx.Done()
+		_ = client.Unsubscribe(topic)
+	}, rxgo.WithContext(ctx))
+}
+
+// ToTopic publishes the items of an Observable to a topic, until the Observable completes, the context is done
+// or a publication fails. Message values are published to their own topic, if any, with their quality of
+// service and retained flag, []byte and string values as is, and other values encoded with the encoder, JSON
+// by default. An error emitted by the Observable is returned.
+func ToTopic(ctx context.Context, client Client, topic string, observable rxgo.Observable, opts ...Option) error {
+	c := newConfig(opts)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	observe := observable.Observe(rxgo.WithContext(ctx))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-observe:
+			if !ok {
+				return nil
+			}
+			if item.Error() {
+				return item.E
+			}
+			msg, err := c.toMessage(topic, item.V)
+			if err != nil {
+				return err
+			}
+			if err := client.Publish(msg.Topic, msg.QoS, msg.Retained, msg.Payload); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toMessage converts a value to a message
+func (c config) toMessage(topic string, v interface{}) (Message, error) {
+	msg := Message{Topic: topic, QoS: c.qos, Retained: c.retained}
+	switch v := v.(type) {
+	case Message:
+		if v.Topic == "" {
+			v.Topic = topic
+		}
+		return v, nil
+	case []byte:
+		msg.Payload = v
+	case string:
+		msg.Payload = []byte(v)
+	default:
+		data, err := c.encode(v)
+		if err != nil {
+			return Message{}, err
+		}
+		msg.Payload = data
+	}
+	return msg, nil
+}
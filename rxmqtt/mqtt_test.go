@@ -0,0 +1,166 @@
+package rxmqtt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+var errMQTT = errors.New("mqtt")
+
+// fakeClient is an in-memory client recording the subscriptions, acknowledgements and publications
+type fakeClient struct {
+	subscribeErr error
+
+	mu         sync.Mutex
+	handlers   map[string]func(Message, func())
+	subscribed chan struct{}
+	acked      []string
+	published  []Message
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		handlers:   make(map[string]func(Message, func())),
+		subscribed: make(chan struct{}, 1),
+	}
+}
+
+func (c *fakeClient) Subscribe(topic string, _ byte, handler func(Message, func())) error {
+	if c.subscribeErr != nil {
+		return c.subscribeErr
+	}
+	c.mu.Lock()
+	c.handlers[topic] = handler
+	c.mu.Unlock()
+	c.subscribed <- struct{}{}
+	return nil
+}
+
+func (c *fakeClient) Unsubscribe(topic string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.handlers, topic)
+	return nil
+}
+
+func (c *fakeClient) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.published = append(c.published, Message{Topic: topic, QoS: qos, Retained: retained, Payload: payload})
+	return nil
+}
+
+// deliver calls the handler of a topic
+func (c *fakeClient) deliver(topic string, qos byte, payload string) {
+	c.mu.Lock()
+	handler := c.handlers[topic]
+	c.mu.Unlock()
+
+	handler(Message{Topic: topic, QoS: qos, Payload: []byte(payload)}, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.acked = append(c.acked, payload)
+	})
+}
+
+func (c *fakeClient) acknowledged() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.acked...)
+}
+
+func (c *fakeClient) subscriptions() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.handlers)
+}
+
+func awaitSubscription(t *testing.T, client *fakeClient) {
+	t.Helper()
+	select {
+	case <-client.subscribed:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "timeout waiting for the subscription")
+	}
+}
+
+func TestFromTopic(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	client := newFakeClient()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	observe := FromTopic(ctx, client, "sensors/+/temperature", AtLeastOnce).Observe()
+	awaitSubscription(t, client)
+	go func() {
+		client.deliver("sensors/+/temperature", AtMostOnce, "a")
+		client.deliver("sensors/+/temperature", AtLeastOnce, "b")
+	}()
+
+	first := <-observe
+	assert.Equal(t, "a", string(first.V.(Message).Payload))
+	second := <-observe
+	assert.Equal(t, "b", string(second.V.(Message).Payload))
+	assert.Equal(t, []string{"a"}, client.acknowledged())
+	second.Ack()
+	assert.Equal(t, []string{"a", "b"}, client.acknowledged())
+
+	cancel()
+	for range observe {
+	}
+	assert.Eventually(t, func() bool {
+		return client.subscriptions() == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestFromTopic_AutoAck(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	client := newFakeClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	observe := FromTopic(ctx, client, "sensors", ExactlyOnce, WithAutoAck()).Observe()
+	awaitSubscription(t, client)
+	go client.deliver("sensors", ExactlyOnce, "a")
+
+	<-observe
+	assert.Eventually(t, func() bool {
+		return len(client.acknowledged()) == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestFromTopic_SubscribeError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	client := newFakeClient()
+	client.subscribeErr = errMQTT
+
+	rxgo.Assert(context.Background(), t, FromTopic(context.Background(), client, "sensors", AtMostOnce),
+		rxgo.IsEmpty(), rxgo.HasError(errMQTT))
+}
+
+func TestToTopic(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	client := newFakeClient()
+
+	err := ToTopic(context.Background(), client, "alerts",
+		rxgo.Just("a", map[string]int{"b": 1}, Message{Topic: "other", QoS: AtMostOnce, Payload: []byte("c")})(),
+		WithQoS(AtLeastOnce), WithRetained())
+	assert.NoError(t, err)
+	assert.Equal(t, []Message{
+		{Topic: "alerts", QoS: AtLeastOnce, Retained: true, Payload: []byte("a")},
+		{Topic: "alerts", QoS: AtLeastOnce, Retained: true, Payload: []byte(`{"b":1}`)},
+		{Topic: "other", QoS: AtMostOnce, Payload: []byte("c")},
+	}, client.published)
+
+	assert.Equal(t, errMQTT, ToTopic(context.Background(), client, "alerts", rxgo.Thrown(errMQTT)))
+}
@@ -0,0 +1,146 @@
+// Package rxnats bridges Subjects and NATS subjects, so that in-process and cross-process publish-subscribe
+// share one programming model.
+//
+// The package does not depend on a NATS client: the bridges are defined against the small Conn interface,
+// which a *nats.Conn of github.com/nats-io/nats.go implements with a small wrapper.
+package rxnats
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/reactivex/rxgo/v2"
+)
+
+// Msg is a NATS message.
+type Msg struct {
+	// Subject is the subject the message was published to.
+	Subject string
+	// Reply is the reply subject of a request, empty otherwise.
+	Reply string
+	// Data is the message payload.
+	Data []byte
+}
+
+// Subscription is a subscription to a NATS subject, such as a *nats.Subscription.
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Conn is the subset of a NATS connection used by the bridges.
+type Conn interface {
+	// Subscribe calls handler for each message published to subject, which may contain wildcards.
+	Subscribe(subject string, handler func(Msg)) (Subscription, error)
+	// Publish publishes data to subject.
+	Publish(subject string, data []byte) error
+}
+
+// Option configures the bridges.
+type Option func(*config)
+
+type config struct {
+	decode func([]byte) (interface{}, error)
+	encode func(interface{}) ([]byte, error)
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		encode: json.Marshal,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithDecoder makes BridgeFromNATS send the values decoded from the message payloads instead of the Msg
+// values.
+func WithDecoder(decode func(data []byte) (interface{}, error)) Option {
+	return func(c *config) {
+		c.decode = decode
+	}
+}
+
+// WithEncoder sets the encoding of the values published by BridgeToNATS, json.Marshal by default.
+func WithEncoder(encode func(interface{}) ([]byte, error)) Option {
+	return func(c *config) {
+		c.encode = encode
+	}
+}
+
+// BridgeFromNATS mirrors the messages published to a NATS subject into a Subject, until the returned
+// Disposable is called. The messages are sent as Msg values, or decoded with WithDecoder, a decoding error
+// being sent as an error item, which does not terminate the Subject.
+//
+// The messages are sent from the goroutine of the NATS subscription: subscribers of the Subject blocking it
+// make the subscription a slow consumer, so they should use the Drop back pressure strategy or a buffer.
+func BridgeFromNATS(conn Conn, subject string, target rxgo.ISubject, opts ...Option) (rxgo.Disposable, error) {
+	c := newConfig(opts)
+	sub, err := conn.Subscribe(subject, func(msg Msg) {
+		if c.decode == nil {
+			target.Next(msg)
+			return
+		}
+		v, err := c.decode(msg.Data)
+		if err != nil {
+			target.NextItem(rxgo.Error(err))
+			return
+		}
+		target.Next(v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = sub.Unsubscribe()
+	}, nil
+}
+
+// BridgeToNATS publishes the items of an Observable, such as a subscription of a Subject, to a NATS subject,
+// until the Observable completes, the context is done or a publication fails. Msg values are published with
+// their data, []byte and string values as is, and other values encoded with the encoder, JSON by default. An
+// error emitted by the Observable is returned.
+//
+// As NATS clients buffer the publications, the connection should be flushed once the function returned to
+// make sure the last messages were sent.
+func BridgeToNATS(ctx context.Context, conn Conn, subject string, observable rxgo.Observable, opts ...Option) error {
+	c := newConfig(opts)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	observe := observable.Observe(rxgo.WithContext(ctx))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-observe:
+			if !ok {
+				return nil
+			}
+			if item.Error() {
+				return item.E
+			}
+			data, err := c.toData(item.V)
+			if err != nil {
+				return err
+			}
+			if err := conn.Publish(subject, data); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toData converts a value to a message payload
+func (c config) toData(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case Msg:
+		return v.Data, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return c.encode(v)
+	}
+}
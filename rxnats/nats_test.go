@@ -0,0 +1,173 @@
+package rxnats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+var errNATS = errors.New("nats")
+
+// fakeConn is an in-memory server delivering the messages synchronously to the handlers of their subject
+type fakeConn struct {
+	mu       sync.Mutex
+	handlers map[string]map[*fakeSubscription]func(Msg)
+}
+
+type fakeSubscription struct {
+	conn    *fakeConn
+	subject string
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{handlers: make(map[string]map[*fakeSubscription]func(Msg))}
+}
+
+func (c *fakeConn) Subscribe(subject string, handler func(Msg)) (Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub := &fakeSubscription{conn: c, subject: subject}
+	if c.handlers[subject] == nil {
+		c.handlers[subject] = make(map[*fakeSubscription]func(Msg))
+	}
+	c.handlers[subject][sub] = handler
+	return sub, nil
+}
+
+func (c *fakeConn) Publish(subject string, data []byte) error {
+	c.mu.Lock()
+	handlers := make([]func(Msg), 0)
+	for _, handler := range c.handlers[subject] {
+		handlers = append(handlers, handler)
+	}
+	c.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(Msg{Subject: subject, Data: data})
+	}
+	return nil
+}
+
+func (s *fakeSubscription) Unsubscribe() error {
+	s.conn.mu.Lock()
+	defer s.conn.mu.Unlock()
+
+	delete(s.conn.handlers[s.subject], s)
+	return nil
+}
+
+// collect observes a subscription, returning its items once the subject completed
+func collect(obs rxgo.Observable) <-chan []rxgo.Item {
+	observe := obs.Observe()
+	res := make(chan []rxgo.Item, 1)
+	go func() {
+		items := make([]rxgo.Item, 0)
+		for item := range observe {
+			items = append(items, item)
+		}
+		res <- items
+	}()
+	return res
+}
+
+func TestBridgeFromNATS(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	conn := newFakeConn()
+	subject := rxgo.NewSubject()
+	sub, obs := subject.Subscribe()
+	defer sub.Unsubscribe()
+	items := collect(obs)
+
+	dispose, err := BridgeFromNATS(conn, "orders", subject)
+	assert.NoError(t, err)
+	assert.NoError(t, conn.Publish("orders", []byte("a")))
+	dispose()
+	assert.NoError(t, conn.Publish("orders", []byte("b")))
+	subject.Complete()
+
+	assert.Equal(t, []rxgo.Item{rxgo.Of(Msg{Subject: "orders", Data: []byte("a")})}, <-items)
+}
+
+func TestBridgeFromNATS_Decoder(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	conn := newFakeConn()
+	subject := rxgo.NewSubject(rxgo.WithErrorStrategy(rxgo.ContinueOnError))
+	sub, obs := subject.Subscribe()
+	defer sub.Unsubscribe()
+	items := collect(obs)
+
+	dispose, err := BridgeFromNATS(conn, "orders", subject, WithDecoder(func(data []byte) (interface{}, error) {
+		if len(data) == 0 {
+			return nil, errNATS
+		}
+		return string(data), nil
+	}))
+	assert.NoError(t, err)
+	defer dispose()
+	assert.NoError(t, conn.Publish("orders", []byte("a")))
+	assert.NoError(t, conn.Publish("orders", nil))
+	assert.NoError(t, conn.Publish("orders", []byte("b")))
+	subject.Complete()
+
+	assert.Equal(t, []rxgo.Item{rxgo.Of("a"), rxgo.Error(errNATS), rxgo.Of("b")}, <-items)
+}
+
+func TestBridgeToNATS(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	conn := newFakeConn()
+	received := make([]string, 0)
+	_, err := conn.Subscribe("orders", func(msg Msg) {
+		received = append(received, string(msg.Data))
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, BridgeToNATS(context.Background(), conn, "orders", rxgo.Just("a", map[string]int{"b": 1}, Msg{Data: []byte("c")})()))
+	assert.Equal(t, []string{"a", `{"b":1}`, "c"}, received)
+
+	assert.Equal(t, errNATS, BridgeToNATS(context.Background(), conn, "orders", rxgo.Thrown(errNATS)))
+}
+
+func TestBridge_RoundTrip(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	conn := newFakeConn()
+	local := rxgo.NewSubject()
+	remote := rxgo.NewSubject()
+	remoteSub, remoteObs := remote.Subscribe()
+	defer remoteSub.Unsubscribe()
+	remoteItems := collect(remoteObs)
+
+	dispose, err := BridgeFromNATS(conn, "orders", remote, WithDecoder(func(data []byte) (interface{}, error) {
+		var v map[string]int
+		err := json.Unmarshal(data, &v)
+		return v, err
+	}))
+	assert.NoError(t, err)
+	defer dispose()
+
+	localSub, localObs := local.Subscribe()
+	done := make(chan error)
+	go func() {
+		done <- BridgeToNATS(context.Background(), conn, "orders", localObs)
+	}()
+	assert.NoError(t, local.AwaitSubscribers(context.Background(), 1))
+	local.Next(map[string]int{"a": 1})
+	local.Complete()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "timeout waiting for the bridge")
+	}
+	localSub.Unsubscribe()
+	remote.Complete()
+
+	assert.Equal(t, []rxgo.Item{rxgo.Of(map[string]int{"a": 1})}, <-remoteItems)
+}
@@ -0,0 +1,231 @@
+// Package rxredis bridges Observables and Redis, consuming Pub/Sub channels and streams.
+//
+// The package does not depend on a Redis client: the sources are defined against small interfaces, which a
+// client of github.com/redis/go-redis implements with a small wrapper.
+package rxredis
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+)
+
+const (
+	defaultCount = 100
+	defaultBlock = 5 * time.Second
+)
+
+// Message is a message received from a Pub/Sub channel.
+type Message struct {
+	// Channel is the channel the message was published to.
+	Channel string
+	// Pattern is the subscription pattern matching the channel, empty for a channel subscription.
+	Pattern string
+	Payload string
+}
+
+// PubSub is a subscription to Pub/Sub channels, such as a *redis.PubSub.
+type PubSub interface {
+	// ReceiveMessage returns the next message, blocking until one is received or the context is done.
+	ReceiveMessage(ctx context.Context) (Message, error)
+	Close() error
+}
+
+// StreamMessage is an entry of a stream.
+type StreamMessage struct {
+	Stream string
+	ID     string
+	Values map[string]interface{}
+}
+
+// StreamReader reads a stream with XREAD.
+type StreamReader interface {
+	// XRead returns at most count entries of the stream following id, blocking at most block until one is
+	// added. It returns no entry once block elapsed.
+	XRead(ctx context.Context, stream, id string, count int64, block time.Duration) ([]StreamMessage, error)
+}
+
+// GroupReader reads a stream as a member of a consumer group, with XREADGROUP and XACK.
+type GroupReader interface {
+	// XReadGroup returns at most count entries of the stream for the consumer of the group, the new entries
+	// with id ">", blocking at most block until one is added, or the pending entries of the consumer following
+	// id otherwise. It returns no entry once block elapsed.
+	XReadGroup(ctx context.Context, group, consumer, stream, id string, count int64, block time.Duration) ([]StreamMessage, error)
+	// XAck acknowledges entries, removing them from the pending entries of the group.
+	XAck(ctx context.Context, stream, group string, ids ...string) error
+}
+
+// Option configures the stream sources.
+type Option func(*config)
+
+type config struct {
+	count   int64
+	block   time.Duration
+	startID string
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		count:   defaultCount,
+		block:   defaultBlock,
+		startID: "$",
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithCount sets the maximum number of entries read at once, 100 by default.
+func WithCount(count int64) Option {
+	return func(c *config) {
+		c.count = count
+	}
+}
+
+// WithBlock sets the maximum time a read waits for new entries, 5s by default.
+func WithBlock(block time.Duration) Option {
+	return func(c *config) {
+		c.block = block
+	}
+}
+
+// WithStartID sets the ID after which FromStream reads the stream, "$" by default for the entries added once
+// subscribed. "0" reads the stream from its start.
+func WithStartID(id string) Option {
+	return func(c *config) {
+		c.startID = id
+	}
+}
+
+// FromPubSub creates an Observable emitting the messages of a Pub/Sub subscription as Message values, until the
+// context is done or a receive fails, in which case the error is emitted. The subscription is closed once the
+// Observable terminates. As Pub/Sub does not keep the messages, those published while the Observable is not
+// consumed fast enough are lost once the client buffer is full.
+func FromPubSub(ctx context.Context, pubsub PubSub) rxgo.Observable {
+	return rxgo.CreateWithEmitter(func(ctx context.Context, emitter rxgo.Emitter) {
+		defer pubsub.Close()
+		for {
+			msg, err := pubsub.ReceiveMessage(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					emitter.Error(err)
+				}
+				return
+			}
+			emitter.Next(msg)
+		}
+	}, rxgo.WithContext(ctx))
+}
+
+// FromStream creates an Observable emitting the entries added to a stream as StreamMessage values, read with
+// XREAD after the start ID, until the context is done or a read fails, in which case the error is emitted.
+func FromStream(ctx context.Context, reader StreamReader, stream string, opts ...Option) rxgo.Observable {
+	c := newConfig(opts)
+
+	return rxgo.CreateWithEmitter(func(ctx context.Context, emitter rxgo.Emitter) {
+		lastID := c.startID
+		for {
+			msgs, err := reader.XRead(ctx, stream, lastID, c.count, c.block)
+			if err != nil {
+				if ctx.Err() == nil {
+					emitter.Error(err)
+				}
+				return
+			}
+			for _, msg := range msgs {
+				emitter.Next(msg)
+				lastID = msg.ID
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}, rxgo.WithContext(ctx))
+}
+
+// FromStreamGroup creates an Observable emitting the entries of a stream delivered to a consumer of a consumer
+// group as StreamMessage values, until the context is done or a read fails, in which case the error is
+// emitted.
+//
+// The entries still pending for the consumer, delivered before a restart but never acknowledged, are emitted
+// first, followed by the new entries. Acknowledging an item with Ack acknowledges its entry with XACK; the
+// entries left unacknowledged are delivered again once the consumer restarts, or claimed by another consumer,
+// giving at-least-once processing. A failed acknowledgement is emitted as an error.
+func FromStreamGroup(ctx context.Context, reader GroupReader, stream, group, consumer string, opts ...Option) rxgo.Observable {
+	c := newConfig(opts)
+
+	return rxgo.CreateWithEmitter(func(ctx context.Context, emitter rxgo.Emitter) {
+		readCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		acks := &acknowledger{ctx: ctx, reader: reader, stream: stream, group: group, stopRead: cancel}
+
+		// the pending entries of the consumer first, then the new ones
+		id := "0"
+		for {
+			msgs, err := reader.XReadGroup(readCtx, group, consumer, stream, id, c.count, c.block)
+			if ackErr := acks.failure(); ackErr != nil {
+				emitter.Error(ackErr)
+				return
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					emitter.Error(err)
+				}
+				return
+			}
+			if id != ">" {
+				if len(msgs) == 0 {
+					id = ">"
+				} else {
+					id = msgs[len(msgs)-1].ID
+				}
+			}
+			for _, msg := range msgs {
+				emitter.NextItem(rxgo.Of(msg).WithAck(acks.ack(msg.ID)))
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}, rxgo.WithContext(ctx))
+}
+
+// acknowledger acknowledges the entries of FromStreamGroup
+type acknowledger struct {
+	ctx    context.Context
+	reader GroupReader
+	stream string
+	group  string
+	// stopRead interrupts the read loop once an acknowledgement failed
+	stopRead func()
+
+	mu  sync.Mutex
+	err error
+}
+
+// ack returns the acknowledgement of an entry
+func (a *acknowledger) ack(id string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if err := a.reader.XAck(a.ctx, a.stream, a.group, id); err != nil && a.ctx.Err() == nil {
+				// reported by the read loop, an acknowledgement being possibly called by the observer goroutine
+				a.mu.Lock()
+				a.err = err
+				a.mu.Unlock()
+				a.stopRead()
+			}
+		})
+	}
+}
+
+// failure returns the error of a failed acknowledgement, if any
+func (a *acknowledger) failure() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.err
+}
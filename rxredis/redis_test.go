@@ -0,0 +1,245 @@
+package rxredis
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+var errRedis = errors.New("redis")
+
+type fakePubSub struct {
+	messages chan Message
+	err      error
+	closed   chan struct{}
+}
+
+func (p *fakePubSub) ReceiveMessage(ctx context.Context) (Message, error) {
+	select {
+	case msg, ok := <-p.messages:
+		if !ok {
+			return Message{}, p.err
+		}
+		return msg, nil
+	case <-ctx.Done():
+		return Message{}, ctx.Err()
+	}
+}
+
+func (p *fakePubSub) Close() error {
+	close(p.closed)
+	return nil
+}
+
+// fakeStream is a stream with numeric IDs, read by a single consumer group
+type fakeStream struct {
+	mu      sync.Mutex
+	entries []StreamMessage
+	added   chan struct{}
+	cursor  int
+	pending map[string]bool
+	acked   []string
+	ackErr  error
+}
+
+func newFakeStream() *fakeStream {
+	return &fakeStream{added: make(chan struct{}), pending: make(map[string]bool)}
+}
+
+func (s *fakeStream) add(value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := strconv.Itoa(len(s.entries) + 1)
+	s.entries = append(s.entries, StreamMessage{Stream: "events", ID: id, Values: map[string]interface{}{"v": value}})
+	close(s.added)
+	s.added = make(chan struct{})
+}
+
+// await returns the entries following index, waiting at most block for one
+func (s *fakeStream) await(ctx context.Context, index int, count int64, block time.Duration) ([]StreamMessage, int) {
+	timer := time.NewTimer(block)
+	defer timer.Stop()
+	for {
+		s.mu.Lock()
+		if index < len(s.entries) {
+			end := index + int(count)
+			if end > len(s.entries) {
+				end = len(s.entries)
+			}
+			msgs := append([]StreamMessage(nil), s.entries[index:end]...)
+			s.mu.Unlock()
+			return msgs, end
+		}
+		added := s.added
+		s.mu.Unlock()
+
+		select {
+		case <-added:
+		case <-timer.C:
+			return nil, index
+		case <-ctx.Done():
+			return nil, index
+		}
+	}
+}
+
+func (s *fakeStream) XRead(ctx context.Context, _, id string, count int64, block time.Duration) ([]StreamMessage, error) {
+	index, _ := strconv.Atoi(id)
+	if id == "$" {
+		s.mu.Lock()
+		index = len(s.entries)
+		s.mu.Unlock()
+	}
+	msgs, _ := s.await(ctx, index, count, block)
+	return msgs, ctx.Err()
+}
+
+func (s *fakeStream) XReadGroup(ctx context.Context, _, _, _, id string, count int64, block time.Duration) ([]StreamMessage, error) {
+	if id != ">" {
+		after, _ := strconv.Atoi(id)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		ids := make([]int, 0)
+		for pending := range s.pending {
+			if n, _ := strconv.Atoi(pending); n > after {
+				ids = append(ids, n)
+			}
+		}
+		sort.Ints(ids)
+		msgs := make([]StreamMessage, 0)
+		for _, n := range ids {
+			msgs = append(msgs, s.entries[n-1])
+		}
+		return msgs, nil
+	}
+
+	s.mu.Lock()
+	cursor := s.cursor
+	s.mu.Unlock()
+	msgs, next := s.await(ctx, cursor, count, block)
+	s.mu.Lock()
+	s.cursor = next
+	for _, msg := range msgs {
+		s.pending[msg.ID] = true
+	}
+	s.mu.Unlock()
+	return msgs, ctx.Err()
+}
+
+func (s *fakeStream) XAck(_ context.Context, _, _ string, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ackErr != nil {
+		return s.ackErr
+	}
+	for _, id := range ids {
+		delete(s.pending, id)
+		s.acked = append(s.acked, id)
+	}
+	return nil
+}
+
+func (s *fakeStream) acknowledged() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.acked...)
+}
+
+func receive(t *testing.T, observe <-chan rxgo.Item, n int) []rxgo.Item {
+	t.Helper()
+	items := make([]rxgo.Item, 0, n)
+	for i := 0; i < n; i++ {
+		select {
+		case item := <-observe:
+			items = append(items, item)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "timeout waiting for items")
+		}
+	}
+	return items
+}
+
+func ids(items []rxgo.Item) []string {
+	res := make([]string, 0, len(items))
+	for _, item := range items {
+		res = append(res, item.V.(StreamMessage).ID)
+	}
+	return res
+}
+
+func TestFromPubSub(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	pubsub := &fakePubSub{messages: make(chan Message, 2), err: errRedis, closed: make(chan struct{})}
+	pubsub.messages <- Message{Channel: "news", Payload: "a"}
+	close(pubsub.messages)
+
+	rxgo.Assert(context.Background(), t, FromPubSub(context.Background(), pubsub),
+		rxgo.HasItems(Message{Channel: "news", Payload: "a"}), rxgo.HasError(errRedis))
+	select {
+	case <-pubsub.closed:
+	case <-time.After(time.Second):
+		assert.Fail(t, "subscription not closed")
+	}
+}
+
+func TestFromStream(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	stream := newFakeStream()
+	stream.add("a")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	observe := FromStream(ctx, stream, "events", WithStartID("0"), WithCount(1), WithBlock(time.Millisecond)).Observe()
+	stream.add("b")
+	assert.Equal(t, []string{"1", "2"}, ids(receive(t, observe, 2)))
+	stream.add("c")
+	assert.Equal(t, []string{"3"}, ids(receive(t, observe, 1)))
+}
+
+func TestFromStreamGroup(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	stream := newFakeStream()
+	stream.add("a")
+	stream.add("b")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	observe := FromStreamGroup(ctx, stream, "events", "workers", "w1", WithBlock(time.Millisecond)).Observe()
+	items := receive(t, observe, 2)
+	assert.Equal(t, []string{"1", "2"}, ids(items))
+	items[1].Ack()
+	assert.Equal(t, []string{"2"}, stream.acknowledged())
+	cancel()
+	for range observe {
+	}
+
+	// the unacknowledged entry is delivered again after a restart, before the new ones
+	stream.add("c")
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	observe = FromStreamGroup(ctx, stream, "events", "workers", "w1", WithBlock(time.Millisecond)).Observe()
+	assert.Equal(t, []string{"1", "3"}, ids(receive(t, observe, 2)))
+}
+
+func TestFromStreamGroup_AckError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	stream := newFakeStream()
+	stream.add("a")
+	stream.ackErr = errRedis
+
+	observe := FromStreamGroup(context.Background(), stream, "events", "workers", "w1", WithBlock(time.Hour)).Observe()
+	items := receive(t, observe, 1)
+	items[0].Ack()
+	items = receive(t, observe, 1)
+	assert.Equal(t, errRedis, items[0].E)
+}
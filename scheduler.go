@@ -0,0 +1,123 @@
+package rxgo
+
+import "sync"
+
+// Scheduler runs tasks on goroutines it controls. It is used by ObserveOn and SubscribeOn to choose the
+// goroutines delivering items and running sources, and by WithScheduler to choose the goroutines
+// running callbacks.
+type Scheduler interface {
+	Schedule(task func())
+}
+
+// Immediate is a Scheduler running tasks on the calling goroutine.
+var Immediate Scheduler = immediateScheduler{}
+
+type immediateScheduler struct{}
+
+func (immediateScheduler) Schedule(task func()) {
+	task()
+}
+
+// PoolScheduler is a Scheduler running tasks on a fixed number of goroutines.
+// With a single goroutine, tasks run one at a time in submission order.
+type PoolScheduler struct {
+	mu      sync.RWMutex
+	tasks   chan func()
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewEventLoopScheduler creates a Scheduler running tasks one at a time, in submission order, on a single
+// goroutine. Schedule blocks once queueSize tasks are waiting.
+func NewEventLoopScheduler(queueSize int) *PoolScheduler {
+	return NewWorkerPoolScheduler(1, queueSize)
+}
+
+// NewWorkerPoolScheduler creates a Scheduler running tasks on the given number of goroutines.
+// Schedule blocks once queueSize tasks are waiting.
+func NewWorkerPoolScheduler(workers, queueSize int) *PoolScheduler {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	s := &PoolScheduler{
+		tasks: make(chan func(), queueSize),
+	}
+	s.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer s.wg.Done()
+			for task := range s.tasks {
+				task()
+			}
+		}()
+	}
+	return s
+}
+
+// Schedule queues a task. Once the scheduler is stopped, the task runs on the calling goroutine.
+func (s *PoolScheduler) Schedule(task func()) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.stopped {
+		task()
+		return
+	}
+	s.tasks <- task
+}
+
+// Stop stops the goroutines once the queued tasks have run, and waits for them.
+func (s *PoolScheduler) Stop() {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.tasks)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// runOn runs a task on the scheduler and waits for its completion
+func runOn(scheduler Scheduler, task func()) {
+	done := make(chan struct{})
+	scheduler.Schedule(func() {
+		defer close(done)
+		task()
+	})
+	<-done
+}
+
+// on returns a function running f on the scheduler, f itself without scheduler
+func (f NextFunc) on(scheduler Scheduler) NextFunc {
+	if scheduler == nil {
+		return f
+	}
+	return func(i interface{}) {
+		runOn(scheduler, func() { f(i) })
+	}
+}
+
+// on returns a function running f on the scheduler, f itself without scheduler
+func (f ErrFunc) on(scheduler Scheduler) ErrFunc {
+	if scheduler == nil {
+		return f
+	}
+	return func(err error) {
+		runOn(scheduler, func() { f(err) })
+	}
+}
+
+// on returns a function running f on the scheduler, f itself without scheduler
+func (f CompletedFunc) on(scheduler Scheduler) CompletedFunc {
+	if scheduler == nil {
+		return f
+	}
+	return func() {
+		runOn(scheduler, f)
+	}
+}
@@ -0,0 +1,106 @@
+package rxgo
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// countingScheduler counts the tasks run by a scheduler
+type countingScheduler struct {
+	scheduler Scheduler
+	tasks     int32
+	running   int32
+}
+
+func (s *countingScheduler) Schedule(task func()) {
+	atomic.AddInt32(&s.tasks, 1)
+	s.scheduler.Schedule(func() {
+		atomic.AddInt32(&s.running, 1)
+		defer atomic.AddInt32(&s.running, -1)
+		task()
+	})
+}
+
+func (s *countingScheduler) inTask() bool {
+	return atomic.LoadInt32(&s.running) > 0
+}
+
+func TestImmediate(t *testing.T) {
+	ran := false
+	Immediate.Schedule(func() {
+		ran = true
+	})
+	assert.True(t, ran)
+}
+
+func TestEventLoopScheduler(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewEventLoopScheduler(10)
+
+	got := make([]int, 0)
+	var wg sync.WaitGroup
+	wg.Add(100)
+	for i := 0; i < 100; i++ {
+		i := i
+		scheduler.Schedule(func() {
+			defer wg.Done()
+			got = append(got, i)
+		})
+	}
+	wg.Wait()
+	scheduler.Stop()
+
+	for i, v := range got {
+		assert.Equal(t, i, v)
+	}
+
+	// tasks scheduled after stop run on the calling goroutine
+	ran := false
+	scheduler.Schedule(func() {
+		ran = true
+	})
+	assert.True(t, ran)
+}
+
+func TestWorkerPoolScheduler(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	scheduler := NewWorkerPoolScheduler(4, 0)
+
+	var count int32
+	for i := 0; i < 100; i++ {
+		scheduler.Schedule(func() {
+			atomic.AddInt32(&count, 1)
+		})
+	}
+	// queued tasks run before the workers stop
+	scheduler.Stop()
+	assert.Equal(t, int32(100), count)
+}
+
+func TestWithScheduler(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	pool := NewEventLoopScheduler(0)
+	defer pool.Stop()
+	scheduler := &countingScheduler{scheduler: pool}
+
+	var outside int32
+	check := func() {
+		if !scheduler.inTask() {
+			atomic.AddInt32(&outside, 1)
+		}
+	}
+	<-Just(1, 2, errFoo)().ForEach(func(interface{}) {
+		check()
+	}, func(error) {
+		check()
+	}, func() {
+		check()
+	}, WithScheduler(scheduler))
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&outside))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&scheduler.tasks))
+}
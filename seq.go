@@ -0,0 +1,81 @@
+//go:build go1.23
+// +build go1.23
+
+package rxgo
+
+import (
+	"context"
+	"iter"
+)
+
+// KeyValue is the item emitted by FromSeq2 for each pair of the sequence.
+type KeyValue struct {
+	K interface{}
+	V interface{}
+}
+
+// FromSeq creates a cold Observable emitting the values of a sequence.
+// The sequence is iterated again for each observer.
+func FromSeq[T any](seq iter.Seq[T], opts ...Option) Observable {
+	return &ObservableImpl{
+		iterable: newFactoryIterable(func(propagatedOptions ...Option) <-chan Item {
+			option := parseOptions(append(opts, propagatedOptions...)...)
+			next := option.buildChannel()
+			ctx := option.buildContext(emptyContext)
+
+			go func() {
+				defer close(next)
+				for v := range seq {
+					if !Of(v).SendContext(ctx, next) {
+						return
+					}
+				}
+			}()
+			return next
+		}),
+	}
+}
+
+// FromSeq2 creates a cold Observable emitting a KeyValue for each pair of a sequence.
+// The sequence is iterated again for each observer.
+func FromSeq2[K, V any](seq iter.Seq2[K, V], opts ...Option) Observable {
+	return FromSeq(func(yield func(KeyValue) bool) {
+		for k, v := range seq {
+			if !yield(KeyValue{K: k, V: v}) {
+				return
+			}
+		}
+	}, opts...)
+}
+
+// seqObservable holds the methods of Observable built with Go 1.23 or later.
+type seqObservable interface {
+	Seq(ctx context.Context) iter.Seq[Item]
+}
+
+// Seq returns a sequence over the items of the Observable, errors included.
+// Breaking out of the iteration cancels the observation.
+func (o *ObservableImpl) Seq(ctx context.Context) iter.Seq[Item] {
+	return ToSeq(ctx, o)
+}
+
+// ToSeq returns a sequence over the items of an Iterable, errors included, such as a Single.
+// Breaking out of the iteration cancels the observation.
+func ToSeq(ctx context.Context, iterable Iterable) iter.Seq[Item] {
+	return func(yield func(Item) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		observe := iterable.Observe(WithContext(ctx))
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-observe:
+				if !ok || !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !go1.23
+// +build !go1.23
+
+package rxgo
+
+// seqObservable holds the methods of Observable built with Go 1.23 or later, none before.
+type seqObservable interface{}
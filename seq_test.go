@@ -0,0 +1,65 @@
+//go:build go1.23
+// +build go1.23
+
+package rxgo
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func Test_FromSeq(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := FromSeq(slices.Values([]int{1, 2, 3}))
+	Assert(context.Background(), t, obs, HasItems(1, 2, 3), HasNoError())
+	Assert(context.Background(), t, obs, HasItems(1, 2, 3), HasNoError())
+}
+
+func Test_FromSeq2(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := FromSeq2(maps.All(map[string]int{"a": 1}))
+	Assert(context.Background(), t, obs, HasItems(KeyValue{K: "a", V: 1}), HasNoError())
+}
+
+func Test_ToSeq(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	got := make([]interface{}, 0)
+	for item := range ToSeq(context.Background(), testObservable(context.Background(), 1, 2, errFoo)) {
+		if item.Error() {
+			assert.Equal(t, errFoo, item.E)
+			continue
+		}
+		got = append(got, item.V)
+	}
+	assert.Equal(t, []interface{}{1, 2}, got)
+}
+
+func Test_ToSeq_Break(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	got := make([]interface{}, 0)
+	for item := range ToSeq(context.Background(), Range(0, 1000)) {
+		got = append(got, item.V)
+		if len(got) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []interface{}{0, 1}, got)
+}
+
+func Test_Observable_Seq(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	var obs Observable = Range(0, 1000)
+	got := make([]interface{}, 0)
+	for item := range obs.Seq(context.Background()) {
+		got = append(got, item.V)
+		if len(got) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []interface{}{0, 1}, got)
+}
@@ -0,0 +1,98 @@
+package rxgo
+
+import (
+	"context"
+	"sync"
+)
+
+// The side outputs of the operators.
+const (
+	// LateOutput receives the late items dropped by WindowWithEventTime.
+	LateOutput = "late"
+	// DuplicateOutput receives the items suppressed by DeduplicateWithin.
+	DuplicateOutput = "duplicate"
+	// DeadLetterOutput receives a DeadLetter for each item skipped by FilterE, SkipWhileE or TakeWhileE because
+	// of a predicate error, with DeadLetterOnPredicateError.
+	DeadLetterOutput = "deadletter"
+)
+
+// sideOutputs are the named side outputs of an operator, each one being a subject created once observed
+type sideOutputs struct {
+	mu       sync.Mutex
+	subjects map[string]*Subject
+}
+
+// observe subscribes to a side output
+func (s *sideOutputs) observe(name string) Observable {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.subjects == nil {
+		s.subjects = make(map[string]*Subject)
+	}
+	subject, exists := s.subjects[name]
+	if !exists {
+		created := newSubject()
+		subject = &created
+		s.subjects[name] = subject
+	}
+	_, obs := subject.Subscribe()
+	return obs
+}
+
+// emit emits an item to a side output, the item being dropped if the side output is not observed
+func (s *sideOutputs) emit(name string, item Item) {
+	s.mu.Lock()
+	subject := s.subjects[name]
+	s.mu.Unlock()
+
+	if subject != nil {
+		subject.NextItem(item)
+	}
+}
+
+// complete completes the side outputs once an observation of the operator terminated, the next observation
+// emitting to new side outputs
+func (s *sideOutputs) complete() {
+	s.mu.Lock()
+	subjects := s.subjects
+	s.subjects = nil
+	s.mu.Unlock()
+
+	for _, subject := range subjects {
+		subject.Complete()
+	}
+}
+
+// fallibleMatch evaluates a PredicateE, handling its error per the policy
+type fallibleMatch struct {
+	apply   PredicateE
+	policy  PredicateErrorPolicy
+	outputs *sideOutputs
+}
+
+// test returns the result of the predicate, and false instead of ok if the item has to be skipped because of an
+// error
+func (m *fallibleMatch) test(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) (match, ok bool) {
+	match, err := m.apply(ctx, item.V)
+	if err == nil {
+		return match, true
+	}
+	switch m.policy {
+	case SkipOnPredicateError:
+	case DeadLetterOnPredicateError:
+		m.outputs.emit(DeadLetterOutput, Of(DeadLetter{Item: item, Reason: err}))
+	default:
+		Error(err).SendContext(ctx, dst)
+		operatorOptions.stop()
+	}
+	return false, false
+}
+
+// withSideOutputs attaches the side outputs of the operator creating an Observable
+func withSideOutputs(obs Observable, outputs *sideOutputs) Observable {
+	if impl, ok := obs.(*ObservableImpl); ok {
+		impl.sideOutputs = outputs
+	}
+	return obs
+}
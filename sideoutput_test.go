@@ -0,0 +1,69 @@
+package rxgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func Test_SideOutput_Late(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 12, 3, 16, 4, 2, 25).WindowWithEventTime(WithDuration(10*time.Second), eventTime,
+		WithDuration(5*time.Second))
+	late := NewTestObserver(t, obs.SideOutput(LateOutput))
+
+	Assert(ctx, t, obs, HasItems(
+		EventTimeWindow{Start: time.Unix(0, 0), End: time.Unix(10, 0), Items: []interface{}{1, 3}},
+		EventTimeWindow{Start: time.Unix(10, 0), End: time.Unix(20, 0), Items: []interface{}{12, 16}},
+		EventTimeWindow{Start: time.Unix(20, 0), End: time.Unix(30, 0), Items: []interface{}{25}},
+	))
+	assert.True(t, late.AwaitDone(time.Second))
+	late.AssertValues(4, 2)
+}
+
+func Test_SideOutput_Duplicate(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := testObservable(ctx, 1, 1, 2, 1).DeduplicateWithin(WithDuration(time.Hour),
+		func(_ context.Context, item interface{}) (interface{}, error) {
+			return item, nil
+		})
+	duplicates := NewTestObserver(t, obs.SideOutput(DuplicateOutput))
+	other := NewTestObserver(t, obs.SideOutput("other"))
+
+	Assert(ctx, t, obs, HasItems(1, 2))
+	assert.True(t, duplicates.AwaitDone(time.Second))
+	duplicates.AssertValues(1, 1)
+	assert.True(t, other.AwaitDone(time.Second))
+	other.AssertValueCount(0)
+}
+
+// Test_SideOutput_Observations verifies each observation of the Observable emits to new side outputs
+func Test_SideOutput_Observations(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := Just(1, 1)().DeduplicateWithin(WithDuration(time.Hour),
+		func(_ context.Context, item interface{}) (interface{}, error) {
+			return item, nil
+		})
+	for i := 0; i < 2; i++ {
+		duplicates := NewTestObserver(t, obs.SideOutput(DuplicateOutput))
+		Assert(context.Background(), t, obs, HasItems(1))
+		assert.True(t, duplicates.AwaitDone(time.Second))
+		duplicates.AssertValues(1)
+	}
+}
+
+func Test_SideOutput_None(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := Just(1)().Map(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i, nil
+	})
+	Assert(context.Background(), t, obs.SideOutput(LateOutput), IsEmpty())
+	Assert(context.Background(), t, obs, HasItems(1))
+}
@@ -0,0 +1,38 @@
+package rxgo
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+// FromSignals creates an Observable emitting the given incoming signals, all of them if none is given, as
+// os.Signal values. Each observer is notified from the time it observes, and the Observable completes once
+// the context is done.
+func FromSignals(ctx context.Context, sig ...os.Signal) Observable {
+	return &ObservableImpl{
+		iterable: newFactoryIterable(func(propagatedOptions ...Option) <-chan Item {
+			option := parseOptions(propagatedOptions...)
+			next := option.buildChannel()
+			ctx := option.buildContext(ctx)
+
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, sig...)
+			go func() {
+				defer close(next)
+				defer signal.Stop(signals)
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case s := <-signals:
+						if !Of(s).SendContext(ctx, next) {
+							return
+						}
+					}
+				}
+			}()
+			return next
+		}),
+	}
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+// +build !windows
+
+package rxgo
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestFromSignals(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	observer := NewTestObserver(t, FromSignals(ctx, syscall.SIGUSR1))
+
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	observer.AwaitCount(1, time.Second)
+	observer.AssertValues(syscall.SIGUSR1)
+
+	cancel()
+	observer.AwaitDone(time.Second)
+	observer.AssertNoErrors()
+}
@@ -0,0 +1,13 @@
+//go:build go1.21
+// +build go1.21
+
+package rxgo
+
+// NextSlice sends the values of a typed slice to all the subscribers of a subject, as a single batch.
+func NextSlice[T any](subject ISubject, values []T) {
+	batch := make([]interface{}, len(values))
+	for i, value := range values {
+		batch[i] = value
+	}
+	subject.NextBatch(batch)
+}
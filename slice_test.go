@@ -0,0 +1,22 @@
+//go:build go1.21
+// +build go1.21
+
+package rxgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextSlice(t *testing.T) {
+	subject := NewSubject()
+
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	NextSlice(subject, []string{"a", "b"})
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("a", "b")
+}
@@ -0,0 +1,294 @@
+package rxgo
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+)
+
+// slidingStat is a statistic over the last values of a stream
+type slidingStat interface {
+	// push adds a value, evicting the oldest one once the window is full, and returns the statistic
+	push(v float64) float64
+}
+
+// toFloat64 converts a numeric value
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// slidingStat emits a statistic over the last window numeric items for each item
+func (o *ObservableImpl) slidingStat(window int, stat func() slidingStat, opts ...Option) Observable {
+	if window <= 0 {
+		return Thrown(IllegalInputError{error: "window must be positive"})
+	}
+	return observable(o.parent, o, func() operator {
+		return &slidingStatOperator{stat: stat()}
+	}, true, false, opts...)
+}
+
+// slidingStatOperator emits a statistic over the last values for each value
+type slidingStatOperator struct {
+	stat slidingStat
+}
+
+func (op *slidingStatOperator) next(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	v, ok := toFloat64(item.V)
+	if !ok {
+		Error(IllegalInputError{error: fmt.Sprintf("expected type: float or int, got: %T", item.V)}).SendContext(ctx, dst)
+		operatorOptions.stop()
+		return
+	}
+	Of(op.stat.push(v)).SendContext(ctx, dst)
+}
+
+func (op *slidingStatOperator) err(ctx context.Context, item Item, dst chan<- Item, operatorOptions operatorOptions) {
+	defaultErrorFuncOperator(ctx, item, dst, operatorOptions)
+}
+
+func (op *slidingStatOperator) end(_ context.Context, _ chan<- Item) {
+}
+
+func (op *slidingStatOperator) gatherNext(_ context.Context, _ Item, _ chan<- Item, _ operatorOptions) {
+}
+
+// movingAverage maintains the mean of the last values in O(1)
+type movingAverage struct {
+	values []float64
+	next   int
+	count  int
+	sum    float64
+}
+
+func newMovingAverage(window int) *movingAverage {
+	return &movingAverage{values: make([]float64, window)}
+}
+
+func (m *movingAverage) push(v float64) float64 {
+	if m.count == len(m.values) {
+		m.sum -= m.values[m.next]
+	} else {
+		m.count++
+	}
+	m.values[m.next] = v
+	m.next = (m.next + 1) % len(m.values)
+	m.sum += v
+	return m.sum / float64(m.count)
+}
+
+// windowEntry is a value of the window along with its position in the stream
+type windowEntry struct {
+	value float64
+	seq   uint64
+}
+
+// movingExtremum maintains the minimum or the maximum of the last values in amortized O(1), with a deque of
+// the values which may still become the extremum
+type movingExtremum struct {
+	window uint64
+	seq    uint64
+	// before returns whether a value supersedes another one
+	before  func(a, b float64) bool
+	entries []windowEntry
+}
+
+func newMovingMin(window int) *movingExtremum {
+	return &movingExtremum{
+		window: uint64(window),
+		before: func(a, b float64) bool {
+			return a <= b
+		},
+	}
+}
+
+func newMovingMax(window int) *movingExtremum {
+	return &movingExtremum{
+		window: uint64(window),
+		before: func(a, b float64) bool {
+			return a >= b
+		},
+	}
+}
+
+func (m *movingExtremum) push(v float64) float64 {
+	for len(m.entries) > 0 && m.before(v, m.entries[len(m.entries)-1].value) {
+		m.entries = m.entries[:len(m.entries)-1]
+	}
+	m.entries = append(m.entries, windowEntry{value: v, seq: m.seq})
+	m.seq++
+	if m.entries[0].seq+m.window < m.seq {
+		m.entries = m.entries[1:]
+	}
+	return m.entries[0].value
+}
+
+// entryHeap is a heap of window entries
+type entryHeap struct {
+	entries []windowEntry
+	less    func(a, b float64) bool
+}
+
+func (h *entryHeap) Len() int {
+	return len(h.entries)
+}
+
+func (h *entryHeap) Less(i, j int) bool {
+	return h.less(h.entries[i].value, h.entries[j].value)
+}
+
+func (h *entryHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+}
+
+func (h *entryHeap) Push(x interface{}) {
+	h.entries = append(h.entries, x.(windowEntry))
+}
+
+func (h *entryHeap) Pop() interface{} {
+	last := h.entries[len(h.entries)-1]
+	h.entries = h.entries[:len(h.entries)-1]
+	return last
+}
+
+func (h *entryHeap) top() windowEntry {
+	return h.entries[0]
+}
+
+// movingPercentile maintains a percentile of the last values in amortized O(log n): the values up to the
+// percentile are held by a max-heap, the others by a min-heap. The evicted values are removed lazily, once
+// they reach the top of their heap.
+type movingPercentile struct {
+	window  int
+	p       float64
+	seq     uint64
+	queue   []windowEntry
+	low     *entryHeap
+	high    *entryHeap
+	lowSize int
+	// inLow tells whether a value of the window is held by the low heap
+	inLow   map[uint64]bool
+	evicted map[uint64]struct{}
+}
+
+func newMovingPercentile(window int, p float64) *movingPercentile {
+	return &movingPercentile{
+		window: window,
+		p:      p,
+		low: &entryHeap{less: func(a, b float64) bool {
+			return a > b
+		}},
+		high: &entryHeap{less: func(a, b float64) bool {
+			return a < b
+		}},
+		inLow:   make(map[uint64]bool),
+		evicted: make(map[uint64]struct{}),
+	}
+}
+
+func (m *movingPercentile) push(v float64) float64 {
+	entry := windowEntry{value: v, seq: m.seq}
+	m.seq++
+	if m.lowSize == 0 || v <= m.low.top().value {
+		heap.Push(m.low, entry)
+		m.inLow[entry.seq] = true
+		m.lowSize++
+	} else {
+		heap.Push(m.high, entry)
+		m.inLow[entry.seq] = false
+	}
+	m.queue = append(m.queue, entry)
+
+	if len(m.queue) > m.window {
+		oldest := m.queue[0]
+		m.queue = m.queue[1:]
+		if m.inLow[oldest.seq] {
+			m.lowSize--
+		}
+		delete(m.inLow, oldest.seq)
+		m.evicted[oldest.seq] = struct{}{}
+		m.prune(m.low)
+		m.prune(m.high)
+		if len(m.evicted) > m.window {
+			// the evicted values not reaching the top would accumulate
+			m.compact()
+		}
+	}
+
+	// nearest rank
+	rank := int(math.Ceil(m.p / 100 * float64(len(m.queue))))
+	if rank < 1 {
+		rank = 1
+	}
+	for m.lowSize > rank {
+		m.move(m.low, m.high, false)
+		m.lowSize--
+	}
+	for m.lowSize < rank {
+		m.move(m.high, m.low, true)
+		m.lowSize++
+	}
+	return m.low.top().value
+}
+
+// move moves the top of a heap to the other one
+func (m *movingPercentile) move(from, to *entryHeap, low bool) {
+	entry := heap.Pop(from).(windowEntry)
+	heap.Push(to, entry)
+	m.inLow[entry.seq] = low
+	m.prune(from)
+}
+
+// prune removes the evicted values from the top of a heap
+func (m *movingPercentile) prune(h *entryHeap) {
+	for h.Len() > 0 {
+		seq := h.top().seq
+		if _, evicted := m.evicted[seq]; !evicted {
+			return
+		}
+		heap.Pop(h)
+		delete(m.evicted, seq)
+	}
+}
+
+// compact removes the evicted values from the heaps
+func (m *movingPercentile) compact() {
+	for _, h := range []*entryHeap{m.low, m.high} {
+		entries := h.entries[:0]
+		for _, entry := range h.entries {
+			if _, evicted := m.evicted[entry.seq]; !evicted {
+				entries = append(entries, entry)
+			}
+		}
+		h.entries = entries
+		heap.Init(h)
+	}
+	m.evicted = make(map[uint64]struct{})
+}
@@ -0,0 +1,53 @@
+package rxgo
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSlidingStats compares the sliding statistics with a computation over the whole window
+func TestSlidingStats(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, window := range []int{1, 2, 7, 32} {
+		average := newMovingAverage(window)
+		min := newMovingMin(window)
+		max := newMovingMax(window)
+		p90 := newMovingPercentile(window, 90)
+		median := newMovingPercentile(window, 50)
+		var values []float64
+		for i := 0; i < 1000; i++ {
+			// duplicates included
+			v := float64(r.Intn(20))
+			values = append(values, v)
+			if len(values) > window {
+				values = values[1:]
+			}
+			sorted := append([]float64(nil), values...)
+			sort.Float64s(sorted)
+			sum := 0.0
+			for _, v := range values {
+				sum += v
+			}
+
+			assert.InDelta(t, sum/float64(len(values)), average.push(v), 1e-9)
+			assert.Equal(t, sorted[0], min.push(v))
+			assert.Equal(t, sorted[len(sorted)-1], max.push(v))
+			assert.Equal(t, sorted[nearestRank(90, len(sorted))], p90.push(v))
+			assert.Equal(t, sorted[nearestRank(50, len(sorted))], median.push(v))
+		}
+		// the evicted values do not accumulate
+		assert.True(t, p90.low.Len()+p90.high.Len() <= 2*window+1)
+	}
+}
+
+func nearestRank(p float64, n int) int {
+	rank := int(math.Ceil(p / 100 * float64(n)))
+	if rank < 1 {
+		rank = 1
+	}
+	return rank - 1
+}
@@ -0,0 +1,83 @@
+package rxgo
+
+import (
+	"context"
+	"database/sql"
+)
+
+// FromRows creates an Observable emitting the values scanned from each row of a result set, and completing at
+// its end or emitting the scan or iteration error. The next row is only read once the previous value was
+// consumed, so that a slow pipeline does not load the whole result set in memory. The rows are closed once
+// the Observable terminates or the context is done. As the rows are consumed, the Observable is meant to be
+// observed once.
+func FromRows(ctx context.Context, rows *sql.Rows, scan func(*sql.Rows) (interface{}, error), opts ...Option) Observable {
+	return CreateWithEmitter(func(ctx context.Context, emitter Emitter) {
+		defer rows.Close()
+		for rows.Next() {
+			v, err := scan(rows)
+			if err != nil {
+				emitter.Error(err)
+				return
+			}
+			emitter.Next(v)
+			if emitter.IsDisposed() {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			emitter.Error(err)
+			return
+		}
+		emitter.Complete()
+	}, append(opts, WithContext(ctx))...)
+}
+
+// ExecBatches observes an Observable of batches, such as the buffers emitted by BufferWithCount, and calls exec
+// for each batch within a transaction, committed once exec returned nil and rolled back otherwise. An item
+// which is not a []interface{} slice is a batch of one value. As the next batch is only received once the
+// previous one is committed, a slow database slows down the Observable.
+//
+// It returns once the Observable completed, nil, or at the first error emitted by the Observable or returned
+// by exec or the transaction, the observation being stopped. It returns the context error if the context is
+// done first.
+func ExecBatches(ctx context.Context, db *sql.DB, observable Observable,
+	exec func(ctx context.Context, tx *sql.Tx, batch []interface{}) error, opts ...Option) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	observe := observable.Observe(append(opts, WithContext(ctx))...)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-observe:
+			if !ok {
+				return nil
+			}
+			if item.Error() {
+				return item.E
+			}
+			batch, ok := item.V.([]interface{})
+			if !ok {
+				batch = []interface{}{item.V}
+			}
+			if err := execBatch(ctx, db, batch, exec); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// execBatch calls exec for a batch within a transaction
+func execBatch(ctx context.Context, db *sql.DB, batch []interface{},
+	exec func(ctx context.Context, tx *sql.Tx, batch []interface{}) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := exec(ctx, tx, batch); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,240 @@
+package rxgo
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// fakeDriver is a database whose queries return the rows of the table named by the query, and whose
+// executions are recorded with their transaction outcome
+type fakeDriver struct {
+	mu      sync.Mutex
+	tables  map[string][][]driver.Value
+	rowsErr error
+	execErr error
+	log     []string
+}
+
+var (
+	fakeDB       = &fakeDriver{tables: make(map[string][][]driver.Value)}
+	registerOnce sync.Once
+)
+
+func openFakeDB(t *testing.T) *sql.DB {
+	registerOnce.Do(func() {
+		sql.Register("rxgo-fake", fakeDB)
+	})
+	fakeDB.mu.Lock()
+	fakeDB.tables = map[string][][]driver.Value{
+		"users": {{int64(1), "alice"}, {int64(2), "bob"}, {int64(3), "carol"}},
+	}
+	fakeDB.rowsErr = nil
+	fakeDB.execErr = nil
+	fakeDB.log = nil
+	fakeDB.mu.Unlock()
+
+	db, err := sql.Open("rxgo-fake", "")
+	assert.NoError(t, err)
+	return db
+}
+
+func (d *fakeDriver) record(entry string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, entry)
+}
+
+func (d *fakeDriver) entries() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.log...)
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return fakeConn{d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{d: c.d, query: query}, nil
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{c.d}, nil
+}
+
+type fakeTx struct {
+	d *fakeDriver
+}
+
+func (tx fakeTx) Commit() error {
+	tx.d.record("commit")
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.d.record("rollback")
+	return nil
+}
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s fakeStmt) Close() error {
+	return nil
+}
+
+func (s fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	err := s.d.execErr
+	s.d.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	s.d.record(s.query)
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &fakeRows{values: s.d.tables[s.query], err: s.d.rowsErr}, nil
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+	err    error
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"id", "name"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		if r.err != nil {
+			return r.err
+		}
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func scanUser(rows *sql.Rows) (interface{}, error) {
+	var u user
+	err := rows.Scan(&u.ID, &u.Name)
+	return u, err
+}
+
+func Test_FromRows(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	db := openFakeDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	rows, err := db.QueryContext(ctx, "users")
+	assert.NoError(t, err)
+	Assert(ctx, t, FromRows(ctx, rows, scanUser),
+		HasItems(user{1, "alice"}, user{2, "bob"}, user{3, "carol"}), HasNoError())
+}
+
+func Test_FromRows_Take(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	db := openFakeDB(t)
+	defer db.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, "users")
+	assert.NoError(t, err)
+	Assert(ctx, t, FromRows(ctx, rows, scanUser).Take(1), HasItems(user{1, "alice"}), HasNoError())
+	// the remaining rows are not read, the next one being pending until the context is done
+	cancel()
+}
+
+func Test_FromRows_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	db := openFakeDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	fakeDB.rowsErr = errFoo
+	rows, err := db.QueryContext(ctx, "users")
+	assert.NoError(t, err)
+	Assert(ctx, t, FromRows(ctx, rows, scanUser), HasItems(user{1, "alice"}, user{2, "bob"}, user{3, "carol"}), HasError(errFoo))
+
+	rows, err = db.QueryContext(ctx, "users")
+	assert.NoError(t, err)
+	Assert(ctx, t, FromRows(ctx, rows, func(*sql.Rows) (interface{}, error) {
+		return nil, errBar
+	}), IsEmpty(), HasError(errBar))
+}
+
+func Test_ExecBatches(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	db := openFakeDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	err := ExecBatches(ctx, db, Range(0, 5).BufferWithCount(2),
+		func(ctx context.Context, tx *sql.Tx, batch []interface{}) error {
+			for range batch {
+				if _, err := tx.ExecContext(ctx, "insert"); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"insert", "insert", "commit", "insert", "insert", "commit", "insert", "commit"}, fakeDB.entries())
+}
+
+func Test_ExecBatches_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	db := openFakeDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	fakeDB.execErr = errFoo
+	err := ExecBatches(ctx, db, Just(1, 2)(), func(ctx context.Context, tx *sql.Tx, batch []interface{}) error {
+		_, err := tx.ExecContext(ctx, "insert")
+		return err
+	})
+	assert.Equal(t, errFoo, err)
+	assert.Equal(t, []string{"rollback"}, fakeDB.entries())
+
+	assert.Equal(t, errBar, ExecBatches(ctx, db, Thrown(errBar), func(context.Context, *sql.Tx, []interface{}) error {
+		return nil
+	}))
+}
@@ -0,0 +1,74 @@
+//go:build go1.18
+// +build go1.18
+
+package rxgo
+
+import "sync"
+
+// State is a concurrency-safe observable state cell holding a value of type T, backed by a BehaviorSubject.
+type State[T any] struct {
+	mu      sync.RWMutex
+	value   T
+	version uint64
+	subject *BehaviorSubject
+}
+
+// NewState creates a state holding an initial value. The options configure the underlying BehaviorSubject.
+func NewState[T any](initial T, opts ...Option) *State[T] {
+	s := &State[T]{
+		value:   initial,
+		subject: NewBehaviorSubject(opts...),
+	}
+	s.subject.Next(initial)
+	return s
+}
+
+// Get returns the current value.
+func (s *State[T]) Get() T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.value
+}
+
+// Set replaces the value and publishes it to the watchers.
+func (s *State[T]) Set(value T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(value)
+}
+
+// set replaces the value, the lock being held, so that the watchers receive the values in order
+func (s *State[T]) set(value T) {
+	s.value = value
+	s.version++
+	s.subject.Next(value)
+}
+
+// Update replaces the value with f applied to the current value, and returns the new value. f is called
+// without holding the state: if the value changed in the meantime, f is called again with the new value until
+// the compare-and-swap succeeds, so f must not have side effects.
+func (s *State[T]) Update(f func(T) T) T {
+	for {
+		s.mu.RLock()
+		current, version := s.value, s.version
+		s.mu.RUnlock()
+
+		next := f(current)
+
+		s.mu.Lock()
+		if s.version == version {
+			s.set(next)
+			s.mu.Unlock()
+			return next
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Watch creates an Observable emitting the current value to each observer, then every new value, the values
+// being of type T. An observer watches the state until the context passed with WithContext is done.
+func (s *State[T]) Watch(opts ...Option) Observable {
+	return observeSubject(s.subject.Subscribe, opts...)
+}
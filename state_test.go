@@ -0,0 +1,66 @@
+//go:build go1.18
+// +build go1.18
+
+package rxgo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestState_GetSet(t *testing.T) {
+	state := NewState(1)
+	assert.Equal(t, 1, state.Get())
+	state.Set(2)
+	assert.Equal(t, 2, state.Get())
+}
+
+func TestState_Update(t *testing.T) {
+	state := NewState(0)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			state.Update(func(n int) int {
+				return n + 1
+			})
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 100, state.Get())
+	assert.Equal(t, 101, state.Update(func(n int) int {
+		return n + 1
+	}))
+}
+
+func TestState_Watch(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	state := NewState("a")
+	ctx, cancel := context.WithCancel(context.Background())
+	observer := NewTestObserver(t, state.Watch(WithContext(ctx)))
+	observer.AwaitCount(1, time.Second)
+
+	state.Set("b")
+	state.Update(func(s string) string {
+		return s + "c"
+	})
+	observer.AwaitCount(3, time.Second)
+	cancel()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues("a", "b", "bc")
+
+	// a late watcher receives the current value
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	late := NewTestObserver(t, state.Watch(WithContext(ctx)))
+	late.AwaitCount(1, time.Second)
+	late.AssertValues("bc")
+	cancel()
+	late.AwaitDone(time.Second)
+}
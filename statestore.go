@@ -0,0 +1,156 @@
+package rxgo
+
+import (
+	"container/list"
+	"time"
+)
+
+// StateStore holds the state of a stateful operator per key, such as the keys seen by Distinct or the groups of
+// GroupByDynamic. A store may evict entries according to its policy, passing them to the function registered
+// with OnEvict so that the operator releases them.
+//
+// A store is used by a single operator observation, WithStateStore taking a factory.
+type StateStore interface {
+	// Get returns the state of a key, and whether it exists.
+	Get(key interface{}) (interface{}, bool)
+	// Put sets the state of a key.
+	Put(key, value interface{})
+	// Delete removes the state of a key, without passing it to the eviction function.
+	Delete(key interface{})
+	// Len returns the number of keys.
+	Len() int
+	// Range calls f for each key and its state, until f returns false.
+	Range(f func(key, value interface{}) bool)
+	// OnEvict registers the function called with each entry evicted by the store.
+	OnEvict(f func(key, value interface{}))
+}
+
+// EvictionPolicy bounds the memory of a state store created by NewMemoryStateStore. The zero value evicts
+// nothing.
+type EvictionPolicy struct {
+	// MaxSize evicts the oldest entries once the store holds more than MaxSize keys, if positive.
+	MaxSize int
+	// LRU makes MaxSize evict the least recently read or written entries instead of the least recently
+	// written ones.
+	LRU bool
+	// TTL evicts the entries not written for TTL, if positive. The expired entries are evicted once read, or
+	// once they are the oldest ones.
+	TTL time.Duration
+}
+
+// memoryEntry is an entry of a memory store
+type memoryEntry struct {
+	key     interface{}
+	value   interface{}
+	written time.Time
+}
+
+// memoryStateStore is a StateStore keeping its entries in memory, ordered from the oldest to the newest
+type memoryStateStore struct {
+	policy  EvictionPolicy
+	clock   Clock
+	entries map[interface{}]*list.Element
+	order   *list.List
+	onEvict func(key, value interface{})
+}
+
+// NewMemoryStateStore creates a StateStore keeping its entries in memory, evicting them according to the
+// policy. The WithClock option sets the clock measuring the TTL. It is not safe for concurrent use.
+func NewMemoryStateStore(policy EvictionPolicy, opts ...Option) StateStore {
+	return &memoryStateStore{
+		policy:  policy,
+		clock:   parseOptions(opts...).getClock(),
+		entries: make(map[interface{}]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (s *memoryStateStore) Get(key interface{}) (interface{}, bool) {
+	elem, exists := s.entries[key]
+	if !exists {
+		return nil, false
+	}
+	entry := elem.Value.(*memoryEntry)
+	if s.policy.TTL > 0 && s.clock.Now().Sub(entry.written) >= s.policy.TTL {
+		s.evict(elem)
+		return nil, false
+	}
+	if s.policy.LRU {
+		s.order.MoveToBack(elem)
+	}
+	return entry.value, true
+}
+
+func (s *memoryStateStore) Put(key, value interface{}) {
+	var now time.Time
+	if s.policy.TTL > 0 {
+		now = s.clock.Now()
+	}
+	if elem, exists := s.entries[key]; exists {
+		entry := elem.Value.(*memoryEntry)
+		entry.value = value
+		entry.written = now
+		s.order.MoveToBack(elem)
+	} else {
+		s.entries[key] = s.order.PushBack(&memoryEntry{key: key, value: value, written: now})
+	}
+
+	if s.policy.TTL > 0 {
+		for front := s.order.Front(); front != nil; front = s.order.Front() {
+			if now.Sub(front.Value.(*memoryEntry).written) < s.policy.TTL {
+				break
+			}
+			s.evict(front)
+		}
+	}
+	if s.policy.MaxSize > 0 {
+		for len(s.entries) > s.policy.MaxSize {
+			s.evict(s.order.Front())
+		}
+	}
+}
+
+func (s *memoryStateStore) Delete(key interface{}) {
+	if elem, exists := s.entries[key]; exists {
+		s.order.Remove(elem)
+		delete(s.entries, key)
+	}
+}
+
+func (s *memoryStateStore) Len() int {
+	return len(s.entries)
+}
+
+func (s *memoryStateStore) Range(f func(key, value interface{}) bool) {
+	for elem := s.order.Front(); elem != nil; {
+		// f may delete the entry
+		next := elem.Next()
+		entry := elem.Value.(*memoryEntry)
+		if !f(entry.key, entry.value) {
+			return
+		}
+		elem = next
+	}
+}
+
+func (s *memoryStateStore) OnEvict(f func(key, value interface{})) {
+	s.onEvict = f
+}
+
+// evict removes an entry and passes it to the eviction function
+func (s *memoryStateStore) evict(elem *list.Element) {
+	entry := elem.Value.(*memoryEntry)
+	s.order.Remove(elem)
+	delete(s.entries, entry.key)
+	if s.onEvict != nil {
+		s.onEvict(entry.key, entry.value)
+	}
+}
+
+// newStateStore creates the state store of an operator, in memory without eviction by default
+func newStateStore(option Option) StateStore {
+	if factory := option.getStateStore(); factory != nil {
+		return factory()
+	}
+	return NewMemoryStateStore(EvictionPolicy{})
+}
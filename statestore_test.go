@@ -0,0 +1,94 @@
+package rxgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// evictions records the keys evicted by a store
+func evictions(store StateStore) *[]interface{} {
+	evicted := &[]interface{}{}
+	store.OnEvict(func(key, _ interface{}) {
+		*evicted = append(*evicted, key)
+	})
+	return evicted
+}
+
+func Test_MemoryStateStore(t *testing.T) {
+	store := NewMemoryStateStore(EvictionPolicy{})
+	evicted := evictions(store)
+	store.Put("a", 1)
+	store.Put("b", 2)
+	store.Put("a", 3)
+	v, ok := store.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, 2, store.Len())
+
+	store.Delete("a")
+	_, ok = store.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, store.Len())
+	assert.Empty(t, *evicted)
+}
+
+func Test_MemoryStateStore_MaxSize(t *testing.T) {
+	store := NewMemoryStateStore(EvictionPolicy{MaxSize: 2})
+	evicted := evictions(store)
+	store.Put("a", 1)
+	store.Put("b", 2)
+	store.Get("a")
+	store.Put("c", 3)
+	assert.Equal(t, []interface{}{"a"}, *evicted)
+	assert.Equal(t, 2, store.Len())
+}
+
+func Test_MemoryStateStore_LRU(t *testing.T) {
+	store := NewMemoryStateStore(EvictionPolicy{MaxSize: 2, LRU: true})
+	evicted := evictions(store)
+	store.Put("a", 1)
+	store.Put("b", 2)
+	store.Get("a")
+	store.Put("c", 3)
+	assert.Equal(t, []interface{}{"b"}, *evicted)
+	_, ok := store.Get("a")
+	assert.True(t, ok)
+}
+
+func Test_MemoryStateStore_TTL(t *testing.T) {
+	scheduler := NewTestScheduler(time.Unix(0, 0))
+	store := NewMemoryStateStore(EvictionPolicy{TTL: time.Minute}, WithClock(scheduler))
+	evicted := evictions(store)
+	store.Put("a", 1)
+	scheduler.Advance(30 * time.Second)
+	store.Put("b", 2)
+	scheduler.Advance(30 * time.Second)
+
+	_, ok := store.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, []interface{}{"a"}, *evicted)
+	_, ok = store.Get("b")
+	assert.True(t, ok)
+
+	scheduler.Advance(30 * time.Second)
+	store.Put("c", 3)
+	assert.Equal(t, []interface{}{"a", "b"}, *evicted)
+	assert.Equal(t, 1, store.Len())
+}
+
+func Test_MemoryStateStore_Range(t *testing.T) {
+	store := NewMemoryStateStore(EvictionPolicy{})
+	store.Put("a", 1)
+	store.Put("b", 2)
+	store.Put("c", 3)
+	var keys []interface{}
+	store.Range(func(key, _ interface{}) bool {
+		keys = append(keys, key)
+		store.Delete(key)
+		return key != "b"
+	})
+	assert.Equal(t, []interface{}{"a", "b"}, keys)
+	assert.Equal(t, 1, store.Len())
+}
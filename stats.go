@@ -0,0 +1,111 @@
+package rxgo
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// latencyBounds are the upper bounds of the publish latency histogram buckets
+var latencyBounds = []time.Duration{
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// SubjectStats is a point in time snapshot of the activity of a subject.
+type SubjectStats struct {
+	// Subscribers is the number of current subscribers.
+	Subscribers int
+	// Emitted is the number of items published by the subject, errors included.
+	Emitted uint64
+	// Delivered is the number of items handed over to observers.
+	Delivered uint64
+	// Dropped is the number of items dropped because of the Drop back pressure strategy.
+	Dropped uint64
+	// Expired is the number of items discarded because they were queued longer than their time to live.
+	Expired uint64
+	// QueueDepth is the number of items waiting to be delivered to observers.
+	QueueDepth int
+	// PublishLatency is the time taken to hand over each published item to all subscribers.
+	PublishLatency LatencyHistogram
+	// Consumers is the lag of the members of the subscription groups and of the durable subscriptions, ordered
+	// by name and subscriber.
+	Consumers []ConsumerStats
+}
+
+// ConsumerStats is the lag of a member of a subscription group or of a durable subscription.
+type ConsumerStats struct {
+	// Name is the name of the subscription group or of the durable subscription.
+	Name string
+	// Subscriber is the id of the subscriber, NoSubscriber for a durable subscription without subscriber.
+	Subscriber int
+	// Undelivered is the number of items waiting to be delivered: the items queued for a group member, or the
+	// items of a durable subscription not delivered to a subscriber yet.
+	Undelivered int
+	// Unacked is the number of items delivered to a durable subscription and not acknowledged yet.
+	Unacked int
+	// OldestPending is the age of the oldest item not delivered, or not acknowledged, yet.
+	OldestPending time.Duration
+}
+
+// LatencyHistogram is a cumulative histogram of durations.
+type LatencyHistogram struct {
+	// Bounds are the upper bounds of the buckets.
+	Bounds []time.Duration
+	// Counts are the number of observations lower or equal to the bound at the same index.
+	Counts []uint64
+	// Count is the total number of observations.
+	Count uint64
+	// Sum is the sum of all observations.
+	Sum time.Duration
+}
+
+// subjectMetrics holds the counters of a subject. It is allocated on its own to keep the 64-bit
+// counters aligned.
+type subjectMetrics struct {
+	emitted      uint64
+	delivered    uint64
+	dropped      uint64
+	expired      uint64
+	latencyCount uint64
+	latencySum   uint64
+	buckets      []uint64
+}
+
+func newSubjectMetrics() *subjectMetrics {
+	return &subjectMetrics{
+		buckets: make([]uint64, len(latencyBounds)),
+	}
+}
+
+// observeLatency records a publish duration
+func (m *subjectMetrics) observeLatency(d time.Duration) {
+	for i, bound := range latencyBounds {
+		if d <= bound {
+			atomic.AddUint64(&m.buckets[i], 1)
+			break
+		}
+	}
+	atomic.AddUint64(&m.latencyCount, 1)
+	atomic.AddUint64(&m.latencySum, uint64(d))
+}
+
+// histogram returns a cumulative snapshot of the publish latencies
+func (m *subjectMetrics) histogram() LatencyHistogram {
+	h := LatencyHistogram{
+		Bounds: append([]time.Duration(nil), latencyBounds...),
+		Counts: make([]uint64, len(latencyBounds)),
+		Count:  atomic.LoadUint64(&m.latencyCount),
+		Sum:    time.Duration(atomic.LoadUint64(&m.latencySum)),
+	}
+	var cumulative uint64
+	for i := range m.buckets {
+		cumulative += atomic.LoadUint64(&m.buckets[i])
+		h.Counts[i] = cumulative
+	}
+	return h
+}
@@ -1,7 +1,10 @@
 package rxgo
 
 import (
+	"context"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 // ISubject defines subject API
@@ -9,27 +12,240 @@ type ISubject interface {
 	Subscribe() (Subscription, Observable)
 	Unsubscribe(id int)
 	Next(value interface{})
+	NextItem(item Item)
+	NextBatch(values []interface{})
 	Error(err error)
 	Complete()
 }
 
-// Subject a basic subject
+// Subject a basic subject.
+// The subscribers are mutated under the subject lock, which also publishes an immutable copy of them.
+// Next iterates over the latest copy without locking the subject, so that publishers never contend with
+// each other nor with Subscribe.
 type Subject struct {
 	sync.RWMutex
 	opts             []Option
-	subscribers      map[int]chan<- Item
+	option           Option
+	subscribers      map[int]*subscriberState
+	snapshot         atomic.Value // *subscriberList
+	deadLetters      atomic.Value // *Subject
+	middlewares      atomic.Value // []func(next NextFunc) NextFunc
 	nextSubscriberId int
+	metrics          *subjectMetrics
+	pool             *deliveryPool
+	fanout           *shardedFanout
+	durables         map[string]*durableSubscription
+	groups           map[string]*subscriptionGroup
+	// buffer is the buffer of the subscriptions set with ResizeSubscriptions, if resized
+	buffer  int
+	resized bool
+	// producers is the number of registered producers not completed yet
+	producers int
+	// onSnapshot, if set, is called under lock once the subscribers changed
+	onSnapshot func()
+	// changed is closed once the subscribers or their observers changed, guarded by changedMu
+	changedMu sync.Mutex
+	changed   chan struct{}
+	// done is closed once the subject terminated, err being the error it terminated with
+	done       chan struct{}
+	terminated bool
+	err        error
+}
+
+// subscriberList is an immutable copy of the subscribers, along with the fan-out shards delivering to them,
+// the durable subscriptions and the subscription groups
+type subscriberList struct {
+	subscribers []*subscriberState
+	fanout      *shardedFanout
+	durables    []*durableSubscription
+	groups      []*subscriptionGroup
+}
+
+// subscriberState holds the state of a single subject subscription. Its items are sent to ch, read by the
+// source goroutine, or dispatched to the source by the delivery pool if ch is nil.
+// Its lock guards the sends against the closing, as a publisher may still hold a closed subscriber.
+type subscriberState struct {
+	sync.RWMutex
+	id      int
+	ch      chan Item
+	pool    *deliveryPool
+	closed  bool
+	source  *eventSourceIterable
+	subject *Subject
+	durable *durableSubscription
+	group   *subscriptionGroup
+	// sent records the times of the items sent to a group member
+	sent *sendTimes
+	// queue, if set, queues the items of a durable subscription, or of a subscription with the Adaptive or the
+	// Conflate strategy
+	queue subscriberQueue
+	// dropWhenFull is set once a subscription with the Drop strategy was resized: its items are dropped once
+	// its buffer is full
+	dropWhenFull bool
+	// predicate, if set, filters the values before they are sent
+	predicate func(interface{}) bool
+	// stage is the stage of the pipeline observing the subscription, once observed
+	stage string
+}
+
+// subscriberQueue queues the items of a subscriber without blocking the publishers, the items being sent to
+// the subscriber channel by the queue goroutine
+type subscriberQueue interface {
+	// push queues an item, returning the items dropped
+	push(item Item) []Item
+	// pop returns the next item, waiting until an item is queued. It returns false once the queue is closed
+	// and drained, or the context is done.
+	pop(ctx context.Context) (Item, bool)
+	len() int
+	// close closes the queue, the queued items being still sent
+	close()
+}
+
+// sendQueued sends the queued items to ch until the queue is closed and drained, or the context is done, then
+// closes ch
+func sendQueued(ctx context.Context, queue subscriberQueue, ch chan<- Item) {
+	defer close(ch)
+	for {
+		item, ok := queue.pop(ctx)
+		if !ok {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- item:
+		}
+	}
+}
+
+// close closes the subscriber, once its pending sends are done
+func (sub *subscriberState) close() {
+	// an awaiting source must read the pending sends
+	sub.source.wake()
+
+	sub.Lock()
+	defer sub.Unlock()
+
+	sub.closed = true
+	if sub.queue != nil {
+		// the queue goroutine closes ch once the queued items were sent
+		sub.queue.close()
+	} else if sub.ch != nil {
+		close(sub.ch)
+		if sub.source.ctx.Err() != nil {
+			// disposed, the pending items are released right away
+			for range sub.ch {
+			}
+		}
+	} else {
+		sub.pool.closeSubscriber(sub)
+	}
+}
+
+func (sub *subscriberState) expired(item Item) {
+	s := sub.subject
+	atomic.AddUint64(&s.metrics.expired, 1)
+	s.logger().Warn("rxgo: item expired", "subscriber", sub.id, "item", item.V)
+	// an expired item is not redelivered to a durable subscription
+	item.Ack()
+	item.ack = nil
+	s.deadLetter(sub.id, item, ErrExpired)
+}
+
+// queued returns the number of items waiting to be delivered to the subscriber
+func (sub *subscriberState) queued() int {
+	sub.RLock()
+	ch := sub.ch
+	sub.RUnlock()
+	queued := len(ch) + sub.source.queued()
+	if sub.queue != nil {
+		queued += sub.queue.len()
+	}
+	return queued
+}
+
+// capacity returns the capacity of the subscriber buffer
+func (sub *subscriberState) capacity() int {
+	sub.RLock()
+	defer sub.RUnlock()
+
+	return cap(sub.ch)
+}
+
+// accepts returns whether an item satisfies the predicate of the subscriber, if any
+func (sub *subscriberState) accepts(item Item) bool {
+	if sub.predicate == nil {
+		return true
+	}
+	item = unwrapped(item)
+	return item.Error() || sub.predicate(item.V)
+}
+
+// unwrapped returns an item, unwrapped if it is held until its time to live elapsed
+func unwrapped(item Item) Item {
+	if e, ok := item.V.(*expiringItem); ok {
+		return e.item
+	}
+	return item
+}
+
+func (sub *subscriberState) observed(stage string) {
+	if stage != "" {
+		sub.Lock()
+		sub.stage = stage
+		sub.Unlock()
+	}
+	sub.subject.notifyChanged()
+}
+
+func (sub *subscriberState) delivered(Item) {
+	atomic.AddUint64(&sub.subject.metrics.delivered, 1)
+}
+
+func (sub *subscriberState) dropped(item Item) {
+	s := sub.subject
+	atomic.AddUint64(&s.metrics.dropped, 1)
+	sub.RLock()
+	stage := sub.stage
+	sub.RUnlock()
+	if stage != "" {
+		s.logger().Warn("rxgo: item dropped", "subscriber", sub.id, "stage", stage, "item", item.V)
+	} else {
+		s.logger().Warn("rxgo: item dropped", "subscriber", sub.id, "item", item.V)
+	}
+	s.deadLetter(sub.id, item, ErrDropped)
+	if span := startSpan(s.option, "rxgo.subject.drop"); span != nil {
+		if stage != "" {
+			span.AddEvent("dropped", "subscriber", sub.id, "stage", stage)
+		} else {
+			span.AddEvent("dropped", "subscriber", sub.id)
+		}
+		span.End()
+	}
+	globalHooks.dropped(s, sub.id, item)
 }
 
 // NewSubject creates a new subject.  with the specified observer options.
+// The error strategy option defines whether Error terminates the subject (StopOnError, the default)
+// or is delivered to the subscribers while the subject keeps flowing (ContinueOnError).
 func NewSubject(opts ...Option) *Subject {
-	res := Subject{
+	res := newSubject(opts...)
+	globalSubjects.add(&res, &res)
+
+	return &res
+}
+
+// newSubject creates a subject without registering it
+func newSubject(opts ...Option) Subject {
+	option := parseOptions(opts...)
+	return Subject{
 		opts:             opts,
-		subscribers:      make(map[int]chan<- Item),
+		option:           option,
+		subscribers:      make(map[int]*subscriberState),
 		nextSubscriberId: 0,
+		metrics:          newSubjectMetrics(),
+		producers:        option.getProducerCount(),
 	}
-
-	return &res
 }
 
 // Subscribe adds a subscriber to the subject. THe function returns a subscription and a new Observable.
@@ -40,60 +256,591 @@ func (s *Subject) Subscribe() (Subscription, Observable) {
 	return s.createSubscription(0)
 }
 
+// SubscribeWhere adds a subscriber receiving only the values satisfying the predicate. Unlike a Filter
+// operator, the other values are discarded before being queued for the subscriber, so that they neither
+// take buffer slots nor cause drops. Inline errors are always received.
+func (s *Subject) SubscribeWhere(predicate func(interface{}) bool) (Subscription, Observable) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.createFilteredSubscription(0, predicate)
+}
+
 func (s *Subject) createSubscription(bufferSize int) (Subscription, Observable) {
+	return s.subscribe(bufferSize, nil, nil, nil)
+}
+
+func (s *Subject) createFilteredSubscription(bufferSize int, predicate func(interface{}) bool) (Subscription, Observable) {
+	return s.subscribe(bufferSize, nil, nil, predicate)
+}
+
+// subscribe adds a subscriber, delivered by its durable subscription or its group if any
+func (s *Subject) subscribe(bufferSize int, durable *durableSubscription, group *subscriptionGroup, predicate func(interface{}) bool) (Subscription, Observable) {
 	id := s.nextSubscriberId
 	s.nextSubscriberId++
 
-	subChan := make(chan Item, bufferSize)
-	s.subscribers[id] = subChan
+	subscriber := &subscriberState{
+		id:        id,
+		subject:   s,
+		durable:   durable,
+		group:     group,
+		predicate: predicate,
+	}
+	if group != nil {
+		subscriber.sent = &sendTimes{}
+	}
+	if workers := s.option.getWorkerPool(); workers > 0 && durable == nil && group == nil {
+		if s.pool == nil {
+			s.pool = newDeliveryPool(workers)
+		}
+		subscriber.pool = s.pool
+		subscriber.source = newPassiveEventSourceIterable(s.option.buildContext(emptyContext), s.option.getBackPressureStrategy(), subscriber)
+	} else if durable != nil {
+		// the items of a durable subscription are queued without blocking the publishers, and not lost until
+		// observed
+		subscriber.ch = make(chan Item, bufferSize)
+		subscriber.queue = newDurableQueue()
+		subscriber.source = newAwaitingEventSourceIterable(s.option.buildContext(emptyContext), subscriber.ch, s.option.getBackPressureStrategy(), subscriber)
+		go sendQueued(subscriber.source.ctx, subscriber.queue, subscriber.ch)
+	} else if strategy := s.option.getBackPressureStrategy(); strategy == Adaptive || strategy == Conflate {
+		// the items are queued without blocking the publishers, then sent to the source as it delivers them
+		subscriber.ch = make(chan Item, bufferSize)
+		if strategy == Adaptive {
+			subscriber.queue = newAdaptiveQueue(s.option.getAdaptivePolicy(), s.option.getClock(), subscriber.adaptiveTransition)
+		} else {
+			subscriber.queue = newConflatingQueue(s.option.getConflationKey(), s.option.getConflationMerge())
+		}
+		subscriber.source = newEventSourceIterable(s.option.buildContext(emptyContext), subscriber.ch, Block, subscriber)
+		go sendQueued(subscriber.source.ctx, subscriber.queue, subscriber.ch)
+	} else {
+		subscriber.ch = make(chan Item, bufferSize)
+		subscriber.source = newEventSourceIterable(s.option.buildContext(emptyContext), subscriber.ch, s.option.getBackPressureStrategy(), subscriber)
+	}
+	if s.resized && subscriber.ch != nil {
+		// the buffer is not shrunk below the items replayed to the subscriber
+		if bufferSize < s.buffer {
+			bufferSize = s.buffer
+		}
+		subscriber.resize(bufferSize)
+	}
+	s.subscribers[id] = subscriber
+	if shards := s.option.getShards(); shards > 0 && durable == nil && group == nil {
+		if s.fanout == nil {
+			s.fanout = newShardedFanout(s, shards)
+		}
+		s.fanout.add(subscriber)
+	}
+	s.updateSnapshot()
+	globalLeaks.track(s, subscriber)
 
-	sub := NewSubscription(id, s)
-	obs := FromEventSource(subChan, s.opts...)
+	var sub Subscription = NewSubscription(id, s)
+	obs := &ObservableImpl{
+		iterable: subscriber.source,
+	}
+	if s.option.isWeakSubscriptions() && durable == nil {
+		sub, obs = newWeakSubscription(id, s, subscriber.source)
+	}
+	s.logger().Debug("rxgo: subscribed", "subscriber", id)
+	globalHooks.subscribed(s, id)
 
 	return sub, obs
 }
 
-// Unsubscribe removes a subscriber identified by ID from the Subject.
+// Unsubscribe removes a subscriber identified by ID from the Subject. The items pending for the subscriber
+// are discarded and its observers are closed, without waiting for them to consume.
 func (s *Subject) Unsubscribe(id int) {
+	s.unsubscribe(id)
+}
+
+// unsubscribe removes a subscriber, returning whether it was found
+func (s *Subject) unsubscribe(id int) bool {
 	s.Lock()
 	defer s.Unlock()
 
-	subChan, found := s.subscribers[id]
+	subscriber, found := s.subscribers[id]
 	if found {
-		close(subChan)
-		delete(s.subscribers, id)
+		// the delivery goroutine and the blocked publishers return without waiting for the observers
+		subscriber.source.dispose()
+		s.closeSubscriber(subscriber)
+		s.releasePool()
+		s.updateSnapshot()
+		s.logger().Debug("rxgo: unsubscribed", "subscriber", id)
 	}
+	return found
 }
 
 // Next sends a new value to all subscribers
 func (s *Subject) Next(value interface{}) {
-	s.RLock()
-	defer s.RUnlock()
+	s.NextItem(Of(value))
+}
+
+// NextItem sends an item to all subscribers.
+// Unlike Error, an item holding an error is delivered inline and never terminates the subject.
+func (s *Subject) NextItem(item Item) {
+	s.nextItems(s.intercept([]Item{item})...)
+}
+
+// NextBatch sends values to all subscribers, in order. Each subscriber receives the whole batch with a single
+// synchronization instead of one per value.
+// With WithBatchDelivery, the batch is delivered as a single item holding the values slice.
+func (s *Subject) NextBatch(values []interface{}) {
+	batch := s.newBatch(values)
+	defer s.releaseBatch(batch)
 
-	s.publish(Of(value))
+	s.nextItems(batch.items...)
+}
+
+// itemBatch holds the items delivered for a batch of values
+type itemBatch struct {
+	items []Item
+}
+
+// maxPooledBatch is the capacity above which a batch is not recycled
+const maxPooledBatch = 4096
+
+var itemBatches = sync.Pool{
+	New: func() interface{} {
+		return &itemBatch{}
+	},
+}
+
+// newBatch returns the items delivered for a batch of values, recycled with WithPooling
+func (s *Subject) newBatch(values []interface{}) *itemBatch {
+	values = s.interceptValues(values)
+	var batch *itemBatch
+	if s.option.isPooling() {
+		batch = itemBatches.Get().(*itemBatch)
+	} else {
+		batch = &itemBatch{items: make([]Item, 0, len(values))}
+	}
+
+	switch {
+	case len(values) == 0:
+	case s.option.isBatchDelivery():
+		batch.items = append(batch.items, Of(values))
+	default:
+		for _, value := range values {
+			batch.items = append(batch.items, Of(value))
+		}
+	}
+	return batch
+}
+
+// releaseBatch recycles a batch once its items are delivered
+func (s *Subject) releaseBatch(batch *itemBatch) {
+	if !s.option.isPooling() || cap(batch.items) > maxPooledBatch {
+		return
+	}
+	for i := range batch.items {
+		batch.items[i] = Item{}
+	}
+	batch.items = batch.items[:0]
+	itemBatches.Put(batch)
+}
+
+// nextItems sends items to all subscribers
+func (s *Subject) nextItems(items ...Item) {
+	s.nextItemsTo(s.loadSubscribers(), items...)
+}
+
+// nextItemsTo sends items to the subscribers of a copy loaded beforehand
+func (s *Subject) nextItemsTo(list *subscriberList, items ...Item) {
+	if len(items) == 0 {
+		return
+	}
+	if span := startSpan(s.option, "rxgo.subject.next"); span != nil {
+		defer span.End()
+		for _, item := range items {
+			if item.Error() {
+				span.RecordError(item.E)
+			}
+		}
+		span.AddEvent("published", "subscribers", len(list.subscribers))
+	}
+
+	for _, item := range items {
+		globalHooks.published(s, item)
+	}
+	s.publish(list, false, items...)
 }
 
-// Error calls the error function on all subscribers
+// Error calls the error function on all subscribers.
+// With the StopOnError strategy, all subscribers are closed afterwards.
 func (s *Subject) Error(err error) {
-	s.RLock()
-	defer s.RUnlock()
+	s.Lock()
+	defer s.Unlock()
+
+	if span := startSpan(s.option, "rxgo.subject.error"); span != nil {
+		defer span.End()
+		span.RecordError(err)
+	}
 
-	s.publish(Error(err))
+	globalHooks.failed(s, err)
+	s.publish(s.loadSubscribers(), true, Error(err))
+	if s.option.getErrorStrategy() == StopOnError {
+		s.logger().Info("rxgo: subject terminated with error", "error", err, "subscribers", len(s.subscribers))
+		s.terminate(err)
+		s.closeSubscribers()
+	}
 }
 
-// publish sends an item to all subscribers
-func (s *Subject) publish(item Item) {
-	for _, subChan := range s.subscribers {
-		subChan <- item
+// publish sends items to the subscribers of a copy, and to a member of each subscription group. A terminal item
+// is sent to all the members.
+func (s *Subject) publish(list *subscriberList, terminal bool, items ...Item) {
+	clock := s.option.getClock()
+	start := clock.Now()
+	items = s.expiring(items)
+	durableItems := items
+	if len(list.durables) > 0 {
+		items, durableItems = s.acknowledged(items, len(list.durables))
+	}
+	if list.fanout != nil {
+		for _, item := range items {
+			list.fanout.dispatch(item)
+		}
+	} else {
+		for _, subscriber := range list.subscribers {
+			if subscriber.durable == nil && subscriber.group == nil {
+				s.send(subscriber, items...)
+			}
+		}
+	}
+	for _, group := range list.groups {
+		group.publish(s, terminal, items)
 	}
+	for _, durable := range list.durables {
+		for _, item := range durableItems {
+			durable.publish(s, item)
+		}
+	}
+	atomic.AddUint64(&s.metrics.emitted, uint64(len(items)))
+	s.metrics.observeLatency(clock.Now().Sub(start))
 }
 
-// Complete closes all subscribers.
+// acknowledged returns the items without their acknowledgement, for the subscribers which are not durable,
+// and the items acknowledged once all the durable subscriptions acknowledged them
+func (s *Subject) acknowledged(items []Item, durables int) ([]Item, []Item) {
+	var plain, shared []Item
+	for i, item := range items {
+		if item.ack == nil {
+			continue
+		}
+		if plain == nil {
+			plain = append(make([]Item, 0, len(items)), items...)
+			shared = append(make([]Item, 0, len(items)), items...)
+		}
+		plain[i].ack = nil
+		if durables > 1 {
+			shared[i].ack = &sharedAck{remaining: int32(durables), upstream: item.ack}
+		}
+	}
+	if plain == nil {
+		return items, items
+	}
+	return plain, shared
+}
+
+// expiring returns the items held until their time to live elapsed, the items themselves without time to live
+func (s *Subject) expiring(items []Item) []Item {
+	ttl := s.option.getItemTTL()
+	if ttl <= 0 {
+		return items
+	}
+	clock := s.option.getClock()
+	expiring := make([]Item, len(items))
+	for i, item := range items {
+		expiring[i] = withTTL(item, ttl, clock)
+	}
+	return expiring
+}
+
+// Complete closes all subscribers, whatever the producers registered with Producer. With WithProducerCount,
+// it completes one of the expected producers instead, the subject completing once all of them and the
+// registered producers completed.
 func (s *Subject) Complete() {
+	if s.option.getProducerCount() > 0 {
+		s.producerCompleted()
+		return
+	}
 	s.Lock()
 	defer s.Unlock()
 
-	for _, subChan := range s.subscribers {
-		close(subChan)
+	s.logger().Debug("rxgo: subject completed", "subscribers", len(s.subscribers))
+	s.terminate(nil)
+	s.closeSubscribers()
+}
+
+// terminate records the first termination of the subject, the lock being held
+func (s *Subject) terminate(err error) {
+	if s.terminated {
+		return
+	}
+	s.terminated = true
+	s.err = err
+	if s.done != nil {
+		close(s.done)
+	}
+}
+
+// Done returns a channel closed once the subject terminated, by Complete or by Error with the StopOnError
+// strategy.
+func (s *Subject) Done() <-chan struct{} {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.done == nil {
+		s.done = make(chan struct{})
+		if s.terminated {
+			close(s.done)
+		}
+	}
+	return s.done
+}
+
+// Err returns the error the subject terminated with, nil if it is not terminated or completed.
+func (s *Subject) Err() error {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.err
+}
+
+// closeSubscribers closes and removes all subscribers. The subject is removed from the registry, and the
+// dead letters are completed.
+func (s *Subject) closeSubscribers() {
+	globalSubjects.remove(s)
+	if letters := s.loadDeadLetters(); letters != nil {
+		letters.Complete()
+	}
+	for _, subscriber := range s.subscribers {
+		s.closeSubscriber(subscriber)
+	}
+	s.releasePool()
+	s.updateSnapshot()
+}
+
+// updateSnapshot publishes a copy of the subscribers, the lock being held
+func (s *Subject) updateSnapshot() {
+	subscribers := make([]*subscriberState, 0, len(s.subscribers))
+	for _, subscriber := range s.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	durables := make([]*durableSubscription, 0, len(s.durables))
+	for _, durable := range s.durables {
+		durables = append(durables, durable)
+	}
+	groups := make([]*subscriptionGroup, 0, len(s.groups))
+	for _, group := range s.groups {
+		groups = append(groups, group)
+	}
+	s.snapshot.Store(&subscriberList{subscribers: subscribers, fanout: s.fanout, durables: durables, groups: groups})
+	if s.onSnapshot != nil {
+		s.onSnapshot()
+	}
+	s.notifyChanged()
+}
+
+// notifyChanged wakes up the pending AwaitSubscribers calls
+func (s *Subject) notifyChanged() {
+	s.changedMu.Lock()
+	defer s.changedMu.Unlock()
+
+	if s.changed != nil {
+		close(s.changed)
+		s.changed = nil
+	}
+}
+
+// AwaitSubscribers blocks until at least n subscribers observe their subscription, so that a producer does not
+// publish before its consumers are attached. It returns the context error if the context is done first.
+func (s *Subject) AwaitSubscribers(ctx context.Context, n int) error {
+	for {
+		s.changedMu.Lock()
+		observed := 0
+		for _, subscriber := range s.loadSubscribers().subscribers {
+			if subscriber.source.hasObservers() {
+				observed++
+			}
+		}
+		if observed >= n {
+			s.changedMu.Unlock()
+			return nil
+		}
+		if s.changed == nil {
+			s.changed = make(chan struct{})
+		}
+		changed := s.changed
+		s.changedMu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// loadSubscribers returns the latest copy of the subscribers
+func (s *Subject) loadSubscribers() *subscriberList {
+	if list, ok := s.snapshot.Load().(*subscriberList); ok {
+		return list
+	}
+	return &subscriberList{}
+}
+
+// send sends items to a subscriber, unless it is closed
+func (s *Subject) send(subscriber *subscriberState, items ...Item) {
+	var dropped []Item
+	defer func() {
+		for _, item := range dropped {
+			subscriber.dropped(item)
+		}
+	}()
+	subscriber.RLock()
+	defer subscriber.RUnlock()
+
+	if subscriber.closed {
+		return
+	}
+	for _, item := range items {
+		if !subscriber.accepts(item) {
+			continue
+		}
+		if subscriber.queue != nil {
+			for _, item := range subscriber.queue.push(item) {
+				if e, ok := item.V.(*expiringItem); ok {
+					item = e.held(item)
+				}
+				dropped = append(dropped, item)
+			}
+		} else if subscriber.dropWhenFull {
+			select {
+			case subscriber.ch <- item:
+			default:
+				if e, ok := item.V.(*expiringItem); ok {
+					item = e.held(item)
+				}
+				dropped = append(dropped, item)
+			}
+		} else if subscriber.ch != nil {
+			select {
+			case subscriber.ch <- item:
+			case <-subscriber.source.ctx.Done():
+				return
+			}
+		} else {
+			subscriber.pool.dispatch(subscriber, item)
+		}
+	}
+}
+
+// closeSubscriber closes and removes a subscriber. The caller updates the snapshot.
+func (s *Subject) closeSubscriber(subscriber *subscriberState) {
+	if s.fanout != nil && subscriber.durable == nil && subscriber.group == nil {
+		s.fanout.closeSubscriber(subscriber)
+	} else {
+		subscriber.close()
+	}
+	if subscriber.durable != nil {
+		subscriber.durable.detach(subscriber)
+	}
+	if group := subscriber.group; group != nil && group.remove(subscriber) {
+		delete(s.groups, group.name)
+	}
+	delete(s.subscribers, subscriber.id)
+	globalLeaks.closed(subscriber)
+}
+
+// releasePool stops the delivery pool and the fan-out shards once there is no subscriber left. They are
+// restarted by the next subscription.
+func (s *Subject) releasePool() {
+	if len(s.subscribers) > 0 {
+		return
+	}
+	if s.fanout != nil {
+		s.fanout.stop(s.pool)
+		s.fanout = nil
+	} else if s.pool != nil {
+		s.pool.stop()
+	}
+	s.pool = nil
+}
+
+// Stats returns a snapshot of the subject activity.
+func (s *Subject) Stats() SubjectStats {
+	s.RLock()
+	defer s.RUnlock()
+
+	stats := SubjectStats{
+		Subscribers:    len(s.subscribers),
+		Emitted:        atomic.LoadUint64(&s.metrics.emitted),
+		Delivered:      atomic.LoadUint64(&s.metrics.delivered),
+		Dropped:        atomic.LoadUint64(&s.metrics.dropped),
+		Expired:        atomic.LoadUint64(&s.metrics.expired),
+		PublishLatency: s.metrics.histogram(),
+	}
+	attached := make(map[*durableSubscription]int, len(s.durables))
+	for _, subscriber := range s.subscribers {
+		stats.QueueDepth += subscriber.queued()
+		if subscriber.durable != nil {
+			attached[subscriber.durable] = subscriber.id
+		}
+	}
+	if s.pool != nil {
+		stats.QueueDepth += s.pool.queued()
+	}
+	if s.fanout != nil {
+		stats.QueueDepth += s.fanout.queued()
+	}
+	stats.Consumers = s.consumers(attached)
+	return stats
+}
+
+// consumers returns the lag of the members of the subscription groups and of the durable subscriptions, the
+// lock being held
+func (s *Subject) consumers(attached map[*durableSubscription]int) []ConsumerStats {
+	if len(s.groups) == 0 && len(s.durables) == 0 {
+		return nil
+	}
+	now := s.option.getClock().Now()
+	var consumers []ConsumerStats
+	for _, group := range s.groups {
+		consumers = append(consumers, group.stats(now)...)
+	}
+	for _, durable := range s.durables {
+		subscriber, exists := attached[durable]
+		if !exists {
+			subscriber = NoSubscriber
+		}
+		consumers = append(consumers, durable.stats(subscriber, now))
+	}
+	sort.Slice(consumers, func(i, j int) bool {
+		if consumers[i].Name != consumers[j].Name {
+			return consumers[i].Name < consumers[j].Name
+		}
+		return consumers[i].Subscriber < consumers[j].Subscriber
+	})
+	return consumers
+}
+
+// Name returns the name set with WithName.
+func (s *Subject) Name() string {
+	return s.option.getName()
+}
+
+func (s *Subject) info() SubjectInfo {
+	return SubjectInfo{
+		Name:  s.Name(),
+		Type:  "Subject",
+		Stats: s.Stats(),
+	}
+}
+
+// logger returns the subject logger, reporting the subject name if any
+func (s *Subject) logger() Logger {
+	logger := s.option.getLogger()
+	if name := s.option.getName(); name != "" {
+		return namedLogger{Logger: logger, name: name}
 	}
+	return logger
 }
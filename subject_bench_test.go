@@ -0,0 +1,61 @@
+package rxgo
+
+import (
+	"fmt"
+	"testing"
+)
+
+const benchBatchSize = 100
+
+func Benchmark_Subject_Subscribers(b *testing.B) {
+	configs := make([]SubjectBenchmark, 0)
+	for _, subscribers := range []int{1, 10, 100, 1000} {
+		configs = append(configs, SubjectBenchmark{
+			Name:        fmt.Sprintf("subscribers-%d", subscribers),
+			Subscribers: subscribers,
+		})
+	}
+	RunSubjectBenchmarks(b, configs...)
+}
+
+func Benchmark_Subject_BufferSize(b *testing.B) {
+	configs := make([]SubjectBenchmark, 0)
+	for _, size := range []int{0, 16, 1024} {
+		configs = append(configs, SubjectBenchmark{
+			Name:        fmt.Sprintf("buffer-%d", size),
+			Subscribers: 10,
+			Options:     []Option{WithBufferedChannel(size)},
+		})
+	}
+	RunSubjectBenchmarks(b, configs...)
+}
+
+func Benchmark_Subject_BackPressure(b *testing.B) {
+	RunSubjectBenchmarks(b,
+		SubjectBenchmark{Name: "block", Subscribers: 10,
+			Options: []Option{WithBackPressureStrategy(Block)}},
+		SubjectBenchmark{Name: "drop", Subscribers: 10,
+			Options: []Option{WithBackPressureStrategy(Drop)}},
+	)
+}
+
+func Benchmark_Subject_Delivery(b *testing.B) {
+	RunSubjectBenchmarks(b,
+		SubjectBenchmark{Name: "goroutines", Subscribers: 1000},
+		SubjectBenchmark{Name: "worker-pool", Subscribers: 1000,
+			Options: []Option{WithWorkerPool(8)}},
+		SubjectBenchmark{Name: "shards", Subscribers: 1000,
+			Options: []Option{WithShards(8)}},
+		SubjectBenchmark{Name: "shards-worker-pool", Subscribers: 1000,
+			Options: []Option{WithShards(8), WithWorkerPool(8)}},
+	)
+}
+
+func Benchmark_Subject_NextBatch(b *testing.B) {
+	RunSubjectBenchmarks(b,
+		SubjectBenchmark{Name: "next", Subscribers: 10},
+		SubjectBenchmark{Name: "batch", Subscribers: 10, BatchSize: benchBatchSize},
+		SubjectBenchmark{Name: "batch-pooling", Subscribers: 10, BatchSize: benchBatchSize,
+			Options: []Option{WithPooling()}},
+	)
+}
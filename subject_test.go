@@ -1,6 +1,7 @@
 package rxgo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -8,39 +9,34 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
 )
 
 // TestDefaultOptions verifies that multiple observers receive the same number of items
 func TestDefaultOptions(t *testing.T) {
 	subject := NewSubject()
+	defer subject.Complete()
 
-	itemCount1 := 0
 	_, obs1 := subject.Subscribe()
-	obs1.DoOnNext(func(i interface{}) {
-		itemCount1++
-	})
-
-	itemCount2 := 0
+	observer1 := NewTestObserver(t, obs1)
 	_, obs2 := subject.Subscribe()
-	obs2.DoOnNext(func(i interface{}) {
-		itemCount2++
-	})
+	observer2 := NewTestObserver(t, obs2)
 
 	items := 10
 	for i := 0; i < items; i++ {
 		subject.Next(i)
 	}
 
-	// short delay to give subscribers time to process last element
-	time.Sleep(10 * time.Millisecond)
-
-	assert.Equal(t, items, itemCount1)
-	assert.Equal(t, items, itemCount2)
+	observer1.AwaitCount(items, time.Second)
+	observer2.AwaitCount(items, time.Second)
+	observer1.AssertValueCount(items)
+	observer2.AssertValueCount(items)
 }
 
 // TestBackPressure verifies messages are dropped with a blocked observer
 func TestBackPressure(t *testing.T) {
 	subject := NewSubject(WithBackPressureStrategy(Drop))
+	defer subject.Complete()
 	_, obs1 := subject.Subscribe()
 	_, obs2 := subject.Subscribe()
 
@@ -101,82 +97,616 @@ func TestBackPressure(t *testing.T) {
 // TestSubscriberBuffer verify no messages dropped with buffer attached
 func TestSubscriberBuffer(t *testing.T) {
 	subject := NewSubject(WithBufferedChannel(10), WithBackPressureStrategy(Drop))
+	defer subject.Complete()
 	_, obs := subject.Subscribe()
+	// observer which does not consume before all items are published
+	observer := NewTestObserver(t, obs, WithBufferedChannel(10))
 
 	items := 10
-	itemCount := 0
-	obs.DoOnNext(func(i interface{}) {
-		itemCount++
-	})
-
 	for i := 0; i < items; i++ {
-		// short delay to let subscriber catch up
-		time.Sleep(1 * time.Millisecond)
 		subject.Next(i)
 	}
 
-	// short delay to give go routines time to process last element
-	time.Sleep(10 * time.Millisecond)
-
-	assert.Equal(t, items, itemCount)
+	observer.AwaitCount(items, time.Second)
+	observer.AssertValues(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
 }
 
 func TestUnsubscribe(t *testing.T) {
 	subject := NewSubject()
 	sub, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
 
 	items := 10
-	itemCount := 0
-	obs.DoOnNext(func(i interface{}) {
-		itemCount += 1
-	})
-
 	for i := 0; i < items; i++ {
 		subject.Next(i)
 	}
 
+	// the pending items are discarded by Unsubscribe
+	observer.AwaitCount(items, time.Second)
 	// items after unsubscribe will be lost
 	sub.Unsubscribe()
 	for i := 0; i < 5; i++ {
 		subject.Next(i)
 	}
 
-	assert.Equal(t, items, itemCount)
+	observer.AwaitDone(time.Second)
+	observer.AssertValueCount(items)
+	observer.AssertNoErrors()
+}
+
+func TestUnsubscribe_ReleasesResources(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	defer subject.Complete()
+
+	for i := 0; i < 10000; i++ {
+		sub, obs := subject.Subscribe()
+		// never consumed, so that the delivery goroutine and the publisher block
+		obs.Observe(WithBufferedChannel(1))
+		published := make(chan struct{})
+		go func() {
+			defer close(published)
+			for j := 0; j < 3; j++ {
+				subject.Next(j)
+			}
+		}()
+		sub.Unsubscribe()
+		<-published
+	}
+
+	stats := subject.Stats()
+	assert.Equal(t, 0, stats.Subscribers)
+	assert.Equal(t, 0, stats.QueueDepth)
+}
+
+func TestUnsubscribe_StopsDelivery(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	defer subject.Complete()
+	sub, obs := subject.Subscribe()
+	observe := obs.Observe()
+	_, other := subject.Subscribe()
+	observer := NewTestObserver(t, other)
+
+	subject.Next(1)
+	// the item pending for the first subscriber is discarded
+	sub.Unsubscribe()
+	subject.Next(2)
+	for item := range observe {
+		assert.NotEqual(t, 2, item.V)
+	}
+	observer.AwaitCount(2, time.Second)
+	observer.AssertValues(1, 2)
 }
 
 func TestReceiveError(t *testing.T) {
 	subject := NewSubject()
+	defer subject.Complete()
 	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
 
 	err := errors.New("test")
+	subject.Error(err)
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues()
+	observer.AssertError(err)
+}
+
+func TestCompletion(t *testing.T) {
+	subject := NewSubject()
+	_, obs := subject.Subscribe()
 
 	obs.DoOnNext(func(i interface{}) {
 		fmt.Printf("next %v", i)
 	})
 
+	obs.DoOnCompleted(func() {
+		fmt.Println("completed")
+	})
+
+	subject.Complete()
+}
+
+// TestErrorStrategyStop verifies an error terminates the subject by default
+func TestErrorStrategyStop(t *testing.T) {
+	subject := NewSubject()
+	_, obs := subject.Subscribe()
+
 	var errRcvd error
-	obs.DoOnError(func(e error) {
-		fmt.Printf("error received: %v", e)
+	errDone := obs.DoOnError(func(e error) {
 		errRcvd = e
 	})
+	completed := obs.DoOnCompleted(func() {})
 
-	subject.Error(err)
+	subject.Error(errFoo)
 
+	<-errDone
+	<-completed
+	assert.Equal(t, errFoo, errRcvd)
+
+	// subject is terminated, further items are ignored
+	subject.Next(1)
+	subject.Complete()
+}
+
+// TestErrorStrategyContinue verifies errors are delivered without terminating the subject
+func TestErrorStrategyContinue(t *testing.T) {
+	subject := NewSubject(WithErrorStrategy(ContinueOnError))
+	_, obs := subject.Subscribe()
+
+	values := make([]interface{}, 0)
+	nextDone := obs.DoOnNext(func(i interface{}) {
+		values = append(values, i)
+	}, WithErrorStrategy(ContinueOnError))
+	errs := make([]error, 0)
+	errDone := obs.DoOnError(func(e error) {
+		errs = append(errs, e)
+	}, WithErrorStrategy(ContinueOnError))
+
+	subject.Next(1)
+	subject.Error(errFoo)
+	subject.Next(2)
+	subject.Error(errBar)
+	subject.Complete()
+
+	<-nextDone
+	<-errDone
+	assert.Equal(t, []interface{}{1, 2}, values)
+	assert.Equal(t, []error{errFoo, errBar}, errs)
+}
+
+// TestNextItem verifies inline errors are delivered without terminating the subject
+func TestNextItem(t *testing.T) {
+	subject := NewSubject()
+	_, obs := subject.Subscribe()
+
+	items := make([]Item, 0)
+	done := obs.ForEach(func(i interface{}) {
+		items = append(items, Of(i))
+	}, func(e error) {
+		items = append(items, Error(e))
+	}, func() {})
+
+	subject.NextItem(Of(1))
+	subject.NextItem(Error(errFoo))
+	subject.Next(2)
+	subject.Complete()
+
+	<-done
+	assert.Equal(t, []Item{Of(1), Error(errFoo), Of(2)}, items)
+}
+
+// TestStats verifies the subject statistics
+func TestStats(t *testing.T) {
+	subject := NewSubject(WithBackPressureStrategy(Drop))
+	defer subject.Complete()
+
+	_, obs := subject.Subscribe()
+	// observer which never consumes
+	_ = obs.Observe(WithBufferedChannel(1))
+
+	// give the observer time to attach to the event source
 	time.Sleep(10 * time.Millisecond)
-	assert.Equal(t, err, errRcvd)
+
+	for i := 0; i < 3; i++ {
+		subject.Next(i)
+	}
+
+	assert.Eventually(t, func() bool {
+		stats := subject.Stats()
+		return stats.Delivered+stats.Dropped == 3
+	}, time.Second, time.Millisecond)
+
+	stats := subject.Stats()
+	assert.Equal(t, 1, stats.Subscribers)
+	assert.Equal(t, uint64(3), stats.Emitted)
+	assert.Equal(t, uint64(1), stats.Delivered)
+	assert.Equal(t, uint64(2), stats.Dropped)
+	assert.Equal(t, 1, stats.QueueDepth)
+	assert.Equal(t, uint64(3), stats.PublishLatency.Count)
+	assert.Equal(t, uint64(3), stats.PublishLatency.Counts[len(stats.PublishLatency.Counts)-1])
 }
 
-func TestCompletion(t *testing.T) {
+// TestStats_Consumers verifies the lag of the group members and of the durable subscriptions
+func TestStats_Consumers(t *testing.T) {
+	scheduler := NewTestScheduler(time.Now())
+	subject := NewSubject(WithClock(scheduler))
+	defer subject.Complete()
+
+	_, member := subject.SubscribeGroup("workers")
+	// observer which never consumes
+	_ = member.Observe(WithBufferedChannel(10))
+	_, billing := subject.SubscribeDurable("billing")
+	received := billing.Observe(WithBufferedChannel(10))
+	audit, _ := subject.SubscribeDurable("audit")
+	audit.Unsubscribe()
+
+	subject.Next(1)
+	scheduler.Advance(2 * time.Second)
+	subject.Next(2)
+	subject.Next(3)
+	(<-received).Ack()
+
+	assert.Eventually(t, func() bool {
+		return subject.Stats().QueueDepth == 5
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []ConsumerStats{
+		{Name: "audit", Subscriber: NoSubscriber, Undelivered: 3, OldestPending: 2 * time.Second},
+		{Name: "billing", Subscriber: 1, Unacked: 2},
+		{Name: "workers", Subscriber: 0, Undelivered: 3, OldestPending: 2 * time.Second},
+	}, subject.Stats().Consumers)
+}
+
+// TestWorkerPool verifies items are delivered in order by the worker pool, which stops with the subject
+func TestWorkerPool(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject(WithWorkerPool(2))
+
+	observers := make([]*TestObserver, 10)
+	for i := range observers {
+		_, obs := subject.Subscribe()
+		observers[i] = NewTestObserver(t, obs)
+	}
+	sub, obs := subject.Subscribe()
+	unsubscribed := NewTestObserver(t, obs)
+
+	for i := 0; i < 100; i++ {
+		subject.Next(i)
+	}
+	// the pending items are discarded by Unsubscribe
+	unsubscribed.AwaitCount(100, time.Second)
+	sub.Unsubscribe()
+	subject.Next(100)
+	subject.Complete()
+
+	expected := make([]interface{}, 101)
+	for i := range expected {
+		expected[i] = i
+	}
+	for _, observer := range observers {
+		observer.AwaitDone(time.Second)
+		observer.AssertValues(expected...)
+	}
+	unsubscribed.AwaitDone(time.Second)
+	unsubscribed.AssertValues(expected[:100]...)
+}
+
+// TestWorkerPool_Blocked verifies an observer which stops consuming blocks the other subscribers of its
+// goroutine with the Block strategy, until it is unsubscribed
+func TestWorkerPool_Blocked(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject(WithWorkerPool(1))
+
+	blockedSub, blockedObs := subject.Subscribe()
+	_ = blockedObs.Observe()
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	// the first item may be delivered to the observer before the blocked subscriber, not the second one
+	subject.Next(1)
+	subject.Next(2)
+	time.Sleep(50 * time.Millisecond)
+	assert.NotContains(t, observer.Values(), 2)
+
+	blockedSub.Unsubscribe()
+	observer.AwaitCount(2, time.Second)
+	subject.Complete()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(1, 2)
+}
+
+// TestWorkerPool_Restart verifies the worker pool restarts once all subscribers left
+func TestWorkerPool_Restart(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewBehaviorSubject(WithWorkerPool(1))
+	subject.Next(1)
+
+	sub, _ := subject.Subscribe()
+	sub.Unsubscribe()
+
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+	subject.Next(2)
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	values := observer.Values()
+	assert.Equal(t, 2, values[len(values)-1])
+}
+
+// TestConcurrentPublishers verifies publishers run alongside subscriptions and unsubscriptions
+func TestConcurrentPublishers(t *testing.T) {
+	defer goleak.VerifyNone(t)
 	subject := NewSubject()
+
 	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	const publishers, items = 4, 1000
+	wg := sync.WaitGroup{}
+	wg.Add(publishers + 1)
+	for i := 0; i < publishers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < items; j++ {
+				subject.Next(j)
+			}
+		}()
+	}
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			sub, obs := subject.Subscribe()
+			obs.Observe()
+			sub.Unsubscribe()
+		}
+	}()
+	wg.Wait()
+	subject.Complete()
 
-	obs.DoOnNext(func(i interface{}) {
-		fmt.Printf("next %v", i)
+	observer.AwaitDone(time.Second)
+	observer.AssertValueCount(publishers * items)
+	assert.Equal(t, 0, subject.Stats().Subscribers)
+}
+
+// TestNextBatch verifies a batch is delivered in order to all subscribers
+func TestNextBatch(t *testing.T) {
+	subject := NewSubject()
+
+	_, obs1 := subject.Subscribe()
+	observer1 := NewTestObserver(t, obs1)
+	_, obs2 := subject.Subscribe()
+	observer2 := NewTestObserver(t, obs2)
+
+	subject.NextBatch([]interface{}{1, 2, 3})
+	subject.NextBatch(nil)
+	subject.Next(4)
+	subject.Complete()
+
+	observer1.AwaitDone(time.Second)
+	observer1.AssertValues(1, 2, 3, 4)
+	observer2.AwaitDone(time.Second)
+	observer2.AssertValues(1, 2, 3, 4)
+	assert.Equal(t, uint64(4), subject.Stats().Emitted)
+}
+
+// TestNextBatch_BatchDelivery verifies a batch is delivered as a single item with WithBatchDelivery
+func TestNextBatch_BatchDelivery(t *testing.T) {
+	subject := NewSubject(WithBatchDelivery())
+
+	_, obs := subject.Subscribe()
+	calls := 0
+	done := obs.DoOnNext(func(i interface{}) {
+		calls++
+		assert.Equal(t, []interface{}{1, 2, 3}, i)
 	})
 
-	obs.DoOnCompleted(func() {
-		fmt.Println("completed")
+	subject.NextBatch([]interface{}{1, 2, 3})
+	subject.Complete()
+
+	<-done
+	assert.Equal(t, 1, calls)
+}
+
+// TestNextBatch_Pooling verifies recycled batches do not alter the delivered items
+func TestNextBatch_Pooling(t *testing.T) {
+	subject := NewReplaySubject(10, WithPooling())
+
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	subject.NextBatch([]interface{}{1, 2, 3})
+	subject.NextBatch([]interface{}{4, 5})
+	subject.NextBatch([]interface{}{6})
+
+	_, replayed := subject.Subscribe()
+	replayObserver := NewTestObserver(t, replayed)
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(1, 2, 3, 4, 5, 6)
+	replayObserver.AwaitDone(time.Second)
+	replayObserver.AssertValues(1, 2, 3, 4, 5, 6)
+}
+
+// TestShards verifies all subscribers receive the items in order when spread over shards
+func TestShards(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject(WithShards(4))
+
+	observers := make([]*TestObserver, 1000)
+	for i := range observers {
+		_, obs := subject.Subscribe()
+		observers[i] = NewTestObserver(t, obs)
+	}
+
+	expected := make([]interface{}, 10)
+	for i := range expected {
+		expected[i] = i
+		subject.Next(i)
+	}
+	subject.Complete()
+
+	for _, observer := range observers {
+		observer.AwaitDone(time.Second)
+		observer.AssertValues(expected...)
+	}
+}
+
+// TestShards_Unsubscribe verifies an unsubscribed subscriber receives its pending items only
+func TestShards_Unsubscribe(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewReplaySubject(10, WithShards(2), WithWorkerPool(2))
+	subject.Next(0)
+
+	sub, obs := subject.Subscribe()
+	unsubscribed := NewTestObserver(t, obs)
+	_, obs = subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+
+	subject.Next(1)
+	// the pending items are discarded by Unsubscribe
+	unsubscribed.AwaitCount(2, time.Second)
+	sub.Unsubscribe()
+	subject.Next(2)
+	subject.Error(errFoo)
+
+	unsubscribed.AwaitDone(time.Second)
+	unsubscribed.AssertValues(0, 1)
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(0, 1, 2)
+	observer.AssertError(errFoo)
+}
+
+// TestNextItem_Allocations guards against allocations when publishing an item
+func TestNextItem_Allocations(t *testing.T) {
+	subject := NewSubject()
+	_, obs := subject.Subscribe()
+	observe := obs.Observe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range observe {
+		}
+	}()
+
+	item := Of(0)
+	allocs := testing.AllocsPerRun(1000, func() {
+		subject.NextItem(item)
 	})
+	subject.Complete()
+	<-done
+
+	assert.Zero(t, allocs)
+}
+
+// TestItemTTL verifies the items queued longer than their time to live are dead letters instead of delivered
+func TestItemTTL(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	clock := NewTestScheduler(time.Now())
+	subject := NewSubject(WithItemTTL(time.Second), WithClock(clock))
+	letters := NewTestObserver(t, subject.DeadLetters())
+
+	// the items are queued while no subscriber is attached to the durable subscription
+	sub, _ := subject.SubscribeDurable("orders")
+	sub.Unsubscribe()
+	subject.Next(1)
+	subject.Next(2)
+	clock.Advance(time.Second)
+	subject.Next(3)
+
+	sub, obs := subject.SubscribeDurable("orders")
+	observe := obs.Observe(WithBufferedChannel(10))
+	items := receive(t, observe, 1)
+	assert.Equal(t, []interface{}{3}, values(items))
+	items[0].Ack()
+
+	letters.AwaitCount(2, time.Second)
+	letters.AssertValues(
+		DeadLetter{Item: Of(1), Subscriber: sub.GetId(), Reason: ErrExpired},
+		DeadLetter{Item: Of(2), Subscriber: sub.GetId(), Reason: ErrExpired},
+	)
+	assert.Equal(t, uint64(2), subject.Stats().Expired)
+	assert.Equal(t, 0, subject.Unacked("orders"))
 
 	subject.Complete()
+	letters.AwaitDone(time.Second)
+}
+
+// TestSubscribeWhere verifies a subscriber only receives the values satisfying its predicate, and the inline errors
+func TestSubscribeWhere(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+	even := func(i interface{}) bool {
+		return i.(int)%2 == 0
+	}
+
+	_, obs := subject.SubscribeWhere(even)
+	observer := NewTestObserver(t, obs)
+	_, all := subject.Subscribe()
+	allObserver := NewTestObserver(t, all)
+
+	subject.Next(1)
+	subject.Next(2)
+	subject.NextItem(Error(errFoo))
+	subject.NextBatch([]interface{}{3, 4, 5, 6})
+	subject.Complete()
+
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(2, 4, 6)
+	observer.AssertError(errFoo)
+	allObserver.AwaitDone(time.Second)
+	allObserver.AssertValues(1, 2, 3, 4, 5, 6)
+	assert.Equal(t, uint64(0), subject.Stats().Dropped)
+}
+
+// TestAwaitSubscribers verifies AwaitSubscribers returns once enough subscribers observe their subscription
+func TestAwaitSubscribers(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- subject.AwaitSubscribers(context.Background(), 2)
+	}()
+
+	_, obs1 := subject.Subscribe()
+	observer1 := NewTestObserver(t, obs1)
+	_, obs2 := subject.Subscribe()
+	select {
+	case <-done:
+		assert.Fail(t, "returned before the second subscriber observed")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	observer2 := NewTestObserver(t, obs2)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "timeout waiting for the subscribers")
+	}
+
+	// no item is missed once the subscribers are awaited
+	subject.Next(1)
+	subject.Complete()
+	observer1.AwaitDone(time.Second)
+	observer1.AssertValues(1)
+	observer2.AwaitDone(time.Second)
+	observer2.AssertValues(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, subject.AwaitSubscribers(ctx, 1))
+}
+
+// TestSubjectDone verifies Done and Err reflect the termination of the subject
+func TestSubjectDone(t *testing.T) {
+	completed := NewSubject()
+	done := completed.Done()
+	select {
+	case <-done:
+		assert.Fail(t, "done before the termination")
+	default:
+	}
+	completed.Complete()
+	<-done
+	assert.NoError(t, completed.Err())
+	completed.Error(errFoo)
+	assert.NoError(t, completed.Err())
+
+	failed := NewSubject()
+	failed.Error(errFoo)
+	<-failed.Done()
+	assert.Equal(t, errFoo, failed.Err())
+
+	continued := NewSubject(WithErrorStrategy(ContinueOnError))
+	continued.Error(errFoo)
+	select {
+	case <-continued.Done():
+		assert.Fail(t, "terminated by an error with the ContinueOnError strategy")
+	default:
+	}
+	assert.NoError(t, continued.Err())
 }
@@ -6,6 +6,24 @@ type Subscription interface {
 	Unsubscribe()
 }
 
+// ResizableSubscription is a subscription whose buffer can be resized, such as the subscriptions of a Subject:
+//
+//	sub.(rxgo.ResizableSubscription).Resize(1024)
+type ResizableSubscription interface {
+	Subscription
+	// Resize replaces the buffer of the subscription, queuing the items between the subject and its
+	// observers, with a buffer of the given size. The items already buffered are delivered first. With the
+	// Drop strategy, the items are then dropped once the buffer is full, instead of once the buffers of the
+	// observers are full, so that a larger buffer absorbs larger bursts. It waits for the items being sent to
+	// the subscription. The subscriptions delivered by a worker pool are not resized.
+	Resize(buffer int)
+}
+
+// resizer is a subject whose subscriptions can be resized
+type resizer interface {
+	resize(id, buffer int)
+}
+
 // subscription implementation of the Subscription interface
 type subscription struct {
 	id      int
@@ -29,3 +47,10 @@ func (s *subscription) GetId() int {
 func (s *subscription) Unsubscribe() {
 	s.subject.Unsubscribe(s.id)
 }
+
+// Resize resizes the buffer of the subscription.
+func (s *subscription) Resize(buffer int) {
+	if subject, ok := s.subject.(resizer); ok {
+		subject.resize(s.id, buffer)
+	}
+}
@@ -0,0 +1,154 @@
+package rxgo
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObserver records all the notifications of an Iterable and provides assertions on them.
+// As the Iterable is observed as soon as the TestObserver is created, no item published by a hot
+// Observable afterwards is missed.
+type TestObserver struct {
+	t         *testing.T
+	mu        sync.Mutex
+	cond      *sync.Cond
+	values    []interface{}
+	errs      []error
+	completed bool
+}
+
+// NewTestObserver creates a TestObserver observing the iterable with the given options.
+func NewTestObserver(t *testing.T, iterable Iterable, opts ...Option) *TestObserver {
+	o := &TestObserver{
+		t:      t,
+		values: make([]interface{}, 0),
+		errs:   make([]error, 0),
+	}
+	o.cond = sync.NewCond(&o.mu)
+
+	observe := iterable.Observe(opts...)
+	go func() {
+		for item := range observe {
+			o.mu.Lock()
+			if item.Error() {
+				o.errs = append(o.errs, item.E)
+			} else {
+				o.values = append(o.values, item.V)
+			}
+			o.cond.Broadcast()
+			o.mu.Unlock()
+		}
+
+		o.mu.Lock()
+		o.completed = true
+		o.cond.Broadcast()
+		o.mu.Unlock()
+	}()
+	return o
+}
+
+// Values returns the values received so far.
+func (o *TestObserver) Values() []interface{} {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append(make([]interface{}, 0, len(o.values)), o.values...)
+}
+
+// Errors returns the errors received so far.
+func (o *TestObserver) Errors() []error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append(make([]error, 0, len(o.errs)), o.errs...)
+}
+
+// IsCompleted returns whether the Iterable closed its channel.
+func (o *TestObserver) IsCompleted() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.completed
+}
+
+// AwaitCount waits until n values are received, or the Iterable completes. It fails the test if the timeout
+// elapses first.
+func (o *TestObserver) AwaitCount(n int, timeout time.Duration) bool {
+	o.t.Helper()
+	if !o.await(timeout, func() bool { return len(o.values) >= n || o.completed }) {
+		return assert.Fail(o.t, "timeout waiting for values", "expected %d values, got %d", n, len(o.Values()))
+	}
+	return true
+}
+
+// AwaitDone waits until the Iterable completes. It fails the test if the timeout elapses first.
+func (o *TestObserver) AwaitDone(timeout time.Duration) bool {
+	o.t.Helper()
+	if !o.await(timeout, func() bool { return o.completed }) {
+		return assert.Fail(o.t, "timeout waiting for completion")
+	}
+	return true
+}
+
+// await waits until the condition, evaluated under lock, is true
+func (o *TestObserver) await(timeout time.Duration, condition func() bool) bool {
+	timer := time.AfterFunc(timeout, func() {
+		o.mu.Lock()
+		o.cond.Broadcast()
+		o.mu.Unlock()
+	})
+	defer timer.Stop()
+	deadline := time.Now().Add(timeout)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for !condition() {
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		o.cond.Wait()
+	}
+	return true
+}
+
+// AssertValues asserts the values received so far.
+func (o *TestObserver) AssertValues(values ...interface{}) bool {
+	o.t.Helper()
+	if values == nil {
+		values = make([]interface{}, 0)
+	}
+	return assert.Equal(o.t, values, o.Values())
+}
+
+// AssertValueCount asserts the number of values received so far.
+func (o *TestObserver) AssertValueCount(n int) bool {
+	o.t.Helper()
+	return assert.Len(o.t, o.Values(), n)
+}
+
+// AssertError asserts a single error was received and equals err.
+func (o *TestObserver) AssertError(err error) bool {
+	o.t.Helper()
+	return assert.Equal(o.t, []error{err}, o.Errors())
+}
+
+// AssertNoErrors asserts no error was received.
+func (o *TestObserver) AssertNoErrors() bool {
+	o.t.Helper()
+	return assert.Empty(o.t, o.Errors())
+}
+
+// AssertCompleted asserts the Iterable closed its channel.
+func (o *TestObserver) AssertCompleted() bool {
+	o.t.Helper()
+	return assert.True(o.t, o.IsCompleted(), "not completed")
+}
+
+// AssertNotCompleted asserts the Iterable did not close its channel.
+func (o *TestObserver) AssertNotCompleted() bool {
+	o.t.Helper()
+	return assert.False(o.t, o.IsCompleted(), "completed")
+}
@@ -0,0 +1,49 @@
+package rxgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestTestObserver(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	observer := NewTestObserver(t, Just(1, 2, errFoo, 3)(), WithErrorStrategy(ContinueOnError))
+
+	assert.True(t, observer.AwaitDone(time.Second))
+	observer.AssertValues(1, 2, 3)
+	observer.AssertValueCount(3)
+	observer.AssertError(errFoo)
+	observer.AssertCompleted()
+}
+
+func TestTestObserver_AwaitCount(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ch := make(chan Item)
+	observer := NewTestObserver(t, FromChannel(ch))
+
+	ch <- Of(1)
+	ch <- Of(2)
+	assert.True(t, observer.AwaitCount(2, time.Second))
+	observer.AssertValues(1, 2)
+	observer.AssertNoErrors()
+	observer.AssertNotCompleted()
+
+	close(ch)
+	observer.AwaitDone(time.Second)
+	observer.AssertCompleted()
+}
+
+func TestTestObserver_AwaitTimeout(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ch := make(chan Item)
+	defer close(ch)
+
+	mockT := &testing.T{}
+	observer := NewTestObserver(mockT, FromChannel(ch))
+	assert.False(t, observer.AwaitCount(1, 10*time.Millisecond))
+	assert.False(t, observer.AwaitDone(10*time.Millisecond))
+	assert.True(t, mockT.Failed())
+}
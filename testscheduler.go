@@ -0,0 +1,173 @@
+package rxgo
+
+import (
+	"sync"
+	"time"
+)
+
+// TestScheduler is a Clock whose time only moves when it is advanced, so that time-based operators
+// can be tested without sleeping. Timers and tickers fire while the time is advanced past their deadline.
+//
+// Operators create their timers from their own goroutine: BlockUntil waits until they are registered
+// before advancing the time. Like time.Ticker, a ticker drops the ticks its receiver is not ready for,
+// so a ticker should be advanced one period at a time.
+type TestScheduler struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	seq    int
+	timers map[*virtualTimer]struct{}
+}
+
+// virtualTimer is a timer or a ticker of a TestScheduler
+type virtualTimer struct {
+	scheduler *TestScheduler
+	when      time.Time
+	period    time.Duration
+	seq       int
+	ch        chan time.Time
+}
+
+// NewTestScheduler creates a TestScheduler starting at the given time.
+func NewTestScheduler(start time.Time) *TestScheduler {
+	s := &TestScheduler{
+		now:    start,
+		timers: make(map[*virtualTimer]struct{}),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// Now returns the virtual time.
+func (s *TestScheduler) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.now
+}
+
+// After waits for the virtual time to advance by d and then sends the virtual time on the returned channel.
+func (s *TestScheduler) After(d time.Duration) <-chan time.Time {
+	return s.NewTimer(d).C()
+}
+
+// NewTimer creates a timer firing once the virtual time advanced by d.
+func (s *TestScheduler) NewTimer(d time.Duration) ClockTimer {
+	return s.schedule(d, 0)
+}
+
+// NewTicker creates a ticker firing each time the virtual time advanced by d. It panics if d is not positive.
+func (s *TestScheduler) NewTicker(d time.Duration) ClockTicker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	return &virtualTicker{timer: s.schedule(d, d)}
+}
+
+func (s *TestScheduler) schedule(d, period time.Duration) *virtualTimer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := &virtualTimer{
+		scheduler: s,
+		when:      s.now.Add(d),
+		period:    period,
+		seq:       s.seq,
+		ch:        make(chan time.Time, 1),
+	}
+	s.seq++
+	s.timers[t] = struct{}{}
+	s.cond.Broadcast()
+	return t
+}
+
+// Advance moves the virtual time forward by d, firing the timers and tickers in deadline order.
+func (s *TestScheduler) Advance(d time.Duration) {
+	s.AdvanceTo(s.Now().Add(d))
+}
+
+// AdvanceTo moves the virtual time forward to t, firing the timers and tickers in deadline order.
+// It does nothing if t is before the current virtual time.
+func (s *TestScheduler) AdvanceTo(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		next := s.nextTimer(t)
+		if next == nil {
+			break
+		}
+		s.now = next.when
+		select {
+		case next.ch <- s.now:
+		default:
+		}
+		if next.period > 0 {
+			next.when = next.when.Add(next.period)
+		} else {
+			delete(s.timers, next)
+		}
+	}
+	if t.After(s.now) {
+		s.now = t
+	}
+}
+
+// nextTimer returns the first timer due at t, nil if none
+func (s *TestScheduler) nextTimer(t time.Time) *virtualTimer {
+	var next *virtualTimer
+	for timer := range s.timers {
+		if timer.when.After(t) {
+			continue
+		}
+		if next == nil || timer.when.Before(next.when) ||
+			(timer.when.Equal(next.when) && timer.seq < next.seq) {
+			next = timer
+		}
+	}
+	return next
+}
+
+// Pending returns the number of active timers and tickers.
+func (s *TestScheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.timers)
+}
+
+// BlockUntil blocks until at least n timers and tickers are active.
+func (s *TestScheduler) BlockUntil(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.timers) < n {
+		s.cond.Wait()
+	}
+}
+
+func (t *virtualTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *virtualTimer) Stop() bool {
+	s := t.scheduler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, active := s.timers[t]
+	delete(s.timers, t)
+	return active
+}
+
+type virtualTicker struct {
+	timer *virtualTimer
+}
+
+func (t *virtualTicker) C() <-chan time.Time {
+	return t.timer.ch
+}
+
+func (t *virtualTicker) Stop() {
+	t.timer.Stop()
+}
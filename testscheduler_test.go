@@ -0,0 +1,82 @@
+package rxgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitDeadline blocks until a timer is due at the given time
+func waitDeadline(s *TestScheduler, deadline time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		for timer := range s.timers {
+			if timer.when.Equal(deadline) {
+				return
+			}
+		}
+		s.cond.Wait()
+	}
+}
+
+func TestTestScheduler_Timers(t *testing.T) {
+	start := time.Unix(0, 0)
+	s := NewTestScheduler(start)
+	t1 := s.NewTimer(2 * time.Second)
+	t2 := s.NewTimer(time.Second)
+	t3 := s.NewTimer(3 * time.Second)
+	assert.Equal(t, 3, s.Pending())
+
+	assert.True(t, t3.Stop())
+	assert.False(t, t3.Stop())
+
+	s.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Second), <-t2.C())
+	assert.Len(t, t1.C(), 0)
+
+	s.AdvanceTo(start.Add(10 * time.Second))
+	assert.Equal(t, start.Add(2*time.Second), <-t1.C())
+	assert.Len(t, t3.C(), 0)
+	assert.Equal(t, start.Add(10*time.Second), s.Now())
+	assert.Equal(t, 0, s.Pending())
+
+	// moving backward does nothing
+	s.AdvanceTo(start)
+	assert.Equal(t, start.Add(10*time.Second), s.Now())
+}
+
+func TestTestScheduler_Ticker(t *testing.T) {
+	start := time.Unix(0, 0)
+	s := NewTestScheduler(start)
+	ticker := s.NewTicker(time.Second)
+
+	s.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Second), <-ticker.C())
+	s.Advance(time.Second)
+	assert.Equal(t, start.Add(2*time.Second), <-ticker.C())
+
+	// ticks are dropped when the receiver is not ready
+	s.Advance(3 * time.Second)
+	assert.Equal(t, start.Add(3*time.Second), <-ticker.C())
+	assert.Len(t, ticker.C(), 0)
+
+	ticker.Stop()
+	s.Advance(time.Second)
+	assert.Len(t, ticker.C(), 0)
+	assert.Equal(t, 0, s.Pending())
+}
+
+func TestTestScheduler_After(t *testing.T) {
+	s := NewTestScheduler(time.Unix(0, 0))
+	done := make(chan time.Time)
+	go func() {
+		done <- <-s.After(time.Minute)
+	}()
+
+	s.BlockUntil(1)
+	s.Advance(time.Minute)
+	assert.Equal(t, time.Unix(60, 0), <-done)
+}
@@ -0,0 +1,282 @@
+package rxgo
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// TopicSubject is a subject routing each item to the subscribers of its topic, an in-process publish-subscribe
+// bus. Topics are made of segments separated by dots, like "orders.eu.created".
+//
+// The methods of Subject publishing or subscribing are shadowed by topic-aware versions, such as NextBatch,
+// Producer, SubscribeTo, SubscribeDurable and SubscribeGroup, which take a topic or a pattern.
+type TopicSubject struct {
+	Subject
+	// patterns are the topic patterns of the subscribers, and of the durable subscriptions and the
+	// subscription groups by name, guarded by the subject lock
+	patterns        map[int][]string
+	durablePatterns map[string][]string
+	groupPatterns   map[string][]string
+	routes          atomic.Value // *topicRoutes
+}
+
+// topicRoutes are the subscribers, the durable subscriptions and the subscription groups along with their
+// topic pattern
+type topicRoutes struct {
+	subscribers []topicRoute
+	durables    []durableRoute
+	groups      []groupRoute
+}
+
+// topicRoute is a subscriber along with its topic pattern
+type topicRoute struct {
+	pattern    []string
+	subscriber *subscriberState
+}
+
+// durableRoute is a durable subscription along with its topic pattern
+type durableRoute struct {
+	pattern []string
+	durable *durableSubscription
+}
+
+// groupRoute is a subscription group along with its topic pattern
+type groupRoute struct {
+	pattern []string
+	group   *subscriptionGroup
+}
+
+// NewTopicSubject creates a new topic subject. The WithShards option is ignored.
+func NewTopicSubject(opts ...Option) *TopicSubject {
+	res := &TopicSubject{
+		Subject:         newSubject(append(opts, WithShards(0))...),
+		patterns:        make(map[int][]string),
+		durablePatterns: make(map[string][]string),
+		groupPatterns:   make(map[string][]string),
+	}
+	res.onSnapshot = res.updateRoutes
+	globalSubjects.add(&res.Subject, res)
+
+	return res
+}
+
+// Next sends a new value to the subscribers of the topic.
+func (s *TopicSubject) Next(topic string, value interface{}) {
+	s.NextItem(topic, Of(value))
+}
+
+// NextItem sends an item to the subscribers of the topic.
+func (s *TopicSubject) NextItem(topic string, item Item) {
+	s.publishTopic(topic, s.intercept([]Item{item}))
+}
+
+// NextBatch sends values to the subscribers of the topic, in order.
+func (s *TopicSubject) NextBatch(topic string, values []interface{}) {
+	batch := s.newBatch(values)
+	defer s.releaseBatch(batch)
+
+	s.publishTopic(topic, batch.items)
+}
+
+// publishTopic sends items to the subscribers, the durable subscriptions and the subscription groups whose
+// pattern matches the topic
+func (s *TopicSubject) publishTopic(topic string, items []Item) {
+	if len(items) == 0 {
+		return
+	}
+	if span := startSpan(s.option, "rxgo.subject.next"); span != nil {
+		defer span.End()
+		for _, item := range items {
+			if item.Error() {
+				span.RecordError(item.E)
+			}
+		}
+		span.AddEvent("published", "topic", topic)
+	}
+	for _, item := range items {
+		globalHooks.published(&s.Subject, item)
+	}
+
+	segments := strings.Split(topic, ".")
+	routes := s.loadRoutes()
+	list := &subscriberList{}
+	for _, route := range routes.subscribers {
+		if matchTopic(route.pattern, segments) {
+			list.subscribers = append(list.subscribers, route.subscriber)
+		}
+	}
+	for _, route := range routes.durables {
+		if matchTopic(route.pattern, segments) {
+			list.durables = append(list.durables, route.durable)
+		}
+	}
+	for _, route := range routes.groups {
+		if matchTopic(route.pattern, segments) {
+			list.groups = append(list.groups, route.group)
+		}
+	}
+	s.publish(list, false, items...)
+}
+
+// Producer registers a producer of the subject, like Subject.Producer, its items being published to the
+// topic.
+func (s *TopicSubject) Producer(topic string) *ProducerHandle {
+	return s.Subject.producer(topicPublisher{TopicSubject: s, topic: topic})
+}
+
+// SubscribeTo forwards the notifications of the sources to the subject, like Subject.SubscribeTo, the values
+// being published to the topic.
+func (s *TopicSubject) SubscribeTo(topic string, sources ...Observable) Disposable {
+	return s.Subject.subscribeTo(topicPublisher{TopicSubject: s, topic: topic}, sources)
+}
+
+// Subscribe shadows base subscribe function to subscribe to all topics.
+func (s *TopicSubject) Subscribe() (Subscription, Observable) {
+	return s.SubscribeTopic("#")
+}
+
+// SubscribeWhere shadows base subscribe where function to subscribe to all topics, receiving only the values
+// satisfying the predicate.
+func (s *TopicSubject) SubscribeWhere(predicate func(interface{}) bool) (Subscription, Observable) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.subscribeTopic("#", 0, predicate)
+}
+
+// SubscribeTopic adds a subscriber receiving the items of the topics matching a pattern. In a pattern, a "*"
+// segment matches exactly one segment and a "#" segment matches zero or more segments: "orders.*.created"
+// matches "orders.eu.created", and "orders.#" matches "orders" and "orders.eu.created".
+func (s *TopicSubject) SubscribeTopic(pattern string) (Subscription, Observable) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.subscribeTopic(pattern, 0, nil)
+}
+
+// SubscribeDurable adds a durable subscription, like Subject.SubscribeDurable, receiving the items of the
+// topics matching a pattern. A durable subscription subscribed again receives the items of its latest pattern.
+func (s *TopicSubject) SubscribeDurable(pattern, name string, opts ...Option) (Subscription, Observable) {
+	s.Lock()
+	s.durablePatterns[name] = strings.Split(pattern, ".")
+	s.Unlock()
+
+	return s.Subject.SubscribeDurable(name, opts...)
+}
+
+// SubscribeGroup adds a member to a subscription group, like Subject.SubscribeGroup, the group receiving the
+// items of the topics matching a pattern. A group receives the items of the pattern of its latest member.
+func (s *TopicSubject) SubscribeGroup(pattern, name string, opts ...Option) (Subscription, Observable) {
+	s.Lock()
+	s.groupPatterns[name] = strings.Split(pattern, ".")
+	s.Unlock()
+
+	return s.Subject.SubscribeGroup(name, opts...)
+}
+
+// Child shadows base child function to create a child subject receiving the values of all topics.
+func (s *TopicSubject) Child(filter Predicate, transform Func, opts ...Option) *Subject {
+	return s.child(func(bufferSize int, predicate func(interface{}) bool) (Subscription, Observable) {
+		return s.subscribeTopic("#", bufferSize, predicate)
+	}, filter, transform, opts)
+}
+
+// subscribeTopic adds a subscriber receiving the values of the topics matching a pattern and satisfying the
+// predicate, if any, the lock being held
+func (s *TopicSubject) subscribeTopic(pattern string, bufferSize int, predicate func(interface{}) bool) (Subscription, Observable) {
+	sub, obs := s.createFilteredSubscription(bufferSize, predicate)
+	s.patterns[sub.GetId()] = strings.Split(pattern, ".")
+	// the pattern is only known once subscribed
+	s.updateRoutes()
+	return sub, obs
+}
+
+// updateRoutes publishes a copy of the routes of the current subscribers, the lock being held. It is called
+// whenever the subscribers changed.
+func (s *TopicSubject) updateRoutes() {
+	routes := &topicRoutes{
+		subscribers: make([]topicRoute, 0, len(s.patterns)),
+	}
+	for id, pattern := range s.patterns {
+		subscriber, exists := s.subscribers[id]
+		if !exists {
+			// unsubscribed, or closed by the subject termination
+			delete(s.patterns, id)
+			continue
+		}
+		routes.subscribers = append(routes.subscribers, topicRoute{pattern: pattern, subscriber: subscriber})
+	}
+	// a durable subscription keeps its items while no subscriber is attached
+	for name, durable := range s.durables {
+		if pattern, exists := s.durablePatterns[name]; exists {
+			routes.durables = append(routes.durables, durableRoute{pattern: pattern, durable: durable})
+		}
+	}
+	for name, pattern := range s.groupPatterns {
+		group, exists := s.groups[name]
+		if !exists {
+			// removed with its last member
+			delete(s.groupPatterns, name)
+			continue
+		}
+		routes.groups = append(routes.groups, groupRoute{pattern: pattern, group: group})
+	}
+	s.routes.Store(routes)
+}
+
+// loadRoutes returns the latest copy of the routes
+func (s *TopicSubject) loadRoutes() *topicRoutes {
+	if routes, ok := s.routes.Load().(*topicRoutes); ok {
+		return routes
+	}
+	return &topicRoutes{}
+}
+
+// topicPublisher publishes the items of a producer or of the sources of SubscribeTo to a topic
+type topicPublisher struct {
+	*TopicSubject
+	topic string
+}
+
+func (p topicPublisher) Next(value interface{}) {
+	p.TopicSubject.Next(p.topic, value)
+}
+
+func (p topicPublisher) NextItem(item Item) {
+	p.TopicSubject.NextItem(p.topic, item)
+}
+
+func (p topicPublisher) NextBatch(values []interface{}) {
+	p.TopicSubject.NextBatch(p.topic, values)
+}
+
+func (s *TopicSubject) info() SubjectInfo {
+	info := s.Subject.info()
+	info.Type = "TopicSubject"
+	return info
+}
+
+// matchTopic returns whether the segments of a topic match a pattern
+func matchTopic(pattern, topic []string) bool {
+	for i, segment := range pattern {
+		switch segment {
+		case "#":
+			for j := 0; j <= len(topic); j++ {
+				if matchTopic(pattern[i+1:], topic[j:]) {
+					return true
+				}
+			}
+			return false
+		case "*":
+			if len(topic) == 0 {
+				return false
+			}
+		default:
+			if len(topic) == 0 || topic[0] != segment {
+				return false
+			}
+		}
+		topic = topic[1:]
+	}
+	return len(topic) == 0
+}
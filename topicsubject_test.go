@@ -0,0 +1,141 @@
+package rxgo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// TestTopicSubject verifies the items are routed to the subscribers of their topic
+func TestTopicSubject(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewTopicSubject()
+
+	_, created := subject.SubscribeTopic("orders.*.created")
+	createdObserver := NewTestObserver(t, created)
+	_, eu := subject.SubscribeTopic("orders.eu.#")
+	euObserver := NewTestObserver(t, eu)
+	_, all := subject.Subscribe()
+	allObserver := NewTestObserver(t, all)
+
+	subject.Next("orders.eu.created", 1)
+	subject.Next("orders.us.created", 2)
+	subject.Next("orders.eu.shipped", 3)
+	subject.Next("payments", 4)
+	subject.Complete()
+
+	createdObserver.AwaitDone(time.Second)
+	createdObserver.AssertValues(1, 2)
+	euObserver.AwaitDone(time.Second)
+	euObserver.AssertValues(1, 3)
+	allObserver.AwaitDone(time.Second)
+	allObserver.AssertValues(1, 2, 3, 4)
+	assert.Equal(t, uint64(4), subject.Stats().Emitted)
+}
+
+// TestTopicSubject_Unsubscribe verifies an unsubscribed subscriber is no longer routed to
+func TestTopicSubject_Unsubscribe(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewTopicSubject()
+
+	sub, obs := subject.SubscribeTopic("orders")
+	observer := NewTestObserver(t, obs)
+	subject.Next("orders", 1)
+	observer.AwaitCount(1, time.Second)
+
+	sub.Unsubscribe()
+	observer.AwaitDone(time.Second)
+	subject.Next("orders", 2)
+	assert.Empty(t, subject.loadRoutes().subscribers)
+	observer.AssertValues(1)
+	subject.Complete()
+}
+
+// TestTopicSubject_Publishers verifies the batches, the producers and the sources are routed to the topic
+func TestTopicSubject_Publishers(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewTopicSubject()
+
+	_, orders := subject.SubscribeTopic("orders.eu")
+	ordersObserver := NewTestObserver(t, orders)
+	_, payments := subject.SubscribeTopic("payments")
+	paymentsObserver := NewTestObserver(t, payments)
+
+	subject.NextBatch("orders.eu", []interface{}{1, 2})
+	producer := subject.Producer("payments")
+	producer.Next(3)
+	subject.SubscribeTo("orders.eu", Just(4)())
+	ordersObserver.AwaitCount(3, time.Second)
+	producer.Complete()
+
+	ordersObserver.AwaitDone(time.Second)
+	ordersObserver.AssertValues(1, 2, 4)
+	paymentsObserver.AwaitDone(time.Second)
+	paymentsObserver.AssertValues(3)
+}
+
+// TestTopicSubject_Subscriptions verifies the filtered subscribers, the children, the durable subscriptions and
+// the subscription groups receive the items of their topics
+func TestTopicSubject_Subscriptions(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewTopicSubject()
+
+	_, where := subject.SubscribeWhere(func(v interface{}) bool {
+		return v.(int) > 1
+	})
+	whereObserver := NewTestObserver(t, where)
+	child := subject.Child(nil, nil)
+	_, childObs := child.Subscribe()
+	childObserver := NewTestObserver(t, childObs)
+	_, durable := subject.SubscribeDurable("orders.#", "audit")
+	durableObserve := durable.Observe(WithBufferedChannel(10))
+	_, member := subject.SubscribeGroup("payments", "workers")
+	memberObserver := NewTestObserver(t, member, WithBufferedChannel(10))
+
+	subject.Next("orders.eu", 1)
+	subject.Next("payments", 2)
+	subject.Next("orders.us", 3)
+
+	items := receive(t, durableObserve, 2)
+	assert.Equal(t, []interface{}{1, 3}, values(items))
+	for _, item := range items {
+		item.Ack()
+	}
+	subject.Complete()
+
+	whereObserver.AwaitDone(time.Second)
+	whereObserver.AssertValues(2, 3)
+	childObserver.AwaitDone(time.Second)
+	childObserver.AssertValues(1, 2, 3)
+	memberObserver.AwaitDone(time.Second)
+	memberObserver.AssertValues(2)
+	assert.Equal(t, 0, subject.Unacked("audit"))
+}
+
+func TestMatchTopic(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		topic   string
+		match   bool
+	}{
+		{"orders", "orders", true},
+		{"orders", "payments", false},
+		{"orders", "orders.eu", false},
+		{"orders.*", "orders.eu", true},
+		{"orders.*", "orders", false},
+		{"orders.*", "orders.eu.created", false},
+		{"*.eu.*", "orders.eu.created", true},
+		{"orders.#", "orders", true},
+		{"orders.#", "orders.eu.created", true},
+		{"#.created", "orders.eu.created", true},
+		{"#.created", "orders.eu.shipped", false},
+		{"orders.#.created", "orders.created", true},
+		{"orders.#.created", "orders.eu.fr.created", true},
+		{"#", "orders.eu", true},
+	} {
+		assert.Equal(t, tc.match, matchTopic(strings.Split(tc.pattern, "."), strings.Split(tc.topic, ".")), "%s %s", tc.pattern, tc.topic)
+	}
+}
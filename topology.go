@@ -0,0 +1,348 @@
+package rxgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// The kinds of the topology nodes.
+const (
+	// SubjectNode is a live subject.
+	SubjectNode = "subject"
+	// SubscriptionNode is a subscription to a subject.
+	SubscriptionNode = "subscription"
+	// OperatorNode is an operator applied to one or more Observables.
+	OperatorNode = "operator"
+	// SourceNode is an Observable created from a channel, a slice, a function or any other source.
+	SourceNode = "source"
+)
+
+// TopologyNode is a stage of a topology.
+type TopologyNode struct {
+	// ID identifies the node within the topology.
+	ID string `json:"id"`
+	// Kind is SubjectNode, SubscriptionNode, OperatorNode or SourceNode.
+	Kind string `json:"kind"`
+	// Name is the operator name, the subject name set with WithName or the source type.
+	Name string `json:"name,omitempty"`
+	// Stage is the name given to an operator with Named.
+	Stage string `json:"stage,omitempty"`
+	// Type is the subject type, for a subject node.
+	Type string `json:"type,omitempty"`
+	// Capacity is the capacity of the channel buffering the output of the stage, of its latest observation for
+	// an operator.
+	Capacity int `json:"capacity"`
+	// QueueDepth is the number of items waiting in that channel, or waiting to be delivered to the subscribers
+	// of a subject.
+	QueueDepth int `json:"queueDepth"`
+}
+
+// TopologyEdge is the flow of items from a node to another.
+type TopologyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Topology is a snapshot of the graph formed by the live subjects, their subscriptions and operator chains.
+type Topology struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
+
+// stage records the name and the upstream Observables of an operator, along with the output channel of its
+// latest observation
+type stage struct {
+	name string
+	// label is the name given with Named
+	label   string
+	parents []Iterable
+	next    atomic.Value // <-chan Item
+}
+
+// attribution returns the name the diagnostics of the stage are attributed to
+func (s *stage) attribution() string {
+	if s.label != "" {
+		return s.label
+	}
+	return s.name
+}
+
+// stageIterable is the iterable of an operator, recording its stage
+type stageIterable struct {
+	Iterable
+	stage *stage
+}
+
+func (i *stageIterable) Observe(opts ...Option) <-chan Item {
+	// the stage option of a downstream stage is overridden
+	next := i.Iterable.Observe(append(opts[:len(opts):len(opts)], inStage(i.stage.attribution()))...)
+	if next != nil {
+		i.stage.next.Store(next)
+	}
+	return next
+}
+
+// stageOf returns the name the diagnostics of an Observable are attributed to, empty if it is not a stage
+func stageOf(iterable Iterable) string {
+	if impl, ok := iterable.(*ObservableImpl); ok {
+		iterable = impl.iterable
+	}
+	if it, ok := iterable.(*stageIterable); ok {
+		return it.stage.attribution()
+	}
+	return ""
+}
+
+// withStage records the stage of the Observable created by an operator, named after the caller skip frames
+// above withStage
+func withStage(skip int, obs Observable, parents ...Iterable) Observable {
+	impl, ok := obs.(*ObservableImpl)
+	if !ok {
+		return obs
+	}
+	impl.iterable = &stageIterable{
+		Iterable: impl.iterable,
+		stage:    &stage{name: callerName(skip + 1), parents: parents},
+	}
+	return impl
+}
+
+// callerName returns the name of the function skip frames above callerName, without its package and receiver
+func callerName(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	// closures are named after their enclosing function
+	for {
+		i := strings.LastIndex(name, ".func")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// Describe returns the topology of the live named subjects with their subscriptions, and of the operator
+// chains of the given Observables, from their sources or subscriptions down to the Observables themselves. The
+// subscriptions of the other subjects are described along with the given Observables.
+func Describe(observables ...Observable) Topology {
+	d := &describer{
+		ids:  make(map[interface{}]string),
+		seqs: make(map[*Subject]int),
+	}
+
+	type liveSubject struct {
+		base  *Subject
+		entry registryEntry
+	}
+	globalSubjects.Lock()
+	subjects := make([]liveSubject, 0, len(globalSubjects.subjects))
+	for base, entry := range globalSubjects.subjects {
+		subjects = append(subjects, liveSubject{base: base, entry: entry})
+		d.seqs[base] = entry.seq
+	}
+	globalSubjects.Unlock()
+
+	sort.Slice(subjects, func(i, j int) bool {
+		return subjects[i].entry.seq < subjects[j].entry.seq
+	})
+	for _, s := range subjects {
+		d.subject(s.entry.seq, s.entry.subject.info())
+		for _, subscriber := range s.base.describeSubscribers() {
+			d.subscription(s.entry.seq, subscriber)
+		}
+	}
+	for _, obs := range observables {
+		d.iterable(obs)
+	}
+
+	return d.topology
+}
+
+// describeSubscribers returns the current subscribers in identifier order
+func (s *Subject) describeSubscribers() []*subscriberState {
+	s.RLock()
+	defer s.RUnlock()
+
+	subscribers := make([]*subscriberState, 0, len(s.subscribers))
+	for _, subscriber := range s.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	sort.Slice(subscribers, func(i, j int) bool {
+		return subscribers[i].id < subscribers[j].id
+	})
+	return subscribers
+}
+
+// describer builds a topology, each node being added once
+type describer struct {
+	topology Topology
+	ids      map[interface{}]string
+	seqs     map[*Subject]int
+	stages   int
+	sources  int
+}
+
+func (d *describer) subject(seq int, info SubjectInfo) string {
+	id := fmt.Sprintf("subject%d", seq)
+	if _, exists := d.ids[id]; !exists {
+		d.ids[id] = id
+		d.topology.Nodes = append(d.topology.Nodes, TopologyNode{
+			ID:         id,
+			Kind:       SubjectNode,
+			Name:       info.Name,
+			Type:       info.Type,
+			QueueDepth: info.Stats.QueueDepth,
+		})
+	}
+	return id
+}
+
+func (d *describer) subscription(seq int, subscriber *subscriberState) string {
+	if id, exists := d.ids[subscriber]; exists {
+		return id
+	}
+	id := fmt.Sprintf("subject%d.%d", seq, subscriber.id)
+	d.ids[subscriber] = id
+	d.topology.Nodes = append(d.topology.Nodes, TopologyNode{
+		ID:         id,
+		Kind:       SubscriptionNode,
+		Capacity:   subscriber.capacity(),
+		QueueDepth: subscriber.queued(),
+	})
+	subjectID := fmt.Sprintf("subject%d", seq)
+	if _, exists := d.ids[subjectID]; !exists {
+		// unnamed or terminated: described without its statistics
+		subjectID = d.subject(seq, SubjectInfo{Name: subscriber.subject.Name()})
+	}
+	d.edge(subjectID, id)
+	return id
+}
+
+// iterable adds the node of an iterable and of its upstream stages, and returns its identifier
+func (d *describer) iterable(it Iterable) string {
+	if impl, ok := it.(*ObservableImpl); ok {
+		it = impl.iterable
+	}
+
+	switch it := it.(type) {
+	case *stageIterable:
+		if id, exists := d.ids[it]; exists {
+			return id
+		}
+		id := fmt.Sprintf("stage%d", d.stages)
+		d.stages++
+		d.ids[it] = id
+		node := TopologyNode{ID: id, Kind: OperatorNode, Name: it.stage.name, Stage: it.stage.label}
+		if next, ok := it.stage.next.Load().(<-chan Item); ok {
+			node.Capacity = cap(next)
+			node.QueueDepth = len(next)
+		}
+		d.topology.Nodes = append(d.topology.Nodes, node)
+		for _, parent := range it.stage.parents {
+			d.edge(d.iterable(parent), id)
+		}
+		return id
+	case *eventSourceIterable:
+		if subscriber, ok := it.listener.(*subscriberState); ok {
+			seq, exists := d.seqs[subscriber.subject]
+			if !exists {
+				// unnamed or terminated: given a negative sequence, unused by the live subjects
+				seq = -len(d.seqs) - 1
+				d.seqs[subscriber.subject] = seq
+			}
+			return d.subscription(seq, subscriber)
+		}
+	}
+
+	pointer := reflect.TypeOf(it).Kind() == reflect.Ptr
+	if pointer {
+		if id, exists := d.ids[it]; exists {
+			return id
+		}
+	}
+	id := fmt.Sprintf("source%d", d.sources)
+	d.sources++
+	if pointer {
+		d.ids[it] = id
+	}
+	name := reflect.TypeOf(it).String()
+	name = strings.TrimSuffix(name[strings.LastIndex(name, ".")+1:], "Iterable")
+	d.topology.Nodes = append(d.topology.Nodes, TopologyNode{ID: id, Kind: SourceNode, Name: name})
+	return id
+}
+
+func (d *describer) edge(from, to string) {
+	d.topology.Edges = append(d.topology.Edges, TopologyEdge{From: from, To: to})
+}
+
+// JSON encodes the topology as JSON.
+func (t Topology) JSON() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+// DOT encodes the topology in the DOT language of Graphviz, each node being labeled with its name and, once it
+// buffers items, its queue depth and capacity.
+func (t Topology) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph rxgo {\n")
+	for _, node := range t.Nodes {
+		label := node.Name
+		shape := "box"
+		switch node.Kind {
+		case SubjectNode:
+			shape = "ellipse"
+			if label == "" {
+				label = node.Type
+			} else if node.Type != "" {
+				label = fmt.Sprintf("%s (%s)", label, node.Type)
+			}
+		case SubscriptionNode:
+			shape = "invhouse"
+			label = node.ID
+		case SourceNode:
+			shape = "cds"
+		case OperatorNode:
+			if node.Stage != "" {
+				label = fmt.Sprintf("%s (%s)", node.Stage, label)
+			}
+		}
+		if node.Capacity > 0 || node.QueueDepth > 0 {
+			label = fmt.Sprintf("%s\n%d/%d", label, node.QueueDepth, node.Capacity)
+		}
+		fmt.Fprintf(&b, "\t%s [label=%s, shape=%s];\n", dotQuote(node.ID), dotQuote(label), shape)
+	}
+	for _, edge := range t.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes a DOT identifier, a newline being kept as a line break
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+// iterables converts Observables to the upstream iterables of a stage
+func iterables(observables []Observable) []Iterable {
+	res := make([]Iterable, len(observables))
+	for i, obs := range observables {
+		res[i] = obs
+	}
+	return res
+}
@@ -0,0 +1,180 @@
+package rxgo
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// node returns the node of a topology with the given name
+func node(t *testing.T, topology Topology, name string) TopologyNode {
+	t.Helper()
+	for _, n := range topology.Nodes {
+		if n.Name == name {
+			return n
+		}
+	}
+	assert.FailNow(t, "node not found", name)
+	return TopologyNode{}
+}
+
+// upstream returns the nodes flowing into a node
+func upstream(topology Topology, id string) []string {
+	var res []string
+	for _, edge := range topology.Edges {
+		if edge.To == id {
+			res = append(res, edge.From)
+		}
+	}
+	return res
+}
+
+func Test_Describe_Chain(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := Just(1, 2)().Map(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i, nil
+	}).Filter(func(interface{}) bool {
+		return true
+	})
+	topology := Describe(obs)
+
+	source := node(t, topology, "just")
+	assert.Equal(t, SourceNode, source.Kind)
+	mapStage := node(t, topology, "Map")
+	assert.Equal(t, OperatorNode, mapStage.Kind)
+	filter := node(t, topology, "Filter")
+	assert.Equal(t, []string{source.ID}, upstream(topology, mapStage.ID))
+	assert.Equal(t, []string{mapStage.ID}, upstream(topology, filter.ID))
+}
+
+func Test_Describe_Merge(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	a := Just(1)()
+	b := Just(2)().Map(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i, nil
+	})
+	merged := Merge([]Observable{a, b})
+	topology := Describe(merged)
+
+	merge := node(t, topology, "Merge")
+	assert.Len(t, upstream(topology, merge.ID), 2)
+	assert.Contains(t, upstream(topology, merge.ID), node(t, topology, "Map").ID)
+	Assert(context.Background(), t, merged, HasItemsNoOrder(1, 2))
+}
+
+// TestDescribe_Subject verifies the subscriptions of a subject are described, along with the chains observing them
+func TestDescribe_Subject(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject(WithName("orders"))
+	defer subject.Complete()
+	_, first := subject.Subscribe()
+	_, second := subject.Subscribe()
+	obs := first.Map(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i, nil
+	})
+	topology := Describe(obs, second)
+
+	s := node(t, topology, "orders")
+	assert.Equal(t, SubjectNode, s.Kind)
+	assert.Equal(t, "Subject", s.Type)
+	subscriptions := make([]string, 0)
+	for _, edge := range topology.Edges {
+		if edge.From == s.ID {
+			subscriptions = append(subscriptions, edge.To)
+		}
+	}
+	assert.Len(t, subscriptions, 2)
+	assert.Equal(t, subscriptions[:1], upstream(topology, node(t, topology, "Map").ID))
+}
+
+func Test_Describe_QueueDepth(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obs := Just(1, 2, 3)().Map(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i, nil
+	}, WithBufferedChannel(5), WithContext(ctx))
+	obs.Observe()
+
+	assert.Eventually(t, func() bool {
+		return node(t, Describe(obs), "Map").QueueDepth == 3
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 5, node(t, Describe(obs), "Map").Capacity)
+}
+
+func Test_Topology_Export(t *testing.T) {
+	topology := Topology{
+		Nodes: []TopologyNode{
+			{ID: "subject0", Kind: SubjectNode, Name: "orders", Type: "Subject"},
+			{ID: "stage0", Kind: OperatorNode, Name: "Map", Capacity: 5, QueueDepth: 2},
+		},
+		Edges: []TopologyEdge{{From: "subject0", To: "stage0"}},
+	}
+
+	dot := topology.DOT()
+	assert.True(t, strings.HasPrefix(dot, "digraph rxgo {\n"))
+	assert.Contains(t, dot, `"subject0" [label="orders (Subject)", shape=ellipse];`)
+	assert.Contains(t, dot, `"stage0" [label="Map\n2/5", shape=box];`)
+	assert.Contains(t, dot, `"subject0" -> "stage0";`)
+
+	data, err := topology.JSON()
+	assert.NoError(t, err)
+	var decoded Topology
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, topology, decoded)
+}
+
+func Test_Describe_Named(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	obs := Just(1, 2)().Named("input").Map(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i, nil
+	}).Named("parse")
+	topology := Describe(obs)
+
+	source := node(t, topology, "just")
+	input := node(t, topology, "Named")
+	assert.Equal(t, "input", input.Stage)
+	mapStage := node(t, topology, "Map")
+	assert.Equal(t, "parse", mapStage.Stage)
+	assert.Equal(t, []string{source.ID}, upstream(topology, input.ID))
+	assert.Equal(t, []string{input.ID}, upstream(topology, mapStage.ID))
+	assert.Contains(t, topology.DOT(), `label="parse (Map)"`)
+}
+
+// stageRecorder is an iterable recording the stage observing it
+type stageRecorder struct {
+	stage string
+}
+
+func (r *stageRecorder) Observe(opts ...Option) <-chan Item {
+	r.stage = parseOptions(opts...).getStage()
+	next := make(chan Item)
+	close(next)
+	return next
+}
+
+func Test_Named_Propagation(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	recorder := &stageRecorder{}
+	obs := (&ObservableImpl{iterable: recorder}).Map(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i, nil
+	}).Named("parse").Filter(func(interface{}) bool {
+		return true
+	})
+	Assert(ctx, t, obs, IsEmpty())
+	// the Map operator observes its source with the stage name
+	assert.Equal(t, "parse", recorder.stage)
+
+	// an unnamed operator is attributed to its name
+	Assert(ctx, t, (&ObservableImpl{iterable: recorder}).Map(func(_ context.Context, i interface{}) (interface{}, error) {
+		return i, nil
+	}), IsEmpty())
+	assert.Equal(t, "Map", recorder.stage)
+}
@@ -0,0 +1,29 @@
+package rxgo
+
+import "context"
+
+// Tracer starts spans. It mirrors a subset of the OpenTelemetry tracing API,
+// so that adapting an OpenTelemetry tracer only takes a few lines.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span is a unit of work started by a Tracer.
+type Span interface {
+	// AddEvent records an event with key-value attributes.
+	AddEvent(name string, keysAndValues ...interface{})
+	// RecordError records an error.
+	RecordError(err error)
+	// End completes the span.
+	End()
+}
+
+// startSpan starts a span if a tracer is configured, otherwise it returns nil.
+func startSpan(option Option, spanName string) Span {
+	tracer := option.getTracer()
+	if tracer == nil {
+		return nil
+	}
+	_, span := tracer.Start(option.buildContext(emptyContext), spanName)
+	return span
+}
@@ -0,0 +1,64 @@
+package rxgo
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingTracer struct {
+	sync.Mutex
+	spans []*recordingSpan
+}
+
+type recordingSpan struct {
+	name   string
+	events []string
+	errs   []error
+	ended  bool
+}
+
+func (t *recordingTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	t.Lock()
+	defer t.Unlock()
+	span := &recordingSpan{name: spanName}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (s *recordingSpan) AddEvent(name string, _ ...interface{}) {
+	s.events = append(s.events, name)
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.errs = append(s.errs, err)
+}
+
+func (s *recordingSpan) End() {
+	s.ended = true
+}
+
+// TestWithTracer verifies spans are recorded for emissions, errors and drops
+func TestWithTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	subject := NewSubject(WithTracer(tracer), WithBackPressureStrategy(Drop))
+	_, obs := subject.Subscribe()
+	// first observer never reads
+	obs.Observe()
+	done := obs.DoOnCompleted(func() {}, WithBufferedChannel(2))
+
+	subject.Next(1)
+	subject.Error(errFoo)
+	<-done
+
+	tracer.Lock()
+	defer tracer.Unlock()
+	names := make([]string, 0)
+	for _, span := range tracer.spans {
+		assert.True(t, span.ended)
+		names = append(names, span.name)
+	}
+	assert.ElementsMatch(t, []string{"rxgo.subject.next", "rxgo.subject.drop", "rxgo.subject.error", "rxgo.subject.drop"}, names)
+}
@@ -27,6 +27,8 @@ type (
 	ErrorFunc func(error) interface{}
 	// Predicate defines a func that returns a bool from an input value.
 	Predicate func(interface{}) bool
+	// PredicateE defines a fallible predicate, such as one parsing the input value or looking it up.
+	PredicateE func(context.Context, interface{}) (bool, error)
 	// Marshaller defines a marshaller type (interface{} to []byte).
 	Marshaller func(interface{}) ([]byte, error)
 	// Unmarshaller defines an unmarshaller type ([]byte to interface).
@@ -56,6 +58,12 @@ const (
 	Block BackpressureStrategy = iota
 	// Drop drops the message.
 	Drop
+	// Adaptive switches a subject subscription between buffering, sampling and keeping the latest item only
+	// as its queue grows, per the AdaptivePolicy. Elsewhere, it blocks like Block.
+	Adaptive
+	// Conflate queues the items of a subject subscription by key, set with WithConflation, an item replacing
+	// the queued item with the same key. Elsewhere, it blocks like Block.
+	Conflate
 )
 
 // OnErrorStrategy is the Observable error strategy.
@@ -69,6 +77,40 @@ const (
 	ContinueOnError
 )
 
+// PredicateErrorPolicy defines how an operator handles an error returned by a PredicateE.
+type PredicateErrorPolicy uint32
+
+const (
+	// FailOnPredicateError is the default policy: the error is emitted and routed per the error strategy,
+	// StopOnError stopping the operator and ContinueOnError skipping the item.
+	FailOnPredicateError PredicateErrorPolicy = iota
+	// SkipOnPredicateError skips the item.
+	SkipOnPredicateError
+	// DeadLetterOnPredicateError skips the item, emitted to the DeadLetterOutput side output as a DeadLetter
+	// whose reason is the error.
+	DeadLetterOnPredicateError
+)
+
+// TerminalPolicy defines when a subject fed by Subject.SubscribeTo completes.
+type TerminalPolicy uint32
+
+const (
+	// CompleteOnAll is the default policy: the subject completes once all the sources completed.
+	CompleteOnAll TerminalPolicy = iota
+	// CompleteOnFirst completes the subject once a source completed, the other sources being disposed of.
+	CompleteOnFirst
+)
+
+// GroupBalancing defines how a subscription group picks the member receiving an item.
+type GroupBalancing uint32
+
+const (
+	// RoundRobin is the default balancing: the members receive the items in turn.
+	RoundRobin GroupBalancing = iota
+	// LeastLoaded sends an item to the member with the fewest items waiting to be delivered.
+	LeastLoaded
+)
+
 // ObservationStrategy defines the strategy to consume from an Observable.
 type ObservationStrategy uint32
 
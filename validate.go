@@ -0,0 +1,360 @@
+package rxgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// NoSubscriber is the subscriber of a dead letter rejected before being delivered to any subscriber, and of the
+// ConsumerStats of a durable subscription without subscriber.
+const NoSubscriber = -1
+
+// ValidationError is the reason of a dead letter rejected by the validator of a subject.
+type ValidationError struct {
+	Err error
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("validation failed: %v", e.Err)
+}
+
+// Unwrap returns the error of the validator.
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validate adds a middleware checking the values published to the subject with validate, such as a validator
+// created by JSONSchema. A value for which validate returns an error is not delivered: it is sent to the dead
+// letters with a ValidationError reason and the NoSubscriber subscriber, or logged if they are not collected.
+func (s *Subject) Validate(validate func(interface{}) error) {
+	s.Use(func(next NextFunc) NextFunc {
+		return func(v interface{}) {
+			err := validate(v)
+			if err == nil {
+				next(v)
+				return
+			}
+			if !s.deadLetter(NoSubscriber, Of(v), ValidationError{Err: err}) {
+				s.logger().Warn("rxgo: invalid item", "item", v, "error", err)
+			}
+		}
+	})
+}
+
+// JSONSchema returns a validator checking values against a JSON schema. A []byte or json.RawMessage value is
+// decoded as JSON, other values, such as maps or structs, are checked as encoded by encoding/json.
+//
+// The schema supports the keywords type, enum, const, properties, required, additionalProperties, items,
+// minItems, maxItems, minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength and pattern.
+// The other keywords are ignored.
+func JSONSchema(schema []byte) (func(interface{}) error, error) {
+	var root jsonSchema
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %v", err)
+	}
+	if err := root.compile(); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %v", err)
+	}
+	return func(v interface{}) error {
+		doc, err := jsonDocument(v)
+		if err != nil {
+			return err
+		}
+		return root.validate("$", doc)
+	}, nil
+}
+
+// jsonDocument returns a value as decoded from JSON
+func jsonDocument(v interface{}) (interface{}, error) {
+	data, ok := v.([]byte)
+	if raw, isRaw := v.(json.RawMessage); isRaw {
+		data, ok = raw, true
+	}
+	if !ok {
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	return doc, nil
+}
+
+// jsonSchema is the supported subset of a JSON schema
+type jsonSchema struct {
+	Type                 jsonTypes              `json:"type"`
+	Enum                 []interface{}          `json:"enum"`
+	Const                *interface{}           `json:"const"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64               `json:"exclusiveMaximum"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+
+	// reject is set by the false schema, rejecting any value
+	reject  bool
+	pattern *regexp.Regexp
+}
+
+// UnmarshalJSON accepts the boolean schemas, true accepting any value and false rejecting any value
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	var accept bool
+	if err := json.Unmarshal(data, &accept); err == nil {
+		s.reject = !accept
+		return nil
+	}
+	type plain jsonSchema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// compile compiles the patterns of the schema and its subschemas
+func (s *jsonSchema) compile() error {
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return err
+		}
+		s.pattern = pattern
+	}
+	for _, sub := range s.subschemas() {
+		if err := sub.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) subschemas() []*jsonSchema {
+	var subs []*jsonSchema
+	for _, sub := range s.Properties {
+		subs = append(subs, sub)
+	}
+	if s.AdditionalProperties != nil {
+		subs = append(subs, s.AdditionalProperties)
+	}
+	if s.Items != nil {
+		subs = append(subs, s.Items)
+	}
+	return subs
+}
+
+// validate checks a decoded JSON value at path against the schema
+func (s *jsonSchema) validate(path string, v interface{}) error {
+	if s.reject {
+		return fmt.Errorf("%s: no value allowed", path)
+	}
+	if len(s.Type) > 0 && !s.Type.matches(v) {
+		return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(s.Type, " or "), jsonTypeOf(v))
+	}
+	if s.Const != nil && !jsonEqual(*s.Const, v) {
+		return fmt.Errorf("%s: expected %v", path, *s.Const)
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, candidate := range s.Enum {
+			if jsonEqual(candidate, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, v, s.Enum)
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return s.validateObject(path, v)
+	case []interface{}:
+		return s.validateArray(path, v)
+	case json.Number:
+		return s.validateNumber(path, v)
+	case string:
+		return s.validateString(path, v)
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateObject(path string, v map[string]interface{}) error {
+	for _, name := range s.Required {
+		if _, exists := v[name]; !exists {
+			return fmt.Errorf("%s: missing property %q", path, name)
+		}
+	}
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	// the first error is reported deterministically
+	sort.Strings(names)
+	for _, name := range names {
+		sub, exists := s.Properties[name]
+		if !exists {
+			sub = s.AdditionalProperties
+		}
+		if sub == nil {
+			continue
+		}
+		if err := sub.validate(path+"."+name, v[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateArray(path string, v []interface{}) error {
+	if s.MinItems != nil && len(v) < *s.MinItems {
+		return fmt.Errorf("%s: expected at least %d items, got %d", path, *s.MinItems, len(v))
+	}
+	if s.MaxItems != nil && len(v) > *s.MaxItems {
+		return fmt.Errorf("%s: expected at most %d items, got %d", path, *s.MaxItems, len(v))
+	}
+	if s.Items != nil {
+		for i, item := range v {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateNumber(path string, v json.Number) error {
+	f, err := v.Float64()
+	if err != nil {
+		return fmt.Errorf("%s: invalid number %s", path, v)
+	}
+	switch {
+	case s.Minimum != nil && f < *s.Minimum:
+		return fmt.Errorf("%s: %v is less than %v", path, v, *s.Minimum)
+	case s.Maximum != nil && f > *s.Maximum:
+		return fmt.Errorf("%s: %v is greater than %v", path, v, *s.Maximum)
+	case s.ExclusiveMinimum != nil && f <= *s.ExclusiveMinimum:
+		return fmt.Errorf("%s: %v is not greater than %v", path, v, *s.ExclusiveMinimum)
+	case s.ExclusiveMaximum != nil && f >= *s.ExclusiveMaximum:
+		return fmt.Errorf("%s: %v is not less than %v", path, v, *s.ExclusiveMaximum)
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateString(path string, v string) error {
+	length := len([]rune(v))
+	switch {
+	case s.MinLength != nil && length < *s.MinLength:
+		return fmt.Errorf("%s: expected at least %d characters, got %d", path, *s.MinLength, length)
+	case s.MaxLength != nil && length > *s.MaxLength:
+		return fmt.Errorf("%s: expected at most %d characters, got %d", path, *s.MaxLength, length)
+	case s.pattern != nil && !s.pattern.MatchString(v):
+		return fmt.Errorf("%s: %q does not match %s", path, v, s.Pattern)
+	}
+	return nil
+}
+
+// jsonTypes is the type keyword, a single type or a list of types
+type jsonTypes []string
+
+func (t *jsonTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = jsonTypes{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+func (t jsonTypes) matches(v interface{}) bool {
+	actual := jsonTypeOf(v)
+	for _, expected := range t {
+		if expected == actual || expected == "number" && actual == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonTypeOf returns the JSON type of a decoded value, integer for the numbers without fractional part
+func jsonTypeOf(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case json.Number:
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// jsonEqual returns whether two decoded values are equal, the numbers being compared by value
+func jsonEqual(a, b interface{}) bool {
+	if na, ok := jsonNumber(a); ok {
+		nb, ok := jsonNumber(b)
+		return ok && na == nb
+	}
+	switch a := a.(type) {
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if w, exists := b[k]; !exists || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+func jsonNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
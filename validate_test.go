@@ -0,0 +1,107 @@
+package rxgo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+const orderSchema = `{
+	"type": "object",
+	"required": ["id", "amount"],
+	"properties": {
+		"id": {"type": "string", "pattern": "^o-[0-9]+$"},
+		"amount": {"type": "number", "exclusiveMinimum": 0},
+		"currency": {"enum": ["EUR", "USD"]},
+		"lines": {"type": "array", "minItems": 1, "items": {"type": "integer", "maximum": 10}}
+	},
+	"additionalProperties": false
+}`
+
+func TestJSONSchema(t *testing.T) {
+	validate, err := JSONSchema([]byte(orderSchema))
+	assert.NoError(t, err)
+
+	type order struct {
+		ID     string  `json:"id"`
+		Amount float64 `json:"amount"`
+	}
+	for _, valid := range []interface{}{
+		[]byte(`{"id": "o-1", "amount": 12.5, "currency": "EUR", "lines": [1, 2]}`),
+		json.RawMessage(`{"id": "o-2", "amount": 1}`),
+		map[string]interface{}{"id": "o-3", "amount": 3},
+		order{ID: "o-4", Amount: 4},
+	} {
+		assert.NoError(t, validate(valid), "%v", valid)
+	}
+
+	for doc, expected := range map[string]string{
+		`[]`:                         "$: expected object, got array",
+		`{"id": "o-1"}`:              `$: missing property "amount"`,
+		`{"id": "x", "amount": 1}`:   `$.id: "x" does not match ^o-[0-9]+$`,
+		`{"id": "o-1", "amount": 0}`: "$.amount: 0 is not greater than 0",
+		`{"id": "o-1", "amount": 1, "currency": "GBP"}`: "$.currency: GBP is not one of [EUR USD]",
+		`{"id": "o-1", "amount": 1, "lines": []}`:       "$.lines: expected at least 1 items, got 0",
+		`{"id": "o-1", "amount": 1, "lines": [1.5]}`:    "$.lines[0]: expected integer, got number",
+		`{"id": "o-1", "amount": 1, "lines": [11]}`:     "$.lines[0]: 11 is greater than 10",
+		`{"id": "o-1", "amount": 1, "note": ""}`:        "$.note: no value allowed",
+		`{"id":`:                                        "invalid JSON: unexpected EOF",
+	} {
+		err := validate([]byte(doc))
+		if assert.Error(t, err, doc) {
+			assert.Equal(t, expected, err.Error(), doc)
+		}
+	}
+
+	_, err = JSONSchema([]byte(`{"pattern": "("}`))
+	assert.Error(t, err)
+	_, err = JSONSchema([]byte(`{"type": 1}`))
+	assert.Error(t, err)
+}
+
+func TestSubject_Validate(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	logger := &recordingLogger{}
+	subject := NewSubject(WithLogger(logger))
+	letters := NewTestObserver(t, subject.DeadLetters(), WithBufferedChannel(10))
+	invalid := errors.New("odd value")
+	subject.Validate(func(i interface{}) error {
+		if i.(int)%2 != 0 {
+			return invalid
+		}
+		return nil
+	})
+	_, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs, WithBufferedChannel(10))
+
+	subject.NextBatch([]interface{}{1, 2, 3, 4})
+	subject.Complete()
+	observer.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{2, 4}, observer.Values())
+
+	letters.AwaitDone(time.Second)
+	assert.Equal(t, []interface{}{
+		DeadLetter{Item: Of(1), Subscriber: NoSubscriber, Reason: ValidationError{Err: invalid}},
+		DeadLetter{Item: Of(3), Subscriber: NoSubscriber, Reason: ValidationError{Err: invalid}},
+	}, letters.Values())
+	assert.True(t, errors.Is(ValidationError{Err: invalid}, invalid))
+	// the invalid items collected by the dead letters are not logged
+	assert.NotContains(t, logger.messages, "rxgo: invalid item")
+}
+
+// TestSubject_Validate_Logged verifies the invalid items are logged if the dead letters are not collected
+func TestSubject_Validate_Logged(t *testing.T) {
+	logger := &recordingLogger{}
+	subject := NewSubject(WithLogger(logger))
+	subject.Validate(func(i interface{}) error {
+		return errors.New("invalid")
+	})
+
+	subject.Next(1)
+	subject.Complete()
+	assert.Contains(t, logger.messages, "rxgo: invalid item")
+}
@@ -0,0 +1,70 @@
+package rxgo
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// weakHandle unsubscribes a subscription once both its Subscription and its Observable became unreachable,
+// unless the subscription is observed: an observer consuming cannot be told apart from an observer which
+// stopped, as the subject references the observer channels. The subject does not reference the handle.
+type weakHandle struct {
+	id      int
+	subject *Subject
+	// refs is the number of reachable references to the handle, the Subscription and the Observable
+	refs int32
+}
+
+// weakSubscription is a subscription whose handle is released by a finalizer once unreachable
+type weakSubscription struct {
+	handle *weakHandle
+}
+
+// newWeakSubscription returns a weak Subscription and its Observable
+func newWeakSubscription(id int, subject *Subject, iterable Iterable) (*weakSubscription, *ObservableImpl) {
+	handle := &weakHandle{id: id, subject: subject, refs: 2}
+	sub := &weakSubscription{handle: handle}
+	runtime.SetFinalizer(sub, func(sub *weakSubscription) {
+		sub.handle.release()
+	})
+	obs := &ObservableImpl{iterable: iterable}
+	runtime.SetFinalizer(obs, func(*ObservableImpl) {
+		handle.release()
+	})
+	return sub, obs
+}
+
+// release releases a reference to the handle, unsubscribing the subscription once the last reference was
+// released without it being observed. The subscription is unsubscribed outside of the finalizer goroutine as
+// the subject may be locked.
+func (h *weakHandle) release() {
+	if atomic.AddInt32(&h.refs, -1) > 0 {
+		return
+	}
+	go func() {
+		h.subject.RLock()
+		subscriber, exists := h.subject.subscribers[h.id]
+		h.subject.RUnlock()
+		if !exists || subscriber.source.hasObservers() {
+			return
+		}
+		if h.subject.unsubscribe(h.id) {
+			h.subject.logger().Warn("rxgo: weak subscription collected without Unsubscribe", "subscriber", h.id)
+		}
+	}()
+}
+
+func (s *weakSubscription) GetId() int {
+	return s.handle.id
+}
+
+// Resize resizes the buffer of the subscription.
+func (s *weakSubscription) Resize(buffer int) {
+	s.handle.subject.resize(s.handle.id, buffer)
+}
+
+// Unsubscribe removes the subscriber, the finalizer being cleared.
+func (s *weakSubscription) Unsubscribe() {
+	runtime.SetFinalizer(s, nil)
+	s.handle.subject.Unsubscribe(s.handle.id)
+}
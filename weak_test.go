@@ -0,0 +1,96 @@
+package rxgo
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+// awaitCollected runs the garbage collector until the subject has no subscriber left
+func awaitCollected(t *testing.T, subject *Subject) {
+	deadline := time.After(time.Second)
+	for subject.Stats().Subscribers > 0 {
+		runtime.GC()
+		select {
+		case <-deadline:
+			assert.FailNow(t, "subscription not collected")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestWeakSubscriptions_Collected(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	logger := &recordingLogger{}
+	subject := NewSubject(WithWeakSubscriptions(), WithLogger(logger))
+	defer subject.Complete()
+
+	// neither the Subscription nor the Observable is kept
+	subject.Subscribe()
+	awaitCollected(t, subject)
+	logger.Lock()
+	defer logger.Unlock()
+	assert.Contains(t, logger.messages, "rxgo: weak subscription collected without Unsubscribe")
+}
+
+func TestWeakSubscriptions_Observed(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject(WithWeakSubscriptions())
+
+	// the observer still consuming keeps the subscription, even once its Subscription and Observable are
+	// unreachable
+	_, obs := subject.Subscribe()
+	observe := obs.Observe()
+	obs = nil
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, subject.Stats().Subscribers)
+	go func() {
+		subject.Next(1)
+		subject.Complete()
+	}()
+	assert.Equal(t, []interface{}{1}, values(receive(t, observe, 1)))
+	_, ok := <-observe
+	assert.False(t, ok)
+}
+
+func TestWeakSubscriptions_Unsubscribed(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	logger := &recordingLogger{}
+	subject := NewSubject(WithWeakSubscriptions(), WithLogger(logger))
+
+	sub, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+	sub.Unsubscribe()
+	observer.AwaitDone(time.Second)
+	sub = nil
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+	}
+	subject.Complete()
+
+	logger.Lock()
+	defer logger.Unlock()
+	assert.NotContains(t, logger.messages, "rxgo: weak subscription collected without Unsubscribe")
+}
+
+func TestWeakSubscriptions_Reachable(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	subject := NewSubject(WithWeakSubscriptions())
+
+	sub, obs := subject.Subscribe()
+	observer := NewTestObserver(t, obs)
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+	}
+	subject.Next(1)
+	subject.Complete()
+	observer.AwaitDone(time.Second)
+	observer.AssertValues(1)
+	runtime.KeepAlive(sub)
+}
@@ -0,0 +1,55 @@
+package rxgo
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// WriteTo observes an Observable and writes each item to w, encoded with encode. Without encode, the items
+// must be []byte or string values, written as is. As the next item is only received once the previous one is
+// written, a slow writer slows down the Observable.
+//
+// It returns once the Observable completed, nil, or at the first error emitted by the Observable or returned
+// by encode or w, the observation being stopped. It returns the context error if the context is done first.
+func WriteTo(ctx context.Context, observable Observable, w io.Writer, encode func(interface{}) ([]byte, error), opts ...Option) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	observe := observable.Observe(append(opts, WithContext(ctx))...)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case item, ok := <-observe:
+			if !ok {
+				return nil
+			}
+			if item.Error() {
+				return item.E
+			}
+			data, err := encodeItem(item.V, encode)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// encodeItem encodes a value, with encode if not nil
+func encodeItem(v interface{}, encode func(interface{}) ([]byte, error)) ([]byte, error) {
+	if encode != nil {
+		return encode(v)
+	}
+	switch v := v.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, IllegalInputError{error: fmt.Sprintf("%T value without encoder", v)}
+	}
+}
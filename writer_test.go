@@ -0,0 +1,58 @@
+package rxgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestWriteTo(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan Item, 2)
+	ch <- Of("a\n")
+	ch <- Of([]byte("b\n"))
+	close(ch)
+	var buf bytes.Buffer
+	assert.NoError(t, WriteTo(ctx, FromChannel(ch), &buf, nil))
+	assert.Equal(t, "a\nb\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteTo(ctx, Just(1, map[string]int{"a": 1})(), &buf, json.Marshal))
+	assert.Equal(t, `1{"a":1}`, buf.String())
+}
+
+func TestWriteTo_Error(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var buf bytes.Buffer
+	assert.Equal(t, errFoo, WriteTo(ctx, testObservable(ctx, "a", errFoo, "b"), &buf, nil))
+	assert.Equal(t, "a", buf.String())
+
+	assert.Error(t, WriteTo(ctx, Just(1)(), &buf, nil))
+	assert.Equal(t, errFoo, WriteTo(ctx, Just("a")(), &buf, func(interface{}) ([]byte, error) {
+		return nil, errFoo
+	}))
+	assert.Equal(t, iotest.ErrTimeout, WriteTo(ctx, Just("a")(), writerFunc(func([]byte) (int, error) {
+		return 0, iotest.ErrTimeout
+	}), nil))
+
+	ctx, cancel = context.WithCancel(ctx)
+	cancel()
+	assert.Equal(t, context.Canceled, WriteTo(ctx, Never(), &buf, nil))
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}